@@ -41,6 +41,14 @@ const (
 	EventAgentOutcome = "agent.outcome"
 	// EventWorkerTimeout records worker process timeout.
 	EventWorkerTimeout = "worker.timeout"
+	// EventWorkerStalled records a worker killed for inactivity.
+	EventWorkerStalled = "worker.stalled"
+	// EventCLICooldown records a CLI entering a rate-limit cooldown.
+	EventCLICooldown = "cli.cooldown"
+	// EventMergeCommit records the base branch commits a task merge produced.
+	EventMergeCommit = "merge.commit"
+	// EventTaskRevert records a merged task being reverted on the base branch.
+	EventTaskRevert = "task.revert"
 )
 
 // Logger appends audit entries to a log file.
@@ -177,6 +185,34 @@ func (logger *Logger) LogTaskTransition(taskID string, role string, from string,
 	})
 }
 
+// LogMergeCommit records the merge result for a task: base is the base branch commit the task
+// landed on and commit is the resulting tip, so base..commit covers everything the task added.
+func (logger *Logger) LogMergeCommit(taskID string, role string, commit string, base string, strategy string) error {
+	return logger.Log(Entry{
+		TaskID: taskID,
+		Role:   role,
+		Event:  EventMergeCommit,
+		Fields: []Field{
+			{Key: "commit", Value: commit},
+			{Key: "base", Value: base},
+			{Key: "strategy", Value: strategy},
+		},
+	})
+}
+
+// LogTaskRevert records the commit that reverted a merged task.
+func (logger *Logger) LogTaskRevert(taskID string, role string, mergeCommit string, revertCommit string) error {
+	return logger.Log(Entry{
+		TaskID: taskID,
+		Role:   role,
+		Event:  EventTaskRevert,
+		Fields: []Field{
+			{Key: "merge_commit", Value: mergeCommit},
+			{Key: "revert_commit", Value: revertCommit},
+		},
+	})
+}
+
 // LogAgentInvoke records an agent invocation event.
 func (logger *Logger) LogAgentInvoke(taskID string, role string, agent string, attempt int) error {
 	return logger.Log(Entry{
@@ -217,6 +253,34 @@ func (logger *Logger) LogWorkerTimeout(taskID string, role string, timeoutSecs i
 	})
 }
 
+// LogWorkerStalled records a worker killed after producing no activity for stallSecs.
+func (logger *Logger) LogWorkerStalled(taskID string, role string, stallSecs int, lastActivity time.Time, worktreePath string) error {
+	return logger.Log(Entry{
+		TaskID: taskID,
+		Role:   role,
+		Event:  EventWorkerStalled,
+		Fields: []Field{
+			{Key: "stall_seconds", Value: strconv.Itoa(stallSecs)},
+			{Key: "last_activity", Value: lastActivity.UTC().Format(time.RFC3339)},
+			{Key: "worktree_path", Value: worktreePath},
+		},
+	})
+}
+
+// LogCLICooldown records a CLI cooldown triggered by a rate-limited worker.
+func (logger *Logger) LogCLICooldown(taskID string, role string, cli string, until time.Time, reason string) error {
+	return logger.Log(Entry{
+		TaskID: taskID,
+		Role:   role,
+		Event:  EventCLICooldown,
+		Fields: []Field{
+			{Key: "cli", Value: cli},
+			{Key: "until", Value: until.UTC().Format(time.RFC3339)},
+			{Key: "reason", Value: reason},
+		},
+	})
+}
+
 // formatEntry renders an audit entry in logfmt-style order.
 func (logger *Logger) formatEntry(entry Entry) (string, error) {
 	if entry.Event == "" {
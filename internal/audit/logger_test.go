@@ -205,3 +205,41 @@ func TestLogWorkerTimeout(t *testing.T) {
 		t.Fatalf("expected audit line %q, got %q", expected, lines[0])
 	}
 }
+
+// TestLogWorkerStalled ensures worker stall events are logged correctly.
+func TestLogWorkerStalled(t *testing.T) {
+	repoRoot := t.TempDir()
+	logPath := filepath.Join(repoRoot, localStateDirName, auditLogFileName)
+	if err := os.MkdirAll(filepath.Dir(logPath), auditLogDirMode); err != nil {
+		t.Fatalf("create audit log dir: %v", err)
+	}
+	if err := os.WriteFile(logPath, []byte(""), auditLogFileMode); err != nil {
+		t.Fatalf("create audit log file: %v", err)
+	}
+
+	var warnings bytes.Buffer
+	logger, err := NewLogger(repoRoot, &warnings)
+	if err != nil {
+		t.Fatalf("new logger: %v", err)
+	}
+	logger.now = func() time.Time {
+		return time.Date(2025, 1, 14, 20, 40, 0, 0, time.UTC)
+	}
+
+	lastActivity := time.Date(2025, 1, 14, 20, 20, 0, 0, time.UTC)
+	if err := logger.LogWorkerStalled("T-042", "worker", 1200, lastActivity, "_governator/_local-state/task-T-042"); err != nil {
+		t.Fatalf("log worker stalled: %v", err)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("expected no warnings, got %q", warnings.String())
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	expected := "ts=2025-01-14T20:40:00Z task_id=T-042 role=worker event=worker.stalled stall_seconds=1200 last_activity=2025-01-14T20:20:00Z worktree_path=_governator/_local-state/task-T-042"
+	if line := strings.TrimSpace(string(data)); line != expected {
+		t.Fatalf("expected audit line %q, got %q", expected, line)
+	}
+}
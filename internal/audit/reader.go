@@ -0,0 +1,77 @@
+// Package audit provides lookups over the append-only audit log.
+package audit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LastTaskEvent returns the fields of the most recent entry for taskID with the given event.
+// The boolean is false when the log is missing or holds no matching entry.
+func LastTaskEvent(repoRoot string, taskID string, event string) (map[string]string, bool, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return nil, false, errors.New("repo root is required")
+	}
+	path := filepath.Join(repoRoot, localStateDirName, auditLogFileName)
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("open audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var last map[string]string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := parseLine(scanner.Text())
+		if fields["task_id"] == taskID && fields["event"] == event {
+			last = fields
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, fmt.Errorf("read audit log %s: %w", path, err)
+	}
+	return last, last != nil, nil
+}
+
+// parseLine decodes one logfmt line written by formatEntry.
+func parseLine(line string) map[string]string {
+	fields := map[string]string{}
+	for i := 0; i < len(line); {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		eq := strings.IndexByte(line[i:], '=')
+		if eq < 0 {
+			break
+		}
+		key := line[i : i+eq]
+		i += eq + 1
+		var value strings.Builder
+		if i < len(line) && line[i] == '"' {
+			i++
+			for i < len(line) && line[i] != '"' {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				}
+				value.WriteByte(line[i])
+				i++
+			}
+			i++
+		} else {
+			for i < len(line) && line[i] != ' ' {
+				value.WriteByte(line[i])
+				i++
+			}
+		}
+		fields[key] = value.String()
+	}
+	return fields
+}
@@ -0,0 +1,54 @@
+// Tests for audit log lookups.
+package audit
+
+import (
+	"io"
+	"testing"
+)
+
+// TestLastTaskEventReturnsLatestMatch finds the newest entry for a task and event.
+func TestLastTaskEventReturnsLatestMatch(t *testing.T) {
+	repoRoot := t.TempDir()
+	logger, err := NewLogger(repoRoot, io.Discard)
+	if err != nil {
+		t.Fatalf("new logger: %v", err)
+	}
+	if err := logger.LogMergeCommit("T-001", "worker", "aaa111", "base000", "squash"); err != nil {
+		t.Fatalf("log merge commit: %v", err)
+	}
+	if err := logger.LogMergeCommit("T-002", "worker", "bbb222", "aaa111", "merge"); err != nil {
+		t.Fatalf("log merge commit: %v", err)
+	}
+	if err := logger.LogMergeCommit("T-001", "worker", "ccc333", "bbb222", "rebase"); err != nil {
+		t.Fatalf("log merge commit: %v", err)
+	}
+
+	fields, ok, err := LastTaskEvent(repoRoot, "T-001", EventMergeCommit)
+	if err != nil || !ok {
+		t.Fatalf("LastTaskEvent = %v, %v, %v", fields, ok, err)
+	}
+	if fields["commit"] != "ccc333" || fields["base"] != "bbb222" || fields["strategy"] != "rebase" {
+		t.Fatalf("fields = %v", fields)
+	}
+
+	if _, ok, err := LastTaskEvent(repoRoot, "T-003", EventMergeCommit); err != nil || ok {
+		t.Fatalf("unexpected match for T-003: %v, %v", ok, err)
+	}
+	if _, ok, err := LastTaskEvent(t.TempDir(), "T-001", EventMergeCommit); err != nil || ok {
+		t.Fatalf("missing log should not match: %v, %v", ok, err)
+	}
+}
+
+// TestParseLineDecodesQuotedValues reverses formatField quoting and escaping.
+func TestParseLineDecodesQuotedValues(t *testing.T) {
+	line := formatField("ts", "2025-01-14T19:02:11Z") + " " +
+		formatField("event", "merge.verify.failure") + " " +
+		formatField("error", `exit "1" in C:\tmp`)
+	fields := parseLine(line)
+	if fields["event"] != "merge.verify.failure" {
+		t.Fatalf("event = %q", fields["event"])
+	}
+	if fields["error"] != `exit "1" in C:\tmp` {
+		t.Fatalf("error = %q", fields["error"])
+	}
+}
@@ -1,17 +1,69 @@
 // Package config provides default configuration handling.
 package config
 
-import "strings"
+import (
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
 
 const (
-	defaultConcurrencyGlobal      = 1
-	defaultConcurrencyDefaultRole = 1
-	defaultWorkerTimeoutSeconds   = 900
-	defaultRetriesMaxAttempts     = 2
-	defaultBranchBase             = "main"
-	defaultWorkerCLI              = CLICodex
+	defaultConcurrencyGlobal          = 1
+	defaultConcurrencyDefaultRole     = 1
+	defaultWorkerTimeoutSeconds       = 900
+	defaultRetriesMaxAttempts         = 2
+	defaultPlanningValidationAttempts = 3
+	defaultBranchBase                 = "main"
+	defaultMergeStrategy              = MergeStrategySquash
+	defaultWorkerCLI                  = CLICodex
+	defaultRateLimitCooldown          = 300
+	defaultRateLimitMaxDeferrals      = 10
+	defaultVerifyTimeoutSeconds       = 900
+	defaultVerifyBatchSize            = 1
+	defaultSandboxMode                = SandboxModeOff
+	defaultGitName                    = "Governator CLI"
+	defaultGitEmail                   = "governator@localhost"
+	reservedEnvPrefix                 = "GOVERNATOR_"
 )
 
+// envVarNamePattern matches portable environment variable names.
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// defaultPromptDropOrder removes generated context first and reasoning guidance last.
+var defaultPromptDropOrder = []string{PromptSectionContext, PromptSectionCustomRole, PromptSectionCustomGlobal, PromptSectionReasoning}
+
+// defaultRateLimitPatterns lists the built-in rate-limit and quota signatures per CLI. Each is
+// anchored to a line the CLI itself prints as an error, so agent output that merely quotes
+// rate-limit text from the code it works on does not start a cooldown.
+var defaultRateLimitPatterns = map[string][]string{
+	CLICodex: {
+		`(?i)^\W*(stream )?error\b.*\brate limit reached`,
+		`(?i)^\W*(error\W+)?you've hit your usage limit`,
+		`(?i)^\W*(stream )?error\b.*\binsufficient_quota\b`,
+		`(?i)^\W*(stream )?error\b.*\b429 too many requests\b`,
+	},
+	CLIClaude: {
+		`(?i)^\W*(api )?error\b.*\brate_limit_error\b`,
+		`(?i)^\W*(claude ai )?usage limit reached`,
+		`(?i)^\W*(api )?error\b.*\boverloaded_error\b`,
+		`(?i)^\W*(api )?error\b.*\b429\b`,
+	},
+	CLIGemini: {
+		`^\W*(\w+ )?[Ee]rror\b.*\bRESOURCE_EXHAUSTED\b`,
+		`(?i)^\W*(\w+ )?error\b.*\bquota exceeded\b`,
+		`(?i)^\W*(\w+ )?error\b.*\b429\b`,
+	},
+	RateLimitPatternKeyDefault: {
+		`(?i)^\W*(\w+ )?error\b.*\brate limit (reached|exceeded)\b`,
+		`(?i)^\W*(\w+ )?error\b.*\bquota exceeded\b`,
+		`(?i)^\W*(\w+ )?error\b.*\b429 too many requests\b`,
+	},
+}
+
 // Defaults returns the documented configuration defaults.
 //
 // Defaults:
@@ -23,7 +75,50 @@ const (
 // - concurrency.default_role: 1
 // - concurrency.roles: {}
 // - timeouts.worker_seconds: 900
+// - timeouts.stall_seconds: 0 (stall detection disabled)
+// - timeouts.roles: {}
+// - timeouts.stages: {}
+// - timeouts.planning_steps: {}
 // - retries.max_attempts: 2
+// - retries.planning_validation_attempts: 3 (two repair passes after failed validations)
+// - branches.base: "main"
+// - branches.merge_strategy: "squash"
+// - branches.remote: "" (nothing is fetched or pushed)
+// - branches.rebase_idle: false
+// - rate_limits.cooldown_seconds: 300
+// - rate_limits.max_deferrals: 10 (rate-limit deferrals per task before it is failed)
+// - rate_limits.patterns: built-in signatures for codex, claude, gemini, and default
+// - sandbox.default.mode: "off"
+// - sandbox.default.disable_network: false
+// - sandbox.default.writable_paths: []
+// - sandbox.default.home_paths: [] (the selected CLI's own config paths are always kept)
+// - sandbox.roles: {}
+// - resources.default: {memory_mb: 0, cpus: 0, max_processes: 0, max_file_size_mb: 0} (0 = unlimited)
+// - resources.roles: {}
+// - env.default: {}
+// - env.roles: {}
+// - env.stages: {}
+// - prompt_budget.default: {warn_tokens: 0, max_tokens: 0, on_exceed: "warn"} (0 = no budget)
+// - prompt_budget.clis: {}
+// - prompt_budget.drop_order: ["context", "custom_role", "custom_global", "reasoning"]
+// - dependency_context.diff_max_bytes: 0 (diffs omitted)
+// - verify.command: [] (merges are not verified)
+// - verify.timeout_seconds: 900
+// - verify.batch_size: 1 (no merge queue)
+// - commits.stage_template: DefaultStageCommitTemplate ("[<state>] <title>" plus worker stdout)
+// - commits.merge_template: DefaultMergeCommitTemplate ("governator: <id> - <title>")
+// - commits.disable_trailers: false
+// - worktrees.root: "" (_governator/_local-state)
+// - worktrees.max_count: 0 (unlimited)
+// - worktrees.max_total_mb: 0 (unlimited)
+// - drift.mode: "replan"
+// - drift.include: [] (everything under _governator/docs)
+// - drift.exclude: []
+// - drift.normalize_markdown: false
+// - git.default: {name: "Governator CLI", email: "governator@localhost"}
+// - git.roles: {}
+// - git.merge: {} (the ambient git config identity)
+// - git.signing: {format: "", key: ""} (merge commits are not signed)
 func Defaults() Config {
 	return Config{
 		Workers: WorkersConfig{
@@ -43,17 +138,66 @@ func Defaults() Config {
 		},
 		Timeouts: TimeoutsConfig{
 			WorkerSeconds: defaultWorkerTimeoutSeconds,
+			Roles:         map[string]int{},
+			Stages:        map[string]int{},
+			PlanningSteps: map[string]int{},
 		},
 		Retries: RetriesConfig{
-			MaxAttempts: defaultRetriesMaxAttempts,
+			MaxAttempts:                defaultRetriesMaxAttempts,
+			PlanningValidationAttempts: defaultPlanningValidationAttempts,
 		},
 		Branches: BranchConfig{
-			Base: defaultBranchBase,
+			Base:          defaultBranchBase,
+			MergeStrategy: defaultMergeStrategy,
 		},
 		ReasoningEffort: ReasoningEffortConfig{
 			Default: DefaultReasoningEffort,
 			Roles:   map[string]string{},
 		},
+		RateLimits: RateLimitConfig{
+			CooldownSeconds: defaultRateLimitCooldown,
+			MaxDeferrals:    defaultRateLimitMaxDeferrals,
+			Patterns:        cloneStringSliceMap(defaultRateLimitPatterns),
+		},
+		Sandbox: SandboxConfig{
+			Default: SandboxPolicy{
+				Mode:          defaultSandboxMode,
+				WritablePaths: []string{},
+				HomePaths:     []string{},
+			},
+			Roles: map[string]SandboxPolicy{},
+		},
+		Resources: ResourcesConfig{
+			Roles: map[string]ResourceLimits{},
+		},
+		Env: EnvConfig{
+			Default: map[string]string{},
+			Roles:   map[string]map[string]string{},
+			Stages:  map[string]map[string]string{},
+		},
+		PromptBudget: PromptBudgetConfig{
+			Default:   PromptBudget{OnExceed: PromptBudgetWarn},
+			CLIs:      map[string]PromptBudget{},
+			DropOrder: cloneStrings(defaultPromptDropOrder),
+		},
+		Verify: VerifyConfig{
+			Command:        []string{},
+			TimeoutSeconds: defaultVerifyTimeoutSeconds,
+			BatchSize:      defaultVerifyBatchSize,
+		},
+		Commits: CommitsConfig{
+			StageTemplate: DefaultStageCommitTemplate,
+			MergeTemplate: DefaultMergeCommitTemplate,
+		},
+		Git: GitConfig{
+			Default: GitIdentity{Name: defaultGitName, Email: defaultGitEmail},
+			Roles:   map[string]GitIdentity{},
+		},
+		Drift: DriftConfig{
+			Mode:    DriftModeReplan,
+			Include: []string{},
+			Exclude: []string{},
+		},
 	}
 }
 
@@ -110,18 +254,174 @@ func ApplyDefaults(cfg Config, warn func(string)) Config {
 		"timeouts.worker_seconds",
 		warn,
 	)
+	if cfg.Timeouts.StallSeconds < 0 {
+		emitWarning(warn, "invalid timeouts.stall_seconds; disabling stall detection")
+		cfg.Timeouts.StallSeconds = 0
+	}
+	cfg.Timeouts.Roles = normalizeTimeoutOverrides(
+		cfg.Timeouts.Roles,
+		nil,
+		"timeouts.roles",
+		warn,
+	)
+	cfg.Timeouts.Stages = normalizeTimeoutOverrides(
+		cfg.Timeouts.Stages,
+		StageKeys,
+		"timeouts.stages",
+		warn,
+	)
+	cfg.Timeouts.PlanningSteps = normalizeTimeoutOverrides(
+		cfg.Timeouts.PlanningSteps,
+		nil,
+		"timeouts.planning_steps",
+		warn,
+	)
 	cfg.Retries.MaxAttempts = normalizePositiveInt(
 		cfg.Retries.MaxAttempts,
 		defaults.Retries.MaxAttempts,
 		"retries.max_attempts",
 		warn,
 	)
+	cfg.Retries.PlanningValidationAttempts = normalizePositiveInt(
+		cfg.Retries.PlanningValidationAttempts,
+		defaults.Retries.PlanningValidationAttempts,
+		"retries.planning_validation_attempts",
+		warn,
+	)
 	cfg.Branches.Base = normalizeBranchBase(
 		cfg.Branches.Base,
 		defaults.Branches.Base,
 		"branches.base",
 		warn,
 	)
+	cfg.Branches.MergeStrategy = normalizeMergeStrategy(
+		cfg.Branches.MergeStrategy,
+		defaults.Branches.MergeStrategy,
+		"branches.merge_strategy",
+		warn,
+	)
+	cfg.Branches.Remote = strings.TrimSpace(cfg.Branches.Remote)
+	cfg.RateLimits.CooldownSeconds = normalizePositiveInt(
+		cfg.RateLimits.CooldownSeconds,
+		defaults.RateLimits.CooldownSeconds,
+		"rate_limits.cooldown_seconds",
+		warn,
+	)
+	cfg.RateLimits.MaxDeferrals = normalizePositiveInt(
+		cfg.RateLimits.MaxDeferrals,
+		defaults.RateLimits.MaxDeferrals,
+		"rate_limits.max_deferrals",
+		warn,
+	)
+	cfg.RateLimits.Patterns = normalizeRateLimitPatterns(
+		cfg.RateLimits.Patterns,
+		defaults.RateLimits.Patterns,
+		"rate_limits.patterns",
+		warn,
+	)
+	cfg.Sandbox.Default = normalizeSandboxPolicy(
+		cfg.Sandbox.Default,
+		defaults.Sandbox.Default.Mode,
+		"sandbox.default",
+		warn,
+	)
+	cfg.Sandbox.Roles = normalizeRoleSandboxes(
+		cfg.Sandbox.Roles,
+		cfg.Sandbox.Default.Mode,
+		"sandbox.roles",
+		warn,
+	)
+	cfg.Resources.Default = normalizeResourceLimits(
+		cfg.Resources.Default,
+		"resources.default",
+		warn,
+	)
+	cfg.Resources.Roles = normalizeRoleResources(
+		cfg.Resources.Roles,
+		"resources.roles",
+		warn,
+	)
+	cfg.Env.Default = normalizeEnvVars(
+		cfg.Env.Default,
+		"env.default",
+		warn,
+	)
+	cfg.Env.Roles = normalizeEnvVarsMap(
+		cfg.Env.Roles,
+		nil,
+		"env.roles",
+		warn,
+	)
+	cfg.Env.Stages = normalizeEnvVarsMap(
+		cfg.Env.Stages,
+		StageKeys,
+		"env.stages",
+		warn,
+	)
+	cfg.DependencyContext.DiffMaxBytes = normalizeNonNegativeInt(
+		cfg.DependencyContext.DiffMaxBytes,
+		"dependency_context.diff_max_bytes",
+		warn,
+	)
+	cfg.Verify.Command = normalizeVerifyCommand(cfg.Verify.Command, "verify.command", warn)
+	cfg.Verify.TimeoutSeconds = normalizePositiveInt(
+		cfg.Verify.TimeoutSeconds,
+		defaults.Verify.TimeoutSeconds,
+		"verify.timeout_seconds",
+		warn,
+	)
+	cfg.Verify.BatchSize = normalizePositiveInt(
+		cfg.Verify.BatchSize,
+		defaults.Verify.BatchSize,
+		"verify.batch_size",
+		warn,
+	)
+	cfg.Commits.StageTemplate = normalizeCommitTemplate(
+		cfg.Commits.StageTemplate,
+		defaults.Commits.StageTemplate,
+		"commits.stage_template",
+		warn,
+	)
+	cfg.Commits.MergeTemplate = normalizeCommitTemplate(
+		cfg.Commits.MergeTemplate,
+		defaults.Commits.MergeTemplate,
+		"commits.merge_template",
+		warn,
+	)
+	cfg.Worktrees.MaxCount = normalizeNonNegativeInt(cfg.Worktrees.MaxCount, "worktrees.max_count", warn)
+	cfg.Worktrees.MaxTotalMB = normalizeNonNegativeInt(cfg.Worktrees.MaxTotalMB, "worktrees.max_total_mb", warn)
+	cfg.Drift.Mode = normalizeDriftMode(cfg.Drift.Mode, defaults.Drift.Mode, "drift.mode", warn)
+	cfg.Drift.Include = normalizeGlobPaths(cfg.Drift.Include, "drift.include", warn)
+	cfg.Drift.Exclude = normalizeGlobPaths(cfg.Drift.Exclude, "drift.exclude", warn)
+	if cfg.Git.Default.Name == "" {
+		cfg.Git.Default.Name = defaults.Git.Default.Name
+	}
+	if cfg.Git.Default.Email == "" {
+		cfg.Git.Default.Email = defaults.Git.Default.Email
+	}
+	if cfg.Git.Roles == nil {
+		cfg.Git.Roles = map[string]GitIdentity{}
+	}
+	cfg.Git.Signing = normalizeGitSigning(cfg.Git.Signing, "git.signing", warn)
+	if cfg.Git.Signing.Enabled() && cfg.Branches.MergeStrategy == MergeStrategyRebase {
+		emitWarning(warn, "git.signing has no effect on task merges with branches.merge_strategy rebase; stage commits land on the base branch unsigned")
+	}
+	cfg.PromptBudget.Default = normalizePromptBudget(
+		cfg.PromptBudget.Default,
+		"prompt_budget.default",
+		warn,
+	)
+	cfg.PromptBudget.CLIs = normalizePromptBudgetCLIs(
+		cfg.PromptBudget.CLIs,
+		"prompt_budget.clis",
+		warn,
+	)
+	cfg.PromptBudget.DropOrder = normalizePromptDropOrder(
+		cfg.PromptBudget.DropOrder,
+		defaults.PromptBudget.DropOrder,
+		"prompt_budget.drop_order",
+		warn,
+	)
 	if cfg.ReasoningEffort.Roles == nil {
 		cfg.ReasoningEffort.Roles = map[string]string{}
 	}
@@ -181,6 +481,58 @@ func normalizeRoleCaps(values map[string]int, keyPrefix string, warn func(string
 	return normalized
 }
 
+// normalizeTimeoutOverrides drops non-positive timeouts and, when allowed is set, unknown keys.
+func normalizeTimeoutOverrides(values map[string]int, allowed []string, keyPrefix string, warn func(string)) map[string]int {
+	if values == nil {
+		return map[string]int{}
+	}
+	normalized := make(map[string]int, len(values))
+	for key, seconds := range values {
+		if allowed != nil && !containsString(allowed, key) {
+			emitWarning(warn, "unknown "+keyPrefix+"."+key+"; ignoring")
+			continue
+		}
+		if seconds <= 0 {
+			emitWarning(warn, "invalid "+keyPrefix+"."+key+"; using timeouts.worker_seconds")
+			continue
+		}
+		normalized[key] = seconds
+	}
+	return normalized
+}
+
+// normalizeEnvVars drops variables with invalid names or names reserved for Governator.
+func normalizeEnvVars(values map[string]string, keyPrefix string, warn func(string)) map[string]string {
+	if values == nil {
+		return map[string]string{}
+	}
+	normalized := make(map[string]string, len(values))
+	for name, value := range values {
+		if !envVarNamePattern.MatchString(name) || strings.HasPrefix(name, reservedEnvPrefix) {
+			emitWarning(warn, "invalid "+keyPrefix+"."+name+"; ignoring")
+			continue
+		}
+		normalized[name] = value
+	}
+	return normalized
+}
+
+// normalizeEnvVarsMap normalizes per-role or per-stage variables and, when allowed is set, drops unknown keys.
+func normalizeEnvVarsMap(values map[string]map[string]string, allowed []string, keyPrefix string, warn func(string)) map[string]map[string]string {
+	if values == nil {
+		return map[string]map[string]string{}
+	}
+	normalized := make(map[string]map[string]string, len(values))
+	for key, vars := range values {
+		if allowed != nil && !containsString(allowed, key) {
+			emitWarning(warn, "unknown "+keyPrefix+"."+key+"; ignoring")
+			continue
+		}
+		normalized[key] = normalizeEnvVars(vars, keyPrefix+"."+key, warn)
+	}
+	return normalized
+}
+
 // normalizePositiveInt defaults invalid values.
 func normalizePositiveInt(value int, fallback int, key string, warn func(string)) int {
 	if value <= 0 {
@@ -210,6 +562,16 @@ func cloneStrings(values []string) []string {
 	return clone
 }
 
+// containsString reports whether values includes target.
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
 // normalizeBranchBase ensures the configured base branch is non-empty.
 func normalizeBranchBase(value string, fallback string, key string, warn func(string)) string {
 	trimmed := strings.TrimSpace(value)
@@ -220,6 +582,32 @@ func normalizeBranchBase(value string, fallback string, key string, warn func(st
 	return trimmed
 }
 
+// normalizeMergeStrategy validates the merge strategy, falling back when unset or unknown.
+func normalizeMergeStrategy(value string, fallback string, key string, warn func(string)) string {
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case trimmed == "":
+		return fallback
+	case !IsValidMergeStrategy(trimmed):
+		emitWarning(warn, "invalid "+key+"; using "+fallback)
+		return fallback
+	}
+	return trimmed
+}
+
+// normalizeDriftMode validates the drift mode, falling back when it is missing or unknown.
+func normalizeDriftMode(value string, fallback string, key string, warn func(string)) string {
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case trimmed == "":
+		return fallback
+	case !IsValidDriftMode(trimmed):
+		emitWarning(warn, "invalid "+key+"; using "+fallback)
+		return fallback
+	}
+	return trimmed
+}
+
 // normalizeCLI validates and defaults the CLI selection.
 func normalizeCLI(value string, fallback string, key string, warn func(string)) string {
 	trimmed := strings.TrimSpace(value)
@@ -247,6 +635,62 @@ func normalizeRoleCLIs(values map[string]string, keyPrefix string, warn func(str
 	return normalized
 }
 
+// normalizeVerifyCommand disables verification when the command has no program to run.
+func normalizeVerifyCommand(value []string, key string, warn func(string)) []string {
+	if len(value) == 0 {
+		return []string{}
+	}
+	if strings.TrimSpace(value[0]) == "" {
+		emitWarning(warn, "invalid "+key+"; verification disabled")
+		return []string{}
+	}
+	return cloneStrings(value)
+}
+
+// normalizeCommitTemplate falls back to the built-in template when value is empty, does not
+// parse, or references fields CommitTemplateData lacks.
+func normalizeCommitTemplate(value string, fallback string, key string, warn func(string)) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	if err := ValidateCommitTemplate(value); err != nil {
+		emitWarning(warn, "invalid "+key+": "+err.Error()+"; using default")
+		return fallback
+	}
+	return value
+}
+
+// ValidateCommitTemplate parses a commit template and renders it against empty data.
+func ValidateCommitTemplate(text string) error {
+	parsed, err := template.New("commit").Parse(text)
+	if err != nil {
+		return err
+	}
+	return parsed.Execute(io.Discard, CommitTemplateData{})
+}
+
+// normalizeGitSigning accepts "gpg" as openpgp and disables signing with an unknown format
+// or without a key.
+func normalizeGitSigning(signing GitSigning, keyPrefix string, warn func(string)) GitSigning {
+	signing.Format = strings.ToLower(strings.TrimSpace(signing.Format))
+	signing.Key = strings.TrimSpace(signing.Key)
+	switch signing.Format {
+	case "":
+		return GitSigning{}
+	case "gpg":
+		signing.Format = GitSigningOpenPGP
+	case GitSigningSSH, GitSigningOpenPGP:
+	default:
+		emitWarning(warn, "invalid "+keyPrefix+".format; signing disabled")
+		return GitSigning{}
+	}
+	if signing.Key == "" {
+		emitWarning(warn, keyPrefix+".key is required to sign commits; signing disabled")
+		return GitSigning{}
+	}
+	return signing
+}
+
 // normalizeCommandOverride validates command overrides (allows empty).
 func normalizeCommandOverride(value []string, key string, warn func(string)) []string {
 	if len(value) == 0 {
@@ -259,6 +703,186 @@ func normalizeCommandOverride(value []string, key string, warn func(string)) []s
 	return cloneStrings(value)
 }
 
+// normalizeRateLimitPatterns drops invalid expressions and fills CLIs without overrides from the defaults.
+func normalizeRateLimitPatterns(values map[string][]string, fallback map[string][]string, keyPrefix string, warn func(string)) map[string][]string {
+	normalized := cloneStringSliceMap(fallback)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		patterns := make([]string, 0, len(values[key]))
+		for _, pattern := range values[key] {
+			trimmed := strings.TrimSpace(pattern)
+			if trimmed == "" {
+				continue
+			}
+			if _, err := regexp.Compile(trimmed); err != nil {
+				emitWarning(warn, "invalid "+keyPrefix+"."+key+" pattern "+trimmed+"; skipping")
+				continue
+			}
+			patterns = append(patterns, trimmed)
+		}
+		normalized[strings.TrimSpace(key)] = patterns
+	}
+	return normalized
+}
+
+// normalizeSandboxPolicy validates the sandbox mode and drops unsafe relative paths.
+func normalizeSandboxPolicy(policy SandboxPolicy, fallbackMode string, keyPrefix string, warn func(string)) SandboxPolicy {
+	mode := strings.TrimSpace(policy.Mode)
+	switch {
+	case mode == "":
+		mode = fallbackMode
+	case !IsValidSandboxMode(mode):
+		emitWarning(warn, "invalid "+keyPrefix+".mode; using "+fallbackMode)
+		mode = fallbackMode
+	}
+	policy.Mode = mode
+	policy.WritablePaths = normalizeRelativePaths(policy.WritablePaths, keyPrefix+".writable_paths", warn)
+	policy.HomePaths = normalizeRelativePaths(policy.HomePaths, keyPrefix+".home_paths", warn)
+	return policy
+}
+
+// normalizeRoleSandboxes validates per-role sandbox policies, inheriting the default mode when unset.
+func normalizeRoleSandboxes(values map[string]SandboxPolicy, fallbackMode string, keyPrefix string, warn func(string)) map[string]SandboxPolicy {
+	if values == nil {
+		return map[string]SandboxPolicy{}
+	}
+	normalized := make(map[string]SandboxPolicy, len(values))
+	for role, policy := range values {
+		normalized[role] = normalizeSandboxPolicy(policy, fallbackMode, keyPrefix+"."+role, warn)
+	}
+	return normalized
+}
+
+// normalizeResourceLimits resets negative limits to unlimited.
+func normalizeResourceLimits(limits ResourceLimits, keyPrefix string, warn func(string)) ResourceLimits {
+	limits.MemoryMB = normalizeNonNegativeInt(limits.MemoryMB, keyPrefix+".memory_mb", warn)
+	limits.CPUs = normalizeNonNegativeInt(limits.CPUs, keyPrefix+".cpus", warn)
+	limits.MaxProcesses = normalizeNonNegativeInt(limits.MaxProcesses, keyPrefix+".max_processes", warn)
+	limits.MaxFileSizeMB = normalizeNonNegativeInt(limits.MaxFileSizeMB, keyPrefix+".max_file_size_mb", warn)
+	return limits
+}
+
+// normalizeRoleResources validates per-role resource limits.
+func normalizeRoleResources(values map[string]ResourceLimits, keyPrefix string, warn func(string)) map[string]ResourceLimits {
+	if values == nil {
+		return map[string]ResourceLimits{}
+	}
+	normalized := make(map[string]ResourceLimits, len(values))
+	for role, limits := range values {
+		normalized[role] = normalizeResourceLimits(limits, keyPrefix+"."+role, warn)
+	}
+	return normalized
+}
+
+// normalizePromptBudget resets negative token limits and unknown actions.
+func normalizePromptBudget(budget PromptBudget, keyPrefix string, warn func(string)) PromptBudget {
+	budget.WarnTokens = normalizeNonNegativeInt(budget.WarnTokens, keyPrefix+".warn_tokens", warn)
+	budget.MaxTokens = normalizeNonNegativeInt(budget.MaxTokens, keyPrefix+".max_tokens", warn)
+	action := strings.TrimSpace(budget.OnExceed)
+	switch {
+	case action == "":
+		action = PromptBudgetWarn
+	case !IsValidPromptBudgetAction(action):
+		emitWarning(warn, "invalid "+keyPrefix+".on_exceed; using "+PromptBudgetWarn)
+		action = PromptBudgetWarn
+	}
+	budget.OnExceed = action
+	return budget
+}
+
+// normalizePromptBudgetCLIs validates per-CLI prompt budgets.
+func normalizePromptBudgetCLIs(values map[string]PromptBudget, keyPrefix string, warn func(string)) map[string]PromptBudget {
+	if values == nil {
+		return map[string]PromptBudget{}
+	}
+	normalized := make(map[string]PromptBudget, len(values))
+	for cli, budget := range values {
+		key := strings.TrimSpace(cli)
+		if !IsValidCLI(key) {
+			emitWarning(warn, "invalid "+keyPrefix+"."+cli+"; ignoring unknown CLI")
+			continue
+		}
+		normalized[key] = normalizePromptBudget(budget, keyPrefix+"."+key, warn)
+	}
+	return normalized
+}
+
+// normalizePromptDropOrder keeps known, unique section kinds; nil selects the default order.
+func normalizePromptDropOrder(values []string, fallback []string, key string, warn func(string)) []string {
+	if values == nil {
+		return cloneStrings(fallback)
+	}
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		kind := strings.TrimSpace(value)
+		if !containsString(PromptSectionDropKinds, kind) {
+			emitWarning(warn, "invalid "+key+" entry "+value+"; ignoring")
+			continue
+		}
+		if containsString(normalized, kind) {
+			continue
+		}
+		normalized = append(normalized, kind)
+	}
+	return normalized
+}
+
+// normalizeNonNegativeInt treats negative values as unset.
+func normalizeNonNegativeInt(value int, key string, warn func(string)) int {
+	if value < 0 {
+		emitWarning(warn, "invalid "+key+"; using unlimited")
+		return 0
+	}
+	return value
+}
+
+// normalizeRelativePaths keeps clean relative paths that stay inside their base directory.
+func normalizeRelativePaths(values []string, key string, warn func(string)) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		cleaned := filepath.Clean(trimmed)
+		if filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			emitWarning(warn, "invalid "+key+" entry "+trimmed+"; skipping")
+			continue
+		}
+		normalized = append(normalized, cleaned)
+	}
+	return normalized
+}
+
+// normalizeGlobPaths drops unsafe relative paths and malformed globs, using forward slashes.
+func normalizeGlobPaths(values []string, key string, warn func(string)) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range normalizeRelativePaths(values, key, warn) {
+		pattern := filepath.ToSlash(value)
+		if _, err := path.Match(pattern, ""); err != nil {
+			emitWarning(warn, "invalid "+key+" entry "+value+"; skipping")
+			continue
+		}
+		normalized = append(normalized, pattern)
+	}
+	return normalized
+}
+
+// cloneStringSliceMap deep-copies a map of string slices.
+func cloneStringSliceMap(values map[string][]string) map[string][]string {
+	clone := make(map[string][]string, len(values))
+	for key, value := range values {
+		copied := make([]string, len(value))
+		copy(copied, value)
+		clone[key] = copied
+	}
+	return clone
+}
+
 // emitWarning forwards warnings to the provided sink.
 func emitWarning(warn func(string), message string) {
 	if warn == nil {
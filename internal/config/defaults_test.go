@@ -2,6 +2,7 @@
 package config
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -24,6 +25,9 @@ func TestDefaultsDocumentedValues(t *testing.T) {
 	if got, want := cfg.Retries.MaxAttempts, defaultRetriesMaxAttempts; got != want {
 		t.Fatalf("retries.max_attempts = %d, want %d", got, want)
 	}
+	if got, want := cfg.Retries.PlanningValidationAttempts, defaultPlanningValidationAttempts; got != want {
+		t.Fatalf("retries.planning_validation_attempts = %d, want %d", got, want)
+	}
 	if got, want := cfg.Workers.CLI.Default, defaultWorkerCLI; got != want {
 		t.Fatalf("workers.cli.default = %q, want %q", got, want)
 	}
@@ -84,12 +88,15 @@ func TestApplyDefaultsInvalidValues(t *testing.T) {
 		},
 		Timeouts: TimeoutsConfig{
 			WorkerSeconds: 0,
+			StallSeconds:  -30,
 		},
 		Retries: RetriesConfig{
-			MaxAttempts: -1,
+			MaxAttempts:                -1,
+			PlanningValidationAttempts: -3,
 		},
 		Branches: BranchConfig{
-			Base: "",
+			Base:          "",
+			MergeStrategy: "octopus",
 		},
 	}
 
@@ -139,9 +146,18 @@ func TestApplyDefaultsInvalidValues(t *testing.T) {
 	if normalized.Timeouts.WorkerSeconds != defaultWorkerTimeoutSeconds {
 		t.Fatal("timeouts.worker_seconds should fall back to default")
 	}
+	if normalized.Timeouts.StallSeconds != 0 {
+		t.Fatal("timeouts.stall_seconds should be disabled when negative")
+	}
 	if normalized.Retries.MaxAttempts != defaultRetriesMaxAttempts {
 		t.Fatal("retries.max_attempts should fall back to default")
 	}
+	if normalized.Retries.PlanningValidationAttempts != defaultPlanningValidationAttempts {
+		t.Fatal("retries.planning_validation_attempts should fall back to default")
+	}
+	if normalized.Branches.MergeStrategy != defaultMergeStrategy {
+		t.Fatal("branches.merge_strategy should fall back to default")
+	}
 	if len(warnings) == 0 {
 		t.Fatal("expected warnings for invalid values")
 	}
@@ -163,6 +179,9 @@ func TestApplyDefaultsInvalidValues(t *testing.T) {
 	if !warningsContain(warnings, "branches.base") {
 		t.Fatal("expected warning for branches.base")
 	}
+	if !warningsContain(warnings, "branches.merge_strategy") {
+		t.Fatal("expected warning for branches.merge_strategy")
+	}
 }
 
 // configsEqual compares configs by value without relying on reflect.DeepEqual.
@@ -170,10 +189,13 @@ func configsEqual(left Config, right Config) bool {
 	if left.Concurrency.Global != right.Concurrency.Global ||
 		left.Concurrency.DefaultRole != right.Concurrency.DefaultRole ||
 		left.Timeouts.WorkerSeconds != right.Timeouts.WorkerSeconds ||
-		left.Retries.MaxAttempts != right.Retries.MaxAttempts {
+		left.Timeouts.StallSeconds != right.Timeouts.StallSeconds ||
+		left.Retries.MaxAttempts != right.Retries.MaxAttempts ||
+		left.Retries.PlanningValidationAttempts != right.Retries.PlanningValidationAttempts {
 		return false
 	}
-	if left.Branches.Base != right.Branches.Base {
+	if left.Branches.Base != right.Branches.Base || left.Branches.MergeStrategy != right.Branches.MergeStrategy ||
+		left.Branches.Remote != right.Branches.Remote || left.Branches.RebaseIdle != right.Branches.RebaseIdle {
 		return false
 	}
 
@@ -213,6 +235,158 @@ func configsEqual(left Config, right Config) bool {
 			return false
 		}
 	}
+	if !intMapsEqual(left.Timeouts.Roles, right.Timeouts.Roles) ||
+		!intMapsEqual(left.Timeouts.Stages, right.Timeouts.Stages) ||
+		!intMapsEqual(left.Timeouts.PlanningSteps, right.Timeouts.PlanningSteps) {
+		return false
+	}
+
+	// Compare rate-limit settings
+	if left.RateLimits.CooldownSeconds != right.RateLimits.CooldownSeconds ||
+		left.RateLimits.MaxDeferrals != right.RateLimits.MaxDeferrals {
+		return false
+	}
+	if len(left.RateLimits.Patterns) != len(right.RateLimits.Patterns) {
+		return false
+	}
+	for cli, patterns := range left.RateLimits.Patterns {
+		other, ok := right.RateLimits.Patterns[cli]
+		if !ok || !stringSlicesEqual(patterns, other) {
+			return false
+		}
+	}
+
+	// Compare sandbox settings
+	if !sandboxPoliciesEqual(left.Sandbox.Default, right.Sandbox.Default) {
+		return false
+	}
+	if len(left.Sandbox.Roles) != len(right.Sandbox.Roles) {
+		return false
+	}
+	for role, policy := range left.Sandbox.Roles {
+		other, ok := right.Sandbox.Roles[role]
+		if !ok || !sandboxPoliciesEqual(policy, other) {
+			return false
+		}
+	}
+
+	// Compare env settings
+	if !stringMapsEqual(left.Env.Default, right.Env.Default) ||
+		!nestedStringMapsEqual(left.Env.Roles, right.Env.Roles) ||
+		!nestedStringMapsEqual(left.Env.Stages, right.Env.Stages) {
+		return false
+	}
+
+	if left.DependencyContext != right.DependencyContext {
+		return false
+	}
+
+	// Compare prompt budgets
+	if left.PromptBudget.Default != right.PromptBudget.Default ||
+		!stringSlicesEqual(left.PromptBudget.DropOrder, right.PromptBudget.DropOrder) {
+		return false
+	}
+	if len(left.PromptBudget.CLIs) != len(right.PromptBudget.CLIs) {
+		return false
+	}
+	for cli, budget := range left.PromptBudget.CLIs {
+		other, ok := right.PromptBudget.CLIs[cli]
+		if !ok || budget != other {
+			return false
+		}
+	}
+
+	// Compare merge verification
+	if !stringSlicesEqual(left.Verify.Command, right.Verify.Command) ||
+		left.Verify.TimeoutSeconds != right.Verify.TimeoutSeconds ||
+		left.Verify.BatchSize != right.Verify.BatchSize {
+		return false
+	}
+
+	if left.Commits != right.Commits {
+		return false
+	}
+	if left.Worktrees != right.Worktrees {
+		return false
+	}
+	if left.Drift.Mode != right.Drift.Mode || left.Drift.NormalizeMarkdown != right.Drift.NormalizeMarkdown ||
+		!stringSlicesEqual(left.Drift.Include, right.Drift.Include) ||
+		!stringSlicesEqual(left.Drift.Exclude, right.Drift.Exclude) {
+		return false
+	}
+	if left.Git.Default != right.Git.Default || left.Git.Merge != right.Git.Merge ||
+		left.Git.Signing != right.Git.Signing || len(left.Git.Roles) != len(right.Git.Roles) {
+		return false
+	}
+	for role, identity := range left.Git.Roles {
+		if other, ok := right.Git.Roles[role]; !ok || identity != other {
+			return false
+		}
+	}
+
+	// Compare resource limits
+	if left.Resources.Default != right.Resources.Default {
+		return false
+	}
+	if len(left.Resources.Roles) != len(right.Resources.Roles) {
+		return false
+	}
+	for role, limits := range left.Resources.Roles {
+		other, ok := right.Resources.Roles[role]
+		if !ok || limits != other {
+			return false
+		}
+	}
+	return true
+}
+
+// sandboxPoliciesEqual compares sandbox policies field by field.
+func sandboxPoliciesEqual(left SandboxPolicy, right SandboxPolicy) bool {
+	return left.Mode == right.Mode &&
+		left.DisableNetwork == right.DisableNetwork &&
+		stringSlicesEqual(left.WritablePaths, right.WritablePaths) &&
+		stringSlicesEqual(left.HomePaths, right.HomePaths)
+}
+
+// intMapsEqual compares int maps by key and value.
+func intMapsEqual(left map[string]int, right map[string]int) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for key, value := range left {
+		other, ok := right[key]
+		if !ok || value != other {
+			return false
+		}
+	}
+	return true
+}
+
+// stringMapsEqual compares string maps by key and value.
+func stringMapsEqual(left map[string]string, right map[string]string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for key, value := range left {
+		other, ok := right[key]
+		if !ok || value != other {
+			return false
+		}
+	}
+	return true
+}
+
+// nestedStringMapsEqual compares maps of string maps.
+func nestedStringMapsEqual(left map[string]map[string]string, right map[string]map[string]string) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for key, value := range left {
+		other, ok := right[key]
+		if !ok || !stringMapsEqual(value, other) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -353,3 +527,356 @@ func TestApplyDefaultsWithCLI(t *testing.T) {
 		t.Fatalf("workers.cli.roles.architect = %q, want %q", cli, "gemini")
 	}
 }
+
+// TestApplyDefaultsRateLimits verifies rate-limit patterns merge with defaults and drop invalid expressions.
+func TestApplyDefaultsRateLimits(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	cfg := ApplyDefaults(Config{
+		RateLimits: RateLimitConfig{
+			CooldownSeconds: -5,
+			MaxDeferrals:    -1,
+			Patterns: map[string][]string{
+				CLIClaude: {"(?i)slow down", "([unclosed"},
+			},
+		},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+
+	if got, want := cfg.RateLimits.CooldownSeconds, defaultRateLimitCooldown; got != want {
+		t.Fatalf("rate_limits.cooldown_seconds = %d, want %d", got, want)
+	}
+	if !warningsContain(warnings, "rate_limits.cooldown_seconds") {
+		t.Fatalf("expected cooldown warning, got %v", warnings)
+	}
+	if got, want := cfg.RateLimits.MaxDeferrals, defaultRateLimitMaxDeferrals; got != want {
+		t.Fatalf("rate_limits.max_deferrals = %d, want %d", got, want)
+	}
+	if !warningsContain(warnings, "rate_limits.patterns.claude") {
+		t.Fatalf("expected invalid pattern warning, got %v", warnings)
+	}
+	if got := cfg.RateLimits.PatternsForCLI(CLIClaude); !stringSlicesEqual(got, []string{"(?i)slow down"}) {
+		t.Fatalf("claude patterns = %v, want override", got)
+	}
+	if got := cfg.RateLimits.PatternsForCLI(CLICodex); !stringSlicesEqual(got, defaultRateLimitPatterns[CLICodex]) {
+		t.Fatalf("codex patterns = %v, want defaults", got)
+	}
+	if got := cfg.RateLimits.PatternsForCLI(""); !stringSlicesEqual(got, defaultRateLimitPatterns[RateLimitPatternKeyDefault]) {
+		t.Fatalf("custom command patterns = %v, want default set", got)
+	}
+}
+
+// TestDefaultRateLimitPatternsMatchCLIErrors matches the errors each CLI prints but not agent
+// output that quotes rate-limit text.
+func TestDefaultRateLimitPatternsMatchCLIErrors(t *testing.T) {
+	t.Parallel()
+
+	matches := func(cli string, line string) bool {
+		for _, pattern := range defaultRateLimitPatterns[cli] {
+			if regexp.MustCompile(pattern).MatchString(line) {
+				return true
+			}
+		}
+		return false
+	}
+	errorLines := map[string][]string{
+		CLICodex: {
+			"ERROR: You've hit your usage limit. Try again later.",
+			"■ You've hit your usage limit.",
+			"stream error: exceeded retry limit, last status: 429 Too Many Requests",
+		},
+		CLIClaude: {
+			`API Error: 429 {"type":"error","error":{"type":"rate_limit_error"}}`,
+			"Claude AI usage limit reached|1767322800",
+		},
+		CLIGemini: {
+			"[API Error: got status: RESOURCE_EXHAUSTED. Quota exceeded]",
+		},
+		RateLimitPatternKeyDefault: {
+			"Error: rate limit exceeded, retry in 60s",
+		},
+	}
+	for cli, lines := range errorLines {
+		for _, line := range lines {
+			if !matches(cli, line) {
+				t.Errorf("%s patterns miss %q", cli, line)
+			}
+		}
+	}
+	quoted := []string{
+		"Handled HTTP 429 Too Many Requests in the client retry loop.",
+		"- Map RESOURCE_EXHAUSTED and quota exceeded errors to retries",
+		"The test asserts the message `rate limit reached` is logged.",
+	}
+	for cli := range defaultRateLimitPatterns {
+		for _, line := range quoted {
+			if matches(cli, line) {
+				t.Errorf("%s patterns match quoted output %q", cli, line)
+			}
+		}
+	}
+}
+
+// TestApplyDefaultsResourceLimits verifies negative resource limits reset to unlimited per role.
+func TestApplyDefaultsResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	cfg := ApplyDefaults(Config{
+		Resources: ResourcesConfig{
+			Default: ResourceLimits{MemoryMB: 2048, CPUs: -1},
+			Roles: map[string]ResourceLimits{
+				"tester": {MemoryMB: 4096, MaxProcesses: -3, MaxFileSizeMB: 512},
+			},
+		},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+
+	if got, want := cfg.Resources.LimitsForRole("worker"), (ResourceLimits{MemoryMB: 2048}); got != want {
+		t.Fatalf("worker limits = %+v, want %+v", got, want)
+	}
+	if got, want := cfg.Resources.LimitsForRole("tester"), (ResourceLimits{MemoryMB: 4096, MaxFileSizeMB: 512}); got != want {
+		t.Fatalf("tester limits = %+v, want %+v", got, want)
+	}
+	if !warningsContain(warnings, "resources.default.cpus") || !warningsContain(warnings, "resources.roles.tester.max_processes") {
+		t.Fatalf("expected resource warnings, got %v", warnings)
+	}
+	if (ResourceLimits{}).Enabled() {
+		t.Fatal("zero limits should be disabled")
+	}
+}
+
+// TestApplyDefaultsDrift falls back to replan for unknown modes and drops unsafe globs.
+func TestApplyDefaultsDrift(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	cfg := ApplyDefaults(Config{
+		Drift: DriftConfig{
+			Mode:    "ignore",
+			Include: []string{"_governator/docs/adr/*.md", " "},
+			Exclude: []string{"../outside.md", "_governator/docs/[draft"},
+		},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+	if cfg.Drift.Mode != DriftModeReplan {
+		t.Fatalf("drift.mode = %q, want %q", cfg.Drift.Mode, DriftModeReplan)
+	}
+	if strings.Join(cfg.Drift.Include, ",") != "_governator/docs/adr/*.md" {
+		t.Fatalf("drift.include = %v", cfg.Drift.Include)
+	}
+	if len(cfg.Drift.Exclude) != 0 {
+		t.Fatalf("drift.exclude = %v, want empty", cfg.Drift.Exclude)
+	}
+	for _, key := range []string{"drift.mode", "drift.exclude"} {
+		if !warningsContain(warnings, key) {
+			t.Fatalf("expected %s warning, got %v", key, warnings)
+		}
+	}
+}
+
+// TestApplyDefaultsVerify keeps a usable verify command and disables an empty program.
+func TestApplyDefaultsVerify(t *testing.T) {
+	t.Parallel()
+
+	cfg := ApplyDefaults(Config{
+		Verify: VerifyConfig{Command: []string{"go", "test", "./..."}, TimeoutSeconds: -5, BatchSize: 4},
+	}, nil)
+	if strings.Join(cfg.Verify.Command, " ") != "go test ./..." {
+		t.Fatalf("verify.command = %v", cfg.Verify.Command)
+	}
+	if cfg.Verify.TimeoutSeconds != defaultVerifyTimeoutSeconds {
+		t.Fatalf("verify.timeout_seconds = %d, want %d", cfg.Verify.TimeoutSeconds, defaultVerifyTimeoutSeconds)
+	}
+	if cfg.Verify.BatchSize != 4 {
+		t.Fatalf("verify.batch_size = %d, want 4", cfg.Verify.BatchSize)
+	}
+
+	var warnings []string
+	cfg = ApplyDefaults(Config{
+		Verify: VerifyConfig{Command: []string{" ", "test"}},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+	if len(cfg.Verify.Command) != 0 {
+		t.Fatalf("verify.command = %v, want disabled", cfg.Verify.Command)
+	}
+	if cfg.Verify.BatchSize != defaultVerifyBatchSize {
+		t.Fatalf("verify.batch_size = %d, want %d", cfg.Verify.BatchSize, defaultVerifyBatchSize)
+	}
+	if !warningsContain(warnings, "verify.command") {
+		t.Fatalf("expected verify.command warning, got %v", warnings)
+	}
+}
+
+// TestApplyDefaultsCommits keeps valid templates and replaces broken ones with the defaults.
+func TestApplyDefaultsCommits(t *testing.T) {
+	t.Parallel()
+
+	cfg := ApplyDefaults(Config{}, nil)
+	if cfg.Commits.StageTemplate != DefaultStageCommitTemplate || cfg.Commits.MergeTemplate != DefaultMergeCommitTemplate {
+		t.Fatalf("commits = %+v, want built-in templates", cfg.Commits)
+	}
+
+	custom := "feat({{.TaskID}}): {{.Title}}"
+	var warnings []string
+	cfg = ApplyDefaults(Config{
+		Commits: CommitsConfig{
+			StageTemplate:   custom,
+			MergeTemplate:   "{{.Ticket}}",
+			DisableTrailers: true,
+		},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+	if cfg.Commits.StageTemplate != custom {
+		t.Fatalf("commits.stage_template = %q, want %q", cfg.Commits.StageTemplate, custom)
+	}
+	if cfg.Commits.MergeTemplate != DefaultMergeCommitTemplate {
+		t.Fatalf("commits.merge_template = %q, want default", cfg.Commits.MergeTemplate)
+	}
+	if !cfg.Commits.DisableTrailers {
+		t.Fatal("commits.disable_trailers was reset")
+	}
+	if !warningsContain(warnings, "commits.merge_template") {
+		t.Fatalf("expected commits.merge_template warning, got %v", warnings)
+	}
+}
+
+// TestApplyDefaultsGit fills the default identity, layers role identities, and validates signing.
+func TestApplyDefaultsGit(t *testing.T) {
+	t.Parallel()
+
+	cfg := ApplyDefaults(Config{
+		Git: GitConfig{
+			Default: GitIdentity{Email: "bots@example.com"},
+			Roles:   map[string]GitIdentity{"backend": {Name: "Backend Agent"}},
+			Signing: GitSigning{Format: "GPG", Key: "ABC123"},
+		},
+	}, nil)
+	if cfg.Git.Default != (GitIdentity{Name: defaultGitName, Email: "bots@example.com"}) {
+		t.Fatalf("git.default = %+v", cfg.Git.Default)
+	}
+	if got := cfg.Git.IdentityForRole("backend"); got != (GitIdentity{Name: "Backend Agent", Email: "bots@example.com"}) {
+		t.Fatalf("backend identity = %+v", got)
+	}
+	if got := cfg.Git.IdentityForRole("qa"); got != cfg.Git.Default {
+		t.Fatalf("qa identity = %+v, want default", got)
+	}
+	if cfg.Git.Signing != (GitSigning{Format: GitSigningOpenPGP, Key: "ABC123"}) {
+		t.Fatalf("git.signing = %+v", cfg.Git.Signing)
+	}
+
+	for _, signing := range []GitSigning{{Format: "x509", Key: "k"}, {Format: GitSigningSSH}} {
+		var warnings []string
+		cfg = ApplyDefaults(Config{Git: GitConfig{Signing: signing}}, func(message string) {
+			warnings = append(warnings, message)
+		})
+		if cfg.Git.Signing.Enabled() {
+			t.Fatalf("signing %+v stayed enabled", signing)
+		}
+		if !warningsContain(warnings, "git.signing") {
+			t.Fatalf("expected git.signing warning for %+v, got %v", signing, warnings)
+		}
+	}
+
+	var warnings []string
+	cfg = ApplyDefaults(Config{
+		Branches: BranchConfig{MergeStrategy: MergeStrategyRebase},
+		Git:      GitConfig{Signing: GitSigning{Format: GitSigningSSH, Key: "~/.ssh/id_ed25519.pub"}},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+	if !cfg.Git.Signing.Enabled() || !warningsContain(warnings, "branches.merge_strategy rebase") {
+		t.Fatalf("expected rebase signing warning, signing=%+v warnings=%v", cfg.Git.Signing, warnings)
+	}
+}
+
+// TestApplyDefaultsPromptBudget verifies budget actions, per-CLI lookup, and drop order validation.
+func TestApplyDefaultsPromptBudget(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	cfg := ApplyDefaults(Config{
+		PromptBudget: PromptBudgetConfig{
+			Default: PromptBudget{WarnTokens: 50000, MaxTokens: -1, OnExceed: "explode"},
+			CLIs: map[string]PromptBudget{
+				"gemini": {MaxTokens: 20000, OnExceed: PromptBudgetDrop},
+				"cursor": {MaxTokens: 100},
+			},
+			DropOrder: []string{"reasoning", "task", "context", "reasoning"},
+		},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+
+	if got, want := cfg.PromptBudget.BudgetForCLI("codex"), (PromptBudget{WarnTokens: 50000, OnExceed: PromptBudgetWarn}); got != want {
+		t.Fatalf("codex budget = %+v, want %+v", got, want)
+	}
+	if got, want := cfg.PromptBudget.BudgetForCLI("gemini"), (PromptBudget{MaxTokens: 20000, OnExceed: PromptBudgetDrop}); got != want {
+		t.Fatalf("gemini budget = %+v, want %+v", got, want)
+	}
+	if _, ok := cfg.PromptBudget.CLIs["cursor"]; ok {
+		t.Fatal("unknown CLI budget should be dropped")
+	}
+	if want := []string{"reasoning", "context"}; !stringSlicesEqual(cfg.PromptBudget.DropOrder, want) {
+		t.Fatalf("drop order = %v, want %v", cfg.PromptBudget.DropOrder, want)
+	}
+	for _, key := range []string{"prompt_budget.default.max_tokens", "prompt_budget.default.on_exceed", "prompt_budget.clis.cursor", "prompt_budget.drop_order"} {
+		if !warningsContain(warnings, key) {
+			t.Fatalf("expected warning for %s, got %v", key, warnings)
+		}
+	}
+
+	defaults := ApplyDefaults(Config{}, nil)
+	if want := []string{"context", "custom_role", "custom_global", "reasoning"}; !stringSlicesEqual(defaults.PromptBudget.DropOrder, want) {
+		t.Fatalf("default drop order = %v, want %v", defaults.PromptBudget.DropOrder, want)
+	}
+}
+
+// TestApplyDefaultsTimeoutOverrides verifies timeout overrides resolve by step, stage, then role.
+func TestApplyDefaultsTimeoutOverrides(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	cfg := ApplyDefaults(Config{
+		Timeouts: TimeoutsConfig{
+			WorkerSeconds: 900,
+			Roles:         map[string]int{"architect": 1800, "tester": -1},
+			Stages:        map[string]int{"review": 300, "deploy": 60},
+			PlanningSteps: map[string]int{"architecture-baseline": 2400},
+		},
+	}, func(message string) {
+		warnings = append(warnings, message)
+	})
+
+	tests := []struct {
+		name  string
+		role  string
+		stage string
+		step  string
+		want  int
+	}{
+		{name: "default", role: "worker", stage: "work", want: 900},
+		{name: "role", role: "architect", stage: "work", want: 1800},
+		{name: "stage beats role", role: "architect", stage: "review", want: 300},
+		{name: "planning step beats stage", role: "architect", stage: StageKeyPlanning, step: "architecture-baseline", want: 2400},
+		{name: "other planning step", role: "planner", stage: StageKeyPlanning, step: "gap-analysis", want: 900},
+		{name: "invalid role dropped", role: "tester", stage: "test", want: 900},
+	}
+	for _, test := range tests {
+		if got := cfg.Timeouts.SecondsFor(test.role, test.stage, test.step); got != test.want {
+			t.Fatalf("%s: SecondsFor(%q, %q, %q) = %d, want %d", test.name, test.role, test.stage, test.step, got, test.want)
+		}
+	}
+	if _, ok := cfg.Timeouts.Stages["deploy"]; ok {
+		t.Fatal("unknown timeouts.stages.deploy should be removed")
+	}
+	if !warningsContain(warnings, "timeouts.roles.tester") || !warningsContain(warnings, "timeouts.stages.deploy") {
+		t.Fatalf("expected timeout override warnings, got %v", warnings)
+	}
+}
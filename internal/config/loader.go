@@ -9,6 +9,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -165,20 +166,225 @@ func decodeConfig(raw map[string]any) Config {
 
 	timeouts := toConfigMap(raw["timeouts"])
 	cfg.Timeouts.WorkerSeconds = parseInt(timeouts["worker_seconds"])
+	cfg.Timeouts.StallSeconds = parseInt(timeouts["stall_seconds"])
+	cfg.Timeouts.Roles = parseIntMap(timeouts["roles"])
+	cfg.Timeouts.Stages = parseIntMap(timeouts["stages"])
+	cfg.Timeouts.PlanningSteps = parseIntMap(timeouts["planning_steps"])
 
 	retries := toConfigMap(raw["retries"])
 	cfg.Retries.MaxAttempts = parseInt(retries["max_attempts"])
+	cfg.Retries.PlanningValidationAttempts = parseInt(retries["planning_validation_attempts"])
 
 	branches := toConfigMap(raw["branches"])
 	cfg.Branches.Base = parseString(branches["base"])
+	cfg.Branches.MergeStrategy = parseString(branches["merge_strategy"])
+	cfg.Branches.Remote = parseString(branches["remote"])
+	cfg.Branches.RebaseIdle = parseBool(branches["rebase_idle"])
 
 	reasoningEffort := toConfigMap(raw["reasoning_effort"])
 	cfg.ReasoningEffort.Default = parseString(reasoningEffort["default"])
 	cfg.ReasoningEffort.Roles = parseStringMap(reasoningEffort["roles"])
 
+	rateLimits := toConfigMap(raw["rate_limits"])
+	cfg.RateLimits.CooldownSeconds = parseInt(rateLimits["cooldown_seconds"])
+	cfg.RateLimits.MaxDeferrals = parseInt(rateLimits["max_deferrals"])
+	cfg.RateLimits.Patterns = parseStringSliceMap(rateLimits["patterns"])
+
+	sandbox := toConfigMap(raw["sandbox"])
+	sandboxDefault := toConfigMap(sandbox["default"])
+	cfg.Sandbox.Default = parseSandboxPolicy(sandboxDefault)
+	cfg.Sandbox.Roles = parseSandboxRoles(sandbox["roles"], sandboxDefault)
+
+	resources := toConfigMap(raw["resources"])
+	resourcesDefault := toConfigMap(resources["default"])
+	cfg.Resources.Default = parseResourceLimits(resourcesDefault)
+	cfg.Resources.Roles = parseResourceRoles(resources["roles"], resourcesDefault)
+
+	env := toConfigMap(raw["env"])
+	cfg.Env.Default = parseEnvVars(env["default"])
+	cfg.Env.Roles = parseEnvVarsMap(env["roles"])
+	cfg.Env.Stages = parseEnvVarsMap(env["stages"])
+
+	promptBudget := toConfigMap(raw["prompt_budget"])
+	promptBudgetDefault := toConfigMap(promptBudget["default"])
+	cfg.PromptBudget.Default = parsePromptBudget(promptBudgetDefault)
+	cfg.PromptBudget.CLIs = parsePromptBudgetCLIs(promptBudget["clis"], promptBudgetDefault)
+	cfg.PromptBudget.DropOrder = parseStringSlice(promptBudget["drop_order"])
+
+	dependencyContext := toConfigMap(raw["dependency_context"])
+	cfg.DependencyContext.DiffMaxBytes = parseInt(dependencyContext["diff_max_bytes"])
+
+	verify := toConfigMap(raw["verify"])
+	cfg.Verify.Command = parseStringSlice(verify["command"])
+	cfg.Verify.TimeoutSeconds = parseInt(verify["timeout_seconds"])
+	cfg.Verify.BatchSize = parseInt(verify["batch_size"])
+
+	commits := toConfigMap(raw["commits"])
+	cfg.Commits.StageTemplate = parseString(commits["stage_template"])
+	cfg.Commits.MergeTemplate = parseString(commits["merge_template"])
+	cfg.Commits.DisableTrailers = parseBool(commits["disable_trailers"])
+
+	worktrees := toConfigMap(raw["worktrees"])
+	cfg.Worktrees.Root = parseString(worktrees["root"])
+	cfg.Worktrees.MaxCount = parseInt(worktrees["max_count"])
+	cfg.Worktrees.MaxTotalMB = parseInt(worktrees["max_total_mb"])
+
+	drift := toConfigMap(raw["drift"])
+	cfg.Drift.Mode = parseString(drift["mode"])
+	cfg.Drift.Include = parseStringSlice(drift["include"])
+	cfg.Drift.Exclude = parseStringSlice(drift["exclude"])
+	cfg.Drift.NormalizeMarkdown = parseBool(drift["normalize_markdown"])
+
+	git := toConfigMap(raw["git"])
+	cfg.Git.Default = parseGitIdentity(git["default"])
+	cfg.Git.Roles = parseGitIdentities(git["roles"])
+	cfg.Git.Merge = parseGitIdentity(git["merge"])
+	signing := toConfigMap(git["signing"])
+	cfg.Git.Signing.Format = parseString(signing["format"])
+	cfg.Git.Signing.Key = parseString(signing["key"])
+
 	return cfg
 }
 
+// parseGitIdentity reads a name and email object.
+func parseGitIdentity(value any) GitIdentity {
+	raw := toConfigMap(value)
+	return GitIdentity{
+		Name:  parseString(raw["name"]),
+		Email: parseString(raw["email"]),
+	}
+}
+
+// parseGitIdentities reads a map of role names to identities.
+func parseGitIdentities(value any) map[string]GitIdentity {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	identities := make(map[string]GitIdentity, len(raw))
+	for role, identity := range raw {
+		identities[role] = parseGitIdentity(identity)
+	}
+	return identities
+}
+
+// parseEnvVars reads an environment variable object, accepting string, number, and boolean values.
+func parseEnvVars(value any) map[string]string {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(raw))
+	for key, item := range raw {
+		switch typed := item.(type) {
+		case string:
+			result[key] = typed
+		case json.Number:
+			result[key] = typed.String()
+		case float64:
+			result[key] = strconv.FormatFloat(typed, 'f', -1, 64)
+		case bool:
+			result[key] = strconv.FormatBool(typed)
+		}
+	}
+	return result
+}
+
+// parseEnvVarsMap reads environment variable objects keyed by role or stage.
+func parseEnvVarsMap(value any) map[string]map[string]string {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]map[string]string, len(raw))
+	for key, item := range raw {
+		if vars := parseEnvVars(item); vars != nil {
+			result[key] = vars
+		}
+	}
+	return result
+}
+
+// parseResourceLimits reads a single resource limits object.
+func parseResourceLimits(raw map[string]any) ResourceLimits {
+	return ResourceLimits{
+		MemoryMB:      parseInt(raw["memory_mb"]),
+		CPUs:          parseInt(raw["cpus"]),
+		MaxProcesses:  parseInt(raw["max_processes"]),
+		MaxFileSizeMB: parseInt(raw["max_file_size_mb"]),
+	}
+}
+
+// parseResourceRoles reads per-role resource limits, layering each over the default limits.
+func parseResourceRoles(value any, defaults map[string]any) map[string]ResourceLimits {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]ResourceLimits, len(raw))
+	for role, item := range raw {
+		override := toConfigMap(item)
+		if override == nil {
+			continue
+		}
+		result[role] = parseResourceLimits(mergeConfigMaps(defaults, override))
+	}
+	return result
+}
+
+// parsePromptBudget reads a single prompt budget object.
+func parsePromptBudget(raw map[string]any) PromptBudget {
+	return PromptBudget{
+		WarnTokens: parseInt(raw["warn_tokens"]),
+		MaxTokens:  parseInt(raw["max_tokens"]),
+		OnExceed:   parseString(raw["on_exceed"]),
+	}
+}
+
+// parsePromptBudgetCLIs reads per-CLI prompt budgets, layering each over the default budget.
+func parsePromptBudgetCLIs(value any, defaults map[string]any) map[string]PromptBudget {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]PromptBudget, len(raw))
+	for cli, item := range raw {
+		override := toConfigMap(item)
+		if override == nil {
+			continue
+		}
+		result[cli] = parsePromptBudget(mergeConfigMaps(defaults, override))
+	}
+	return result
+}
+
+// parseSandboxPolicy reads a single sandbox policy object.
+func parseSandboxPolicy(raw map[string]any) SandboxPolicy {
+	return SandboxPolicy{
+		Mode:           parseString(raw["mode"]),
+		DisableNetwork: parseBool(raw["disable_network"]),
+		WritablePaths:  parseStringSlice(raw["writable_paths"]),
+		HomePaths:      parseStringSlice(raw["home_paths"]),
+	}
+}
+
+// parseSandboxRoles reads per-role sandbox policies, layering each over the default policy.
+func parseSandboxRoles(value any, defaults map[string]any) map[string]SandboxPolicy {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]SandboxPolicy, len(raw))
+	for role, item := range raw {
+		override := toConfigMap(item)
+		if override == nil {
+			continue
+		}
+		result[role] = parseSandboxPolicy(mergeConfigMaps(defaults, override))
+	}
+	return result
+}
+
 // toConfigMap asserts a value as map[string]any.
 func toConfigMap(value any) map[string]any {
 	if value == nil {
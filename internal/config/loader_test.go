@@ -82,6 +82,293 @@ func TestLoadConfigInvalidJSON(t *testing.T) {
 	}
 }
 
+// TestLoadConfigSandboxRoles verifies role sandbox policies layer over the default policy.
+func TestLoadConfigSandboxRoles(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "sandbox": {
+    "default": {
+      "mode": "auto",
+      "disable_network": true,
+      "home_paths": [".gitconfig"]
+    },
+    "roles": {
+      "researcher": {
+        "disable_network": false
+      },
+      "architect": {
+        "mode": "chroot",
+        "writable_paths": ["_governator/docs", "../escape"]
+      }
+    }
+  }
+}`)
+
+	var warnings []string
+	cfg, err := Load(repoRoot, nil, func(message string) {
+		warnings = append(warnings, message)
+	})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	researcher := cfg.Sandbox.PolicyForRole("researcher")
+	if researcher.Mode != SandboxModeAuto || researcher.DisableNetwork {
+		t.Fatalf("researcher sandbox = %+v, want auto mode with network", researcher)
+	}
+	if len(researcher.HomePaths) != 1 || researcher.HomePaths[0] != ".gitconfig" {
+		t.Fatalf("researcher home paths = %v, want inherited [.gitconfig]", researcher.HomePaths)
+	}
+	architect := cfg.Sandbox.PolicyForRole("architect")
+	if architect.Mode != SandboxModeAuto {
+		t.Fatalf("architect mode = %q, want fallback to %q", architect.Mode, SandboxModeAuto)
+	}
+	if len(architect.WritablePaths) != 1 || architect.WritablePaths[0] != "_governator/docs" {
+		t.Fatalf("architect writable paths = %v, want [_governator/docs]", architect.WritablePaths)
+	}
+	if !architect.DisableNetwork {
+		t.Fatal("architect should inherit disable_network from the default policy")
+	}
+	if worker := cfg.Sandbox.PolicyForRole("worker"); !worker.DisableNetwork || worker.Mode != SandboxModeAuto {
+		t.Fatalf("worker sandbox = %+v, want default policy", worker)
+	}
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "sandbox.roles.architect.mode") || !strings.Contains(joined, "../escape") {
+		t.Fatalf("expected sandbox warnings, got %v", warnings)
+	}
+}
+
+func TestLoadConfigEnv(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "env": {
+    "default": {"LOG_LEVEL": "info", "GOVERNATOR_ROLE": "spoofed"},
+    "roles": {
+      "tester": {"LOG_LEVEL": "debug", "DB_PORT": 5432}
+    },
+    "stages": {
+      "test": {"CI": true},
+      "deploy": {"TARGET": "prod"}
+    }
+  }
+}`)
+
+	var warnings []string
+	cfg, err := Load(repoRoot, nil, func(message string) {
+		warnings = append(warnings, message)
+	})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	vars := cfg.Env.VarsFor("tester", "test")
+	want := map[string]string{"LOG_LEVEL": "debug", "DB_PORT": "5432", "CI": "true"}
+	if len(vars) != len(want) {
+		t.Fatalf("tester test vars = %v, want %v", vars, want)
+	}
+	for key, value := range want {
+		if vars[key] != value {
+			t.Fatalf("tester test vars = %v, want %v", vars, want)
+		}
+	}
+	if vars := cfg.Env.VarsFor("worker", "work"); len(vars) != 1 || vars["LOG_LEVEL"] != "info" {
+		t.Fatalf("worker work vars = %v, want only LOG_LEVEL=info", vars)
+	}
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "env.default.GOVERNATOR_ROLE") || !strings.Contains(joined, "env.stages.deploy") {
+		t.Fatalf("expected env warnings, got %v", warnings)
+	}
+}
+
+// TestLoadConfigPromptBudget layers per-CLI prompt budgets over the default budget.
+func TestLoadConfigPromptBudget(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "prompt_budget": {
+    "default": {"warn_tokens": 60000, "max_tokens": 120000, "on_exceed": "block"},
+    "clis": {
+      "gemini": {"max_tokens": 30000, "on_exceed": "drop"}
+    },
+    "drop_order": ["custom_global", "context"]
+  }
+}`)
+
+	cfg, err := Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got, want := cfg.PromptBudget.BudgetForCLI("gemini"), (PromptBudget{WarnTokens: 60000, MaxTokens: 30000, OnExceed: PromptBudgetDrop}); got != want {
+		t.Fatalf("gemini budget = %+v, want %+v", got, want)
+	}
+	if got, want := cfg.PromptBudget.BudgetForCLI(""), (PromptBudget{WarnTokens: 60000, MaxTokens: 120000, OnExceed: PromptBudgetBlock}); got != want {
+		t.Fatalf("default budget = %+v, want %+v", got, want)
+	}
+	if strings.Join(cfg.PromptBudget.DropOrder, ",") != "custom_global,context" {
+		t.Fatalf("drop order = %v", cfg.PromptBudget.DropOrder)
+	}
+}
+
+// TestLoadConfigMergeStrategy keeps the base branch when only other branch settings are overridden.
+func TestLoadConfigMergeStrategy(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "branches": {"merge_strategy": "rebase", "remote": " origin ", "rebase_idle": true}
+}`)
+
+	cfg, err := Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Branches.MergeStrategy != MergeStrategyRebase {
+		t.Fatalf("branches.merge_strategy = %q, want %q", cfg.Branches.MergeStrategy, MergeStrategyRebase)
+	}
+	if cfg.Branches.Base != defaultBranchBase {
+		t.Fatalf("branches.base = %q, want %q", cfg.Branches.Base, defaultBranchBase)
+	}
+	if cfg.Branches.Remote != "origin" {
+		t.Fatalf("branches.remote = %q, want origin", cfg.Branches.Remote)
+	}
+	if !cfg.Branches.RebaseIdle {
+		t.Fatal("branches.rebase_idle = false, want true")
+	}
+}
+
+// TestLoadConfigRetries reads the planning validation attempt limit alongside max_attempts.
+func TestLoadConfigRetries(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "retries": {"planning_validation_attempts": 1}
+}`)
+
+	cfg, err := Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Retries != (RetriesConfig{MaxAttempts: defaultRetriesMaxAttempts, PlanningValidationAttempts: 1}) {
+		t.Fatalf("retries = %+v", cfg.Retries)
+	}
+}
+
+// TestLoadConfigCommits reads commit templates and the trailer switch.
+func TestLoadConfigCommits(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "commits": {"merge_template": "feat: {{.Title}}", "disable_trailers": true}
+}`)
+
+	cfg, err := Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Commits.MergeTemplate != "feat: {{.Title}}" {
+		t.Fatalf("commits.merge_template = %q", cfg.Commits.MergeTemplate)
+	}
+	if cfg.Commits.StageTemplate != DefaultStageCommitTemplate {
+		t.Fatalf("commits.stage_template = %q, want default", cfg.Commits.StageTemplate)
+	}
+	if !cfg.Commits.DisableTrailers {
+		t.Fatal("commits.disable_trailers = false, want true")
+	}
+}
+
+// TestLoadConfigWorktrees reads the worktree root and quotas, treating negative quotas as unlimited.
+func TestLoadConfigWorktrees(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "worktrees": {"root": " /scratch/worktrees ", "max_count": 4, "max_total_mb": -1}
+}`)
+
+	var warnings []string
+	cfg, err := Load(repoRoot, nil, func(message string) {
+		warnings = append(warnings, message)
+	})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Worktrees != (WorktreesConfig{Root: "/scratch/worktrees", MaxCount: 4}) {
+		t.Fatalf("worktrees = %+v", cfg.Worktrees)
+	}
+	if !warningsContain(warnings, "worktrees.max_total_mb") {
+		t.Fatalf("expected worktrees.max_total_mb warning, got %v", warnings)
+	}
+}
+
+// TestLoadConfigDrift reads the drift mode, globs, and markdown normalization.
+func TestLoadConfigDrift(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "drift": {"mode": "notify", "include": ["_governator/docs/adr/"], "exclude": ["_governator/docs/drafts/*"], "normalize_markdown": true}
+}`)
+
+	cfg, err := Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Drift.Mode != DriftModeNotify || !cfg.Drift.NormalizeMarkdown {
+		t.Fatalf("drift = %+v", cfg.Drift)
+	}
+	if strings.Join(cfg.Drift.Include, ",") != "_governator/docs/adr" ||
+		strings.Join(cfg.Drift.Exclude, ",") != "_governator/docs/drafts/*" {
+		t.Fatalf("drift globs = %v / %v", cfg.Drift.Include, cfg.Drift.Exclude)
+	}
+}
+
+// TestLoadConfigGit reads identities and signing from the git section.
+func TestLoadConfigGit(t *testing.T) {
+	homeDir := t.TempDir()
+	repoRoot := filepath.Join(t.TempDir(), "repo")
+	t.Setenv("HOME", homeDir)
+
+	writeConfigFile(t, filepath.Join(repoRoot, repoConfigDirName, userConfigFileName), `{
+  "git": {
+    "roles": {"backend": {"name": "Backend Agent", "email": "backend@example.com"}},
+    "merge": {"name": "Release Operator", "email": "ops@example.com"},
+    "signing": {"format": "ssh", "key": "~/.ssh/id_ed25519.pub"}
+  }
+}`)
+
+	cfg, err := Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := cfg.Git.IdentityForRole("backend"); got != (GitIdentity{Name: "Backend Agent", Email: "backend@example.com"}) {
+		t.Fatalf("backend identity = %+v", got)
+	}
+	if cfg.Git.Default != Defaults().Git.Default {
+		t.Fatalf("git.default = %+v, want built-in identity", cfg.Git.Default)
+	}
+	if cfg.Git.Merge != (GitIdentity{Name: "Release Operator", Email: "ops@example.com"}) {
+		t.Fatalf("git.merge = %+v", cfg.Git.Merge)
+	}
+	if cfg.Git.Signing != (GitSigning{Format: GitSigningSSH, Key: "~/.ssh/id_ed25519.pub"}) {
+		t.Fatalf("git.signing = %+v", cfg.Git.Signing)
+	}
+}
+
 // writeConfigFile creates a config file with the provided contents.
 func writeConfigFile(t *testing.T, path string, contents string) {
 	t.Helper()
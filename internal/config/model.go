@@ -5,12 +5,23 @@ import "strings"
 
 // Config defines the full configuration surface for Governator v2.
 type Config struct {
-	Workers         WorkersConfig         `json:"workers"`
-	Concurrency     ConcurrencyConfig     `json:"concurrency"`
-	Timeouts        TimeoutsConfig        `json:"timeouts"`
-	Retries         RetriesConfig         `json:"retries"`
-	Branches        BranchConfig          `json:"branches"`
-	ReasoningEffort ReasoningEffortConfig `json:"reasoning_effort"`
+	Workers           WorkersConfig           `json:"workers"`
+	Concurrency       ConcurrencyConfig       `json:"concurrency"`
+	Timeouts          TimeoutsConfig          `json:"timeouts"`
+	Retries           RetriesConfig           `json:"retries"`
+	Branches          BranchConfig            `json:"branches"`
+	ReasoningEffort   ReasoningEffortConfig   `json:"reasoning_effort"`
+	RateLimits        RateLimitConfig         `json:"rate_limits"`
+	Sandbox           SandboxConfig           `json:"sandbox"`
+	Resources         ResourcesConfig         `json:"resources"`
+	Env               EnvConfig               `json:"env"`
+	PromptBudget      PromptBudgetConfig      `json:"prompt_budget"`
+	DependencyContext DependencyContextConfig `json:"dependency_context"`
+	Verify            VerifyConfig            `json:"verify"`
+	Commits           CommitsConfig           `json:"commits"`
+	Git               GitConfig               `json:"git"`
+	Worktrees         WorktreesConfig         `json:"worktrees"`
+	Drift             DriftConfig             `json:"drift"`
 }
 
 // WorkersConfig captures worker execution settings.
@@ -40,17 +51,36 @@ type ConcurrencyConfig struct {
 
 // TimeoutsConfig defines timeout settings in seconds.
 type TimeoutsConfig struct {
-	WorkerSeconds int `json:"worker_seconds"`
+	WorkerSeconds int            `json:"worker_seconds"`
+	StallSeconds  int            `json:"stall_seconds"`  // kill workers with no log or worktree activity for this long; 0 disables
+	Roles         map[string]int `json:"roles"`          // worker_seconds overrides keyed by role
+	Stages        map[string]int `json:"stages"`         // worker_seconds overrides keyed by StageKeys entry
+	PlanningSteps map[string]int `json:"planning_steps"` // worker_seconds overrides keyed by planning step name
 }
 
+// Stage keys for per-stage settings beyond the execution stages (work, test, review, resolve).
+const (
+	StageKeyPlanning = "planning"
+	StageKeyTriage   = "triage"
+)
+
+// StageKeys lists the keys accepted under timeouts.stages and env.stages.
+var StageKeys = []string{StageKeyPlanning, StageKeyTriage, "work", "test", "review", "resolve"}
+
 // RetriesConfig defines retry limits.
 type RetriesConfig struct {
-	MaxAttempts int `json:"max_attempts"`
+	MaxAttempts                int `json:"max_attempts"`
+	PlanningValidationAttempts int `json:"planning_validation_attempts"` // passes at a planning step, including repairs of failed validations
 }
 
 // BranchConfig describes how branches should be created for tasks.
 type BranchConfig struct {
-	Base string `json:"base"`
+	Base          string `json:"base"`
+	MergeStrategy string `json:"merge_strategy"`
+	// Remote names the git remote for fetching and pushing; empty keeps everything local.
+	Remote string `json:"remote"`
+	// RebaseIdle rebases idle task worktrees onto the base branch whenever it advances.
+	RebaseIdle bool `json:"rebase_idle"`
 }
 
 // ReasoningEffortConfig captures the default reasoning effort and role overrides.
@@ -59,8 +89,201 @@ type ReasoningEffortConfig struct {
 	Roles   map[string]string `json:"roles"`
 }
 
+// RateLimitConfig defines rate-limit detection patterns and the CLI cooldown window.
+type RateLimitConfig struct {
+	CooldownSeconds int                 `json:"cooldown_seconds"`
+	MaxDeferrals    int                 `json:"max_deferrals"` // rate-limit deferrals per task before it counts as a failure
+	Patterns        map[string][]string `json:"patterns"`      // per-CLI regular expressions
+}
+
+// SandboxConfig defines the worker sandbox policy and per-role overrides.
+type SandboxConfig struct {
+	Default SandboxPolicy            `json:"default"`
+	Roles   map[string]SandboxPolicy `json:"roles"` // per-role policies layered over the default
+}
+
+// SandboxPolicy describes how a worker process is isolated from the host.
+type SandboxPolicy struct {
+	Mode           string   `json:"mode"`            // "off", "auto", "bwrap", or "unshare"
+	DisableNetwork bool     `json:"disable_network"` // run the worker in an empty network namespace
+	WritablePaths  []string `json:"writable_paths"`  // repo-relative paths left writable (e.g. under _governator/)
+	HomePaths      []string `json:"home_paths"`      // $HOME-relative paths left visible to the worker
+}
+
+// ResourcesConfig defines worker resource limits and per-role overrides.
+type ResourcesConfig struct {
+	Default ResourceLimits            `json:"default"`
+	Roles   map[string]ResourceLimits `json:"roles"` // per-role limits layered over the default
+}
+
+// ResourceLimits caps what a worker process tree may consume; zero leaves a resource unlimited.
+type ResourceLimits struct {
+	MemoryMB      int `json:"memory_mb"`
+	CPUs          int `json:"cpus"`
+	MaxProcesses  int `json:"max_processes"`
+	MaxFileSizeMB int `json:"max_file_size_mb"`
+}
+
+// PromptBudgetConfig caps the estimated token size of worker prompts per worker CLI.
+type PromptBudgetConfig struct {
+	Default   PromptBudget            `json:"default"`
+	CLIs      map[string]PromptBudget `json:"clis"`       // per-CLI budgets layered over the default
+	DropOrder []string                `json:"drop_order"` // optional section kinds removed first to last when on_exceed is "drop"
+}
+
+// PromptBudget bounds one CLI's prompt size; zero token limits disable the check.
+type PromptBudget struct {
+	WarnTokens int    `json:"warn_tokens"` // warn when the estimate exceeds this
+	MaxTokens  int    `json:"max_tokens"`  // apply on_exceed when the estimate exceeds this
+	OnExceed   string `json:"on_exceed"`   // "warn", "drop", or "block"
+}
+
+// DependencyContextConfig controls the merged-dependency summary added to work prompts.
+type DependencyContextConfig struct {
+	DiffMaxBytes int `json:"diff_max_bytes"` // include each dependency's diff up to this many bytes; 0 omits diffs
+}
+
+// VerifyConfig describes the command that checks a merged result before the base branch moves.
+type VerifyConfig struct {
+	Command        []string `json:"command"`         // argv run in the merge worktree; empty disables verification
+	TimeoutSeconds int      `json:"timeout_seconds"` // kill the command after this many seconds
+	BatchSize      int      `json:"batch_size"`      // mergeable tasks stacked and verified together; 1 merges one at a time
+}
+
+// CommitsConfig shapes the messages Governator writes for stage and merge commits.
+type CommitsConfig struct {
+	StageTemplate   string `json:"stage_template"`   // text/template for worker stage commits
+	MergeTemplate   string `json:"merge_template"`   // text/template for squash and merge commits
+	DisableTrailers bool   `json:"disable_trailers"` // omit the Governator-* provenance trailers
+}
+
+// WorktreesConfig places task worktrees and caps how many the scheduler may create.
+type WorktreesConfig struct {
+	Root       string `json:"root"`         // absolute or repo-relative directory; empty keeps worktrees in local state
+	MaxCount   int    `json:"max_count"`    // task worktrees allowed at once; 0 means unlimited
+	MaxTotalMB int    `json:"max_total_mb"` // combined task worktree size in MiB; 0 means unlimited
+}
+
+// DriftConfig scopes planning drift detection and sets how the supervisor reacts to drift.
+type DriftConfig struct {
+	Mode              string   `json:"mode"`               // "replan" or "notify"
+	Include           []string `json:"include"`            // repo-relative globs under _governator/docs to track; empty tracks all
+	Exclude           []string `json:"exclude"`            // repo-relative globs under _governator/docs to ignore
+	NormalizeMarkdown bool     `json:"normalize_markdown"` // digest .md files ignoring whitespace, line ending, and reflow changes
+}
+
+// GitConfig sets the identities Governator commits under and how base branch commits are signed.
+type GitConfig struct {
+	Default GitIdentity            `json:"default"` // stage commits for roles without an override
+	Roles   map[string]GitIdentity `json:"roles"`   // per-role stage commit identities layered over default
+	Merge   GitIdentity            `json:"merge"`   // squash, merge, and revert commits on the base branch; empty uses git config
+	Signing GitSigning             `json:"signing"`
+}
+
+// GitIdentity is the author and committer name and email recorded on a commit.
+type GitIdentity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// GitSigning configures signing of the commits Governator makes on the base branch.
+type GitSigning struct {
+	Format string `json:"format"` // "ssh" or "openpgp"; empty disables signing
+	Key    string `json:"key"`    // user.signingkey: an ssh key path or an openpgp key id
+}
+
+// Commit signing formats, matching git's gpg.format values.
+const (
+	GitSigningSSH     = "ssh"
+	GitSigningOpenPGP = "openpgp"
+)
+
+// IdentityForRole returns the stage commit identity for a role. Role fields win over the
+// default identity field by field.
+func (cfg GitConfig) IdentityForRole(role string) GitIdentity {
+	identity := cfg.Default
+	if override, ok := cfg.Roles[role]; ok {
+		if override.Name != "" {
+			identity.Name = override.Name
+		}
+		if override.Email != "" {
+			identity.Email = override.Email
+		}
+	}
+	return identity
+}
+
+// Enabled reports whether base branch commits are signed.
+func (signing GitSigning) Enabled() bool {
+	return signing.Format != ""
+}
+
+// Built-in commit message templates.
+const (
+	DefaultStageCommitTemplate = "[{{.State}}] {{.Title}}{{if .Log}}\n\n{{.Log}}{{end}}"
+	DefaultMergeCommitTemplate = "governator: {{.TaskID}} - {{.Title}}{{if .Commits}}\n\n{{.Commits}}{{end}}"
+)
+
+// CommitTemplateData is the value commit templates render against. Fields that do not
+// apply to a commit are left empty.
+type CommitTemplateData struct {
+	TaskID   string
+	Title    string
+	Role     string
+	Stage    string // work, test, review, resolve, or merge
+	State    string // state the commit moves the task to, e.g. implemented
+	Attempt  int
+	CLI      string // built-in CLI that ran the worker; empty for custom commands
+	Tokens   int    // total tokens the worker reported, when known
+	Log      string // redacted, truncated worker stdout
+	Strategy string // merge strategy, for merge commits
+	Commits  string // stage commit subjects, for the merge strategy
+}
+
+// Prompt budget actions applied when a prompt exceeds max_tokens.
+const (
+	PromptBudgetWarn  = "warn"
+	PromptBudgetDrop  = "drop"
+	PromptBudgetBlock = "block"
+)
+
+// Optional prompt section kinds that prompt_budget.drop_order may remove.
+const (
+	PromptSectionReasoning    = "reasoning"
+	PromptSectionCustomGlobal = "custom_global"
+	PromptSectionCustomRole   = "custom_role"
+	PromptSectionContext      = "context"
+)
+
+// PromptSectionDropKinds lists the section kinds accepted in prompt_budget.drop_order.
+var PromptSectionDropKinds = []string{PromptSectionReasoning, PromptSectionCustomGlobal, PromptSectionCustomRole, PromptSectionContext}
+
+// Merge strategies for landing a task branch on the base branch.
+const (
+	MergeStrategySquash = "squash"
+	MergeStrategyMerge  = "merge"
+	MergeStrategyRebase = "rebase"
+)
+
+// Drift modes
+const (
+	DriftModeReplan = "replan" // drain workers and replan from the affected planning step
+	DriftModeNotify = "notify" // report drift in status without draining
+)
+
+// Sandbox modes
+const (
+	SandboxModeOff     = "off"
+	SandboxModeAuto    = "auto"
+	SandboxModeBwrap   = "bwrap"
+	SandboxModeUnshare = "unshare"
+)
+
 const DefaultReasoningEffort = "medium"
 
+// RateLimitPatternKeyDefault names the pattern set used for custom worker commands.
+const RateLimitPatternKeyDefault = "default"
+
 // Built-in CLI names
 const (
 	CLICodex  = "codex"
@@ -84,6 +307,36 @@ func BuiltInCommand(cli string) ([]string, bool) {
 	}
 }
 
+// IsValidMergeStrategy returns true if the strategy is a known merge strategy.
+func IsValidMergeStrategy(strategy string) bool {
+	switch strategy {
+	case MergeStrategySquash, MergeStrategyMerge, MergeStrategyRebase:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidDriftMode returns true if the mode is a known drift mode.
+func IsValidDriftMode(mode string) bool {
+	switch mode {
+	case DriftModeReplan, DriftModeNotify:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidSandboxMode returns true if the mode is a known sandbox mode.
+func IsValidSandboxMode(mode string) bool {
+	switch mode {
+	case SandboxModeOff, SandboxModeAuto, SandboxModeBwrap, SandboxModeUnshare:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsValidCLI returns true if the CLI name is a known built-in.
 func IsValidCLI(cli string) bool {
 	_, ok := BuiltInCommand(cli)
@@ -105,3 +358,119 @@ func (cfg ReasoningEffortConfig) LevelForRole(role string) string {
 	}
 	return DefaultReasoningEffort
 }
+
+// SecondsFor returns the worker timeout for a role, stage, and optional planning step.
+// A planning step override wins over the stage, the stage wins over the role, and
+// WorkerSeconds applies when none match.
+func (cfg TimeoutsConfig) SecondsFor(role string, stage string, step string) int {
+	if seconds, ok := positiveOverride(cfg.PlanningSteps, step); ok {
+		return seconds
+	}
+	if seconds, ok := positiveOverride(cfg.Stages, stage); ok {
+		return seconds
+	}
+	if seconds, ok := positiveOverride(cfg.Roles, role); ok {
+		return seconds
+	}
+	return cfg.WorkerSeconds
+}
+
+// positiveOverride looks up a positive override for a non-empty key.
+func positiveOverride(values map[string]int, key string) (int, bool) {
+	key = strings.TrimSpace(key)
+	if key == "" || values == nil {
+		return 0, false
+	}
+	if seconds, ok := values[key]; ok && seconds > 0 {
+		return seconds, true
+	}
+	return 0, false
+}
+
+// PatternsForCLI returns the rate-limit patterns for the supplied CLI name.
+func (cfg RateLimitConfig) PatternsForCLI(cli string) []string {
+	cli = strings.TrimSpace(cli)
+	if cli == "" {
+		cli = RateLimitPatternKeyDefault
+	}
+	if cfg.Patterns != nil {
+		if patterns, ok := cfg.Patterns[cli]; ok {
+			return patterns
+		}
+		if patterns, ok := cfg.Patterns[RateLimitPatternKeyDefault]; ok {
+			return patterns
+		}
+	}
+	return nil
+}
+
+// PolicyForRole returns the sandbox policy for the supplied role.
+func (cfg SandboxConfig) PolicyForRole(role string) SandboxPolicy {
+	if cfg.Roles != nil {
+		if policy, ok := cfg.Roles[role]; ok {
+			return policy
+		}
+	}
+	return cfg.Default
+}
+
+// Enabled reports whether the policy requests any sandboxing.
+func (policy SandboxPolicy) Enabled() bool {
+	mode := strings.TrimSpace(policy.Mode)
+	return mode != "" && mode != SandboxModeOff
+}
+
+// EnvConfig defines extra environment variables passed to worker processes.
+// Secrets belong in the local secrets file instead, which is never committed.
+type EnvConfig struct {
+	Default map[string]string            `json:"default"`
+	Roles   map[string]map[string]string `json:"roles"`  // per-role variables layered over default
+	Stages  map[string]map[string]string `json:"stages"` // per-stage variables layered over roles, keyed by StageKeys entry
+}
+
+// VarsFor returns the environment variables for a role and stage. Stage values win over
+// role values, and role values win over the defaults.
+func (cfg EnvConfig) VarsFor(role string, stage string) map[string]string {
+	vars := map[string]string{}
+	for _, layer := range []map[string]string{cfg.Default, cfg.Roles[role], cfg.Stages[stage]} {
+		for key, value := range layer {
+			vars[key] = value
+		}
+	}
+	return vars
+}
+
+// LimitsForRole returns the resource limits for the supplied role.
+func (cfg ResourcesConfig) LimitsForRole(role string) ResourceLimits {
+	if cfg.Roles != nil {
+		if limits, ok := cfg.Roles[role]; ok {
+			return limits
+		}
+	}
+	return cfg.Default
+}
+
+// Enabled reports whether any resource is limited.
+func (limits ResourceLimits) Enabled() bool {
+	return limits.MemoryMB > 0 || limits.CPUs > 0 || limits.MaxProcesses > 0 || limits.MaxFileSizeMB > 0
+}
+
+// BudgetForCLI returns the prompt budget for the supplied CLI name, falling back to the default.
+func (cfg PromptBudgetConfig) BudgetForCLI(cli string) PromptBudget {
+	if cfg.CLIs != nil {
+		if budget, ok := cfg.CLIs[strings.TrimSpace(cli)]; ok {
+			return budget
+		}
+	}
+	return cfg.Default
+}
+
+// IsValidPromptBudgetAction returns true if the action is a known on_exceed value.
+func IsValidPromptBudgetAction(action string) bool {
+	switch action {
+	case PromptBudgetWarn, PromptBudgetDrop, PromptBudgetBlock:
+		return true
+	default:
+		return false
+	}
+}
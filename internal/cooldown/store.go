@@ -0,0 +1,174 @@
+// Package cooldown manages persisted CLI cooldown windows after provider rate limits.
+package cooldown
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	localStateDirName = "_governator/_local-state"
+	cooldownFileName  = "cooldowns.json"
+	cooldownFileMode  = 0o644
+	localStateDirMode = 0o755
+)
+
+// Store provides access to persisted CLI cooldowns.
+type Store struct {
+	path string
+}
+
+// Entry captures a single CLI cooldown window.
+type Entry struct {
+	CLI       string    `json:"cli"`
+	StartedAt time.Time `json:"started_at"`
+	Until     time.Time `json:"until"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Set tracks cooldowns keyed by CLI name.
+type Set map[string]Entry
+
+type snapshot struct {
+	Cooldowns []Entry `json:"cooldowns"`
+}
+
+// NewStore builds a Store rooted at the provided repository root.
+func NewStore(repoRoot string) (Store, error) {
+	if repoRoot == "" {
+		return Store{}, errors.New("repo root is required")
+	}
+	return Store{path: filepath.Join(repoRoot, localStateDirName, cooldownFileName)}, nil
+}
+
+// Load reads the cooldown set from disk.
+func (store Store) Load() (Set, error) {
+	if store.path == "" {
+		return nil, errors.New("cooldown store path is required")
+	}
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Set{}, nil
+		}
+		return nil, fmt.Errorf("read cooldown data %s: %w", store.path, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return Set{}, nil
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("decode cooldown data %s: %w", store.path, err)
+	}
+	set := make(Set, len(snap.Cooldowns))
+	for _, entry := range snap.Cooldowns {
+		if strings.TrimSpace(entry.CLI) == "" {
+			return nil, fmt.Errorf("decode cooldown data %s: empty cli", store.path)
+		}
+		set[entry.CLI] = entry
+	}
+	return set, nil
+}
+
+// Save writes the cooldown set to disk deterministically.
+func (store Store) Save(set Set) error {
+	if store.path == "" {
+		return errors.New("cooldown store path is required")
+	}
+	if set == nil {
+		return errors.New("cooldown set is required")
+	}
+	dir := filepath.Dir(store.path)
+	if err := os.MkdirAll(dir, localStateDirMode); err != nil {
+		return fmt.Errorf("create cooldown directory %s: %w", dir, err)
+	}
+	encoded, err := json.MarshalIndent(snapshot{Cooldowns: set.Entries()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cooldown data %s: %w", store.path, err)
+	}
+	encoded = append(encoded, '\n')
+	if err := os.WriteFile(store.path, encoded, cooldownFileMode); err != nil {
+		return fmt.Errorf("write cooldown data %s: %w", store.path, err)
+	}
+	return nil
+}
+
+// Start records a cooldown for the entry's CLI, keeping any later existing deadline.
+func (set Set) Start(entry Entry) error {
+	if set == nil {
+		return errors.New("cooldown set is required")
+	}
+	entry.CLI = strings.TrimSpace(entry.CLI)
+	if entry.CLI == "" {
+		return errors.New("cli is required")
+	}
+	if entry.Until.IsZero() {
+		return errors.New("cooldown end time is required")
+	}
+	if existing, ok := set[entry.CLI]; ok && existing.Until.After(entry.Until) {
+		return nil
+	}
+	set[entry.CLI] = entry
+	return nil
+}
+
+// Active returns the cooldown for the CLI when it has not yet expired.
+func (set Set) Active(cli string, now time.Time) (Entry, bool) {
+	if set == nil {
+		return Entry{}, false
+	}
+	entry, ok := set[strings.TrimSpace(cli)]
+	if !ok || !now.Before(entry.Until) {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// ActiveEntries returns the unexpired cooldowns sorted by CLI name.
+func (set Set) ActiveEntries(now time.Time) []Entry {
+	entries := make([]Entry, 0, len(set))
+	for _, entry := range set.Entries() {
+		if now.Before(entry.Until) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Prune removes expired cooldowns and reports whether any were removed.
+func (set Set) Prune(now time.Time) bool {
+	pruned := false
+	for cli, entry := range set {
+		if !now.Before(entry.Until) {
+			delete(set, cli)
+			pruned = true
+		}
+	}
+	return pruned
+}
+
+// Entries returns every cooldown sorted by CLI name.
+func (set Set) Entries() []Entry {
+	clis := make([]string, 0, len(set))
+	for cli := range set {
+		clis = append(clis, cli)
+	}
+	sort.Strings(clis)
+	entries := make([]Entry, 0, len(clis))
+	for _, cli := range clis {
+		entry := set[cli]
+		if entry.CLI == "" {
+			entry.CLI = cli
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,97 @@
+// Package cooldown provides tests for cooldown persistence.
+package cooldown
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestStoreSaveAndLoadRoundTrip ensures cooldown entries persist correctly.
+func TestStoreSaveAndLoadRoundTrip(t *testing.T) {
+	store := newTempStore(t)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	set := Set{}
+	if err := set.Start(Entry{CLI: "claude", StartedAt: now, Until: now.Add(5 * time.Minute), TaskID: "T-1", Reason: "usage limit reached"}); err != nil {
+		t.Fatalf("start cooldown: %v", err)
+	}
+	if err := store.Save(set); err != nil {
+		t.Fatalf("save cooldowns: %v", err)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("load cooldowns: %v", err)
+	}
+	entry, ok := loaded.Active("claude", now.Add(time.Minute))
+	if !ok {
+		t.Fatal("expected claude cooldown to be active")
+	}
+	if entry.TaskID != "T-1" || entry.Reason != "usage limit reached" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if _, ok := loaded.Active("codex", now); ok {
+		t.Fatal("expected codex to have no cooldown")
+	}
+}
+
+// TestSetStartKeepsLaterDeadline ensures a shorter cooldown does not shorten an existing one.
+func TestSetStartKeepsLaterDeadline(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	set := Set{}
+	if err := set.Start(Entry{CLI: "codex", StartedAt: now, Until: now.Add(10 * time.Minute)}); err != nil {
+		t.Fatalf("start cooldown: %v", err)
+	}
+	if err := set.Start(Entry{CLI: "codex", StartedAt: now, Until: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("start cooldown: %v", err)
+	}
+	if got, want := set["codex"].Until, now.Add(10*time.Minute); !got.Equal(want) {
+		t.Fatalf("until = %v, want %v", got, want)
+	}
+}
+
+// TestSetPruneRemovesExpired ensures expired cooldowns are dropped.
+func TestSetPruneRemovesExpired(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	set := Set{
+		"codex":  {CLI: "codex", Until: now.Add(-time.Second)},
+		"claude": {CLI: "claude", Until: now.Add(time.Minute)},
+	}
+	if !set.Prune(now) {
+		t.Fatal("expected prune to report removal")
+	}
+	if _, ok := set["codex"]; ok {
+		t.Fatal("expected expired codex cooldown to be removed")
+	}
+	if active := set.ActiveEntries(now); len(active) != 1 || active[0].CLI != "claude" {
+		t.Fatalf("unexpected active entries: %+v", active)
+	}
+}
+
+// TestStoreLoadMissingFile ensures a missing file yields an empty set.
+func TestStoreLoadMissingFile(t *testing.T) {
+	store := newTempStore(t)
+	set, err := store.Load()
+	if err != nil {
+		t.Fatalf("load cooldowns: %v", err)
+	}
+	if len(set) != 0 {
+		t.Fatalf("expected empty set, got %d entries", len(set))
+	}
+}
+
+// newTempStore creates a store rooted in a temporary repo directory.
+func newTempStore(t *testing.T) Store {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, localStateDirName), 0o755); err != nil {
+		t.Fatalf("create local state: %v", err)
+	}
+	store, err := NewStore(root)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	return store
+}
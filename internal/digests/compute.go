@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/cmtonkinson/governator/internal/index"
 )
@@ -14,21 +16,47 @@ import (
 const (
 	governatorFileName = "GOVERNATOR.md"
 	docsDirName        = "_governator/docs"
+	// rawDigestPrefix marks a digest of the file bytes as they are.
+	rawDigestPrefix = "sha256:"
+	// markdownDigestPrefix marks a digest of normalized markdown.
+	markdownDigestPrefix = "sha256-md:"
 )
 
+// Policy scopes which planning docs are digested and how markdown is compared.
+// GOVERNATOR.md is always tracked.
+type Policy struct {
+	Include           []string // repo-relative globs under _governator/docs; empty tracks every doc
+	Exclude           []string // repo-relative globs under _governator/docs to ignore
+	NormalizeMarkdown bool     // digest .md files ignoring whitespace, line ending, and reflow changes
+}
+
+// Tracks reports whether a repo-relative planning doc path is in scope for the policy. A glob
+// covers the paths it matches and everything beneath a directory it matches.
+func (policy Policy) Tracks(relPath string) bool {
+	if len(policy.Include) > 0 && !globsMatch(policy.Include, relPath) {
+		return false
+	}
+	return !globsMatch(policy.Exclude, relPath)
+}
+
 // Compute builds digests for GOVERNATOR.md and planning artifacts under _governator/docs.
 func Compute(repoRoot string) (index.Digests, error) {
+	return ComputeWithPolicy(repoRoot, Policy{})
+}
+
+// ComputeWithPolicy builds digests for GOVERNATOR.md and the planning docs the policy tracks.
+func ComputeWithPolicy(repoRoot string, policy Policy) (index.Digests, error) {
 	if repoRoot == "" {
 		return index.Digests{}, fmt.Errorf("repo root is required")
 	}
 
 	governatorPath := filepath.Join(repoRoot, governatorFileName)
-	governatorDigest, err := digestFile(governatorPath)
+	governatorDigest, err := digestPath(governatorPath, policy)
 	if err != nil {
 		return index.Digests{}, err
 	}
 
-	planningDocs, err := docsDigests(repoRoot)
+	planningDocs, err := docsDigests(repoRoot, policy)
 	if err != nil {
 		return index.Digests{}, err
 	}
@@ -49,11 +77,113 @@ func digestFile(path string) (string, error) {
 		return "", fmt.Errorf("read %s: %w", path, err)
 	}
 	sum := sha256.Sum256(data)
-	return fmt.Sprintf("sha256:%x", sum), nil
+	return fmt.Sprintf("%s%x", rawDigestPrefix, sum), nil
+}
+
+// digestPath digests a file, normalizing markdown first when the policy asks for it.
+func digestPath(path string, policy Policy) (string, error) {
+	if policy.NormalizeMarkdown && strings.EqualFold(filepath.Ext(path), ".md") {
+		return digestMarkdown(path)
+	}
+	return digestFile(path)
+}
+
+// digestMarkdown returns a digest of the file's markdown with whitespace, line endings, and
+// paragraph wrapping normalized, or an empty string if it is missing.
+func digestMarkdown(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	sum := sha256.Sum256([]byte(normalizeMarkdown(string(data))))
+	return fmt.Sprintf("%s%x", markdownDigestPrefix, sum), nil
+}
+
+// digestLike digests a file with the same scheme as an existing digest of it.
+func digestLike(path string, existing string) (string, error) {
+	if strings.HasPrefix(existing, markdownDigestPrefix) {
+		return digestMarkdown(path)
+	}
+	return digestFile(path)
 }
 
-// docsDigests collects digests for all regular files under _governator/docs.
-func docsDigests(repoRoot string) (map[string]string, error) {
+// normalizeMarkdown converts CRLF line endings to LF, strips trailing whitespace, collapses runs
+// of internal whitespace and of blank lines, and joins the wrapped lines of a paragraph, so
+// reflowed text digests the same. Indentation, bullet markers, and the lines inside fenced code
+// blocks are left as written.
+func normalizeMarkdown(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	inFence := false
+	joinable := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		body := strings.TrimLeft(line, " \t")
+		if isMarkdownFence(body) {
+			inFence = !inFence
+			out = append(out, line)
+			joinable = false
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		if body == "" {
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			joinable = false
+			continue
+		}
+		body = strings.Join(strings.Fields(body), " ")
+		if joinable && !startsMarkdownBlock(body) {
+			out[len(out)-1] += " " + body
+			continue
+		}
+		out = append(out, line[:len(line)-len(strings.TrimLeft(line, " \t"))]+body)
+		joinable = !strings.HasPrefix(body, "#") && !strings.HasPrefix(body, "|")
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+// isMarkdownFence reports whether a line opens or closes a fenced code block.
+func isMarkdownFence(body string) bool {
+	return strings.HasPrefix(body, "```") || strings.HasPrefix(body, "~~~")
+}
+
+// startsMarkdownBlock reports whether a line starts a heading, list item, quote, or table row
+// rather than continuing the paragraph above it.
+func startsMarkdownBlock(body string) bool {
+	switch {
+	case strings.HasPrefix(body, "#"), strings.HasPrefix(body, ">"), strings.HasPrefix(body, "|"):
+		return true
+	case strings.HasPrefix(body, "- "), strings.HasPrefix(body, "* "), strings.HasPrefix(body, "+ "):
+		return true
+	}
+	digits := len(body) - len(strings.TrimLeft(body, "0123456789"))
+	rest := body[digits:]
+	return digits > 0 && (strings.HasPrefix(rest, ". ") || strings.HasPrefix(rest, ") "))
+}
+
+// globsMatch reports whether any glob matches the path or one of its parent directories.
+func globsMatch(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+		for candidate := relPath; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			if matched, err := path.Match(pattern, candidate); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// docsDigests collects digests for the regular files under _governator/docs the policy tracks.
+func docsDigests(repoRoot string, policy Policy) (map[string]string, error) {
 	docsRoot := filepath.Join(repoRoot, docsDirName)
 	entries := map[string]string{}
 
@@ -71,11 +201,14 @@ func docsDigests(repoRoot string) (map[string]string, error) {
 			return nil
 		}
 
-		digest, err := digestFile(path)
+		relative, err := repoRelativePath(repoRoot, path)
 		if err != nil {
 			return err
 		}
-		relative, err := repoRelativePath(repoRoot, path)
+		if !policy.Tracks(relative) {
+			return nil
+		}
+		digest, err := digestPath(path, policy)
 		if err != nil {
 			return err
 		}
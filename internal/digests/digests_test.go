@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/cmtonkinson/governator/internal/index"
 )
 
 func TestComputeDigests(t *testing.T) {
@@ -96,6 +98,9 @@ func TestDetectDriftGoverningDocChange(t *testing.T) {
 	if !strings.Contains(report.Message, "replan required") {
 		t.Fatalf("expected replanning prompt, got %q", report.Message)
 	}
+	if len(report.ChangedPaths) != 1 || report.ChangedPaths[0] != "GOVERNATOR.md" {
+		t.Fatalf("expected GOVERNATOR.md changed path, got %v", report.ChangedPaths)
+	}
 }
 
 func writeRepoFixture(root string) error {
@@ -150,3 +155,144 @@ func TestCompute_IgnoresPlanningNotes(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeWithPolicyScopesDocs(t *testing.T) {
+	root := t.TempDir()
+	if err := writeRepoFixture(root); err != nil {
+		t.Fatalf("write repo: %v", err)
+	}
+	draftsDir := filepath.Join(root, "_governator", "docs", "drafts")
+	if err := os.MkdirAll(draftsDir, 0o755); err != nil {
+		t.Fatalf("mkdir drafts: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(draftsDir, "idea.md"), []byte("idea\n"), 0o644); err != nil {
+		t.Fatalf("write draft: %v", err)
+	}
+
+	got, err := ComputeWithPolicy(root, Policy{Exclude: []string{"_governator/docs/drafts"}})
+	if err != nil {
+		t.Fatalf("ComputeWithPolicy error: %v", err)
+	}
+	if _, ok := got.PlanningDocs["_governator/docs/drafts/idea.md"]; ok {
+		t.Fatalf("expected excluded draft to be skipped, got %v", got.PlanningDocs)
+	}
+	if _, ok := got.PlanningDocs["_governator/docs/roadmap.md"]; !ok {
+		t.Fatalf("expected roadmap digest, got %v", got.PlanningDocs)
+	}
+
+	got, err = ComputeWithPolicy(root, Policy{Include: []string{"_governator/docs/drafts/*.md"}})
+	if err != nil {
+		t.Fatalf("ComputeWithPolicy error: %v", err)
+	}
+	if len(got.PlanningDocs) != 1 || got.PlanningDocs["_governator/docs/drafts/idea.md"] == "" {
+		t.Fatalf("expected only the included draft, got %v", got.PlanningDocs)
+	}
+	if got.GovernatorMD == "" {
+		t.Fatal("expected GOVERNATOR.md to stay tracked")
+	}
+}
+
+func TestDetectWithPolicyIgnoresFormattingAndUntrackedDocs(t *testing.T) {
+	root := t.TempDir()
+	if err := writeRepoFixture(root); err != nil {
+		t.Fatalf("write repo: %v", err)
+	}
+	policy := Policy{Exclude: []string{"_governator/docs/drafts"}, NormalizeMarkdown: true}
+	roadmapPath := filepath.Join(root, "_governator", "docs", "roadmap.md")
+	if err := os.WriteFile(roadmapPath, []byte("# Plan\n\nShip the parser first and the\nformatter after it.\n\n* first item\n* second item that wraps\n"), 0o644); err != nil {
+		t.Fatalf("write roadmap: %v", err)
+	}
+	stored, err := ComputeWithPolicy(root, policy)
+	if err != nil {
+		t.Fatalf("ComputeWithPolicy error: %v", err)
+	}
+	stored.PlanningDocs["_governator/docs/drafts/old.md"] = "sha256:stale"
+
+	if err := os.WriteFile(roadmapPath, []byte("# Plan  \r\n\r\n\r\nShip the parser\nfirst and the formatter after it.\n\n\n* first item\t\r\n* second item that wraps   \n\n\n"), 0o644); err != nil {
+		t.Fatalf("reformat roadmap: %v", err)
+	}
+	report, err := DetectWithPolicy(root, stored, policy)
+	if err != nil {
+		t.Fatalf("DetectWithPolicy error: %v", err)
+	}
+	if report.HasDrift {
+		t.Fatalf("expected no drift for a reformat, got %v", report.Details)
+	}
+
+	if err := os.WriteFile(roadmapPath, []byte("# Plan\n\nShip the parser first and the\nformatter after it.\n\n- first item\n- second item that wraps\n"), 0o644); err != nil {
+		t.Fatalf("edit roadmap: %v", err)
+	}
+	report, err = DetectWithPolicy(root, stored, policy)
+	if err != nil {
+		t.Fatalf("DetectWithPolicy error: %v", err)
+	}
+	if !report.HasDrift || len(report.ChangedPaths) != 1 || report.ChangedPaths[0] != "_governator/docs/roadmap.md" {
+		t.Fatalf("expected roadmap drift only, got %+v", report)
+	}
+}
+
+func TestNormalizeMarkdownIgnoresReflowAndBlankLines(t *testing.T) {
+	plan := "# Plan\n\nShip the parser first and the\nformatter after it.\n\n- first item\n- second item\n"
+	for _, tc := range []struct {
+		name   string
+		before string
+		after  string
+		same   bool
+	}{
+		{name: "reflowed paragraph", before: plan, after: "# Plan\n\nShip the parser\nfirst and  the formatter\nafter it.\n\n- first item\n- second item\n", same: true},
+		{name: "extra blank lines", before: plan, after: "# Plan\n\n\n\nShip the parser first and the\nformatter after it.\n\n\n- first item\n- second item\n", same: true},
+		{name: "merged list items", before: plan, after: "# Plan\n\nShip the parser first and the\nformatter after it.\n\n- first item - second item\n", same: false},
+		{name: "changed words", before: plan, after: "# Plan\n\nShip the formatter first and the\nparser after it.\n\n- first item\n- second item\n", same: false},
+		{name: "respaced code block", before: "```\na b\n```\n", after: "```\na  b\n```\n", same: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before, after := normalizeMarkdown(tc.before), normalizeMarkdown(tc.after)
+			if got := before == after; got != tc.same {
+				t.Fatalf("normalized equal = %v, want %v\nbefore: %q\nafter:  %q", got, tc.same, before, after)
+			}
+		})
+	}
+}
+
+func TestDetectWithPolicyComparesUnderStoredScheme(t *testing.T) {
+	root := t.TempDir()
+	if err := writeRepoFixture(root); err != nil {
+		t.Fatalf("write repo: %v", err)
+	}
+	raw, err := ComputeWithPolicy(root, Policy{})
+	if err != nil {
+		t.Fatalf("ComputeWithPolicy error: %v", err)
+	}
+	normalized, err := ComputeWithPolicy(root, Policy{NormalizeMarkdown: true})
+	if err != nil {
+		t.Fatalf("ComputeWithPolicy error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		stored index.Digests
+		policy Policy
+	}{
+		{stored: raw, policy: Policy{NormalizeMarkdown: true}},
+		{stored: normalized, policy: Policy{}},
+	} {
+		report, err := DetectWithPolicy(root, tc.stored, tc.policy)
+		if err != nil {
+			t.Fatalf("DetectWithPolicy error: %v", err)
+		}
+		if report.HasDrift {
+			t.Fatalf("expected no drift after toggling normalization to %v, got %v", tc.policy.NormalizeMarkdown, report.Details)
+		}
+	}
+
+	roadmapPath := filepath.Join(root, "_governator", "docs", "roadmap.md")
+	if err := os.WriteFile(roadmapPath, []byte("rewritten plan\n"), 0o644); err != nil {
+		t.Fatalf("edit roadmap: %v", err)
+	}
+	report, err := DetectWithPolicy(root, raw, Policy{NormalizeMarkdown: true})
+	if err != nil {
+		t.Fatalf("DetectWithPolicy error: %v", err)
+	}
+	if !report.HasDrift || len(report.ChangedPaths) != 1 || report.ChangedPaths[0] != "_governator/docs/roadmap.md" {
+		t.Fatalf("expected roadmap drift only, got %+v", report)
+	}
+}
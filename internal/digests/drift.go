@@ -3,6 +3,7 @@ package digests
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -11,19 +12,38 @@ import (
 
 // DriftReport summarizes whether stored digests match the current repository state.
 type DriftReport struct {
-	HasDrift bool
-	Message  string
-	Details  []string
+	HasDrift     bool
+	Message      string
+	Details      []string
+	ChangedPaths []string // repo-relative paths that changed, were added, or went missing
 }
 
 // Detect compares stored digests against the current repository and reports drift.
 func Detect(repoRoot string, stored index.Digests) (DriftReport, error) {
-	current, err := Compute(repoRoot)
+	return DetectWithPolicy(repoRoot, stored, Policy{})
+}
+
+// DetectWithPolicy compares stored digests against the planning docs the policy tracks. Stored
+// digests for docs outside the policy are ignored, so narrowing the scope never reports drift.
+// Each doc is compared under the scheme of its stored digest, so toggling markdown
+// normalization alone never reports drift either.
+func DetectWithPolicy(repoRoot string, stored index.Digests, policy Policy) (DriftReport, error) {
+	current, err := ComputeWithPolicy(repoRoot, policy)
 	if err != nil {
 		return DriftReport{}, err
 	}
+	scoped := map[string]string{}
+	for path, digest := range stored.PlanningDocs {
+		if policy.Tracks(path) {
+			scoped[path] = digest
+		}
+	}
+	stored.PlanningDocs = scoped
+	if err := matchStoredSchemes(repoRoot, stored, &current); err != nil {
+		return DriftReport{}, err
+	}
 
-	reasons := driftReasons(stored, current)
+	reasons, changed := driftReasons(stored, current)
 	if len(reasons) == 0 {
 		return DriftReport{
 			HasDrift: false,
@@ -32,16 +52,52 @@ func Detect(repoRoot string, stored index.Digests) (DriftReport, error) {
 	}
 
 	return DriftReport{
-		HasDrift: true,
-		Message:  formatDriftMessage(reasons),
-		Details:  reasons,
+		HasDrift:     true,
+		Message:      formatDriftMessage(reasons),
+		Details:      reasons,
+		ChangedPaths: changed,
 	}, nil
 }
 
-func driftReasons(stored index.Digests, current index.Digests) []string {
+// matchStoredSchemes recomputes current digests whose scheme differs from the stored digest
+// for the same file.
+func matchStoredSchemes(repoRoot string, stored index.Digests, current *index.Digests) error {
+	if digestScheme(stored.GovernatorMD) != digestScheme(current.GovernatorMD) && current.GovernatorMD != "" {
+		digest, err := digestLike(filepath.Join(repoRoot, governatorFileName), stored.GovernatorMD)
+		if err != nil {
+			return err
+		}
+		current.GovernatorMD = digest
+	}
+	for path, storedDigest := range stored.PlanningDocs {
+		currentDigest, ok := current.PlanningDocs[path]
+		if !ok || digestScheme(storedDigest) == digestScheme(currentDigest) {
+			continue
+		}
+		digest, err := digestLike(filepath.Join(repoRoot, filepath.FromSlash(path)), storedDigest)
+		if err != nil {
+			return err
+		}
+		current.PlanningDocs[path] = digest
+	}
+	return nil
+}
+
+// digestScheme returns the scheme prefix of a digest, such as "sha256:".
+func digestScheme(digest string) string {
+	if colon := strings.Index(digest, ":"); colon >= 0 {
+		return digest[:colon+1]
+	}
+	return ""
+}
+
+// driftReasons lists human-readable drift reasons and the sorted repo-relative paths behind them.
+func driftReasons(stored index.Digests, current index.Digests) ([]string, []string) {
 	reasons := []string{}
+	changed := []string{}
 	if stored.GovernatorMD != current.GovernatorMD {
 		reasons = append(reasons, "GOVERNATOR.md changed")
+		changed = append(changed, governatorFileName)
 	}
 
 	storedDocs := stored.PlanningDocs
@@ -50,20 +106,24 @@ func driftReasons(stored index.Digests, current index.Digests) []string {
 		currentDigest, ok := currentDocs[path]
 		if !ok {
 			reasons = append(reasons, fmt.Sprintf("planning doc missing: %s", path))
+			changed = append(changed, path)
 			continue
 		}
 		if storedDigest != currentDigest {
 			reasons = append(reasons, fmt.Sprintf("planning doc changed: %s", path))
+			changed = append(changed, path)
 		}
 	}
 	for path := range currentDocs {
 		if _, ok := storedDocs[path]; !ok {
 			reasons = append(reasons, fmt.Sprintf("planning doc added: %s", path))
+			changed = append(changed, path)
 		}
 	}
 
 	sort.Strings(reasons)
-	return reasons
+	sort.Strings(changed)
+	return reasons, changed
 }
 
 func formatDriftMessage(reasons []string) string {
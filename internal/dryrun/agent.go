@@ -0,0 +1,226 @@
+// Package dryrun provides the fake agent process that follows a dry-run script.
+package dryrun
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// triageTaskID mirrors the task ID the supervisor uses for backlog triage.
+	triageTaskID = "triage-dag"
+	// triageOutputPath is where triage expects the dependency mapping.
+	triageOutputPath = "_governator/_local-state/dag.json"
+	// planningNotesPath receives notes for tasks whose file must not be edited.
+	planningNotesPath = "_governator/_local-state/planning-notes.md"
+	fileMode          = 0o644
+	dirMode           = 0o755
+)
+
+// RunAgent executes the fake agent in the current directory and returns its exit code.
+// It reads the task, stage, and planning step from the GOVERNATOR_* environment.
+func RunAgent(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet(AgentCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	scriptPath := flags.String("script", "", "")
+	stateDir := flags.String("state", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if strings.TrimSpace(*scriptPath) == "" || strings.TrimSpace(*stateDir) == "" {
+		fmt.Fprintln(stderr, "[dry-run] --script and --state are required")
+		return 2
+	}
+
+	script, err := LoadScript(*scriptPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "[dry-run] %v\n", err)
+		return 2
+	}
+
+	taskID := os.Getenv("GOVERNATOR_TASK_ID")
+	stage := os.Getenv("GOVERNATOR_STAGE")
+	taskKey := taskID
+	if step := os.Getenv("GOVERNATOR_PLANNING_STEP"); step != "" {
+		taskKey = step
+	}
+	attempt, err := nextAttempt(*stateDir, taskKey, stage)
+	if err != nil {
+		fmt.Fprintf(stderr, "[dry-run] %v\n", err)
+		return 2
+	}
+
+	action := script.Match(taskKey, stage, attempt)
+	fmt.Fprintf(stdout, "[dry-run] task %s stage %s attempt %d\n", taskKey, stage, attempt)
+	if action.DelaySeconds > 0 {
+		fmt.Fprintf(stdout, "[dry-run] sleeping %ds\n", action.DelaySeconds)
+		time.Sleep(time.Duration(action.DelaySeconds) * time.Second)
+	}
+
+	for _, file := range action.Files {
+		if err := applyFileAction(file); err != nil {
+			fmt.Fprintf(stderr, "[dry-run] %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "[dry-run] %s %s\n", fileActionVerb(file), file.Path)
+	}
+
+	if taskID == triageTaskID {
+		if err := ensureTriageOutput(); err != nil {
+			fmt.Fprintf(stderr, "[dry-run] %v\n", err)
+			return 1
+		}
+	}
+
+	notesPath := taskNotesPath(os.Getenv("GOVERNATOR_TASK_PATH"))
+	if reason := strings.TrimSpace(action.BlockReason); reason != "" {
+		if err := appendSection(notesPath, "Blocking Reason", reason); err != nil {
+			fmt.Fprintf(stderr, "[dry-run] %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stderr, "[dry-run] blocked: %s\n", reason)
+		if action.ExitCode == 0 {
+			return 1
+		}
+		return action.ExitCode
+	}
+
+	if action.ExitCode == 0 && notesPath != "" {
+		summary := fmt.Sprintf("Dry-run agent completed %s for %s (attempt %d).", stage, taskKey, attempt)
+		if err := appendSection(notesPath, "Change Summary", summary); err != nil {
+			fmt.Fprintf(stderr, "[dry-run] %v\n", err)
+			return 1
+		}
+	}
+	fmt.Fprintf(stdout, "[dry-run] exiting with code %d\n", action.ExitCode)
+	return action.ExitCode
+}
+
+// nextAttempt increments and returns the invocation count for a task and stage.
+func nextAttempt(stateDir string, taskKey string, stage string) (int, error) {
+	if err := os.MkdirAll(stateDir, dirMode); err != nil {
+		return 0, fmt.Errorf("create dry-run state dir %s: %w", stateDir, err)
+	}
+	name := fmt.Sprintf("%s--%s.count", sanitizeKey(taskKey), sanitizeKey(stage))
+	path := filepath.Join(stateDir, name)
+	count := 0
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("read dry-run attempt count %s: %w", path, err)
+	}
+	if err == nil {
+		count, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+	}
+	count++
+	if err := os.WriteFile(path, []byte(strconv.Itoa(count)+"\n"), fileMode); err != nil {
+		return 0, fmt.Errorf("write dry-run attempt count %s: %w", path, err)
+	}
+	return count, nil
+}
+
+// sanitizeKey makes a task or stage name safe to use in a file name.
+func sanitizeKey(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "none"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, value)
+}
+
+// applyFileAction performs one scripted file change relative to the current directory.
+func applyFileAction(file FileAction) error {
+	path := filepath.FromSlash(file.Path)
+	if file.Delete {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("delete %s: %w", file.Path, err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return fmt.Errorf("create directory for %s: %w", file.Path, err)
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if file.Append {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	handle, err := os.OpenFile(path, flags, fileMode)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", file.Path, err)
+	}
+	defer handle.Close()
+	if _, err := handle.WriteString(file.Content); err != nil {
+		return fmt.Errorf("write %s: %w", file.Path, err)
+	}
+	return nil
+}
+
+// fileActionVerb describes a file action for the agent log.
+func fileActionVerb(file FileAction) string {
+	switch {
+	case file.Delete:
+		return "deleted"
+	case file.Append:
+		return "appended to"
+	default:
+		return "wrote"
+	}
+}
+
+// ensureTriageOutput writes an empty dependency mapping when the script did not provide one.
+func ensureTriageOutput() error {
+	path := filepath.FromSlash(triageOutputPath)
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return fmt.Errorf("create directory for %s: %w", triageOutputPath, err)
+	}
+	if err := os.WriteFile(path, []byte("{}\n"), fileMode); err != nil {
+		return fmt.Errorf("write %s: %w", triageOutputPath, err)
+	}
+	return nil
+}
+
+// taskNotesPath returns the file that receives completion and blocking notes, following
+// the worker contract: planning and local-state prompts use the planning notes file.
+func taskNotesPath(taskPath string) string {
+	taskPath = filepath.ToSlash(strings.TrimSpace(taskPath))
+	if taskPath == "" {
+		return ""
+	}
+	if strings.HasPrefix(taskPath, "_governator/prompts") || strings.HasPrefix(taskPath, "_governator/_local-state") {
+		return filepath.FromSlash(planningNotesPath)
+	}
+	return filepath.FromSlash(taskPath)
+}
+
+// appendSection appends a markdown section to path, creating the file when missing.
+func appendSection(path string, title string, body string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return fmt.Errorf("create directory for %s: %w", path, err)
+	}
+	handle, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileMode)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer handle.Close()
+	if _, err := fmt.Fprintf(handle, "\n## %s\n\n%s\n", title, body); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
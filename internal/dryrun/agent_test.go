@@ -0,0 +1,110 @@
+// Tests for the dry-run fake agent process.
+package dryrun
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupAgent prepares a worker directory, script, and GOVERNATOR_* environment for RunAgent.
+func setupAgent(t *testing.T, script string, taskID string, stage string, taskPath string) (string, []string) {
+	t.Helper()
+	workDir := t.TempDir()
+	scriptPath := filepath.Join(t.TempDir(), "dry-run.json")
+	stateDir := filepath.Join(t.TempDir(), "state")
+	writeFile(t, scriptPath, script)
+	t.Chdir(workDir)
+	t.Setenv("GOVERNATOR_TASK_ID", taskID)
+	t.Setenv("GOVERNATOR_STAGE", stage)
+	t.Setenv("GOVERNATOR_TASK_PATH", taskPath)
+	t.Setenv("GOVERNATOR_PLANNING_STEP", "")
+	return workDir, []string{"--script", scriptPath, "--state", stateDir, "prompt.md"}
+}
+
+// TestRunAgentAppliesFilesAndSummary writes scripted files and a change summary.
+func TestRunAgentAppliesFilesAndSummary(t *testing.T) {
+	workDir, args := setupAgent(t, `{"rules": [{"task": "T-001", "files": [
+  {"path": "src/app.txt", "content": "hello\n"},
+  {"path": "src/app.txt", "content": "again\n", "append": true}
+]}]}`, "T-001", "work", "_governator/tasks/T-001.md")
+	writeFile(t, filepath.Join(workDir, "_governator", "tasks", "T-001.md"), "# Task\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := RunAgent(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+	content, err := os.ReadFile(filepath.Join(workDir, "src", "app.txt"))
+	if err != nil {
+		t.Fatalf("read scripted file: %v", err)
+	}
+	if string(content) != "hello\nagain\n" {
+		t.Fatalf("scripted file = %q", string(content))
+	}
+	task, err := os.ReadFile(filepath.Join(workDir, "_governator", "tasks", "T-001.md"))
+	if err != nil {
+		t.Fatalf("read task: %v", err)
+	}
+	if !strings.Contains(string(task), "## Change Summary") {
+		t.Fatalf("task file missing change summary: %s", string(task))
+	}
+}
+
+// TestRunAgentBlocksAndCountsAttempts blocks on the first attempt and succeeds on the retry.
+func TestRunAgentBlocksAndCountsAttempts(t *testing.T) {
+	workDir, args := setupAgent(t, `{"rules": [
+  {"task": "T-002", "attempt": 1, "block_reason": "database schema undecided"}
+]}`, "T-002", "work", "_governator/tasks/T-002.md")
+	taskPath := filepath.Join(workDir, "_governator", "tasks", "T-002.md")
+	writeFile(t, taskPath, "# Task\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := RunAgent(args, &stdout, &stderr); code != 1 {
+		t.Fatalf("first attempt exit code = %d, want 1", code)
+	}
+	task, err := os.ReadFile(taskPath)
+	if err != nil {
+		t.Fatalf("read task: %v", err)
+	}
+	if !strings.Contains(string(task), "## Blocking Reason\n\ndatabase schema undecided") {
+		t.Fatalf("task file missing blocking reason: %s", string(task))
+	}
+	if code := RunAgent(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("second attempt exit code = %d, want 0; stderr: %s", code, stderr.String())
+	}
+}
+
+// TestRunAgentUsesPlanningStepAndTriageDefaults matches planning steps by ID and writes an empty DAG for triage.
+func TestRunAgentUsesPlanningStepAndTriageDefaults(t *testing.T) {
+	workDir, args := setupAgent(t, `{"rules": [
+  {"task": "architecture-baseline", "files": [{"path": "_governator/docs/arch-asr.md", "content": "# ASR\n"}]}
+]}`, "planning", "work", "_governator/prompts/architecture-baseline.md")
+	t.Setenv("GOVERNATOR_PLANNING_STEP", "architecture-baseline")
+
+	var stdout, stderr bytes.Buffer
+	if code := RunAgent(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("planning exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "_governator", "docs", "arch-asr.md")); err != nil {
+		t.Fatalf("planning file missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, filepath.FromSlash(planningNotesPath))); err != nil {
+		t.Fatalf("planning notes missing: %v", err)
+	}
+
+	t.Setenv("GOVERNATOR_PLANNING_STEP", "")
+	t.Setenv("GOVERNATOR_TASK_ID", triageTaskID)
+	t.Setenv("GOVERNATOR_TASK_PATH", "_governator/_local-state/triage/dag-order-tasks.md")
+	if code := RunAgent(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("triage exit code = %d, stderr: %s", code, stderr.String())
+	}
+	dag, err := os.ReadFile(filepath.Join(workDir, filepath.FromSlash(triageOutputPath)))
+	if err != nil {
+		t.Fatalf("read dag: %v", err)
+	}
+	if strings.TrimSpace(string(dag)) != "{}" {
+		t.Fatalf("dag = %q, want {}", string(dag))
+	}
+}
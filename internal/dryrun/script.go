@@ -0,0 +1,193 @@
+// Package dryrun provides the scripted fake agent used by `governator start --dry-run`.
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/roles"
+)
+
+const (
+	localStateDirName = "_governator/_local-state"
+	// DefaultScriptPath is the repo-relative script used when --script is not supplied.
+	DefaultScriptPath = "_governator/dry-run.json"
+	// AgentCommand is the hidden CLI subcommand that runs the fake agent.
+	AgentCommand = "dry-run-agent"
+	// stateDirName holds per-task attempt counters for the fake agent.
+	stateDirName = "dry-run"
+)
+
+// Script describes how the fake agent behaves for each task and stage.
+type Script struct {
+	// Default applies when no rule matches.
+	Default Action `json:"default"`
+	// Rules are checked in order; the first match wins.
+	Rules []Rule `json:"rules"`
+}
+
+// Rule pairs match criteria with the action to perform.
+type Rule struct {
+	// Task matches a task ID or planning step ID. Empty or "*" matches any task.
+	Task string `json:"task"`
+	// Stage matches the worker stage (work, test, review, resolve). Empty matches any stage.
+	Stage string `json:"stage"`
+	// Attempt matches the 1-based invocation count for this task and stage. Zero matches any.
+	Attempt int `json:"attempt"`
+	Action
+}
+
+// Action describes what the fake agent does once a rule matches.
+type Action struct {
+	ExitCode     int          `json:"exit_code"`
+	DelaySeconds int          `json:"delay_seconds"`
+	Files        []FileAction `json:"files"`
+	BlockReason  string       `json:"block_reason"`
+}
+
+// FileAction writes, appends to, or deletes one file relative to the worker's directory.
+type FileAction struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	Append  bool   `json:"append"`
+	Delete  bool   `json:"delete"`
+}
+
+// Settings identifies the fake agent invocation wired into worker commands.
+type Settings struct {
+	Executable string
+	ScriptPath string
+	StateDir   string
+}
+
+// NewSettings resolves dry-run settings for a repository. An empty scriptPath selects
+// DefaultScriptPath; relative paths resolve against the repo root.
+func NewSettings(repoRoot string, executable string, scriptPath string) (Settings, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return Settings{}, errors.New("repo root is required")
+	}
+	if strings.TrimSpace(executable) == "" {
+		return Settings{}, errors.New("executable is required")
+	}
+	scriptPath = strings.TrimSpace(scriptPath)
+	if scriptPath == "" {
+		scriptPath = DefaultScriptPath
+	}
+	if !filepath.IsAbs(scriptPath) {
+		scriptPath = filepath.Join(repoRoot, scriptPath)
+	}
+	return Settings{
+		Executable: executable,
+		ScriptPath: scriptPath,
+		StateDir:   filepath.Join(repoRoot, localStateDirName, stateDirName),
+	}, nil
+}
+
+// Apply swaps every worker command in cfg for the fake agent. Role-specific commands and
+// CLI selections are cleared so no real agent can be chosen, and the sandbox is disabled
+// because the fake agent needs the governator binary and the script file.
+func (settings Settings) Apply(cfg config.Config) config.Config {
+	cfg.Workers.Commands.Default = []string{
+		settings.Executable,
+		AgentCommand,
+		"--script", settings.ScriptPath,
+		"--state", settings.StateDir,
+		"{prompt_path}",
+	}
+	cfg.Workers.Commands.Roles = map[string][]string{}
+	cfg.Workers.CLI.Default = ""
+	cfg.Workers.CLI.Roles = map[string]string{}
+	cfg.Sandbox = config.SandboxConfig{}
+	return cfg
+}
+
+// LoadScript reads and validates a dry-run script. A missing file yields an empty script,
+// so every step succeeds without touching files.
+func LoadScript(path string) (Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Script{}, nil
+		}
+		return Script{}, fmt.Errorf("read dry-run script %s: %w", path, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var script Script
+	if err := decoder.Decode(&script); err != nil {
+		return Script{}, fmt.Errorf("parse dry-run script %s: %w", path, err)
+	}
+	if err := validateAction("default", script.Default); err != nil {
+		return Script{}, fmt.Errorf("dry-run script %s: %w", path, err)
+	}
+	for i, rule := range script.Rules {
+		label := fmt.Sprintf("rules[%d]", i)
+		if rule.Stage != "" && !roles.Stage(rule.Stage).Valid() {
+			return Script{}, fmt.Errorf("dry-run script %s: %s stage %q is not one of work, test, review, resolve", path, label, rule.Stage)
+		}
+		if rule.Attempt < 0 {
+			return Script{}, fmt.Errorf("dry-run script %s: %s attempt must not be negative", path, label)
+		}
+		if err := validateAction(label, rule.Action); err != nil {
+			return Script{}, fmt.Errorf("dry-run script %s: %w", path, err)
+		}
+	}
+	return script, nil
+}
+
+// Match returns the action for a task, stage, and attempt.
+func (script Script) Match(task string, stage string, attempt int) Action {
+	for _, rule := range script.Rules {
+		if rule.Task != "" && rule.Task != "*" && rule.Task != task {
+			continue
+		}
+		if rule.Stage != "" && rule.Stage != stage {
+			continue
+		}
+		if rule.Attempt != 0 && rule.Attempt != attempt {
+			continue
+		}
+		return rule.Action
+	}
+	return script.Default
+}
+
+// validateAction checks exit codes, delays, and file paths for one action.
+func validateAction(label string, action Action) error {
+	if action.ExitCode < 0 || action.ExitCode > 255 {
+		return fmt.Errorf("%s exit_code must be between 0 and 255", label)
+	}
+	if action.DelaySeconds < 0 {
+		return fmt.Errorf("%s delay_seconds must not be negative", label)
+	}
+	for i, file := range action.Files {
+		if err := validateFilePath(file.Path); err != nil {
+			return fmt.Errorf("%s files[%d]: %w", label, i, err)
+		}
+		if file.Delete && (file.Append || file.Content != "") {
+			return fmt.Errorf("%s files[%d]: delete cannot be combined with content or append", label, i)
+		}
+	}
+	return nil
+}
+
+// validateFilePath requires a relative path that stays inside the worker directory.
+func validateFilePath(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return errors.New("path is required")
+	}
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("path %q must be relative", path)
+	}
+	cleaned := filepath.Clean(filepath.FromSlash(path))
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path %q escapes the worker directory", path)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Tests for dry-run script loading and matching.
+package dryrun
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+)
+
+// TestLoadScriptMatchesRulesInOrder ensures the first matching rule wins and defaults apply otherwise.
+func TestLoadScriptMatchesRulesInOrder(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "dry-run.json")
+	writeFile(t, path, `{
+  "default": {"exit_code": 0},
+  "rules": [
+    {"task": "T-001", "stage": "work", "attempt": 1, "exit_code": 3},
+    {"task": "T-001", "stage": "work", "delay_seconds": 2},
+    {"task": "*", "stage": "review", "block_reason": "needs a decision"}
+  ]
+}`)
+
+	script, err := LoadScript(path)
+	if err != nil {
+		t.Fatalf("LoadScript: %v", err)
+	}
+	if got := script.Match("T-001", "work", 1); got.ExitCode != 3 {
+		t.Fatalf("first attempt exit code = %d, want 3", got.ExitCode)
+	}
+	if got := script.Match("T-001", "work", 2); got.ExitCode != 0 || got.DelaySeconds != 2 {
+		t.Fatalf("second attempt = %+v, want delay 2 and exit 0", got)
+	}
+	if got := script.Match("T-009", "review", 1); got.BlockReason != "needs a decision" {
+		t.Fatalf("wildcard review = %+v, want block reason", got)
+	}
+	if got := script.Match("T-009", "test", 1); got.ExitCode != 0 || got.BlockReason != "" || len(got.Files) != 0 {
+		t.Fatalf("unmatched = %+v, want default action", got)
+	}
+}
+
+// TestLoadScriptMissingFileIsEmpty lets dry runs proceed without a script.
+func TestLoadScriptMissingFileIsEmpty(t *testing.T) {
+	t.Parallel()
+	script, err := LoadScript(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadScript: %v", err)
+	}
+	if len(script.Rules) != 0 {
+		t.Fatalf("rules = %v, want none", script.Rules)
+	}
+}
+
+// TestLoadScriptRejectsInvalidScripts reports the offending rule.
+func TestLoadScriptRejectsInvalidScripts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown field", content: `{"rules": [{"tsak": "T-001"}]}`, wantErr: "unknown field"},
+		{name: "unknown stage", content: `{"rules": [{"stage": "deploy"}]}`, wantErr: "rules[0] stage"},
+		{name: "exit code range", content: `{"default": {"exit_code": 300}}`, wantErr: "default exit_code"},
+		{name: "escaping path", content: `{"rules": [{"files": [{"path": "../outside"}]}]}`, wantErr: "escapes"},
+		{name: "absolute path", content: `{"rules": [{"files": [{"path": "/etc/passwd"}]}]}`, wantErr: "must be relative"},
+		{name: "delete with content", content: `{"rules": [{"files": [{"path": "a", "delete": true, "content": "x"}]}]}`, wantErr: "delete cannot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dry-run.json")
+			writeFile(t, path, tt.content)
+			_, err := LoadScript(path)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %q, want %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSettingsApplyReplacesEveryWorkerCommand ensures no real agent can be selected.
+func TestSettingsApplyReplacesEveryWorkerCommand(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+	settings, err := NewSettings(repoRoot, "/usr/local/bin/governator", "")
+	if err != nil {
+		t.Fatalf("NewSettings: %v", err)
+	}
+	if settings.ScriptPath != filepath.Join(repoRoot, DefaultScriptPath) {
+		t.Fatalf("script path = %q", settings.ScriptPath)
+	}
+
+	cfg := config.Defaults()
+	cfg.Workers.Commands.Roles = map[string][]string{"tester": {"real-agent", "{task_path}"}}
+	cfg.Workers.CLI.Roles = map[string]string{"reviewer": "claude"}
+	cfg.Sandbox.Default.Mode = "unshare"
+
+	applied := settings.Apply(cfg)
+	want := []string{"/usr/local/bin/governator", AgentCommand, "--script", settings.ScriptPath, "--state", settings.StateDir, "{prompt_path}"}
+	if strings.Join(applied.Workers.Commands.Default, " ") != strings.Join(want, " ") {
+		t.Fatalf("default command = %v, want %v", applied.Workers.Commands.Default, want)
+	}
+	if len(applied.Workers.Commands.Roles) != 0 || len(applied.Workers.CLI.Roles) != 0 || applied.Workers.CLI.Default != "" {
+		t.Fatalf("role overrides survived: %+v", applied.Workers)
+	}
+	if applied.Sandbox.PolicyForRole("tester").Enabled() {
+		t.Fatal("sandbox should be disabled for dry runs")
+	}
+}
+
+// writeFile writes content to path, creating parent directories.
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -18,22 +18,23 @@ type Digests struct {
 
 // Task captures a single task entry from the task index.
 type Task struct {
-	ID            string           `json:"id"`
-	Title         string           `json:"title,omitempty"`
-	Path          string           `json:"path"`
-	Kind          TaskKind         `json:"kind"`
-	State         TaskState        `json:"state"`
-	Role          Role             `json:"role"`
-	AssignedRole  string           `json:"assigned_role,omitempty"`
-	BlockedReason string           `json:"blocked,omitempty"`
-	MergeConflict bool             `json:"merge_conflict,omitempty"`
-	PID           int              `json:"pid,omitempty"`
-	Dependencies  []string         `json:"dependencies"`
-	Retries       RetryPolicy      `json:"retries"`
-	Attempts      AttemptCounters  `json:"attempts"`
-	Metrics       ExecutionMetrics `json:"metrics,omitempty"`
-	Order         int              `json:"order"`
-	Overlap       []string         `json:"overlap"`
+	ID             string           `json:"id"`
+	Title          string           `json:"title,omitempty"`
+	Path           string           `json:"path"`
+	Kind           TaskKind         `json:"kind"`
+	State          TaskState        `json:"state"`
+	Role           Role             `json:"role"`
+	AssignedRole   string           `json:"assigned_role,omitempty"`
+	BlockedReason  string           `json:"blocked,omitempty"`
+	MergeConflict  bool             `json:"merge_conflict,omitempty"`
+	PID            int              `json:"pid,omitempty"`
+	Dependencies   []string         `json:"dependencies"`
+	Retries        RetryPolicy      `json:"retries"`
+	Attempts       AttemptCounters  `json:"attempts"`
+	Metrics        ExecutionMetrics `json:"metrics,omitempty"`
+	Order          int              `json:"order"`
+	Overlap        []string         `json:"overlap"`
+	TimeoutSeconds int              `json:"timeout_seconds,omitempty"` // task front matter override of the worker timeout
 }
 
 // TaskState labels the lifecycle state for a task.
@@ -60,6 +61,8 @@ const (
 	TaskStateConflict TaskState = state.TaskStateConflict
 	// TaskStateResolved indicates a previously conflicted task has been resolved.
 	TaskStateResolved TaskState = state.TaskStateResolved
+	// TaskStateReverted indicates a merged task has been undone on the base branch.
+	TaskStateReverted TaskState = state.TaskStateReverted
 	// Backwards compatibility aliases
 	TaskStateOpen   TaskState = TaskStateTriaged
 	TaskStateWorked TaskState = TaskStateImplemented
@@ -86,8 +89,9 @@ type RetryPolicy struct {
 
 // AttemptCounters tracks how many attempts have been made.
 type AttemptCounters struct {
-	Total  int `json:"total"`
-	Failed int `json:"failed"`
+	Total       int `json:"total"`
+	Failed      int `json:"failed"`
+	RateLimited int `json:"rate_limited,omitempty"` // deferrals for provider rate limits; not failures
 }
 
 // ExecutionMetrics captures execution statistics for retrospective analysis.
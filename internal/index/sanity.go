@@ -77,4 +77,5 @@ var knownTaskStates = map[TaskState]struct{}{
 	TaskStateBlocked:     {},
 	TaskStateConflict:    {},
 	TaskStateResolved:    {},
+	TaskStateReverted:    {},
 }
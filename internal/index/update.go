@@ -112,6 +112,17 @@ func IncrementTaskFailedAttempt(idx *Index, taskID string) error {
 	return nil
 }
 
+// IncrementTaskRateLimitedAttempt counts a rate-limit deferral for a task without touching its
+// failed attempts.
+func IncrementTaskRateLimitedAttempt(idx *Index, taskID string) error {
+	task, err := findTaskByID(idx, taskID)
+	if err != nil {
+		return err
+	}
+	task.Attempts.RateLimited++
+	return nil
+}
+
 // transitionTaskState enforces lifecycle state transitions before updating a task.
 func transitionTaskState(idx *Index, taskID string, to TaskState) error {
 	return TransitionTaskStateWithAudit(idx, taskID, to, nil)
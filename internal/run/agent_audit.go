@@ -78,6 +78,15 @@ func statusFromIngestResult(result worker.IngestResult) string {
 	if result.TimedOut {
 		return "timeout"
 	}
+	if result.Stalled {
+		return "stalled"
+	}
+	if result.RateLimited {
+		return "rate_limited"
+	}
+	if result.ResourceLimit != "" {
+		return "resource_limit"
+	}
 	if result.Success {
 		return "success"
 	}
@@ -3,11 +3,14 @@ package run
 
 import (
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/cmtonkinson/governator/internal/audit"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/scheduler"
+	"github.com/cmtonkinson/governator/internal/worker"
 )
 
 // inFlightMap converts the in-flight set to a scheduler-friendly lookup.
@@ -111,3 +114,93 @@ func worktreePathForTask(set inflight.Set, taskID string) (string, bool) {
 func formatTimeoutReason(timeoutSecs int) string {
 	return fmt.Sprintf("worker timed out after %d seconds", timeoutSecs)
 }
+
+// formatStallReason produces a consistent stall message.
+func formatStallReason(stallSecs int) string {
+	return fmt.Sprintf("worker stalled: no output or worktree changes for %d seconds", stallSecs)
+}
+
+// workerExpiry describes why an unfinished worker must be stopped.
+type workerExpiry struct {
+	stalled      bool
+	seconds      int
+	lastActivity time.Time
+	reason       string
+}
+
+// kind names the expiry for warnings and kill diagnostics.
+func (expiry workerExpiry) kind() string {
+	if expiry.stalled {
+		return "stall"
+	}
+	return "timeout"
+}
+
+// checkWorkerExpiry reports whether an unfinished worker hit its hard timeout or stall limit.
+func checkWorkerExpiry(set inflight.Set, taskID string, workerStateDir string, worktreePath string, timeoutSecs int, stallSecs int, warn func(string)) (workerExpiry, bool) {
+	startedAt, ok := startedAtForTask(set, taskID)
+	if !ok {
+		return workerExpiry{}, false
+	}
+	if timedOut(startedAt, timeoutSecs) {
+		return workerExpiry{
+			seconds: timeoutSecs,
+			reason:  formatTimeoutReason(timeoutSecs),
+		}, true
+	}
+	if lastActivity, stalled := workerStalled(startedAt, workerStateDir, worktreePath, stallSecs, warn); stalled {
+		return workerExpiry{
+			stalled:      true,
+			seconds:      stallSecs,
+			lastActivity: lastActivity,
+			reason:       formatStallReason(stallSecs),
+		}, true
+	}
+	return workerExpiry{}, false
+}
+
+// workerStalled reports whether neither the worker logs nor its worktree changed within stallSecs.
+func workerStalled(startedAt time.Time, workerStateDir string, worktreePath string, stallSecs int, warn func(string)) (time.Time, bool) {
+	if stallSecs <= 0 || startedAt.IsZero() {
+		return time.Time{}, false
+	}
+	cutoff := time.Now().Add(-time.Duration(stallSecs) * time.Second)
+	if startedAt.After(cutoff) {
+		return time.Time{}, false
+	}
+	lastActivity, err := worker.LastActivity(workerStateDir, worktreePath, cutoff)
+	if err != nil {
+		// An unreadable worktree is not evidence of a hang; leave it to the hard timeout.
+		if warn != nil {
+			warn(fmt.Sprintf("failed to check worker activity: %v", err))
+		}
+		return time.Time{}, false
+	}
+	if lastActivity.After(cutoff) {
+		return time.Time{}, false
+	}
+	if lastActivity.Before(startedAt) {
+		lastActivity = startedAt
+	}
+	return lastActivity, true
+}
+
+// emitWorkerExpiry reports a timed-out or stalled worker on the run output.
+func emitWorkerExpiry(out io.Writer, taskID string, role string, stage string, expiry workerExpiry) {
+	if expiry.stalled {
+		emitTaskStalled(out, taskID, role, stage, expiry.reason, expiry.seconds)
+		return
+	}
+	emitTaskTimeout(out, taskID, role, stage, expiry.reason, expiry.seconds)
+}
+
+// logWorkerExpiry records the matching audit event for a timed-out or stalled worker.
+func logWorkerExpiry(auditor *audit.Logger, taskID string, role string, worktreePath string, expiry workerExpiry) error {
+	if auditor == nil {
+		return nil
+	}
+	if expiry.stalled {
+		return auditor.LogWorkerStalled(taskID, role, expiry.seconds, expiry.lastActivity, worktreePath)
+	}
+	return auditor.LogWorkerTimeout(taskID, role, expiry.seconds, worktreePath)
+}
@@ -0,0 +1,90 @@
+// Package run provides templated commit messages with provenance trailers.
+package run
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// Provenance trailers recorded at the end of Governator commit messages.
+const (
+	trailerTask    = "Governator-Task"
+	trailerRole    = "Governator-Role"
+	trailerStage   = "Governator-Stage"
+	trailerAttempt = "Governator-Attempt"
+	trailerCLI     = "Governator-CLI"
+	trailerTokens  = "Governator-Tokens"
+	// mergeStageTrailer marks squash and merge commits in the Governator-Stage trailer.
+	mergeStageTrailer = "merge"
+)
+
+// commitProvenance identifies the worker run behind a stage commit, how to word it, and who
+// authors it.
+type commitProvenance struct {
+	Role     index.Role
+	CLI      string
+	Commits  config.CommitsConfig
+	Identity config.GitIdentity
+}
+
+// newCommitProvenance describes a stage commit made for a worker running as role.
+func newCommitProvenance(cfg config.Config, role index.Role) commitProvenance {
+	return commitProvenance{
+		Role:     role,
+		CLI:      worker.CLIForRole(cfg, role),
+		Commits:  cfg.Commits,
+		Identity: cfg.Git.IdentityForRole(string(role)),
+	}
+}
+
+// renderCommitMessage renders text, or fallback when text is empty, against data and appends
+// the provenance trailers unless they are disabled.
+func renderCommitMessage(text string, fallback string, data config.CommitTemplateData, disableTrailers bool) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	parsed, err := template.New("commit").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse commit template: %w", err)
+	}
+	var builder strings.Builder
+	if err := parsed.Execute(&builder, data); err != nil {
+		return "", fmt.Errorf("render commit template: %w", err)
+	}
+	message := strings.TrimSpace(builder.String())
+	if message == "" {
+		return "", errors.New("commit template rendered an empty message")
+	}
+	if !disableTrailers {
+		message += "\n\n" + commitTrailers(data)
+	}
+	return message + "\n", nil
+}
+
+// commitTrailers formats the Governator-* trailers for a commit, skipping unknown values.
+func commitTrailers(data config.CommitTemplateData) string {
+	lines := []string{trailerTask + ": " + data.TaskID}
+	if data.Role != "" {
+		lines = append(lines, trailerRole+": "+data.Role)
+	}
+	if data.Stage != "" {
+		lines = append(lines, trailerStage+": "+data.Stage)
+	}
+	if data.Attempt > 0 {
+		lines = append(lines, trailerAttempt+": "+strconv.Itoa(data.Attempt))
+	}
+	if data.CLI != "" {
+		lines = append(lines, trailerCLI+": "+data.CLI)
+	}
+	if data.Tokens > 0 {
+		lines = append(lines, trailerTokens+": "+strconv.Itoa(data.Tokens))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,66 @@
+// Tests for templated commit messages and provenance trailers.
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+)
+
+// TestRenderCommitMessage renders custom and default templates with and without trailers.
+func TestRenderCommitMessage(t *testing.T) {
+	data := config.CommitTemplateData{
+		TaskID:  "T-100",
+		Title:   "Add login",
+		Role:    "backend",
+		Stage:   "work",
+		State:   "implemented",
+		Attempt: 2,
+		CLI:     "claude",
+		Tokens:  1234,
+		Log:     "did the work",
+	}
+
+	message, err := renderCommitMessage("feat({{.TaskID}}): {{.Title}}", config.DefaultStageCommitTemplate, data, false)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "feat(T-100): Add login\n\n" +
+		"Governator-Task: T-100\n" +
+		"Governator-Role: backend\n" +
+		"Governator-Stage: work\n" +
+		"Governator-Attempt: 2\n" +
+		"Governator-CLI: claude\n" +
+		"Governator-Tokens: 1234\n"
+	if message != want {
+		t.Fatalf("message = %q, want %q", message, want)
+	}
+
+	message, err = renderCommitMessage("", config.DefaultStageCommitTemplate, data, true)
+	if err != nil {
+		t.Fatalf("render default: %v", err)
+	}
+	if message != "[implemented] Add login\n\ndid the work\n" {
+		t.Fatalf("default message = %q", message)
+	}
+}
+
+// TestRenderCommitMessageOmitsUnknownTrailers leaves out trailers without a value.
+func TestRenderCommitMessageOmitsUnknownTrailers(t *testing.T) {
+	message, err := renderCommitMessage(config.DefaultMergeCommitTemplate, "", config.CommitTemplateData{TaskID: "T-7", Title: "Docs", Stage: mergeStageTrailer}, false)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if message != "governator: T-7 - Docs\n\nGovernator-Task: T-7\nGovernator-Stage: merge\n" {
+		t.Fatalf("message = %q", message)
+	}
+}
+
+// TestRenderCommitMessageRejectsEmptyOutput refuses templates that render nothing.
+func TestRenderCommitMessageRejectsEmptyOutput(t *testing.T) {
+	_, err := renderCommitMessage("{{if .CLI}}{{.CLI}}{{end}}", "", config.CommitTemplateData{TaskID: "T-1"}, false)
+	if err == nil || !strings.Contains(err.Error(), "empty message") {
+		t.Fatalf("error = %v, want empty message error", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Package run provides dependency summaries for downstream worker prompts.
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// dependencyContextFileName is the generated work-stage dependency summary in the worker state dir.
+const dependencyContextFileName = "dependency-context.md"
+
+// configureDependencyContextStageInput adds a prompt summarizing the task's merged
+// dependencies to the stage input as a generated context prompt, so the prompt budget
+// accounts for it. Tasks without merged dependencies are left unchanged.
+func configureDependencyContextStageInput(repoRoot string, tasks []index.Task, task index.Task, cfg config.Config, stageInput *worker.StageInput) error {
+	if stageInput == nil {
+		return fmt.Errorf("stage input is required")
+	}
+	content, err := dependencyContextContent(repoRoot, tasks, task, cfg)
+	if err != nil || content == "" {
+		return err
+	}
+	contextPath, err := workerContextPromptPath(repoRoot, stageInput.WorkerStateDir, dependencyContextFileName)
+	if err != nil {
+		return err
+	}
+	stageInput.ContextPrompts = append(stageInput.ContextPrompts, worker.PromptSection{
+		Path:    contextPath,
+		Kind:    config.PromptSectionContext,
+		Content: content,
+	})
+	return nil
+}
+
+// dependencyContextContent renders the dependency summary for task, or an empty string
+// when none of its dependencies have merged.
+func dependencyContextContent(repoRoot string, tasks []index.Task, task index.Task, cfg config.Config) (string, error) {
+	merged := mergedDependencies(tasks, task)
+	if len(merged) == 0 {
+		return "", nil
+	}
+	base := baseBranchName(cfg)
+	builder := &strings.Builder{}
+	builder.WriteString("# Dependency Context\n")
+	fmt.Fprintf(builder, "This task depends on work that is already merged into `%s` and present in your worktree.\n", base)
+	builder.WriteString("Build on these changes instead of re-implementing them.\n")
+	for _, dependency := range merged {
+		fmt.Fprintf(builder, "\n## %s: %s\n", dependency.ID, dependency.Title)
+		commit, baseCommit, err := findTaskMerge(repoRoot, base, dependency)
+		if err != nil {
+			return "", err
+		}
+		if commit == "" {
+			fmt.Fprintf(builder, "- Merge commit: not found on `%s`\n", base)
+			continue
+		}
+		fmt.Fprintf(builder, "- Merge commit: `%s`\n", commit)
+		stat, err := runGitOutput(repoRoot, "diff", "--stat", baseCommit, commit)
+		if err != nil {
+			return "", fmt.Errorf("summarize merge commit %s for %s: %w", commit, dependency.ID, err)
+		}
+		if stat = strings.TrimRight(stat, "\n"); stat != "" {
+			fmt.Fprintf(builder, "- Files changed:\n\n```\n%s\n```\n", stat)
+		}
+		if limit := cfg.DependencyContext.DiffMaxBytes; limit > 0 {
+			diff, err := runGitOutput(repoRoot, "diff", baseCommit, commit)
+			if err != nil {
+				return "", fmt.Errorf("diff merge commit %s for %s: %w", commit, dependency.ID, err)
+			}
+			diff, truncated := truncateBytes(strings.TrimRight(diff, "\n"), limit)
+			if diff == "" {
+				continue
+			}
+			label := "- Diff:"
+			if truncated {
+				label = fmt.Sprintf("- Diff (truncated to %d bytes):", limit)
+			}
+			fmt.Fprintf(builder, "%s\n\n```diff\n%s\n```\n", label, diff)
+		}
+	}
+	return builder.String(), nil
+}
+
+// mergedDependencies returns the task's dependencies that have merged, in dependency order.
+func mergedDependencies(tasks []index.Task, task index.Task) []index.Task {
+	byID := make(map[string]index.Task, len(tasks))
+	for _, candidate := range tasks {
+		byID[candidate.ID] = candidate
+	}
+	merged := make([]index.Task, 0, len(task.Dependencies))
+	for _, id := range task.Dependencies {
+		dependency, ok := byID[id]
+		if !ok || dependency.State != index.TaskStateMerged {
+			continue
+		}
+		merged = append(merged, dependency)
+	}
+	return merged
+}
+
+// findTaskMerge returns the commit a task's merge produced and the base commit it landed on,
+// or empty strings when neither the audit log nor base records the merge. The audit log's
+// merge.commit entry is preferred because it covers every merge strategy, including rebase
+// merges whose commits carry no merge trailers.
+func findTaskMerge(repoRoot string, base string, task index.Task) (string, string, error) {
+	fields, ok, err := audit.LastTaskEvent(repoRoot, task.ID, audit.EventMergeCommit)
+	if err != nil {
+		return "", "", err
+	}
+	if ok && fields["commit"] != "" && fields["base"] != "" {
+		return fields["commit"], fields["base"], nil
+	}
+	commit, err := findMergeCommit(repoRoot, base, task)
+	if err != nil || commit == "" {
+		return "", "", err
+	}
+	parent, err := runGitOutput(repoRoot, "rev-parse", commit+"^1")
+	if err != nil {
+		return "", "", err
+	}
+	return commit, strings.TrimSpace(parent), nil
+}
+
+// findMergeCommit returns the most recent commit on base that merged the dependency, or an
+// empty string when none exists. Merge commits are found by their Governator-Task and
+// Governator-Stage trailers, falling back to the built-in merge subject for commits made
+// without trailers.
+func findMergeCommit(repoRoot string, base string, dependency index.Task) (string, error) {
+	output, err := runGitOutput(repoRoot, "log", "-1", "--format=%H", "--extended-regexp", "--all-match",
+		"--grep", "^"+trailerTask+": "+regexp.QuoteMeta(dependency.ID)+"$",
+		"--grep", "^"+trailerStage+": "+mergeStageTrailer+"$",
+		base)
+	if err != nil {
+		return "", fmt.Errorf("find merge commit for %s: %w", dependency.ID, err)
+	}
+	if commit := strings.TrimSpace(output); commit != "" {
+		return commit, nil
+	}
+	output, err = runGitOutput(repoRoot, "log", "-1", "--format=%H", "--fixed-strings", "--grep", mergeCommitSubject(dependency), base)
+	if err != nil {
+		return "", fmt.Errorf("find merge commit for %s: %w", dependency.ID, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// truncateBytes shortens value to at most limit bytes on a line boundary when possible.
+func truncateBytes(value string, limit int) (string, bool) {
+	if len(value) <= limit {
+		return value, false
+	}
+	cut := value[:limit]
+	if newline := strings.LastIndex(cut, "\n"); newline > 0 {
+		cut = cut[:newline]
+	}
+	return cut, true
+}
+
+// writeWorkerContextPrompt writes a generated context prompt into the worker state dir and
+// returns its repo-relative path when it lives under repoRoot.
+func writeWorkerContextPrompt(repoRoot string, workerStateDir string, fileName string, content string) (string, error) {
+	contextPath, err := workerContextPromptPath(repoRoot, workerStateDir, fileName)
+	if err != nil {
+		return "", err
+	}
+	absoluteContextPath := contextPath
+	if !filepath.IsAbs(absoluteContextPath) {
+		absoluteRepoRoot, err := filepath.Abs(repoRoot)
+		if err != nil {
+			return "", fmt.Errorf("resolve repo root %s: %w", repoRoot, err)
+		}
+		absoluteContextPath = filepath.Join(absoluteRepoRoot, filepath.FromSlash(contextPath))
+	}
+	if err := os.MkdirAll(filepath.Dir(absoluteContextPath), 0o755); err != nil {
+		return "", fmt.Errorf("create worker state dir %s: %w", filepath.Dir(absoluteContextPath), err)
+	}
+	if err := os.WriteFile(absoluteContextPath, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("write context prompt %s: %w", absoluteContextPath, err)
+	}
+	return contextPath, nil
+}
+
+// workerContextPromptPath returns where a generated context prompt lives in the worker state
+// dir: repo-relative when it is under repoRoot, absolute otherwise.
+func workerContextPromptPath(repoRoot string, workerStateDir string, fileName string) (string, error) {
+	if strings.TrimSpace(workerStateDir) == "" {
+		return "", fmt.Errorf("worker state dir is required")
+	}
+	absoluteWorkerStateDir, err := filepath.Abs(workerStateDir)
+	if err != nil {
+		return "", fmt.Errorf("resolve worker state dir %s: %w", workerStateDir, err)
+	}
+	contextPath := filepath.Join(absoluteWorkerStateDir, fileName)
+
+	absoluteRepoRoot, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("resolve repo root %s: %w", repoRoot, err)
+	}
+	relativePath, err := filepath.Rel(absoluteRepoRoot, contextPath)
+	if err != nil {
+		return contextPath, nil
+	}
+	if strings.HasPrefix(relativePath, "..") {
+		return contextPath, nil
+	}
+	return filepath.ToSlash(relativePath), nil
+}
@@ -0,0 +1,140 @@
+// Tests for merged dependency summaries in work prompts.
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// commitMergedDependency records a commit the way the merge flow does for task.
+func commitMergedDependency(t *testing.T, repo *testrepos.TempRepo, task index.Task, file string, content string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo.Root, file), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", file, err)
+	}
+	repo.RunGit(t, "add", file)
+	repo.RunGit(t, "commit", "-m", mergeCommitSubject(task))
+	return strings.TrimSpace(repo.RunGit(t, "rev-parse", "HEAD"))
+}
+
+// TestDependencyContextSummarizesMergedDependencies lists merged dependencies with their commits.
+func TestDependencyContextSummarizesMergedDependencies(t *testing.T) {
+	t.Parallel()
+	repo := testrepos.New(t)
+	storage := index.Task{ID: "010-storage", Title: "Add storage layer", State: index.TaskStateMerged}
+	api := index.Task{ID: "020-api", Title: "Add API", State: index.TaskStateTriaged}
+	missing := index.Task{ID: "030-lost", Title: "Lost history", State: index.TaskStateMerged}
+	storageCommit := commitMergedDependency(t, repo, storage, "storage.go", "package storage\n\nfunc Open() {}\n")
+	task := index.Task{ID: "040-ui", Title: "Add UI", Dependencies: []string{"010-storage", "020-api", "030-lost"}}
+	tasks := []index.Task{storage, api, missing, task}
+
+	content, err := dependencyContextContent(repo.Root, tasks, task, config.Config{Branches: config.BranchConfig{Base: "main"}})
+	if err != nil {
+		t.Fatalf("dependencyContextContent: %v", err)
+	}
+	for _, want := range []string{
+		"## 010-storage: Add storage layer",
+		"- Merge commit: `" + storageCommit + "`",
+		"storage.go | 3 +++",
+		"## 030-lost: Lost history",
+		"- Merge commit: not found on `main`",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("context missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "020-api") {
+		t.Fatalf("unmerged dependency included:\n%s", content)
+	}
+	if strings.Contains(content, "```diff") {
+		t.Fatalf("diff included without diff_max_bytes:\n%s", content)
+	}
+
+	cfg := config.Config{DependencyContext: config.DependencyContextConfig{DiffMaxBytes: 60}}
+	content, err = dependencyContextContent(repo.Root, tasks, task, cfg)
+	if err != nil {
+		t.Fatalf("dependencyContextContent with diff: %v", err)
+	}
+	if !strings.Contains(content, "- Diff (truncated to 60 bytes):") || !strings.Contains(content, "diff --git a/storage.go b/storage.go") {
+		t.Fatalf("context missing truncated diff:\n%s", content)
+	}
+	if strings.Contains(content, "func Open") {
+		t.Fatalf("diff was not truncated:\n%s", content)
+	}
+}
+
+// TestDependencyContextUsesAuditedRebaseMerge finds rebase merges, which carry no merge
+// trailer, through the audit log and summarizes every commit they added.
+func TestDependencyContextUsesAuditedRebaseMerge(t *testing.T) {
+	t.Parallel()
+	repo := testrepos.New(t)
+	storage := index.Task{ID: "010-storage", Title: "Add storage layer", State: index.TaskStateMerged}
+	base := strings.TrimSpace(repo.RunGit(t, "rev-parse", "HEAD"))
+	for _, file := range []string{"storage.go", "schema.sql"} {
+		if err := os.WriteFile(filepath.Join(repo.Root, file), []byte("content\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+		repo.RunGit(t, "add", file)
+		repo.RunGit(t, "commit", "-m", "Add "+file)
+	}
+	head := strings.TrimSpace(repo.RunGit(t, "rev-parse", "HEAD"))
+	auditor, err := audit.NewLogger(repo.Root, os.Stderr)
+	if err != nil {
+		t.Fatalf("new audit logger: %v", err)
+	}
+	if err := auditor.LogMergeCommit(storage.ID, "default", head, base, "rebase"); err != nil {
+		t.Fatalf("log merge commit: %v", err)
+	}
+	task := index.Task{ID: "040-ui", Title: "Add UI", Dependencies: []string{"010-storage"}}
+
+	content, err := dependencyContextContent(repo.Root, []index.Task{storage, task}, task, config.Config{Branches: config.BranchConfig{Base: "main"}})
+	if err != nil {
+		t.Fatalf("dependencyContextContent: %v", err)
+	}
+	for _, want := range []string{"- Merge commit: `" + head + "`", "storage.go", "schema.sql"} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("context missing %q:\n%s", want, content)
+		}
+	}
+}
+
+// TestConfigureDependencyContextStageInput adds the summary as a context prompt only when needed.
+func TestConfigureDependencyContextStageInput(t *testing.T) {
+	t.Parallel()
+	repo := testrepos.New(t)
+	storage := index.Task{ID: "010-storage", Title: "Add storage layer", State: index.TaskStateMerged}
+	commitMergedDependency(t, repo, storage, "storage.go", "package storage\n")
+	stateDir := filepath.Join(repo.Root, "_governator", "_local-state", "worker-1-work-default")
+
+	independent := index.Task{ID: "020-docs", Title: "Docs"}
+	stageInput := worker.StageInput{WorkerStateDir: stateDir}
+	if err := configureDependencyContextStageInput(repo.Root, []index.Task{storage, independent}, independent, config.Config{}, &stageInput); err != nil {
+		t.Fatalf("configure independent task: %v", err)
+	}
+	if len(stageInput.ContextPrompts) != 0 {
+		t.Fatalf("independent task got context prompts: %v", stageInput.ContextPrompts)
+	}
+
+	dependent := index.Task{ID: "030-api", Title: "API", Dependencies: []string{"010-storage"}}
+	if err := configureDependencyContextStageInput(repo.Root, []index.Task{storage, dependent}, dependent, config.Config{}, &stageInput); err != nil {
+		t.Fatalf("configure dependent task: %v", err)
+	}
+	want := "_governator/_local-state/worker-1-work-default/" + dependencyContextFileName
+	if len(stageInput.ContextPrompts) != 1 || stageInput.ContextPrompts[0].Path != want {
+		t.Fatalf("context prompts = %v, want [%s]", stageInput.ContextPrompts, want)
+	}
+	if !strings.Contains(stageInput.ContextPrompts[0].Content, "## 010-storage: Add storage layer") {
+		t.Fatalf("dependency context = %q", stageInput.ContextPrompts[0].Content)
+	}
+	if len(stageInput.ExtraPromptPath) != 0 {
+		t.Fatalf("extra prompts = %v, want none", stageInput.ExtraPromptPath)
+	}
+}
@@ -4,37 +4,65 @@ package run
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/digests"
 	"github.com/cmtonkinson/governator/internal/index"
 )
 
+// planningDriftFallbackStepID is where planning restarts after drift when the spec declares no
+// step inputs to route changed paths by.
+const planningDriftFallbackStepID = "gap-analysis"
+
 // ErrPlanningDrift indicates stored planning digests no longer match the repo state.
 var ErrPlanningDrift = errors.New("planning drift detected")
 
 // PlanningDriftReport summarizes planning digest drift detected since the last refresh.
 type PlanningDriftReport struct {
-	HasDrift bool
-	Details  []string
-	Message  string
+	HasDrift     bool
+	Details      []string
+	Message      string
+	ChangedPaths []string
 }
 
-// DetectPlanningDrift reports planning digest drift relative to stored digests.
-func DetectPlanningDrift(repoRoot string, stored index.Digests) (PlanningDriftReport, error) {
-	report, err := digests.Detect(repoRoot, stored)
+// DetectPlanningDrift reports planning digest drift relative to stored digests, scoped by the
+// drift policy in cfg.
+func DetectPlanningDrift(repoRoot string, cfg config.Config, stored index.Digests) (PlanningDriftReport, error) {
+	report, err := digests.DetectWithPolicy(repoRoot, stored, planningDigestPolicy(cfg))
 	if err != nil {
 		return PlanningDriftReport{}, fmt.Errorf("detect planning drift: %w", err)
 	}
 	return PlanningDriftReport{
-		HasDrift: report.HasDrift,
-		Details:  report.Details,
-		Message:  report.Message,
+		HasDrift:     report.HasDrift,
+		Details:      report.Details,
+		Message:      report.Message,
+		ChangedPaths: report.ChangedPaths,
 	}, nil
 }
 
+// planningDigestPolicy translates the drift config into the digest policy.
+func planningDigestPolicy(cfg config.Config) digests.Policy {
+	return digests.Policy{
+		Include:           cfg.Drift.Include,
+		Exclude:           cfg.Drift.Exclude,
+		NormalizeMarkdown: cfg.Drift.NormalizeMarkdown,
+	}
+}
+
+// computePlanningDigests computes the planning digests under root using the drift policy
+// configured there.
+func computePlanningDigests(root string) (index.Digests, error) {
+	cfg, err := config.Load(root, nil, nil)
+	if err != nil {
+		return index.Digests{}, fmt.Errorf("load config: %w", err)
+	}
+	return digests.ComputeWithPolicy(root, planningDigestPolicy(cfg))
+}
+
 // CheckPlanningDrift stops a run when planning digests changed since planning.
-func CheckPlanningDrift(repoRoot string, stored index.Digests) error {
-	report, err := DetectPlanningDrift(repoRoot, stored)
+func CheckPlanningDrift(repoRoot string, cfg config.Config, stored index.Digests) error {
+	report, err := DetectPlanningDrift(repoRoot, cfg, stored)
 	if err != nil {
 		return fmt.Errorf("detect planning drift: %w", err)
 	}
@@ -45,3 +73,97 @@ func CheckPlanningDrift(repoRoot string, stored index.Digests) error {
 	message := fmt.Sprintf("%s\nNext steps: rerun `governator start` to regenerate planning artifacts and the task index.", report.Message)
 	return fmt.Errorf("%w: %s", ErrPlanningDrift, message)
 }
+
+// planningReplanStep picks the step planning restarts from after drift in the changed paths.
+// Each path restarts at the earliest step that lists it as an input. A path that only appears
+// as some step's output needs no restart, and a path no step declares restarts at the first
+// step, as does drift with no recorded paths. A spec that declares no inputs at all restarts
+// at the gap-analysis step, or the first step when it has none. It reports false when no
+// restart is needed, including when planning is still running at or before the chosen step.
+func planningReplanStep(planning planningTask, currentStateID string, changed []string) (string, bool) {
+	if len(planning.ordered) == 0 {
+		return "", false
+	}
+	start := planningDriftFallbackPosition(planning)
+	if planningDeclaresInputs(planning) {
+		start = len(planning.ordered)
+		if len(changed) == 0 {
+			start = 0
+		}
+		for _, changedPath := range changed {
+			if pos := planningReplanPosition(planning, changedPath); pos < start {
+				start = pos
+			}
+		}
+	}
+	if start == len(planning.ordered) {
+		return "", false
+	}
+	currentStateID = strings.TrimSpace(currentStateID)
+	if currentStateID == PlanningNotStartedState {
+		return "", false
+	}
+	for i := 0; i <= start; i++ {
+		if planning.ordered[i].name == currentStateID {
+			return "", false
+		}
+	}
+	return planning.ordered[start].name, true
+}
+
+// planningDeclaresInputs reports whether any planning step lists its inputs.
+func planningDeclaresInputs(planning planningTask) bool {
+	for _, step := range planning.ordered {
+		if len(step.inputs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// planningDriftFallbackPosition returns the index of the gap-analysis step, or 0 when the spec
+// has no such step.
+func planningDriftFallbackPosition(planning planningTask) int {
+	for i, step := range planning.ordered {
+		if step.name == planningDriftFallbackStepID {
+			return i
+		}
+	}
+	return 0
+}
+
+// planningReplanPosition returns the index of the step a changed path restarts planning at, or
+// len(planning.ordered) when the path needs no restart.
+func planningReplanPosition(planning planningTask, changedPath string) int {
+	produced := false
+	for i, step := range planning.ordered {
+		for _, input := range step.inputs {
+			if planningArtifactMatches(input, changedPath) {
+				return i
+			}
+		}
+		for _, output := range step.outputs {
+			if planningArtifactMatches(output, changedPath) {
+				produced = true
+			}
+		}
+	}
+	if produced {
+		return len(planning.ordered)
+	}
+	return 0
+}
+
+// replanAfterDrift restarts planning from the step the drifted paths call for, or only refreshes
+// the stored digests when no step needs to run again. It returns the restart step id, if any.
+func replanAfterDrift(repoRoot string, idx index.Index, planning planningTask, changed []string) (string, error) {
+	stateID, err := planningTaskState(idx)
+	if err != nil {
+		return "", fmt.Errorf("planning index: %w", err)
+	}
+	stepID, restart := planningReplanStep(planning, stateID, changed)
+	if !restart {
+		return "", refreshPlanningDigests(repoRoot, nil)
+	}
+	return stepID, ResetPlanningToStep(repoRoot, stepID)
+}
@@ -8,7 +8,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/digests"
+	"github.com/cmtonkinson/governator/internal/templates"
 )
 
 // TestCheckPlanningDriftNoChanges ensures clean repos pass the drift check.
@@ -23,7 +25,7 @@ func TestCheckPlanningDriftNoChanges(t *testing.T) {
 		t.Fatalf("Compute error: %v", err)
 	}
 
-	if err := CheckPlanningDrift(root, stored); err != nil {
+	if err := CheckPlanningDrift(root, config.Defaults(), stored); err != nil {
 		t.Fatalf("unexpected drift error: %v", err)
 	}
 }
@@ -45,7 +47,7 @@ func TestCheckPlanningDriftDetectsPlanningDocChange(t *testing.T) {
 		t.Fatalf("update roadmap: %v", err)
 	}
 
-	err = CheckPlanningDrift(root, stored)
+	err = CheckPlanningDrift(root, config.Defaults(), stored)
 	assertPlanningDriftError(t, err)
 	assertErrorContains(t, err, "planning doc changed: _governator/docs/roadmap.md")
 }
@@ -67,7 +69,7 @@ func TestCheckPlanningDriftDetectsPlanningDocDeletion(t *testing.T) {
 		t.Fatalf("remove roadmap: %v", err)
 	}
 
-	err = CheckPlanningDrift(root, stored)
+	err = CheckPlanningDrift(root, config.Defaults(), stored)
 	assertPlanningDriftError(t, err)
 	assertErrorContains(t, err, "planning doc missing: _governator/docs/roadmap.md")
 }
@@ -88,7 +90,7 @@ func TestCheckPlanningDriftDetectsNonADRDocAddition(t *testing.T) {
 		t.Fatalf("write planning doc: %v", err)
 	}
 
-	err = CheckPlanningDrift(root, stored)
+	err = CheckPlanningDrift(root, config.Defaults(), stored)
 	assertPlanningDriftError(t, err)
 	assertErrorContains(t, err, "planning doc added: _governator/docs/implementation-plan.md")
 }
@@ -126,3 +128,82 @@ func writeRepoFixture(root string) error {
 	}
 	return nil
 }
+
+// TestPlanningReplanStep ensures drift restarts the default pipeline from the earliest step that consumes it.
+func TestPlanningReplanStep(t *testing.T) {
+	data, err := templates.Read("planning/planning.json")
+	if err != nil {
+		t.Fatalf("read planning spec template: %v", err)
+	}
+	spec, err := ParsePlanningSpec(data)
+	if err != nil {
+		t.Fatalf("parse planning spec: %v", err)
+	}
+	planning, err := planningTaskFromSpec(spec)
+	if err != nil {
+		t.Fatalf("planning task: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		state       string
+		changed     []string
+		wantStep    string
+		wantRestart bool
+	}{
+		{name: "governator_md", state: PlanningCompleteState, changed: []string{"GOVERNATOR.md"}, wantStep: "architecture-baseline", wantRestart: true},
+		{name: "new_adr", state: PlanningCompleteState, changed: []string{"_governator/docs/adr/adr-0002.md"}, wantStep: "gap-analysis", wantRestart: true},
+		{name: "edited_epics", state: PlanningCompleteState, changed: []string{"_governator/docs/epics.md"}, wantStep: "task-planning", wantRestart: true},
+		{name: "earliest_wins", state: PlanningCompleteState, changed: []string{"_governator/docs/epics.md", "_governator/docs/gap-register.md"}, wantStep: "project-planning", wantRestart: true},
+		{name: "undeclared_path", state: PlanningCompleteState, changed: []string{"_governator/docs/notes.md"}, wantStep: "architecture-baseline", wantRestart: true},
+		{name: "no_paths", state: PlanningCompleteState, wantStep: "architecture-baseline", wantRestart: true},
+		{name: "planning_not_yet_there", state: "gap-analysis", changed: []string{"_governator/docs/epics.md"}},
+		{name: "planning_past_it", state: "task-planning", changed: []string{"_governator/docs/adr/adr-0002.md"}, wantStep: "gap-analysis", wantRestart: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, restart := planningReplanStep(planning, tt.state, tt.changed)
+			if step != tt.wantStep || restart != tt.wantRestart {
+				t.Fatalf("planningReplanStep = %q, %v; want %q, %v", step, restart, tt.wantStep, tt.wantRestart)
+			}
+		})
+	}
+}
+
+// TestPlanningReplanStepOutputOnly ensures a path only produced by a step, and consumed by none, needs no restart.
+func TestPlanningReplanStepOutputOnly(t *testing.T) {
+	planning, err := planningTaskFromSpec(PlanningSpec{
+		Version: planningSpecVersion,
+		Steps: []PlanningStepSpec{
+			{ID: "design", Name: "Design", Prompt: "p.md", Role: "architect", Inputs: []string{"GOVERNATOR.md"}, Outputs: []string{"_governator/docs/design.md"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("planning task: %v", err)
+	}
+	if step, restart := planningReplanStep(planning, PlanningCompleteState, []string{"_governator/docs/design.md"}); restart {
+		t.Fatalf("expected no restart, got %q", step)
+	}
+}
+
+// TestPlanningReplanStepWithoutInputs ensures a spec that declares no inputs keeps restarting
+// drift replans at gap analysis.
+func TestPlanningReplanStepWithoutInputs(t *testing.T) {
+	planning, err := planningTaskFromSpec(PlanningSpec{
+		Version: planningSpecVersion,
+		Steps: []PlanningStepSpec{
+			{ID: "architecture-baseline", Name: "Architecture", Prompt: "a.md", Role: "architect", Outputs: []string{"_governator/docs/arch.md"}},
+			{ID: "gap-analysis", Name: "Gaps", Prompt: "g.md", Role: "architect"},
+			{ID: "task-planning", Name: "Tasks", Prompt: "t.md", Role: "planner"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("planning task: %v", err)
+	}
+	for _, changed := range [][]string{nil, {"_governator/docs/notes.md"}, {"_governator/docs/arch.md"}} {
+		step, restart := planningReplanStep(planning, PlanningCompleteState, changed)
+		if step != "gap-analysis" || !restart {
+			t.Fatalf("planningReplanStep(%v) = %q, %v; want gap-analysis", changed, step, restart)
+		}
+	}
+}
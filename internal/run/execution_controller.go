@@ -30,16 +30,19 @@ const (
 
 // executionController adapts task execution stages to the workstream runner.
 type executionController struct {
-	repoRoot           string
-	idx                *index.Index
-	cfg                config.Config
-	caps               scheduler.RoleCaps
-	inFlight           inflight.Set
-	resumeWorktrees    map[string]string
-	worktreeOverrides  map[string]string
-	transitionAuditor  index.TransitionAuditor
-	workerAuditor      *audit.Logger
-	opts               Options
+	repoRoot          string
+	idx               *index.Index
+	cfg               config.Config
+	caps              scheduler.RoleCaps
+	inFlight          inflight.Set
+	resumeWorktrees   map[string]string
+	worktreeOverrides map[string]string
+	transitionAuditor index.TransitionAuditor
+	workerAuditor     *audit.Logger
+	opts              Options
+	// worktreeChanges caches in-flight worktree changes, so the stages of one tick share a
+	// single view of them.
+	worktreeChanges    worktreeChanges
 	baseBranch         string
 	cursor             int
 	stages             []executionStage
@@ -65,6 +68,7 @@ func newExecutionController(repoRoot string, idx *index.Index, cfg config.Config
 		transitionAuditor: transitionAuditor,
 		workerAuditor:     workerAuditor,
 		opts:              opts,
+		worktreeChanges:   worktreeChanges{},
 		baseBranch:        baseBranch,
 		stages: []executionStage{
 			executionStageMerge,
@@ -110,38 +114,38 @@ func (controller *executionController) Dispatch(step workstreamStep) (workstream
 	result := workstreamDispatchResult{Continue: true}
 	switch stage {
 	case executionStageWork:
-		workResult, err := ExecuteWorkStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.resumeWorktrees, controller.transitionAuditor, controller.workerAuditor, controller.opts)
+		workResult, err := ExecuteWorkStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.resumeWorktrees, controller.transitionAuditor, controller.workerAuditor, controller.worktreeChanges, controller.opts)
 		if err != nil {
 			return workstreamDispatchResult{}, err
 		}
 		controller.workResult = workResult
 		controller.worktreeOverrides = mergeWorktreeOverrides(controller.resumeWorktrees, workResult.WorktreePaths)
 		controller.markInFlightUpdated(workResult.InFlightUpdated)
-		result.Handled = workResult.TasksDispatched > 0 || workResult.TasksWorked > 0 || workResult.TasksBlocked > 0
+		result.Handled = workResult.TasksDispatched > 0 || workResult.TasksWorked > 0 || workResult.TasksBlocked > 0 || workResult.TasksRateLimited > 0
 	case executionStageTest:
-		testResult, err := ExecuteTestStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.opts)
+		testResult, err := ExecuteTestStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.worktreeChanges, controller.opts)
 		if err != nil {
 			return workstreamDispatchResult{}, err
 		}
 		controller.testResult = testResult
 		controller.markInFlightUpdated(testResult.InFlightUpdated)
-		result.Handled = testResult.TasksDispatched > 0 || testResult.TasksTested > 0 || testResult.TasksBlocked > 0
+		result.Handled = testResult.TasksDispatched > 0 || testResult.TasksTested > 0 || testResult.TasksBlocked > 0 || testResult.TasksRateLimited > 0
 	case executionStageReview:
-		reviewResult, err := ExecuteReviewStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.opts)
+		reviewResult, err := ExecuteReviewStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.worktreeChanges, controller.opts)
 		if err != nil {
 			return workstreamDispatchResult{}, err
 		}
 		controller.reviewResult = reviewResult
 		controller.markInFlightUpdated(reviewResult.InFlightUpdated)
-		result.Handled = reviewResult.TasksDispatched > 0 || reviewResult.TasksReviewed > 0 || reviewResult.TasksBlocked > 0
+		result.Handled = reviewResult.TasksDispatched > 0 || reviewResult.TasksReviewed > 0 || reviewResult.TasksBlocked > 0 || reviewResult.TasksRateLimited > 0
 	case executionStageResolve:
-		conflictResult, err := ExecuteConflictResolutionStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.opts)
+		conflictResult, err := ExecuteConflictResolutionStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.inFlight, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.worktreeChanges, controller.opts)
 		if err != nil {
 			return workstreamDispatchResult{}, err
 		}
 		controller.conflictResult = conflictResult
 		controller.markInFlightUpdated(conflictResult.InFlightUpdated)
-		result.Handled = conflictResult.TasksDispatched > 0 || conflictResult.TasksResolved > 0 || conflictResult.TasksBlocked > 0
+		result.Handled = conflictResult.TasksDispatched > 0 || conflictResult.TasksResolved > 0 || conflictResult.TasksBlocked > 0 || conflictResult.TasksRateLimited > 0
 	case executionStageMerge:
 		mergeResult, err := ExecuteMergeStage(controller.repoRoot, controller.idx, controller.cfg, controller.caps, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.opts)
 		if err != nil {
@@ -149,6 +153,13 @@ func (controller *executionController) Dispatch(step workstreamStep) (workstream
 		}
 		controller.mergeResult = mergeResult
 		result.Handled = mergeResult.TasksProcessed > 0
+		if controller.cfg.Branches.RebaseIdle {
+			rebaseResult, err := rebaseIdleWorktrees(controller.repoRoot, controller.idx, controller.cfg, controller.inFlight, controller.worktreeOverrides, controller.transitionAuditor, controller.workerAuditor, controller.opts)
+			if err != nil {
+				fmt.Fprintf(controller.opts.Stderr, "Warning: failed to rebase idle worktrees: %v\n", err)
+			}
+			result.Handled = result.Handled || rebaseResult.TasksRebased > 0 || rebaseResult.TasksConflicted > 0
+		}
 	default:
 		return workstreamDispatchResult{}, fmt.Errorf("unsupported execution stage %q", stage)
 	}
@@ -33,7 +33,7 @@ func executionComplete(idx index.Index) (bool, error) {
 // executionTerminalState reports whether a task state is terminal for execution.
 func executionTerminalState(state index.TaskState) bool {
 	switch state {
-	case index.TaskStateMerged, index.TaskStateBlocked, index.TaskStateConflict:
+	case index.TaskStateMerged, index.TaskStateBlocked, index.TaskStateConflict, index.TaskStateReverted:
 		return true
 	default:
 		return false
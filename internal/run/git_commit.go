@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/roles"
 	"github.com/cmtonkinson/governator/internal/worker"
@@ -22,8 +23,11 @@ const (
 	commitLogCharLimit    = 8000
 )
 
-// finalizeStageSuccess captures git status and creates a Governator-owned commit.
-func finalizeStageSuccess(worktreePath string, workerStateDir string, task index.Task, stage roles.Stage) (worker.IngestResult, error) {
+// finalizeStageSuccess captures git status and creates a Governator-owned commit. Values from
+// the repo-local secrets file are redacted from the worker logs, git status capture, and
+// commit message before anything is written or committed. The commit message follows the
+// configured stage template and records provenance as trailers.
+func finalizeStageSuccess(repoRoot string, worktreePath string, workerStateDir string, task index.Task, stage roles.Stage, provenance commitProvenance) (worker.IngestResult, error) {
 	if strings.TrimSpace(worktreePath) == "" {
 		return worker.IngestResult{}, errors.New("worktree path is required")
 	}
@@ -40,14 +44,34 @@ func finalizeStageSuccess(worktreePath string, workerStateDir string, task index
 		return worker.IngestResult{}, fmt.Errorf("create worker state dir %s: %w", workerStateDir, err)
 	}
 
+	redactor, err := worker.LoadRedactor(repoRoot)
+	if err != nil {
+		return worker.IngestResult{}, err
+	}
+	if err := redactor.RedactWorkerLogs(workerStateDir); err != nil {
+		return worker.IngestResult{}, fmt.Errorf("redact worker logs: %w", err)
+	}
+
 	statusOutput, err := runGitOutput(worktreePath, "status", "--untracked-files=all")
 	if err != nil {
 		return worker.IngestResult{}, err
 	}
-	if err := os.WriteFile(filepath.Join(workerStateDir, gitChangesFileName), []byte(statusOutput), 0o644); err != nil {
+	if err := os.WriteFile(filepath.Join(workerStateDir, gitChangesFileName), []byte(redactor.Redact(statusOutput)), 0o644); err != nil {
 		return worker.IngestResult{}, fmt.Errorf("write git status: %w", err)
 	}
 
+	// Read metrics from exit.json if available
+	var metrics index.ExecutionMetrics
+	exitStatus, found, err := worker.ReadExitStatus(workerStateDir, task.ID, stage)
+	if err == nil && found {
+		metrics = index.ExecutionMetrics{
+			DurationMs:     exitStatus.DurationMs,
+			TokensPrompt:   exitStatus.TokensPrompt,
+			TokensResponse: exitStatus.TokensResponse,
+			TokensTotal:    exitStatus.TokensTotal,
+		}
+	}
+
 	hasChanges, err := worktreeHasChanges(worktreePath)
 	if err != nil {
 		return worker.IngestResult{}, err
@@ -56,7 +80,7 @@ func finalizeStageSuccess(worktreePath string, workerStateDir string, task index
 		if err := runGit(worktreePath, "add", "-A"); err != nil {
 			return worker.IngestResult{}, err
 		}
-		if err := commitWorktree(worktreePath, workerStateDir, task, stage); err != nil {
+		if err := commitWorktree(worktreePath, workerStateDir, task, stage, redactor, provenance, metrics.TokensTotal); err != nil {
 			return worker.IngestResult{}, err
 		}
 	}
@@ -66,18 +90,6 @@ func finalizeStageSuccess(worktreePath string, workerStateDir string, task index
 		return worker.IngestResult{}, err
 	}
 
-	// Read metrics from exit.json if available
-	var metrics index.ExecutionMetrics
-	exitStatus, found, err := worker.ReadExitStatus(workerStateDir, task.ID, stage)
-	if err == nil && found {
-		metrics = index.ExecutionMetrics{
-			DurationMs:     exitStatus.DurationMs,
-			TokensPrompt:   exitStatus.TokensPrompt,
-			TokensResponse: exitStatus.TokensResponse,
-			TokensTotal:    exitStatus.TokensTotal,
-		}
-	}
-
 	return worker.IngestResult{
 		Success:   true,
 		NewState:  stageToSuccessState(stage),
@@ -126,8 +138,8 @@ func worktreeHasCommit(worktreePath string) (bool, error) {
 }
 
 // commitWorktree builds the Governator commit message and performs the commit.
-func commitWorktree(worktreePath string, workerStateDir string, task index.Task, stage roles.Stage) error {
-	message, err := buildCommitMessage(workerStateDir, task, stage)
+func commitWorktree(worktreePath string, workerStateDir string, task index.Task, stage roles.Stage, redactor *worker.Redactor, provenance commitProvenance, tokens int) error {
+	message, err := buildCommitMessage(workerStateDir, task, stage, redactor, provenance, tokens)
 	if err != nil {
 		return err
 	}
@@ -135,22 +147,16 @@ func commitWorktree(worktreePath string, workerStateDir string, task index.Task,
 	if err := os.WriteFile(messagePath, []byte(message), 0o644); err != nil {
 		return fmt.Errorf("write commit message: %w", err)
 	}
-	return runGitWithEnv(worktreePath, []string{
-		"GIT_AUTHOR_NAME=Governator CLI",
-		"GIT_AUTHOR_EMAIL=governator@localhost",
-		"GIT_COMMITTER_NAME=Governator CLI",
-		"GIT_COMMITTER_EMAIL=governator@localhost",
-	}, "commit", "-F", messagePath)
+	return runGitWithEnv(worktreePath, gitIdentityEnv(stageCommitIdentity(provenance.Identity)), "commit", "-F", messagePath)
 }
 
-// buildCommitMessage creates a message of the form "[state] Title\n\n<stdout.log>".
-func buildCommitMessage(workerStateDir string, task index.Task, stage roles.Stage) (string, error) {
-	statusLabel := strings.ToLower(string(stageToSuccessState(stage)))
+// buildCommitMessage renders the stage commit template, by default "[state] Title\n\n<stdout.log>",
+// with secret values redacted from the log and Governator-* trailers appended.
+func buildCommitMessage(workerStateDir string, task index.Task, stage roles.Stage, redactor *worker.Redactor, provenance commitProvenance, tokens int) (string, error) {
 	title := strings.TrimSpace(task.Title)
 	if title == "" {
 		title = task.ID
 	}
-	subject := fmt.Sprintf("[%s] %s", statusLabel, title)
 
 	stdoutPath := filepath.Join(workerStateDir, stdoutLogFileName)
 	body, err := readLogWithLimit(stdoutPath, commitLogCharLimit)
@@ -158,11 +164,23 @@ func buildCommitMessage(workerStateDir string, task index.Task, stage roles.Stag
 		// Missing logs should not prevent commits; record the failure explicitly.
 		body = fmt.Sprintf("stdout log unavailable: %v\n", err)
 	}
-	body = strings.TrimSpace(body)
-	if body == "" {
-		return subject + "\n", nil
+
+	role := provenance.Role
+	if role == "" {
+		role = task.Role
 	}
-	return subject + "\n\n" + body + "\n", nil
+	data := config.CommitTemplateData{
+		TaskID:  task.ID,
+		Title:   title,
+		Role:    string(role),
+		Stage:   string(stage),
+		State:   strings.ToLower(string(stageToSuccessState(stage))),
+		Attempt: maxInt(task.Attempts.Total, 1),
+		CLI:     provenance.CLI,
+		Tokens:  tokens,
+		Log:     strings.TrimSpace(redactor.Redact(body)),
+	}
+	return renderCommitMessage(provenance.Commits.StageTemplate, config.DefaultStageCommitTemplate, data, provenance.Commits.DisableTrailers)
 }
 
 // readLogWithLimit reads up to the provided character limit from a log file.
@@ -198,6 +216,32 @@ func runGitWithEnv(worktreePath string, env []string, args ...string) error {
 	return err
 }
 
+// gitIdentityEnv returns the environment that sets the author and committer to identity.
+// Empty fields are left to the ambient git config.
+func gitIdentityEnv(identity config.GitIdentity) []string {
+	var env []string
+	if identity.Name != "" {
+		env = append(env, "GIT_AUTHOR_NAME="+identity.Name, "GIT_COMMITTER_NAME="+identity.Name)
+	}
+	if identity.Email != "" {
+		env = append(env, "GIT_AUTHOR_EMAIL="+identity.Email, "GIT_COMMITTER_EMAIL="+identity.Email)
+	}
+	return env
+}
+
+// stageCommitIdentity fills unset identity fields from the built-in Governator identity so
+// stage commits never depend on the ambient git config.
+func stageCommitIdentity(identity config.GitIdentity) config.GitIdentity {
+	fallback := config.Defaults().Git.Default
+	if identity.Name == "" {
+		identity.Name = fallback.Name
+	}
+	if identity.Email == "" {
+		identity.Email = fallback.Email
+	}
+	return identity
+}
+
 // runGitBytes runs git and returns stdout bytes, including stderr in the error message.
 func runGitBytes(worktreePath string, env []string, args ...string) ([]byte, error) {
 	if strings.TrimSpace(worktreePath) == "" {
@@ -8,9 +8,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/roles"
 	"github.com/cmtonkinson/governator/internal/testrepos"
+	"github.com/cmtonkinson/governator/internal/worker"
 )
 
 // TestFinalizeStageSuccessCommitsChanges ensures Governator captures status and commits.
@@ -34,7 +36,7 @@ func TestFinalizeStageSuccessCommitsChanges(t *testing.T) {
 	}
 
 	task := index.Task{ID: "T-001", Title: "Demo task"}
-	result, err := finalizeStageSuccess(worktreePath, workerStateDir, task, roles.StageWork)
+	result, err := finalizeStageSuccess(worktreePath, worktreePath, workerStateDir, task, roles.StageWork, commitProvenance{})
 	if err != nil {
 		t.Fatalf("finalize stage: %v", err)
 	}
@@ -79,7 +81,7 @@ func TestFinalizeStageSuccessNoChanges(t *testing.T) {
 	}
 
 	task := index.Task{ID: "T-002", Title: "No-op task"}
-	result, err := finalizeStageSuccess(worktreePath, workerStateDir, task, roles.StageWork)
+	result, err := finalizeStageSuccess(worktreePath, worktreePath, workerStateDir, task, roles.StageWork, commitProvenance{})
 	if err != nil {
 		t.Fatalf("finalize stage: %v", err)
 	}
@@ -93,6 +95,135 @@ func TestFinalizeStageSuccessNoChanges(t *testing.T) {
 	}
 }
 
+// TestFinalizeStageSuccessRedactsSecrets keeps secret values out of logs, status, and commits.
+func TestFinalizeStageSuccessRedactsSecrets(t *testing.T) {
+	t.Parallel()
+	repo := testrepos.New(t)
+	worktreePath := repo.Root
+	configureLocalStateIgnore(t, repo)
+
+	secret := "pg-pass-8c41f"
+	secretsPath := worker.SecretsPath(repo.Root)
+	if err := os.MkdirAll(filepath.Dir(secretsPath), 0o755); err != nil {
+		t.Fatalf("mkdir local state: %v", err)
+	}
+	if err := os.WriteFile(secretsPath, []byte("DATABASE_PASSWORD="+secret+"\n"), 0o600); err != nil {
+		t.Fatalf("write secrets: %v", err)
+	}
+
+	workerStateDir := filepath.Join(worktreePath, "_governator", "_local-state", "worker-test")
+	if err := os.MkdirAll(workerStateDir, 0o755); err != nil {
+		t.Fatalf("mkdir worker state: %v", err)
+	}
+	stdoutPath := filepath.Join(workerStateDir, stdoutLogFileName)
+	if err := os.WriteFile(stdoutPath, []byte("connected with "+secret+"\n"), 0o644); err != nil {
+		t.Fatalf("write stdout log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "dump-"+secret+".txt"), []byte("change\n"), 0o644); err != nil {
+		t.Fatalf("write change: %v", err)
+	}
+
+	task := index.Task{ID: "T-003", Title: "Secret task"}
+	if _, err := finalizeStageSuccess(repo.Root, worktreePath, workerStateDir, task, roles.StageWork, commitProvenance{}); err != nil {
+		t.Fatalf("finalize stage: %v", err)
+	}
+
+	for _, path := range []string{stdoutPath, filepath.Join(workerStateDir, gitChangesFileName)} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if strings.Contains(string(content), secret) {
+			t.Fatalf("%s leaks secret: %s", path, string(content))
+		}
+		if !strings.Contains(string(content), "[REDACTED:DATABASE_PASSWORD]") {
+			t.Fatalf("%s missing redaction marker: %s", path, string(content))
+		}
+	}
+	logOutput := runGitLog(t, worktreePath)
+	if strings.Contains(logOutput, secret) {
+		t.Fatalf("commit body leaks secret: %s", logOutput)
+	}
+	if !strings.Contains(logOutput, "connected with [REDACTED:DATABASE_PASSWORD]") {
+		t.Fatalf("commit body missing redacted stdout: %s", logOutput)
+	}
+}
+
+// TestFinalizeStageSuccessUsesCommitTemplate renders the stage template and records provenance trailers.
+func TestFinalizeStageSuccessUsesCommitTemplate(t *testing.T) {
+	t.Parallel()
+	repo := testrepos.New(t)
+	worktreePath := repo.Root
+	configureLocalStateIgnore(t, repo)
+
+	workerStateDir := filepath.Join(worktreePath, "_governator", "_local-state", "worker-2-test-qa")
+	if err := os.MkdirAll(workerStateDir, 0o755); err != nil {
+		t.Fatalf("mkdir worker state: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workerStateDir, "exit.json"), []byte(`{"exit_code":0,"tokens_total":4321}`), 0o644); err != nil {
+		t.Fatalf("write exit status: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "tests.txt"), []byte("covered\n"), 0o644); err != nil {
+		t.Fatalf("write change: %v", err)
+	}
+
+	task := index.Task{ID: "T-004", Title: "Cover login", Role: "default", Attempts: index.AttemptCounters{Total: 2}}
+	provenance := commitProvenance{
+		Role:    "qa",
+		CLI:     "codex",
+		Commits: config.CommitsConfig{StageTemplate: "test({{.TaskID}}): {{.Title}} [{{.State}}]"},
+	}
+	if _, err := finalizeStageSuccess(repo.Root, worktreePath, workerStateDir, task, roles.StageTest, provenance); err != nil {
+		t.Fatalf("finalize stage: %v", err)
+	}
+
+	if subject := strings.TrimSpace(repo.RunGit(t, "log", "-1", "--format=%s")); subject != "test(T-004): Cover login [tested]" {
+		t.Fatalf("subject = %q", subject)
+	}
+	trailers := repo.RunGit(t, "log", "-1", "--format=%(trailers:only,unfold)")
+	for _, want := range []string{
+		"Governator-Task: T-004",
+		"Governator-Role: qa",
+		"Governator-Stage: test",
+		"Governator-Attempt: 2",
+		"Governator-CLI: codex",
+		"Governator-Tokens: 4321",
+	} {
+		if !strings.Contains(trailers, want) {
+			t.Fatalf("trailers missing %q:\n%s", want, trailers)
+		}
+	}
+}
+
+// TestFinalizeStageSuccessUsesRoleIdentity authors stage commits as the role's git identity.
+func TestFinalizeStageSuccessUsesRoleIdentity(t *testing.T) {
+	t.Parallel()
+	repo := testrepos.New(t)
+	worktreePath := repo.Root
+	configureLocalStateIgnore(t, repo)
+
+	workerStateDir := filepath.Join(worktreePath, "_governator", "_local-state", "worker-3-work-backend")
+	if err := os.MkdirAll(workerStateDir, 0o755); err != nil {
+		t.Fatalf("mkdir worker state: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "api.go"), []byte("package api\n"), 0o644); err != nil {
+		t.Fatalf("write change: %v", err)
+	}
+
+	cfg := config.Defaults()
+	cfg.Git.Roles = map[string]config.GitIdentity{"backend": {Name: "Backend Agent"}}
+	task := index.Task{ID: "T-005", Title: "Add API", Role: "backend"}
+	if _, err := finalizeStageSuccess(repo.Root, worktreePath, workerStateDir, task, roles.StageWork, newCommitProvenance(cfg, "backend")); err != nil {
+		t.Fatalf("finalize stage: %v", err)
+	}
+
+	for _, format := range []string{"%an <%ae>", "%cn <%ce>"} {
+		if got := strings.TrimSpace(repo.RunGit(t, "log", "-1", "--format="+format)); got != "Backend Agent <governator@localhost>" {
+			t.Fatalf("%s = %q", format, got)
+		}
+	}
+}
+
 // runGitLog returns the latest commit message body in the worktree.
 func runGitLog(t *testing.T, dir string) string {
 	t.Helper()
@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/worktree"
 )
 
 // MergeFlowInput defines the inputs required for the review merge flow.
@@ -20,7 +22,22 @@ type MergeFlowInput struct {
 	WorktreePath string
 	Task         index.Task
 	MainBranch   string
-	Auditor      *audit.Logger
+	// WorktreesRoot is the configured worktrees.root; merge worktrees are created beneath it.
+	WorktreesRoot string
+	// Strategy selects squash, merge, or rebase; empty means squash.
+	Strategy string
+	// Remote names the git remote to fetch from and push to; empty keeps the flow local.
+	Remote string
+	// VerifyCommand runs in the merge worktree before the merge is published; empty skips it.
+	VerifyCommand        []string
+	VerifyTimeoutSeconds int
+	// Commits supplies the merge commit template and whether to add provenance trailers.
+	Commits config.CommitsConfig
+	// Identity authors squash and merge commits; empty fields use the ambient git config.
+	Identity config.GitIdentity
+	// Signing signs squash and merge commits. Rebase lands existing commits and signs nothing.
+	Signing config.GitSigning
+	Auditor *audit.Logger
 }
 
 // MergeFlowResult captures the outcome of the review merge flow.
@@ -30,9 +47,13 @@ type MergeFlowResult struct {
 	ConflictError string
 }
 
-// ExecuteReviewMergeFlow performs local git rebase and merge operations for a reviewed task.
-// This implements the flow: rebase on main → squash in isolated worktree → update local main → done.
-// On conflict: mark as conflict state for manual resolution.
+// ExecuteReviewMergeFlow performs git rebase and merge operations for a reviewed task.
+// This implements the flow: rebase on main → land the branch in an isolated worktree using the
+// configured strategy → update local main → done.
+// With a remote, local main is first rebased onto the fetched remote base, and the task branch
+// and merge are pushed before local main moves. A verify command, when set, must pass on the
+// merged result before anything is pushed or local main moves.
+// On conflict, a failed verify, or a rejected push: mark as conflict state for resolution.
 func ExecuteReviewMergeFlow(input MergeFlowInput) (MergeFlowResult, error) {
 	if strings.TrimSpace(input.RepoRoot) == "" {
 		return MergeFlowResult{}, fmt.Errorf("repo root is required")
@@ -49,40 +70,32 @@ func ExecuteReviewMergeFlow(input MergeFlowInput) (MergeFlowResult, error) {
 	if strings.TrimSpace(input.Task.Title) == "" {
 		return MergeFlowResult{}, fmt.Errorf("task title is required")
 	}
+	input.Strategy = strings.TrimSpace(input.Strategy)
+	if input.Strategy == "" {
+		input.Strategy = config.MergeStrategySquash
+	}
+	if !config.IsValidMergeStrategy(input.Strategy) {
+		return MergeFlowResult{}, fmt.Errorf("unknown merge strategy %q", input.Strategy)
+	}
 	if err := ensureCleanWorktree(input.WorktreePath); err != nil {
 		return MergeFlowResult{}, err
 	}
+	input.Remote = strings.TrimSpace(input.Remote)
 
-	// Step 1: Attempt rebase on local main branch.
-	rebaseErr := runGitInWorktree(input.WorktreePath, "rebase", input.MainBranch)
-	if rebaseErr != nil {
-		// Check if this is a rebase conflict
-		if isRebaseConflict(rebaseErr) {
-			// Abort the rebase to leave worktree in clean state
-			_ = runGitInWorktree(input.WorktreePath, "rebase", "--abort")
-
-			// Log the conflict to audit
-			if input.Auditor != nil {
-				_ = input.Auditor.LogTaskTransition(
-					input.Task.ID,
-					string(input.Task.Role),
-					string(index.TaskStateTested),
-					string(index.TaskStateConflict),
-				)
-			}
-
-			return MergeFlowResult{
-				Success:       false,
-				NewState:      index.TaskStateConflict,
-				ConflictError: fmt.Sprintf("rebase conflict with local %s: %v", input.MainBranch, rebaseErr),
-			}, nil
+	// Step 1: Fetch the remote base branch so upstream commits are merged against.
+	remoteBase := ""
+	if input.Remote != "" {
+		found, err := fetchRemoteBase(input.RepoRoot, input.Remote, input.MainBranch)
+		if err != nil {
+			return MergeFlowResult{}, err
+		}
+		if found {
+			remoteBase = input.Remote + "/" + input.MainBranch
 		}
-		// Non-conflict rebase error
-		return MergeFlowResult{}, fmt.Errorf("rebase failed: %w", rebaseErr)
 	}
 
 	// Step 2: Create an isolated merge worktree on the main branch.
-	mergeWorktreePath, cleanupMergeWorktree, err := createMergeWorktree(input.RepoRoot, input.MainBranch, input.Task.ID)
+	mergeWorktreePath, cleanupMergeWorktree, err := createMergeWorktree(input.RepoRoot, input.WorktreesRoot, input.MainBranch, input.Task.ID)
 	if err != nil {
 		return MergeFlowResult{}, err
 	}
@@ -97,98 +110,113 @@ func ExecuteReviewMergeFlow(input MergeFlowInput) (MergeFlowResult, error) {
 		}
 	}()
 
-	// Step 3: Perform squash merge of task branch in the isolated worktree.
+	// Step 3: Rebase the local main onto the remote base so human commits upstream are kept.
+	rebaseTarget := input.MainBranch
+	if remoteBase != "" {
+		if err := runGitInWorktree(mergeWorktreePath, "rebase", remoteBase); err != nil {
+			if isRebaseConflict(err) {
+				_ = runGitInWorktree(mergeWorktreePath, "rebase", "--abort")
+				return mergeConflictResult(input, fmt.Sprintf("rebase conflict between local %s and %s: %v", input.MainBranch, remoteBase, err)), nil
+			}
+			return MergeFlowResult{}, fmt.Errorf("rebase local %s onto %s: %w", input.MainBranch, remoteBase, err)
+		}
+		if rebaseTarget, err = getWorktreeCommit(mergeWorktreePath); err != nil {
+			return MergeFlowResult{}, fmt.Errorf("get rebased base commit: %w", err)
+		}
+	}
+
+	// Step 4: Attempt rebase of the task branch on the merge base.
+	rebaseErr := runGitInWorktree(input.WorktreePath, "rebase", rebaseTarget)
+	if rebaseErr != nil {
+		// Check if this is a rebase conflict
+		if isRebaseConflict(rebaseErr) {
+			// Abort the rebase to leave worktree in clean state
+			_ = runGitInWorktree(input.WorktreePath, "rebase", "--abort")
+			return mergeConflictResult(input, fmt.Sprintf("rebase conflict with local %s: %v", input.MainBranch, rebaseErr)), nil
+		}
+		// Non-conflict rebase error
+		return MergeFlowResult{}, fmt.Errorf("rebase failed: %w", rebaseErr)
+	}
+
+	// Step 5: Land the task branch in the isolated worktree.
+	baseCommit, err := getWorktreeCommit(mergeWorktreePath)
+	if err != nil {
+		return MergeFlowResult{}, fmt.Errorf("get merge base commit: %w", err)
+	}
 	taskBranch := TaskBranchName(input.Task)
-	mergeErr := runGitInWorktree(mergeWorktreePath, "merge", "--squash", taskBranch)
+	commitMsg, err := mergeCommitMessage(mergeWorktreePath, input, rebaseTarget, taskBranch)
+	if err != nil {
+		return MergeFlowResult{}, err
+	}
+	mergeErr := landTaskBranch(mergeWorktreePath, input, taskBranch, commitMsg)
 	if mergeErr != nil {
 		// Check if this is a merge conflict
 		if isMergeConflict(mergeErr) {
 			// Reset the merge worktree to a clean state.
 			_ = runGitInWorktree(mergeWorktreePath, "reset", "--hard", "HEAD")
-
-			// Log the conflict to audit
-			if input.Auditor != nil {
-				_ = input.Auditor.LogTaskTransition(
-					input.Task.ID,
-					string(input.Task.Role),
-					string(index.TaskStateTested),
-					string(index.TaskStateConflict),
-				)
-			}
-
-			return MergeFlowResult{
-				Success:       false,
-				NewState:      index.TaskStateConflict,
-				ConflictError: fmt.Sprintf("merge conflict with local %s: %v", input.MainBranch, mergeErr),
-			}, nil
+			return mergeConflictResult(input, fmt.Sprintf("merge conflict with local %s: %v", input.MainBranch, mergeErr)), nil
 		}
 		// Non-conflict merge error
-		return MergeFlowResult{}, fmt.Errorf("squash merge failed: %w", mergeErr)
+		return MergeFlowResult{}, fmt.Errorf("%s merge failed: %w", input.Strategy, mergeErr)
 	}
 
-	// Step 4: Commit the squashed changes.
-	commitMsg := fmt.Sprintf("governator: %s - %s", input.Task.ID, input.Task.Title)
-	commitErr := runGitInWorktree(mergeWorktreePath, "commit", "-m", commitMsg)
-	if commitErr != nil {
-		lower := strings.ToLower(commitErr.Error())
-		if !strings.Contains(lower, "nothing to commit") && !strings.Contains(lower, "working tree clean") {
-			return MergeFlowResult{}, fmt.Errorf("commit squashed changes: %w", commitErr)
+	// Step 6: Commit the squashed changes; the other strategies commit while landing.
+	if input.Strategy == config.MergeStrategySquash {
+		commitErr := runBaseBranchCommit(mergeWorktreePath, input.Identity, input.Signing, "commit", "-m", commitMsg)
+		if commitErr != nil {
+			lower := strings.ToLower(commitErr.Error())
+			if !strings.Contains(lower, "nothing to commit") && !strings.Contains(lower, "working tree clean") {
+				return MergeFlowResult{}, fmt.Errorf("commit squashed changes: %w", commitErr)
+			}
 		}
 	}
 
-	// Step 5: Get the merge commit SHA from the merge worktree.
+	// Step 7: Get the merge commit SHA from the merge worktree.
 	mergeCommit, err := getWorktreeCommit(mergeWorktreePath)
 	if err != nil {
 		return MergeFlowResult{}, fmt.Errorf("get merge commit: %w", err)
 	}
 
-	// Update main worktree to the merge commit
-	if err := runGitInRepo(input.RepoRoot, "reset", "--hard", mergeCommit); err != nil {
-		return MergeFlowResult{}, fmt.Errorf("update main to merge commit: %w", err)
-	}
-
-	// Step 6: Remove task worktree to allow branch deletion.
-	worktreePath := filepath.Join(input.RepoRoot, "_governator", "_local-state", fmt.Sprintf("task-%s", input.Task.ID))
-	if _, err := os.Stat(worktreePath); err == nil {
-		if err := runGitInRepo(input.RepoRoot, "worktree", "remove", "--force", worktreePath); err != nil {
-			// Log warning but don't fail merge
+	// Step 8: Verify the merged result so individually reviewed tasks cannot break main together.
+	if len(input.VerifyCommand) > 0 {
+		if output, err := runMergeVerify(mergeWorktreePath, input.VerifyCommand, input.VerifyTimeoutSeconds); err != nil {
 			if input.Auditor != nil {
 				_ = input.Auditor.Log(audit.Entry{
 					TaskID: input.Task.ID,
 					Role:   string(input.Task.Role),
-					Event:  "worktree.remove.warning",
-					Fields: []audit.Field{{Key: "error", Value: err.Error()}},
+					Event:  "merge.verify.failure",
+					Fields: []audit.Field{
+						{Key: "command", Value: strings.Join(input.VerifyCommand, " ")},
+						{Key: "error", Value: err.Error()},
+					},
 				})
 			}
+			return mergeConflictResult(input, verifyFailureMessage(input.VerifyCommand, err, output)), nil
 		}
 	}
 
-	// Step 7: Clean up task branch after successful merge.
-	branchManager := NewBranchLifecycleManager(input.RepoRoot, input.Auditor)
-	if err := branchManager.CleanupTaskBranch(input.Task); err != nil {
-		// Log warning but don't fail the merge - branch cleanup is not critical
-		if input.Auditor != nil {
-			_ = input.Auditor.Log(audit.Entry{
-				TaskID: input.Task.ID,
-				Role:   string(input.Task.Role),
-				Event:  "branch.cleanup.warning",
-				Fields: []audit.Field{
-					{Key: "error", Value: err.Error()},
-				},
-			})
+	// Step 9: Publish the task branch and the merge before local main moves. A rejected
+	// push means the remote base moved underneath us, which is retried as a conflict.
+	if input.Remote != "" {
+		if err := runGitInWorktree(mergeWorktreePath, "push", "--force", input.Remote, taskBranch+":refs/heads/"+taskBranch); err != nil {
+			return MergeFlowResult{}, fmt.Errorf("push task branch %s to %s: %w", taskBranch, input.Remote, err)
+		}
+		if err := runGitInWorktree(mergeWorktreePath, "push", input.Remote, mergeCommit+":refs/heads/"+input.MainBranch); err != nil {
+			if isPushRejected(err) {
+				return mergeConflictResult(input, fmt.Sprintf("push to %s/%s rejected: %v", input.Remote, input.MainBranch, err)), nil
+			}
+			return MergeFlowResult{}, fmt.Errorf("push %s to %s: %w", input.MainBranch, input.Remote, err)
 		}
 	}
 
-	// Step 8: Log successful transition to audit.
-	if input.Auditor != nil {
-		_ = input.Auditor.LogTaskTransition(
-			input.Task.ID,
-			string(input.Task.Role),
-			string(index.TaskStateTested),
-			string(index.TaskStateMerged),
-		)
+	// Update main worktree to the merge commit
+	if err := runGitInRepo(input.RepoRoot, "reset", "--hard", mergeCommit); err != nil {
+		return MergeFlowResult{}, fmt.Errorf("update main to merge commit: %w", err)
 	}
 
+	// Steps 10-12: Remove the task worktree and branch and record the merge.
+	finishTaskMerge(input, mergeCommit, baseCommit)
+
 	return MergeFlowResult{
 		Success:  true,
 		NewState: index.TaskStateMerged,
@@ -248,8 +276,173 @@ func ExecuteConflictResolutionMergeFlow(input MergeFlowInput) (MergeFlowResult,
 	return result, nil
 }
 
+// finishTaskMerge removes the task worktree and branch once its merge is on the base branch
+// and records the merge commits in the audit log.
+func finishTaskMerge(input MergeFlowInput, mergeCommit string, baseCommit string) {
+	// Remove task worktree to allow branch deletion. The flow was given the resolved path, so this
+	// also covers worktrees placed under a configured worktrees.root.
+	worktreePath := input.WorktreePath
+	if _, err := os.Stat(worktreePath); err == nil && !samePath(worktreePath, input.RepoRoot) {
+		if err := runGitInRepo(input.RepoRoot, "worktree", "remove", "--force", worktreePath); err != nil {
+			// Log warning but don't fail merge
+			if input.Auditor != nil {
+				_ = input.Auditor.Log(audit.Entry{
+					TaskID: input.Task.ID,
+					Role:   string(input.Task.Role),
+					Event:  "worktree.remove.warning",
+					Fields: []audit.Field{{Key: "error", Value: err.Error()}},
+				})
+			}
+		}
+	}
+
+	// Clean up task branch after successful merge.
+	branchManager := NewBranchLifecycleManager(input.RepoRoot, input.Auditor)
+	if err := branchManager.CleanupTaskBranch(input.Task); err != nil {
+		// Log warning but don't fail the merge - branch cleanup is not critical
+		if input.Auditor != nil {
+			_ = input.Auditor.Log(audit.Entry{
+				TaskID: input.Task.ID,
+				Role:   string(input.Task.Role),
+				Event:  "branch.cleanup.warning",
+				Fields: []audit.Field{
+					{Key: "error", Value: err.Error()},
+				},
+			})
+		}
+	}
+
+	// Log the merge commits and the successful transition to audit.
+	if input.Auditor != nil {
+		_ = input.Auditor.LogMergeCommit(input.Task.ID, string(input.Task.Role), mergeCommit, baseCommit, input.Strategy)
+		_ = input.Auditor.LogTaskTransition(
+			input.Task.ID,
+			string(input.Task.Role),
+			string(index.TaskStateTested),
+			string(index.TaskStateMerged),
+		)
+	}
+}
+
+// samePath reports whether two paths name the same directory once made absolute.
+func samePath(a string, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// mergeConflictResult records the tested → conflict transition and returns the conflict result.
+func mergeConflictResult(input MergeFlowInput, message string) MergeFlowResult {
+	if input.Auditor != nil {
+		_ = input.Auditor.LogTaskTransition(
+			input.Task.ID,
+			string(input.Task.Role),
+			string(index.TaskStateTested),
+			string(index.TaskStateConflict),
+		)
+	}
+	return MergeFlowResult{
+		Success:       false,
+		NewState:      index.TaskStateConflict,
+		ConflictError: message,
+	}
+}
+
+// fetchRemoteBase fetches the base branch from the remote into its remote-tracking ref.
+// It reports false when the remote does not have the branch yet, as with a fresh bare repo.
+func fetchRemoteBase(repoRoot string, remote string, mainBranch string) (bool, error) {
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", mainBranch, remote, mainBranch)
+	if err := runGitInRepo(repoRoot, "fetch", remote, refspec); err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "couldn't find remote ref") {
+			return false, nil
+		}
+		return false, fmt.Errorf("fetch %s from %s: %w", mainBranch, remote, err)
+	}
+	return true, nil
+}
+
+// isPushRejected determines if a git push error is a non-fast-forward rejection.
+func isPushRejected(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "non-fast-forward") ||
+		strings.Contains(errStr, "fetch first") ||
+		strings.Contains(errStr, "[rejected]")
+}
+
+// landTaskBranch applies the task branch to the merge worktree using the given strategy.
+// Squash stages the combined changes for a later commit, merge records a no-ff merge commit
+// with message, and rebase fast-forwards onto the already rebased branch so every stage
+// commit is kept as-is.
+func landTaskBranch(mergeWorktreePath string, input MergeFlowInput, taskBranch string, message string) error {
+	switch input.Strategy {
+	case config.MergeStrategyMerge:
+		return runBaseBranchCommit(mergeWorktreePath, input.Identity, input.Signing, "merge", "--no-ff", "-m", message, taskBranch)
+	case config.MergeStrategyRebase:
+		return runGitInWorktree(mergeWorktreePath, "merge", "--ff-only", taskBranch)
+	default:
+		return runGitInWorktree(mergeWorktreePath, "merge", "--squash", taskBranch)
+	}
+}
+
+// stageCommitSummary lists the subjects of the commits the task branch adds on top of base.
+func stageCommitSummary(dir string, base string, taskBranch string) (string, error) {
+	output, err := runGitOutput(dir, "log", "--reverse", "--format=- %s", base+".."+taskBranch)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// mergeCommitMessage renders the merge commit template for a task. With the merge strategy
+// the template also receives the subjects of the stage commits being merged.
+func mergeCommitMessage(dir string, input MergeFlowInput, base string, taskBranch string) (string, error) {
+	data := config.CommitTemplateData{
+		TaskID:   input.Task.ID,
+		Title:    input.Task.Title,
+		Role:     string(input.Task.Role),
+		Stage:    mergeStageTrailer,
+		State:    string(index.TaskStateMerged),
+		Attempt:  input.Task.Attempts.Total,
+		Strategy: input.Strategy,
+	}
+	if input.Strategy == config.MergeStrategyMerge {
+		if summary, err := stageCommitSummary(dir, base, taskBranch); err == nil {
+			data.Commits = summary
+		}
+	}
+	message, err := renderCommitMessage(input.Commits.MergeTemplate, config.DefaultMergeCommitTemplate, data, input.Commits.DisableTrailers)
+	if err != nil {
+		return "", fmt.Errorf("build merge commit message: %w", err)
+	}
+	return message, nil
+}
+
+// mergeCommitSubject returns the built-in merge commit subject for a task.
+func mergeCommitSubject(task index.Task) string {
+	return fmt.Sprintf("governator: %s - %s", task.ID, task.Title)
+}
+
+// runBaseBranchCommit runs a commit or merge that creates a commit on the base branch under
+// identity, signing it with the configured key when signing is enabled.
+func runBaseBranchCommit(worktreePath string, identity config.GitIdentity, signing config.GitSigning, args ...string) error {
+	if signing.Enabled() && len(args) > 0 {
+		signed := []string{"-c", "gpg.format=" + signing.Format, "-c", "user.signingkey=" + signing.Key, args[0], "-S"}
+		args = append(signed, args[1:]...)
+	}
+	return runGitInWorktreeWithEnv(worktreePath, gitIdentityEnv(identity), args...)
+}
+
 // runGitInWorktree executes a git command in the specified worktree directory.
 func runGitInWorktree(worktreePath string, args ...string) error {
+	return runGitInWorktreeWithEnv(worktreePath, nil, args...)
+}
+
+// runGitInWorktreeWithEnv executes a git command in the worktree with additional environment
+// variables, keeping stdout in the error so merge conflicts stay detectable.
+func runGitInWorktreeWithEnv(worktreePath string, env []string, args ...string) error {
 	if strings.TrimSpace(worktreePath) == "" {
 		return fmt.Errorf("worktree path is required")
 	}
@@ -259,6 +452,9 @@ func runGitInWorktree(worktreePath string, args ...string) error {
 
 	cmd := exec.Command("git", args...)
 	cmd.Dir = worktreePath
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -311,7 +507,8 @@ func ensureCleanWorktree(worktreePath string) error {
 }
 
 // createMergeWorktree creates a temporary worktree on the main branch for safe merges.
-func createMergeWorktree(repoRoot string, mainBranch string, taskID string) (string, func() error, error) {
+// It lives under the resolved worktrees root so merges share the task worktrees' volume.
+func createMergeWorktree(repoRoot string, worktreesRoot string, mainBranch string, taskID string) (string, func() error, error) {
 	if strings.TrimSpace(repoRoot) == "" {
 		return "", nil, fmt.Errorf("repo root is required")
 	}
@@ -322,7 +519,11 @@ func createMergeWorktree(repoRoot string, mainBranch string, taskID string) (str
 		return "", nil, fmt.Errorf("task id is required")
 	}
 
-	mergeDir := filepath.Join(repoRoot, "_governator", "_local-state", "merge-worktrees")
+	worktreeDir, err := worktree.ResolveDir(repoRoot, worktreesRoot)
+	if err != nil {
+		return "", nil, err
+	}
+	mergeDir := filepath.Join(worktreeDir, "merge-worktrees")
 	if err := os.MkdirAll(mergeDir, 0o755); err != nil {
 		return "", nil, fmt.Errorf("create merge worktree dir %s: %w", mergeDir, err)
 	}
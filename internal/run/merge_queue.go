@@ -0,0 +1,300 @@
+// Package run provides the batched merge queue with verify bisection.
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+)
+
+// MergeQueueEntry is one mergeable task waiting in the merge queue.
+type MergeQueueEntry struct {
+	Task         index.Task
+	WorktreePath string
+}
+
+// MergeQueueOutcome reports how one queued task left the queue. Err is set when the task
+// could not be merged for a reason other than a conflict or a failed verify.
+type MergeQueueOutcome struct {
+	TaskID string
+	Result MergeFlowResult
+	Err    error
+}
+
+// queuedTask tracks a queued task while the stack is built.
+type queuedTask struct {
+	input MergeFlowInput
+	// head is the task branch rebased onto the queue base, restored before each landing.
+	head string
+	// base and tip are the stack commits just before and after the task landed.
+	base string
+	tip  string
+}
+
+// ExecuteMergeQueue lands entries, in order, on one stack above the base branch and runs the
+// verify command once on the combined result. When verify fails it bisects the stack by
+// verifying prefixes, sends only the first failing task to conflict, and lands the rest
+// again until the stack passes. flow carries the settings shared by every merge; its Task
+// and WorktreePath are ignored. Outcomes are returned for every entry that was decided; an
+// error may leave later entries without one.
+func ExecuteMergeQueue(flow MergeFlowInput, entries []MergeQueueEntry) ([]MergeQueueOutcome, error) {
+	if strings.TrimSpace(flow.RepoRoot) == "" {
+		return nil, fmt.Errorf("repo root is required")
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	if strings.TrimSpace(flow.MainBranch) == "" {
+		flow.MainBranch = "main"
+	}
+	flow.Strategy = strings.TrimSpace(flow.Strategy)
+	if flow.Strategy == "" {
+		flow.Strategy = config.MergeStrategySquash
+	}
+	if !config.IsValidMergeStrategy(flow.Strategy) {
+		return nil, fmt.Errorf("unknown merge strategy %q", flow.Strategy)
+	}
+	flow.Remote = strings.TrimSpace(flow.Remote)
+
+	var outcomes []MergeQueueOutcome
+	decide := func(input MergeFlowInput, result MergeFlowResult, err error) {
+		outcomes = append(outcomes, MergeQueueOutcome{TaskID: input.Task.ID, Result: result, Err: err})
+	}
+	inputs := make([]MergeFlowInput, 0, len(entries))
+	for _, entry := range entries {
+		input := flow
+		input.Task = entry.Task
+		input.WorktreePath = entry.WorktreePath
+		inputs = append(inputs, input)
+	}
+
+	remoteBase := ""
+	if flow.Remote != "" {
+		found, err := fetchRemoteBase(flow.RepoRoot, flow.Remote, flow.MainBranch)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			remoteBase = flow.Remote + "/" + flow.MainBranch
+		}
+	}
+
+	queueID := "queue-" + entries[0].Task.ID
+	mergeWorktreePath, cleanupMergeWorktree, err := createMergeWorktree(flow.RepoRoot, flow.WorktreesRoot, flow.MainBranch, queueID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cleanupErr := cleanupMergeWorktree(); cleanupErr != nil && flow.Auditor != nil {
+			_ = flow.Auditor.Log(audit.Entry{
+				TaskID: entries[0].Task.ID,
+				Event:  "merge.worktree.cleanup.warning",
+				Fields: []audit.Field{{Key: "error", Value: cleanupErr.Error()}},
+			})
+		}
+	}()
+
+	if remoteBase != "" {
+		if err := runGitInWorktree(mergeWorktreePath, "rebase", remoteBase); err != nil {
+			if !isRebaseConflict(err) {
+				return nil, fmt.Errorf("rebase local %s onto %s: %w", flow.MainBranch, remoteBase, err)
+			}
+			_ = runGitInWorktree(mergeWorktreePath, "rebase", "--abort")
+			for _, input := range inputs {
+				decide(input, mergeConflictResult(input, fmt.Sprintf("rebase conflict between local %s and %s: %v", flow.MainBranch, remoteBase, err)), nil)
+			}
+			return outcomes, nil
+		}
+	}
+	queueBase, err := getWorktreeCommit(mergeWorktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("get merge queue base commit: %w", err)
+	}
+
+	// Rebase every task onto the queue base first so a task sent back never carries the
+	// commits of the tasks stacked beneath it.
+	pending := make([]*queuedTask, 0, len(inputs))
+	for _, input := range inputs {
+		if err := ensureCleanWorktree(input.WorktreePath); err != nil {
+			decide(input, MergeFlowResult{}, err)
+			continue
+		}
+		if err := runGitInWorktree(input.WorktreePath, "rebase", queueBase); err != nil {
+			if !isRebaseConflict(err) {
+				decide(input, MergeFlowResult{}, fmt.Errorf("rebase failed: %w", err))
+				continue
+			}
+			_ = runGitInWorktree(input.WorktreePath, "rebase", "--abort")
+			decide(input, mergeConflictResult(input, fmt.Sprintf("rebase conflict with local %s: %v", flow.MainBranch, err)), nil)
+			continue
+		}
+		head, err := getWorktreeCommit(input.WorktreePath)
+		if err != nil {
+			decide(input, MergeFlowResult{}, err)
+			continue
+		}
+		pending = append(pending, &queuedTask{input: input, head: head})
+	}
+
+	stack := landQueuedTasks(mergeWorktreePath, pending, decide)
+
+	// known counts the stack prefix that has passed verify; the queue base is assumed good.
+	// Verify runs again only when tasks beyond that prefix are still stacked.
+	known := 0
+	for len(stack) > known && len(flow.VerifyCommand) > 0 {
+		output, verifyErr := runMergeVerify(mergeWorktreePath, flow.VerifyCommand, flow.VerifyTimeoutSeconds)
+		if verifyErr == nil {
+			break
+		}
+		culprit, message, err := bisectMergeQueue(mergeWorktreePath, flow, stack, known, verifyFailureMessage(flow.VerifyCommand, verifyErr, output))
+		if err != nil {
+			return outcomes, err
+		}
+		sendBack := stack[culprit]
+		if flow.Auditor != nil {
+			_ = flow.Auditor.Log(audit.Entry{
+				TaskID: sendBack.input.Task.ID,
+				Role:   string(sendBack.input.Task.Role),
+				Event:  "merge.verify.failure",
+				Fields: []audit.Field{
+					{Key: "command", Value: strings.Join(flow.VerifyCommand, " ")},
+					{Key: "batch", Value: fmt.Sprintf("%d", len(stack))},
+				},
+			})
+		}
+		_ = runGitInWorktree(sendBack.input.WorktreePath, "reset", "--hard", sendBack.head)
+		decide(sendBack.input, mergeConflictResult(sendBack.input, message), nil)
+
+		rest := stack[culprit+1:]
+		if err := runGitInWorktree(mergeWorktreePath, "reset", "--hard", sendBack.base); err != nil {
+			return outcomes, fmt.Errorf("reset merge queue to %s: %w", sendBack.base, err)
+		}
+		stack = append(stack[:culprit:culprit], landQueuedTasks(mergeWorktreePath, rest, decide)...)
+		known = culprit
+	}
+	if len(stack) == 0 {
+		return outcomes, nil
+	}
+
+	// Publish the stack before local main moves, as the single-task flow does.
+	tip := stack[len(stack)-1].tip
+	if flow.Remote != "" {
+		for _, queued := range stack {
+			taskBranch := TaskBranchName(queued.input.Task)
+			if err := runGitInWorktree(mergeWorktreePath, "push", "--force", flow.Remote, taskBranch+":refs/heads/"+taskBranch); err != nil {
+				return outcomes, fmt.Errorf("push task branch %s to %s: %w", taskBranch, flow.Remote, err)
+			}
+		}
+		if err := runGitInWorktree(mergeWorktreePath, "push", flow.Remote, tip+":refs/heads/"+flow.MainBranch); err != nil {
+			if !isPushRejected(err) {
+				return outcomes, fmt.Errorf("push %s to %s: %w", flow.MainBranch, flow.Remote, err)
+			}
+			for _, queued := range stack {
+				decide(queued.input, mergeConflictResult(queued.input, fmt.Sprintf("push to %s/%s rejected: %v", flow.Remote, flow.MainBranch, err)), nil)
+			}
+			return outcomes, nil
+		}
+	}
+	if err := runGitInRepo(flow.RepoRoot, "reset", "--hard", tip); err != nil {
+		return outcomes, fmt.Errorf("update main to merge queue commit: %w", err)
+	}
+	for _, queued := range stack {
+		finishTaskMerge(queued.input, queued.tip, queued.base)
+		decide(queued.input, MergeFlowResult{Success: true, NewState: index.TaskStateMerged}, nil)
+	}
+	return outcomes, nil
+}
+
+// landQueuedTasks lands each task on top of the merge worktree HEAD with the configured
+// strategy and returns the tasks that landed. Tasks that conflict with the stack beneath
+// them are decided as conflicts and left off the stack.
+func landQueuedTasks(mergeWorktreePath string, tasks []*queuedTask, decide func(MergeFlowInput, MergeFlowResult, error)) []*queuedTask {
+	landed := make([]*queuedTask, 0, len(tasks))
+	for _, queued := range tasks {
+		input := queued.input
+		base, err := getWorktreeCommit(mergeWorktreePath)
+		if err != nil {
+			decide(input, MergeFlowResult{}, err)
+			continue
+		}
+		tip, conflict, err := landQueuedTask(mergeWorktreePath, queued, base)
+		if err != nil || conflict != "" {
+			_ = runGitInWorktree(mergeWorktreePath, "reset", "--hard", base)
+			_ = runGitInWorktree(input.WorktreePath, "reset", "--hard", queued.head)
+			if err != nil {
+				decide(input, MergeFlowResult{}, err)
+			} else {
+				decide(input, mergeConflictResult(input, conflict), nil)
+			}
+			continue
+		}
+		queued.base = base
+		queued.tip = tip
+		landed = append(landed, queued)
+	}
+	return landed
+}
+
+// landQueuedTask rebases the task branch from its queue-base head onto base and lands it in
+// the merge worktree. It returns the new stack tip, or a conflict description.
+func landQueuedTask(mergeWorktreePath string, queued *queuedTask, base string) (string, string, error) {
+	input := queued.input
+	if err := runGitInWorktree(input.WorktreePath, "reset", "--hard", queued.head); err != nil {
+		return "", "", fmt.Errorf("reset task branch: %w", err)
+	}
+	if err := runGitInWorktree(input.WorktreePath, "rebase", base); err != nil {
+		_ = runGitInWorktree(input.WorktreePath, "rebase", "--abort")
+		if isRebaseConflict(err) {
+			return "", fmt.Sprintf("rebase conflict with queued merges on %s: %v", input.MainBranch, err), nil
+		}
+		return "", "", fmt.Errorf("rebase failed: %w", err)
+	}
+	taskBranch := TaskBranchName(input.Task)
+	message, err := mergeCommitMessage(mergeWorktreePath, input, base, taskBranch)
+	if err != nil {
+		return "", "", err
+	}
+	if err := landTaskBranch(mergeWorktreePath, input, taskBranch, message); err != nil {
+		if isMergeConflict(err) {
+			return "", fmt.Sprintf("merge conflict with queued merges on %s: %v", input.MainBranch, err), nil
+		}
+		return "", "", fmt.Errorf("%s merge failed: %w", input.Strategy, err)
+	}
+	if input.Strategy == config.MergeStrategySquash {
+		if err := runBaseBranchCommit(mergeWorktreePath, input.Identity, input.Signing, "commit", "-m", message); err != nil {
+			lower := strings.ToLower(err.Error())
+			if !strings.Contains(lower, "nothing to commit") && !strings.Contains(lower, "working tree clean") {
+				return "", "", fmt.Errorf("commit squashed changes: %w", err)
+			}
+		}
+	}
+	tip, err := getWorktreeCommit(mergeWorktreePath)
+	if err != nil {
+		return "", "", fmt.Errorf("get merge commit: %w", err)
+	}
+	return tip, "", nil
+}
+
+// bisectMergeQueue finds the first task whose prefix of the stack fails verify, given that
+// the first known tasks pass and the whole stack fails with failure. It returns the index of
+// that task and the failure message for it, leaving the merge worktree at an arbitrary prefix.
+func bisectMergeQueue(mergeWorktreePath string, flow MergeFlowInput, stack []*queuedTask, known int, failure string) (int, string, error) {
+	good, bad := known, len(stack)
+	for bad-good > 1 {
+		mid := (good + bad) / 2
+		if err := runGitInWorktree(mergeWorktreePath, "reset", "--hard", stack[mid-1].tip); err != nil {
+			return 0, "", fmt.Errorf("reset merge queue for bisection: %w", err)
+		}
+		output, err := runMergeVerify(mergeWorktreePath, flow.VerifyCommand, flow.VerifyTimeoutSeconds)
+		if err == nil {
+			good = mid
+			continue
+		}
+		bad = mid
+		failure = verifyFailureMessage(flow.VerifyCommand, err, output)
+	}
+	return bad - 1, failure, nil
+}
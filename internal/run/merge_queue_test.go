@@ -0,0 +1,210 @@
+// Tests for the batched merge queue.
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/scheduler"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+	"github.com/cmtonkinson/governator/internal/worktree"
+)
+
+// TestExecuteMergeQueueVerifiesBatchOnce lands every task and runs verify a single time.
+func TestExecuteMergeQueueVerifiesBatchOnce(t *testing.T) {
+	for _, strategy := range []string{config.MergeStrategySquash, config.MergeStrategyMerge, config.MergeStrategyRebase} {
+		t.Run(strategy, func(t *testing.T) {
+			repo := testrepos.New(t)
+			entries := []MergeQueueEntry{
+				addQueueTask(t, repo, "T-Q-001", "one.txt"),
+				addQueueTask(t, repo, "T-Q-002", "two.txt"),
+				addQueueTask(t, repo, "T-Q-003", "three.txt"),
+			}
+			runsPath := filepath.Join(t.TempDir(), "runs")
+
+			outcomes, err := ExecuteMergeQueue(MergeFlowInput{
+				RepoRoot:      repo.Root,
+				MainBranch:    "main",
+				Strategy:      strategy,
+				VerifyCommand: []string{"sh", "-c", "echo run >> " + runsPath},
+			}, entries)
+			if err != nil {
+				t.Fatalf("merge queue: %v", err)
+			}
+			for _, outcome := range outcomes {
+				if outcome.Err != nil || !outcome.Result.Success {
+					t.Fatalf("outcome %s = %+v", outcome.TaskID, outcome)
+				}
+			}
+			if len(outcomes) != len(entries) {
+				t.Fatalf("outcomes = %d, want %d", len(outcomes), len(entries))
+			}
+			for _, name := range []string{"one.txt", "two.txt", "three.txt"} {
+				if _, err := os.Stat(filepath.Join(repo.Root, name)); err != nil {
+					t.Fatalf("%s missing from main: %v", name, err)
+				}
+			}
+			if runs := queueVerifyRuns(t, runsPath); runs != 1 {
+				t.Fatalf("verify runs = %d, want 1", runs)
+			}
+		})
+	}
+}
+
+// TestExecuteMergeQueueBisectsVerifyFailure sends back only the task that breaks verify.
+func TestExecuteMergeQueueBisectsVerifyFailure(t *testing.T) {
+	repo := testrepos.New(t)
+	entries := []MergeQueueEntry{
+		addQueueTask(t, repo, "T-Q-101", "one.txt"),
+		addQueueTask(t, repo, "T-Q-102", "BROKEN"),
+		addQueueTask(t, repo, "T-Q-103", "three.txt"),
+		addQueueTask(t, repo, "T-Q-104", "four.txt"),
+	}
+
+	outcomes, err := ExecuteMergeQueue(MergeFlowInput{
+		RepoRoot:      repo.Root,
+		MainBranch:    "main",
+		VerifyCommand: []string{"sh", "-c", "if [ -e BROKEN ]; then echo 'FAIL: broken build'; exit 1; fi"},
+	}, entries)
+	if err != nil {
+		t.Fatalf("merge queue: %v", err)
+	}
+	got := make(map[string]MergeQueueOutcome, len(outcomes))
+	for _, outcome := range outcomes {
+		got[outcome.TaskID] = outcome
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("outcomes = %+v, want one per task", outcomes)
+	}
+	culprit := got["T-Q-102"]
+	if culprit.Result.Success || culprit.Result.NewState != index.TaskStateConflict {
+		t.Fatalf("culprit outcome = %+v, want conflict", culprit)
+	}
+	if !strings.Contains(culprit.Result.ConflictError, "FAIL: broken build") {
+		t.Fatalf("culprit conflict error = %q, want verify output", culprit.Result.ConflictError)
+	}
+	for _, id := range []string{"T-Q-101", "T-Q-103", "T-Q-104"} {
+		if outcome := got[id]; outcome.Err != nil || !outcome.Result.Success {
+			t.Fatalf("outcome %s = %+v, want merged", id, outcome)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repo.Root, "BROKEN")); !os.IsNotExist(err) {
+		t.Fatalf("culprit change reached main: %v", err)
+	}
+	for _, name := range []string{"one.txt", "three.txt", "four.txt"} {
+		if _, err := os.Stat(filepath.Join(repo.Root, name)); err != nil {
+			t.Fatalf("%s missing from main: %v", name, err)
+		}
+	}
+
+	// The culprit branch is left on the original base so it carries no other task's commits.
+	culpritBranch := TaskBranchName(entries[1].Task)
+	if log := repo.RunGit(t, "log", "--format=%s", "main.."+culpritBranch); strings.TrimSpace(log) != "Add BROKEN" {
+		t.Fatalf("culprit branch commits = %q", log)
+	}
+}
+
+// TestExecuteMergeQueueSkipsVerifiedPrefix does not verify again a prefix bisection already passed.
+func TestExecuteMergeQueueSkipsVerifiedPrefix(t *testing.T) {
+	repo := testrepos.New(t)
+	entries := []MergeQueueEntry{
+		addQueueTask(t, repo, "T-Q-201", "one.txt"),
+		addQueueTask(t, repo, "T-Q-202", "two.txt"),
+		addQueueTask(t, repo, "T-Q-203", "BROKEN"),
+	}
+	runsPath := filepath.Join(t.TempDir(), "runs")
+
+	outcomes, err := ExecuteMergeQueue(MergeFlowInput{
+		RepoRoot:      repo.Root,
+		MainBranch:    "main",
+		VerifyCommand: []string{"sh", "-c", "echo run >> " + runsPath + "; if [ -e BROKEN ]; then exit 1; fi"},
+	}, entries)
+	if err != nil {
+		t.Fatalf("merge queue: %v", err)
+	}
+	for _, outcome := range outcomes {
+		wantSuccess := outcome.TaskID != "T-Q-203"
+		if outcome.Err != nil || outcome.Result.Success != wantSuccess {
+			t.Fatalf("outcome %s = %+v", outcome.TaskID, outcome)
+		}
+	}
+	// One run for the whole stack and two to bisect it; the surviving prefix is not rerun.
+	if runs := queueVerifyRuns(t, runsPath); runs != 3 {
+		t.Fatalf("verify runs = %d, want 3", runs)
+	}
+	for _, name := range []string{"one.txt", "two.txt"} {
+		if _, err := os.Stat(filepath.Join(repo.Root, name)); err != nil {
+			t.Fatalf("%s missing from main: %v", name, err)
+		}
+	}
+}
+
+// TestExecuteMergeStageMergeQueue lands resolved and waiting mergeable tasks as one batch.
+func TestExecuteMergeStageMergeQueue(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := testrepos.New(t)
+	first := addQueueTask(t, repo, "T-Q-201", "one.txt")
+	second := addQueueTask(t, repo, "T-Q-202", "two.txt")
+	second.Task.State = index.TaskStateResolved
+	first.Task.Kind = index.TaskKindExecution
+	second.Task.Kind = index.TaskKindExecution
+	idx := &index.Index{Tasks: []index.Task{first.Task, second.Task}}
+
+	cfg := config.Defaults()
+	cfg.Verify.Command = []string{"true"}
+	cfg.Verify.BatchSize = 5
+	var stdout, stderr strings.Builder
+	result, err := ExecuteMergeStage(repo.Root, idx, cfg, scheduler.RoleCapsFromConfig(cfg), map[string]string{
+		first.Task.ID:  first.WorktreePath,
+		second.Task.ID: second.WorktreePath,
+	}, &mockTransitionAuditor{}, nil, Options{Stdout: &stdout, Stderr: &stderr})
+	if err != nil {
+		t.Fatalf("ExecuteMergeStage: %v", err)
+	}
+	if result.TasksProcessed != 2 || result.TasksMerged != 2 {
+		t.Fatalf("result = %+v, stderr = %s", result, stderr.String())
+	}
+	for _, task := range idx.Tasks {
+		if task.State != index.TaskStateMerged {
+			t.Fatalf("%s state = %q, want merged", task.ID, task.State)
+		}
+	}
+}
+
+// addQueueTask creates a task branch adding one file and its worktree, returning the queue entry.
+func addQueueTask(t *testing.T, repo *testrepos.TempRepo, taskID string, fileName string) MergeQueueEntry {
+	t.Helper()
+	task := index.Task{ID: taskID, Title: "Queue " + fileName, Role: "default", State: index.TaskStateMergeable}
+	branchName := TaskBranchName(task)
+	repo.RunGit(t, "checkout", "-b", branchName, "main")
+	if err := os.WriteFile(filepath.Join(repo.Root, fileName), []byte(taskID+"\n"), 0o644); err != nil {
+		t.Fatalf("write %s: %v", fileName, err)
+	}
+	repo.RunGit(t, "add", fileName)
+	repo.RunGit(t, "commit", "-m", "Add "+fileName)
+	repo.RunGit(t, "checkout", "main")
+
+	manager, err := worktree.NewManager(repo.Root)
+	if err != nil {
+		t.Fatalf("create worktree manager: %v", err)
+	}
+	result, err := manager.EnsureWorktree(worktree.Spec{WorkstreamID: task.ID, Branch: branchName, BaseBranch: "main"})
+	if err != nil {
+		t.Fatalf("ensure worktree: %v", err)
+	}
+	return MergeQueueEntry{Task: task, WorktreePath: result.Path}
+}
+
+// queueVerifyRuns counts the lines the verify command appended to path.
+func queueVerifyRuns(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read verify runs: %v", err)
+	}
+	return strings.Count(string(data), "\n")
+}
@@ -3,10 +3,12 @@ package run
 import (
 	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/testrepos"
 	"github.com/cmtonkinson/governator/internal/worktree"
@@ -80,6 +82,399 @@ func TestExecuteReviewMergeFlow_Success(t *testing.T) {
 	}
 }
 
+// TestExecuteReviewMergeFlow_RemovesWorktreeUnderConfiguredRoot verifies a merged task's worktree
+// and branch are cleaned up when worktrees live outside the repo.
+func TestExecuteReviewMergeFlow_RemovesWorktreeUnderConfiguredRoot(t *testing.T) {
+	repo := testrepos.New(t)
+	task := index.Task{
+		ID:    "T-MERGE-ROOT",
+		Title: "Merge from configured worktree root",
+		Role:  "default",
+		State: index.TaskStateTested,
+	}
+	branchName := TaskBranchName(task)
+	repo.RunGit(t, "checkout", "-b", branchName)
+	if err := os.WriteFile(filepath.Join(repo.Root, "FEATURE.md"), []byte("feature\n"), 0o644); err != nil {
+		t.Fatalf("write feature file: %v", err)
+	}
+	repo.RunGit(t, "add", "FEATURE.md")
+	repo.RunGit(t, "commit", "-m", "Add feature")
+	repo.RunGit(t, "checkout", "main")
+
+	worktreesRoot := t.TempDir()
+	manager, err := worktree.NewManagerWithRoot(repo.Root, worktreesRoot)
+	if err != nil {
+		t.Fatalf("create worktree manager: %v", err)
+	}
+	worktreeResult, err := manager.EnsureWorktree(worktree.Spec{WorkstreamID: task.ID, Branch: branchName, BaseBranch: "main"})
+	if err != nil {
+		t.Fatalf("ensure worktree: %v", err)
+	}
+
+	result, err := ExecuteReviewMergeFlow(MergeFlowInput{RepoRoot: repo.Root, WorktreePath: worktreeResult.Path, Task: task, MainBranch: "main", WorktreesRoot: worktreesRoot})
+	if err != nil || !result.Success {
+		t.Fatalf("merge flow = %+v, %v; want success", result, err)
+	}
+	if _, err := os.Stat(filepath.Join(manager.Dir(), "merge-worktrees")); err != nil {
+		t.Fatalf("expected merge worktree dir under configured root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Root, "_governator", "_local-state", "merge-worktrees")); !os.IsNotExist(err) {
+		t.Fatalf("expected no merge worktree dir in repo local state, stat err = %v", err)
+	}
+	if _, err := os.Stat(worktreeResult.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected worktree %s removed, stat err = %v", worktreeResult.Path, err)
+	}
+	if branches := strings.TrimSpace(repo.RunGit(t, "branch", "--list", branchName)); branches != "" {
+		t.Fatalf("expected branch %s deleted, got %q", branchName, branches)
+	}
+}
+
+// TestExecuteReviewMergeFlow_Strategies checks the history each merge strategy leaves on main.
+func TestExecuteReviewMergeFlow_Strategies(t *testing.T) {
+	tests := []struct {
+		strategy    string
+		wantParents int
+		wantSubject string
+		wantLog     []string
+		wantMissing []string
+	}{
+		{strategy: config.MergeStrategySquash, wantParents: 1, wantSubject: "governator: T-STRAT-001 - Strategy coverage", wantLog: []string{"Advance main"}, wantMissing: []string{"Add part one"}},
+		{strategy: config.MergeStrategyMerge, wantParents: 2, wantSubject: "governator: T-STRAT-001 - Strategy coverage", wantLog: []string{"Add part one", "Add part two", "Advance main"}},
+		{strategy: config.MergeStrategyRebase, wantParents: 1, wantSubject: "Add part two", wantLog: []string{"Add part one", "Advance main"}, wantMissing: []string{"governator: T-STRAT-001"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			repo := testrepos.New(t)
+			task := index.Task{
+				ID:    "T-STRAT-001",
+				Title: "Strategy coverage",
+				Role:  "default",
+				State: index.TaskStateTested,
+			}
+
+			branchName := TaskBranchName(task)
+			repo.RunGit(t, "checkout", "-b", branchName)
+			for _, part := range []string{"one", "two"} {
+				if err := os.WriteFile(filepath.Join(repo.Root, "part-"+part+".txt"), []byte(part+"\n"), 0o644); err != nil {
+					t.Fatalf("write part %s: %v", part, err)
+				}
+				repo.RunGit(t, "add", "part-"+part+".txt")
+				repo.RunGit(t, "commit", "-m", "Add part "+part)
+			}
+			repo.RunGit(t, "checkout", "main")
+			if err := os.WriteFile(filepath.Join(repo.Root, "MAIN.md"), []byte("main moved\n"), 0o644); err != nil {
+				t.Fatalf("write main file: %v", err)
+			}
+			repo.RunGit(t, "add", "MAIN.md")
+			repo.RunGit(t, "commit", "-m", "Advance main")
+
+			manager, err := worktree.NewManager(repo.Root)
+			if err != nil {
+				t.Fatalf("create worktree manager: %v", err)
+			}
+			worktreeResult, err := manager.EnsureWorktree(worktree.Spec{
+				WorkstreamID: task.ID,
+				Branch:       branchName,
+				BaseBranch:   "main",
+			})
+			if err != nil {
+				t.Fatalf("ensure worktree: %v", err)
+			}
+
+			result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+				RepoRoot:     repo.Root,
+				WorktreePath: worktreeResult.Path,
+				Task:         task,
+				MainBranch:   "main",
+				Strategy:     tt.strategy,
+			})
+			if err != nil {
+				t.Fatalf("execute merge flow: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("unexpected merge result: %+v", result)
+			}
+
+			parents := strings.Fields(repo.RunGit(t, "log", "-1", "--format=%P", "main"))
+			if len(parents) != tt.wantParents {
+				t.Fatalf("main parents = %v, want %d", parents, tt.wantParents)
+			}
+			if subject := strings.TrimSpace(repo.RunGit(t, "log", "-1", "--format=%s", "main")); subject != tt.wantSubject {
+				t.Fatalf("main subject = %q, want %q", subject, tt.wantSubject)
+			}
+			history := repo.RunGit(t, "log", "--format=%B", "main")
+			for _, want := range tt.wantLog {
+				if !strings.Contains(history, want) {
+					t.Fatalf("main history missing %q:\n%s", want, history)
+				}
+			}
+			for _, unwanted := range tt.wantMissing {
+				if strings.Contains(history, unwanted) {
+					t.Fatalf("main history unexpectedly contains %q:\n%s", unwanted, history)
+				}
+			}
+			for _, part := range []string{"part-one.txt", "part-two.txt", "MAIN.md"} {
+				if _, err := os.Stat(filepath.Join(repo.Root, part)); err != nil {
+					t.Fatalf("%s missing after merge: %v", part, err)
+				}
+			}
+		})
+	}
+}
+
+// TestExecuteReviewMergeFlow_CommitTemplate renders the merge template and finds the merge by its trailers.
+func TestExecuteReviewMergeFlow_CommitTemplate(t *testing.T) {
+	repo, task, worktreePath := setupMergeTaskRepo(t, "T-TMPL-001")
+	task.Attempts = index.AttemptCounters{Total: 3}
+
+	result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+		RepoRoot:     repo.Root,
+		WorktreePath: worktreePath,
+		Task:         task,
+		MainBranch:   "main",
+		Strategy:     config.MergeStrategySquash,
+		Commits:      config.CommitsConfig{MergeTemplate: "feat({{.TaskID}}): {{.Title}}"},
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("merge flow: %+v, %v", result, err)
+	}
+
+	if subject := strings.TrimSpace(repo.RunGit(t, "log", "-1", "--format=%s", "main")); subject != "feat(T-TMPL-001): Merge flow coverage" {
+		t.Fatalf("main subject = %q", subject)
+	}
+	trailers := repo.RunGit(t, "log", "-1", "--format=%(trailers:only,unfold)", "main")
+	for _, want := range []string{"Governator-Task: T-TMPL-001", "Governator-Role: default", "Governator-Stage: merge", "Governator-Attempt: 3"} {
+		if !strings.Contains(trailers, want) {
+			t.Fatalf("merge trailers missing %q:\n%s", want, trailers)
+		}
+	}
+
+	commit, err := findMergeCommit(repo.Root, "main", task)
+	if err != nil {
+		t.Fatalf("find merge commit: %v", err)
+	}
+	if head := strings.TrimSpace(repo.RunGit(t, "rev-parse", "main")); commit != head {
+		t.Fatalf("findMergeCommit = %q, want %q", commit, head)
+	}
+}
+
+// TestExecuteReviewMergeFlow_IdentityAndSigning authors the merge commit as the configured
+// merge identity and signs it with an ssh key.
+func TestExecuteReviewMergeFlow_IdentityAndSigning(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	repo, task, worktreePath := setupMergeTaskRepo(t, "T-SIGN-001")
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v: %s", err, output)
+	}
+
+	result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+		RepoRoot:     repo.Root,
+		WorktreePath: worktreePath,
+		Task:         task,
+		MainBranch:   "main",
+		Strategy:     config.MergeStrategyMerge,
+		Identity:     config.GitIdentity{Name: "Release Operator", Email: "ops@example.com"},
+		Signing:      config.GitSigning{Format: config.GitSigningSSH, Key: keyPath},
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("merge flow: %+v, %v", result, err)
+	}
+
+	if got := strings.TrimSpace(repo.RunGit(t, "log", "-1", "--format=%an <%ae>|%cn <%ce>", "main")); got != "Release Operator <ops@example.com>|Release Operator <ops@example.com>" {
+		t.Fatalf("merge identity = %q", got)
+	}
+	if raw := repo.RunGit(t, "cat-file", "-p", "main"); !strings.Contains(raw, "-----BEGIN SSH SIGNATURE-----") {
+		t.Fatalf("merge commit is not signed:\n%s", raw)
+	}
+}
+
+// TestExecuteReviewMergeFlow_Remote rebases onto upstream commits and pushes the result.
+func TestExecuteReviewMergeFlow_Remote(t *testing.T) {
+	repo, upstream, task, worktreePath := setupRemoteMergeRepo(t, "T-REMOTE-001")
+	writeUpstreamCommit(t, repo, upstream, "UPSTREAM.md", "Human commit upstream")
+
+	result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+		RepoRoot:     repo.Root,
+		WorktreePath: worktreePath,
+		Task:         task,
+		MainBranch:   "main",
+		Remote:       "origin",
+	})
+	if err != nil {
+		t.Fatalf("execute merge flow: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("unexpected merge result: %+v", result)
+	}
+
+	for _, name := range []string{"UPSTREAM.md", "FEATURE.md"} {
+		if _, err := os.Stat(filepath.Join(repo.Root, name)); err != nil {
+			t.Fatalf("%s missing from local main: %v", name, err)
+		}
+	}
+	localMain := strings.TrimSpace(repo.RunGit(t, "rev-parse", "main"))
+	remoteMain := strings.TrimSpace(repo.RunGit(t, "ls-remote", "origin", "refs/heads/main"))
+	if !strings.HasPrefix(remoteMain, localMain) {
+		t.Fatalf("remote main = %q, want %s", remoteMain, localMain)
+	}
+	if remoteBranch := repo.RunGit(t, "ls-remote", "origin", "refs/heads/"+TaskBranchName(task)); strings.TrimSpace(remoteBranch) == "" {
+		t.Fatal("task branch was not pushed to the remote")
+	}
+}
+
+// TestExecuteReviewMergeFlow_RemotePushRejected treats a lost push race as a conflict.
+func TestExecuteReviewMergeFlow_RemotePushRejected(t *testing.T) {
+	repo, upstream, task, worktreePath := setupRemoteMergeRepo(t, "T-REMOTE-002")
+
+	// The pre-push hook lands an upstream commit once, right before Governator pushes main.
+	marker := filepath.Join(t.TempDir(), "raced")
+	hook := strings.Join([]string{
+		"#!/bin/sh",
+		"[ -e " + marker + " ] && exit 0",
+		"touch " + marker,
+		"unset GIT_DIR GIT_WORK_TREE GIT_INDEX_FILE",
+		"cd " + upstream + " && git commit -q --allow-empty -m race && git push -q origin main",
+	}, "\n") + "\n"
+	hookPath := filepath.Join(repo.Root, ".git", "hooks", "pre-push")
+	if err := os.WriteFile(hookPath, []byte(hook), 0o755); err != nil {
+		t.Fatalf("write pre-push hook: %v", err)
+	}
+
+	result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+		RepoRoot:     repo.Root,
+		WorktreePath: worktreePath,
+		Task:         task,
+		MainBranch:   "main",
+		Remote:       "origin",
+	})
+	if err != nil {
+		t.Fatalf("execute merge flow: %v", err)
+	}
+	if result.Success || result.NewState != index.TaskStateConflict {
+		t.Fatalf("unexpected merge result: %+v", result)
+	}
+	if !strings.Contains(result.ConflictError, "rejected") {
+		t.Fatalf("conflict error = %q, want push rejection", result.ConflictError)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Root, "FEATURE.md")); err == nil {
+		t.Fatal("local main moved despite the rejected push")
+	}
+}
+
+// TestExecuteReviewMergeFlow_Verify gates the merge on the verify command run against the merged tree.
+func TestExecuteReviewMergeFlow_Verify(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		repo, task, worktreePath := setupMergeTaskRepo(t, "T-VERIFY-001")
+		result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+			RepoRoot:      repo.Root,
+			WorktreePath:  worktreePath,
+			Task:          task,
+			MainBranch:    "main",
+			VerifyCommand: []string{"test", "-f", "FEATURE.md"},
+		})
+		if err != nil {
+			t.Fatalf("execute merge flow: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("unexpected merge result: %+v", result)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		repo, task, worktreePath := setupMergeTaskRepo(t, "T-VERIFY-002")
+		before := strings.TrimSpace(repo.RunGit(t, "rev-parse", "main"))
+		result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+			RepoRoot:      repo.Root,
+			WorktreePath:  worktreePath,
+			Task:          task,
+			MainBranch:    "main",
+			VerifyCommand: []string{"sh", "-c", "echo 'FAIL: TestIntegration'; exit 3"},
+		})
+		if err != nil {
+			t.Fatalf("execute merge flow: %v", err)
+		}
+		if result.Success || result.NewState != index.TaskStateConflict {
+			t.Fatalf("unexpected merge result: %+v", result)
+		}
+		if !strings.Contains(result.ConflictError, "post-merge verify") || !strings.Contains(result.ConflictError, "FAIL: TestIntegration") {
+			t.Fatalf("conflict error = %q, want verify output", result.ConflictError)
+		}
+		if after := strings.TrimSpace(repo.RunGit(t, "rev-parse", "main")); after != before {
+			t.Fatalf("main moved from %s to %s despite failed verify", before, after)
+		}
+		if branches := repo.RunGit(t, "branch", "--list", TaskBranchName(task)); strings.TrimSpace(branches) == "" {
+			t.Fatal("task branch was removed despite failed verify")
+		}
+	})
+}
+
+// setupRemoteMergeRepo creates a task repo with a bare origin and a second clone of origin
+// for simulating human commits upstream.
+func setupRemoteMergeRepo(t *testing.T, taskID string) (*testrepos.TempRepo, string, index.Task, string) {
+	t.Helper()
+	repo, task, worktreePath := setupMergeTaskRepo(t, taskID)
+	remotes := t.TempDir()
+	bare := filepath.Join(remotes, "origin.git")
+	repo.RunGitInDir(t, remotes, "init", "--bare", "--initial-branch=main", bare)
+	repo.RunGit(t, "remote", "add", "origin", bare)
+	repo.RunGit(t, "push", "origin", "main")
+
+	upstream := filepath.Join(remotes, "upstream")
+	repo.RunGitInDir(t, remotes, "clone", bare, upstream)
+	repo.RunGitInDir(t, upstream, "config", "user.name", "Upstream Human")
+	repo.RunGitInDir(t, upstream, "config", "user.email", "human@example.com")
+	return repo, upstream, task, worktreePath
+}
+
+// setupMergeTaskRepo creates a repo with a tested task whose branch adds FEATURE.md, and
+// the task worktree the merge flow rebases.
+func setupMergeTaskRepo(t *testing.T, taskID string) (*testrepos.TempRepo, index.Task, string) {
+	t.Helper()
+	repo := testrepos.New(t)
+	task := index.Task{
+		ID:    taskID,
+		Title: "Merge flow coverage",
+		Role:  "default",
+		State: index.TaskStateTested,
+	}
+	branchName := TaskBranchName(task)
+	repo.RunGit(t, "checkout", "-b", branchName)
+	if err := os.WriteFile(filepath.Join(repo.Root, "FEATURE.md"), []byte("feature\n"), 0o644); err != nil {
+		t.Fatalf("write feature file: %v", err)
+	}
+	repo.RunGit(t, "add", "FEATURE.md")
+	repo.RunGit(t, "commit", "-m", "Add feature")
+	repo.RunGit(t, "checkout", "main")
+
+	manager, err := worktree.NewManager(repo.Root)
+	if err != nil {
+		t.Fatalf("create worktree manager: %v", err)
+	}
+	worktreeResult, err := manager.EnsureWorktree(worktree.Spec{
+		WorkstreamID: task.ID,
+		Branch:       branchName,
+		BaseBranch:   "main",
+	})
+	if err != nil {
+		t.Fatalf("ensure worktree: %v", err)
+	}
+	return repo, task, worktreeResult.Path
+}
+
+// writeUpstreamCommit commits and pushes a file from the upstream clone.
+func writeUpstreamCommit(t *testing.T, repo *testrepos.TempRepo, upstream string, name string, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(upstream, name), []byte(message+"\n"), 0o644); err != nil {
+		t.Fatalf("write upstream file: %v", err)
+	}
+	repo.RunGitInDir(t, upstream, "add", name)
+	repo.RunGitInDir(t, upstream, "commit", "-m", message)
+	repo.RunGitInDir(t, upstream, "push", "origin", "main")
+}
+
 func TestExecuteReviewMergeFlow_ValidationErrors(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -233,6 +628,44 @@ func TestIsMergeConflict(t *testing.T) {
 	}
 }
 
+func TestIsPushRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "fetch first rejection",
+			err:      errors.New(" ! [rejected]        main -> main (fetch first)"),
+			expected: true,
+		},
+		{
+			name:     "non-fast-forward rejection",
+			err:      errors.New("hint: Updates were rejected because the tip of your current branch is behind (non-fast-forward)"),
+			expected: true,
+		},
+		{
+			name:     "hook rejection",
+			err:      errors.New(" ! [remote rejected] main -> main (pre-receive hook declined)"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isPushRejected(tt.err)
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestRunGitInWorktree_ValidationErrors(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,54 @@
+// Package run provides the post-merge verification gate.
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// verifyOutputLimit caps how much verify output is kept in the conflict reason.
+	verifyOutputLimit = 4000
+	// verifyWaitDelay bounds how long output pipes held by leftover child processes may
+	// keep a timed-out verify command from returning.
+	verifyWaitDelay = 10 * time.Second
+)
+
+// runMergeVerify runs the verify command in dir and returns its combined output.
+// A non-positive timeout runs the command without a deadline.
+func runMergeVerify(dir string, command []string, timeoutSeconds int) (string, error) {
+	if len(command) == 0 {
+		return "", errors.New("verify command is required")
+	}
+	ctx := context.Background()
+	if timeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.WaitDelay = verifyWaitDelay
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return string(output), fmt.Errorf("timed out after %ds", timeoutSeconds)
+	}
+	return string(output), err
+}
+
+// verifyFailureMessage describes a failed verify run, keeping the tail of its output.
+func verifyFailureMessage(command []string, err error, output string) string {
+	message := fmt.Sprintf("post-merge verify `%s` failed: %v", strings.Join(command, " "), err)
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return message
+	}
+	if len(output) > verifyOutputLimit {
+		output = "..." + output[len(output)-verifyOutputLimit:]
+	}
+	return message + "\n" + output
+}
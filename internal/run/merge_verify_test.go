@@ -0,0 +1,33 @@
+// Tests for the post-merge verification gate.
+package run
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRunMergeVerifyTimeout stops a verify command that runs past its deadline.
+func TestRunMergeVerifyTimeout(t *testing.T) {
+	t.Parallel()
+	_, err := runMergeVerify(t.TempDir(), []string{"sleep", "5"}, 1)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 1s") {
+		t.Fatalf("error = %v, want timeout", err)
+	}
+}
+
+// TestVerifyFailureMessageKeepsTail keeps the end of long output, where failures usually are.
+func TestVerifyFailureMessageKeepsTail(t *testing.T) {
+	t.Parallel()
+	output := strings.Repeat("noise\n", verifyOutputLimit) + "FAIL: TestLast\n"
+	message := verifyFailureMessage([]string{"go", "test", "./..."}, errors.New("exit status 1"), output)
+	if !strings.HasPrefix(message, "post-merge verify `go test ./...` failed: exit status 1\n...") {
+		t.Fatalf("message prefix = %q", message[:80])
+	}
+	if !strings.HasSuffix(message, "FAIL: TestLast") {
+		t.Fatalf("message lost the output tail: %q", message[len(message)-40:])
+	}
+	if len(message) > verifyOutputLimit+100 {
+		t.Fatalf("message length = %d, want output capped at %d", len(message), verifyOutputLimit)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/cmtonkinson/governator/internal/audit"
 	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/dryrun"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/roles"
@@ -24,6 +25,8 @@ const (
 	indexFilePath = "_governator/_local-state/index.json"
 	// conflictResolutionPromptPath is the task prompt used for resolve-stage workers.
 	conflictResolutionPromptPath = "_governator/prompts/conflict-resolution.md"
+	// conflictContextFileName is the generated resolve-stage context prompt in the worker state dir.
+	conflictContextFileName = "conflict-context.md"
 )
 
 // Options defines the configuration for a run execution.
@@ -34,6 +37,8 @@ type Options struct {
 	DisableDispatch bool
 	// SkipPlanningDrift disables planning drift checks for this run invocation.
 	SkipPlanningDrift bool
+	// DryRun, when set, replaces every worker command with the scripted fake agent.
+	DryRun *dryrun.Settings
 }
 
 // Result captures the outcome of a run execution.
@@ -57,6 +62,9 @@ func Run(repoRoot string, opts Options) (Result, error) {
 	if err != nil {
 		return Result{}, fmt.Errorf("load config: %w", err)
 	}
+	if opts.DryRun != nil {
+		cfg = opts.DryRun.Apply(cfg)
+	}
 
 	inFlightStore, err := inflight.NewStore(repoRoot)
 	if err != nil {
@@ -101,9 +109,9 @@ func Run(repoRoot string, opts Options) (Result, error) {
 	caps := scheduler.RoleCapsFromConfig(cfg)
 	baseBranch := baseBranchName(cfg)
 
-	if !opts.SkipPlanningDrift {
+	if !opts.SkipPlanningDrift && cfg.Drift.Mode != config.DriftModeNotify {
 		// Check for planning drift
-		if err := CheckPlanningDrift(repoRoot, idx.Digests); err != nil {
+		if err := CheckPlanningDrift(repoRoot, cfg, idx.Digests); err != nil {
 			if errors.Is(err, ErrPlanningDrift) {
 				emitPlanningDriftMessage(opts.Stdout, err.Error())
 			}
@@ -180,8 +188,10 @@ func Run(repoRoot string, opts Options) (Result, error) {
 	conflictResult := executionController.conflictResult
 	mergeResult := executionController.mergeResult
 
+	rateLimitedTasks := workResult.TasksRateLimited + testResult.TasksRateLimited + reviewResult.TasksRateLimited + conflictResult.TasksRateLimited
+
 	// Save updated index
-	if len(resumedTasks) > 0 || len(blockedTasks) > 0 || workResult.TasksWorked > 0 || workResult.TasksBlocked > 0 || testResult.TasksTested > 0 || testResult.TasksBlocked > 0 || reviewResult.TasksReviewed > 0 || reviewResult.TasksBlocked > 0 || conflictResult.TasksResolved > 0 || conflictResult.TasksBlocked > 0 || mergeResult.TasksProcessed > 0 {
+	if len(resumedTasks) > 0 || len(blockedTasks) > 0 || workResult.TasksWorked > 0 || workResult.TasksBlocked > 0 || testResult.TasksTested > 0 || testResult.TasksBlocked > 0 || reviewResult.TasksReviewed > 0 || reviewResult.TasksBlocked > 0 || conflictResult.TasksResolved > 0 || conflictResult.TasksBlocked > 0 || mergeResult.TasksProcessed > 0 || rateLimitedTasks > 0 {
 		if err := index.SaveWithLock(indexPath, idx, indexWriteLock); err != nil {
 			return Result{}, fmt.Errorf("save task index: %w", err)
 		}
@@ -249,6 +259,12 @@ func Run(repoRoot string, opts Options) (Result, error) {
 		}
 		message.WriteString(fmt.Sprintf("processed %d merge task(s)", mergeResult.TasksProcessed))
 	}
+	if rateLimitedTasks > 0 {
+		if message.Len() > 0 {
+			message.WriteString(", ")
+		}
+		message.WriteString(fmt.Sprintf("deferred %d rate-limited task(s)", rateLimitedTasks))
+	}
 	if message.Len() == 0 {
 		message.WriteString("No tasks to resume or execute")
 	}
@@ -262,25 +278,28 @@ func Run(repoRoot string, opts Options) (Result, error) {
 
 // TestStageResult captures the outcome of test stage execution.
 type TestStageResult struct {
-	TasksDispatched int
-	TasksTested     int
-	TasksBlocked    int
-	InFlightUpdated bool
-	Metrics         map[string]index.ExecutionMetrics // Metrics by task ID
+	TasksDispatched  int
+	TasksTested      int
+	TasksBlocked     int
+	TasksRateLimited int
+	InFlightUpdated  bool
+	Metrics          map[string]index.ExecutionMetrics // Metrics by task ID
 }
 
 // WorkStageResult captures the outcome of work stage execution.
 type WorkStageResult struct {
-	TasksDispatched int
-	TasksWorked     int
-	TasksBlocked    int
-	InFlightUpdated bool
-	WorktreePaths   map[string]string
-	Metrics         map[string]index.ExecutionMetrics // Metrics by task ID
+	TasksDispatched  int
+	TasksWorked      int
+	TasksBlocked     int
+	TasksRateLimited int
+	TasksDeferred    int // tasks left triaged by the worktree quota
+	InFlightUpdated  bool
+	WorktreePaths    map[string]string
+	Metrics          map[string]index.ExecutionMetrics // Metrics by task ID
 }
 
 // ExecuteWorkStage processes tasks in the open state through the work stage.
-func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, resumeWorktrees map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, opts Options) (WorkStageResult, error) {
+func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, resumeWorktrees map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, changes worktreeChanges, opts Options) (WorkStageResult, error) {
 	result := WorkStageResult{
 		WorktreePaths: map[string]string{},
 		Metrics:       map[string]index.ExecutionMetrics{},
@@ -292,7 +311,7 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 		resumeWorktrees = map[string]string{}
 	}
 
-	manager, err := worktree.NewManager(repoRoot)
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
 	if err != nil {
 		return result, fmt.Errorf("create worktree manager: %w", err)
 	}
@@ -318,18 +337,24 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			continue
 		}
 
-		exitStatus, finished, err := worker.ReadExitStatus(entry.WorkerStateDir, task.ID, roles.StageWork)
+		exitStatus, finished, err := collectWorkerExit(repoRoot, entry.WorkerStateDir, task.ID, roles.StageWork, func(message string) {
+			fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+		})
 		if err != nil {
 			fmt.Fprintf(opts.Stderr, "Warning: failed to read exit status for task %s: %v\n", task.ID, err)
 			continue
 		}
 		if !finished {
-			if startedAt, ok := startedAtForTask(inFlight, task.ID); ok && timedOut(startedAt, cfg.Timeouts.WorkerSeconds) {
+			expiry, expired := checkWorkerExpiry(inFlight, task.ID, entry.WorkerStateDir, worktreePath, worker.TimeoutSecondsForTask(cfg, task, roles.StageWork), cfg.Timeouts.StallSeconds, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s: %s\n", task.ID, message)
+			})
+			if expired {
 				failedResult := worker.IngestResult{
 					Success:     false,
 					NewState:    index.TaskStateBlocked,
-					BlockReason: formatTimeoutReason(cfg.Timeouts.WorkerSeconds),
-					TimedOut:    true,
+					BlockReason: expiry.reason,
+					TimedOut:    !expiry.stalled,
+					Stalled:     expiry.stalled,
 				}
 				logAgentOutcome(workerAuditor, task.ID, task.Role, roles.StageWork, statusFromIngestResult(failedResult), exitCodeForOutcome(-1, true), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
@@ -341,14 +366,12 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 					fmt.Fprintf(opts.Stderr, "Warning: failed to update task state for %s: %v\n", task.ID, updateErr)
 				} else {
 					result.TasksBlocked++
-					emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork), failedResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+					emitWorkerExpiry(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork), expiry)
 				}
-				if workerAuditor != nil {
-					if auditErr := workerAuditor.LogWorkerTimeout(task.ID, string(task.Role), cfg.Timeouts.WorkerSeconds, worktreePath); auditErr != nil {
-						fmt.Fprintf(opts.Stderr, "Warning: failed to log worker timeout for %s: %v\n", task.ID, auditErr)
-					}
+				if auditErr := logWorkerExpiry(workerAuditor, task.ID, string(task.Role), worktreePath, expiry); auditErr != nil {
+					fmt.Fprintf(opts.Stderr, "Warning: failed to log worker %s for %s: %v\n", expiry.kind(), task.ID, auditErr)
 				}
-				killWorkerProcess(task.PID, entry.WorkerStateDir, func(message string) {
+				killWorkerProcess(repoRoot, task.PID, entry.WorkerStateDir, expiry.kind(), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
 				})
 				if err := inFlight.Remove(task.ID); err == nil {
@@ -358,15 +381,21 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			continue
 		}
 
+		if exitStatus.ExitCode != 0 && deferRateLimitedTask(repoRoot, idx, cfg, task, roles.StageWork, entry.WorkerStateDir, exitStatus.ExitCode, workerAuditor, opts) {
+			result.TasksRateLimited++
+			if err := inFlight.Remove(task.ID); err == nil {
+				result.InFlightUpdated = true
+			}
+			continue
+		}
+
 		var ingestResult worker.IngestResult
 		if exitStatus.ExitCode != 0 {
-			ingestResult = worker.IngestResult{
-				Success:     false,
-				NewState:    index.TaskStateBlocked,
-				BlockReason: fmt.Sprintf("worker process exited with code %d", exitStatus.ExitCode),
-			}
+			ingestResult = worker.FailedExitResult(entry.WorkerStateDir, exitStatus.ExitCode, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+			})
 		} else {
-			ingestResult, err = finalizeStageSuccess(worktreePath, entry.WorkerStateDir, task, roles.StageWork)
+			ingestResult, err = finalizeStageSuccess(repoRoot, worktreePath, entry.WorkerStateDir, task, roles.StageWork, newCommitProvenance(cfg, index.Role(entry.Role)))
 			if err != nil {
 				ingestResult = worker.IngestResult{
 					Success:     false,
@@ -401,7 +430,7 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			emitTaskComplete(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork))
 		} else if ingestResult.TimedOut {
 			result.TasksBlocked++
-			emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork), ingestResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+			emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork), ingestResult.BlockReason, worker.TimeoutSecondsForTask(cfg, task, roles.StageWork))
 		} else {
 			result.TasksBlocked++
 			emitTaskFailure(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork), ingestResult.BlockReason)
@@ -416,7 +445,9 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 	if opts.DisableDispatch {
 		return result, nil
 	}
-	selectedTasks, err := selectTasksForStage(*idx, adjustedCaps, inFlight, index.TaskStateTriaged)
+	selectedTasks, err := selectTasksForStageExcluding(*idx, adjustedCaps, inFlight, baseBranchName(cfg), changes, cooldownExclusion(repoRoot, cfg, func(msg string) {
+		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
+	}), index.TaskStateTriaged)
 	if err != nil {
 		return result, fmt.Errorf("schedule work tasks: %w", err)
 	}
@@ -425,7 +456,23 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 		return result, nil
 	}
 
+	quota := newWorktreeQuota(manager, cfg)
 	for _, task := range selectedTasks {
+		refreshTaskTimeout(repoRoot, idx, &task)
+		// Leave the task triaged when a new worktree would exceed the configured quota.
+		if _, resuming := resumeWorktrees[task.ID]; !resuming {
+			if _, exists, _ := manager.ExistingWorktreePath(task.ID); !exists {
+				reason, err := quota.reached()
+				if err != nil {
+					fmt.Fprintf(opts.Stderr, "Warning: failed to check worktree quota for task %s: %v\n", task.ID, err)
+				} else if reason != "" {
+					fmt.Fprintf(opts.Stderr, "Deferring task %s: %s\n", task.ID, reason)
+					result.TasksDeferred++
+					continue
+				}
+			}
+		}
+
 		attempt, err := ensureWorkAttempt(idx, task.ID)
 		if err != nil {
 			fmt.Fprintf(opts.Stderr, "Warning: failed to set attempt for task %s: %v\n", task.ID, err)
@@ -500,6 +547,11 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 				continue
 			}
 			worktreePath = worktreeResult.Path
+			if !worktreeResult.Reused {
+				if err := quota.add(worktreePath); err != nil {
+					fmt.Fprintf(opts.Stderr, "Warning: failed to measure worktree for task %s: %v\n", task.ID, err)
+				}
+			}
 
 			if !worktreeResult.Reused && workerAuditor != nil {
 				if err := workerAuditor.LogWorktreeCreate(task.ID, string(task.Role), worktreeResult.RelativePath, branchName); err != nil {
@@ -508,9 +560,10 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			}
 		}
 
-		stageInput := newWorkerStageInput(
+		stageInput := newTaskStageInput(
 			repoRoot,
 			worktreePath,
+			idx.Tasks,
 			task,
 			roles.StageWork,
 			task.Role,
@@ -539,6 +592,7 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			}
 			continue
 		}
+		emitTaskPrompt(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork), stageResult)
 
 		dispatchResult, err := worker.DispatchWorkerFromConfig(cfg, task, stageResult, worktreePath, roles.StageWork, func(msg string) {
 			fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
@@ -581,11 +635,12 @@ func ExecuteWorkStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 	return result, nil
 }
 
-// ExecuteWorkAgent runs the work agent for a specific task.
-func ExecuteWorkAgent(repoRoot, worktreePath string, task index.Task, cfg config.Config, auditor *audit.Logger, opts Options) (worker.IngestResult, error) {
-	stageInput := newWorkerStageInput(
+// ExecuteWorkAgent runs the work agent for a specific task; tasks supplies its dependencies.
+func ExecuteWorkAgent(repoRoot, worktreePath string, tasks []index.Task, task index.Task, cfg config.Config, auditor *audit.Logger, opts Options) (worker.IngestResult, error) {
+	stageInput := newTaskStageInput(
 		repoRoot,
 		worktreePath,
+		tasks,
 		task,
 		roles.StageWork,
 		task.Role,
@@ -604,6 +659,7 @@ func ExecuteWorkAgent(repoRoot, worktreePath string, task index.Task, cfg config
 	if err != nil {
 		return worker.IngestResult{}, fmt.Errorf("stage work environment: %w", err)
 	}
+	emitTaskPrompt(opts.Stdout, task.ID, string(task.Role), string(roles.StageWork), stageResult)
 
 	execResult, err := worker.ExecuteWorkerFromConfigWithAudit(cfg, task, stageResult, worktreePath, func(msg string) {
 		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
@@ -625,7 +681,7 @@ func ExecuteWorkAgent(repoRoot, worktreePath string, task index.Task, cfg config
 			TimedOut:    execResult.TimedOut,
 		}
 	} else {
-		ingestResult, err = finalizeStageSuccess(worktreePath, stageResult.WorkerStateDir, task, roles.StageWork)
+		ingestResult, err = finalizeStageSuccess(repoRoot, worktreePath, stageResult.WorkerStateDir, task, roles.StageWork, newCommitProvenance(cfg, task.Role))
 		if err != nil {
 			return worker.IngestResult{}, fmt.Errorf("finalize work result: %w", err)
 		}
@@ -672,7 +728,7 @@ func UpdateTaskStateFromWorkResult(idx *index.Index, taskID string, workResult w
 }
 
 // ExecuteTestStage processes tasks in the worked state through the test stage.
-func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, opts Options) (TestStageResult, error) {
+func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, changes worktreeChanges, opts Options) (TestStageResult, error) {
 	result := TestStageResult{
 		Metrics: map[string]index.ExecutionMetrics{},
 	}
@@ -680,7 +736,7 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 		inFlight = inflight.Set{}
 	}
 
-	manager, err := worktree.NewManager(repoRoot)
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
 	if err != nil {
 		return result, fmt.Errorf("create worktree manager: %w", err)
 	}
@@ -703,18 +759,24 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			continue
 		}
 
-		exitStatus, finished, err := worker.ReadExitStatus(entry.WorkerStateDir, task.ID, roles.StageTest)
+		exitStatus, finished, err := collectWorkerExit(repoRoot, entry.WorkerStateDir, task.ID, roles.StageTest, func(message string) {
+			fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+		})
 		if err != nil {
 			fmt.Fprintf(opts.Stderr, "Warning: failed to read exit status for task %s: %v\n", task.ID, err)
 			continue
 		}
 		if !finished {
-			if startedAt, ok := startedAtForTask(inFlight, task.ID); ok && timedOut(startedAt, cfg.Timeouts.WorkerSeconds) {
+			expiry, expired := checkWorkerExpiry(inFlight, task.ID, entry.WorkerStateDir, worktreePath, worker.TimeoutSecondsForTask(cfg, task, roles.StageTest), cfg.Timeouts.StallSeconds, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s: %s\n", task.ID, message)
+			})
+			if expired {
 				failedResult := worker.IngestResult{
 					Success:     false,
 					NewState:    index.TaskStateBlocked,
-					BlockReason: formatTimeoutReason(cfg.Timeouts.WorkerSeconds),
-					TimedOut:    true,
+					BlockReason: expiry.reason,
+					TimedOut:    !expiry.stalled,
+					Stalled:     expiry.stalled,
 				}
 				logAgentOutcome(workerAuditor, task.ID, task.Role, roles.StageTest, statusFromIngestResult(failedResult), exitCodeForOutcome(-1, true), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
@@ -726,14 +788,12 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 					fmt.Fprintf(opts.Stderr, "Warning: failed to update task state for %s: %v\n", task.ID, updateErr)
 				} else {
 					result.TasksBlocked++
-					emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest), failedResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+					emitWorkerExpiry(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest), expiry)
 				}
-				if workerAuditor != nil {
-					if auditErr := workerAuditor.LogWorkerTimeout(task.ID, string(task.Role), cfg.Timeouts.WorkerSeconds, worktreePath); auditErr != nil {
-						fmt.Fprintf(opts.Stderr, "Warning: failed to log worker timeout for %s: %v\n", task.ID, auditErr)
-					}
+				if auditErr := logWorkerExpiry(workerAuditor, task.ID, string(task.Role), worktreePath, expiry); auditErr != nil {
+					fmt.Fprintf(opts.Stderr, "Warning: failed to log worker %s for %s: %v\n", expiry.kind(), task.ID, auditErr)
 				}
-				killWorkerProcess(task.PID, entry.WorkerStateDir, func(message string) {
+				killWorkerProcess(repoRoot, task.PID, entry.WorkerStateDir, expiry.kind(), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
 				})
 				if err := inFlight.Remove(task.ID); err == nil {
@@ -743,15 +803,21 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			continue
 		}
 
+		if exitStatus.ExitCode != 0 && deferRateLimitedTask(repoRoot, idx, cfg, task, roles.StageTest, entry.WorkerStateDir, exitStatus.ExitCode, workerAuditor, opts) {
+			result.TasksRateLimited++
+			if err := inFlight.Remove(task.ID); err == nil {
+				result.InFlightUpdated = true
+			}
+			continue
+		}
+
 		var ingestResult worker.IngestResult
 		if exitStatus.ExitCode != 0 {
-			ingestResult = worker.IngestResult{
-				Success:     false,
-				NewState:    index.TaskStateBlocked,
-				BlockReason: fmt.Sprintf("worker process exited with code %d", exitStatus.ExitCode),
-			}
+			ingestResult = worker.FailedExitResult(entry.WorkerStateDir, exitStatus.ExitCode, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+			})
 		} else {
-			ingestResult, err = finalizeStageSuccess(worktreePath, entry.WorkerStateDir, task, roles.StageTest)
+			ingestResult, err = finalizeStageSuccess(repoRoot, worktreePath, entry.WorkerStateDir, task, roles.StageTest, newCommitProvenance(cfg, index.Role(entry.Role)))
 			if err != nil {
 				ingestResult = worker.IngestResult{
 					Success:     false,
@@ -785,7 +851,7 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			emitTaskComplete(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest))
 		} else if ingestResult.TimedOut {
 			result.TasksBlocked++
-			emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest), ingestResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+			emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest), ingestResult.BlockReason, worker.TimeoutSecondsForTask(cfg, task, roles.StageTest))
 		} else {
 			result.TasksBlocked++
 			emitTaskFailure(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest), ingestResult.BlockReason)
@@ -800,7 +866,9 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 	if opts.DisableDispatch {
 		return result, nil
 	}
-	selectedTasks, err := selectTasksForStage(*idx, adjustedCaps, inFlight, index.TaskStateImplemented)
+	selectedTasks, err := selectTasksForStageExcluding(*idx, adjustedCaps, inFlight, baseBranchName(cfg), changes, cooldownExclusion(repoRoot, cfg, func(msg string) {
+		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
+	}), index.TaskStateImplemented)
 	if err != nil {
 		return result, fmt.Errorf("schedule test tasks: %w", err)
 	}
@@ -810,6 +878,7 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 	}
 
 	for _, task := range selectedTasks {
+		refreshTaskTimeout(repoRoot, idx, &task)
 		worktreePath, ok := worktreePathForTask(inFlight, task.ID)
 		if !ok {
 			worktreePath, err = resolveWorktreePath(manager, task, worktreeOverrides)
@@ -851,6 +920,7 @@ func ExecuteTestStage(repoRoot string, idx *index.Index, cfg config.Config, caps
 			}
 			continue
 		}
+		emitTaskPrompt(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest), stageResult)
 
 		dispatchResult, err := worker.DispatchWorkerFromConfig(cfg, task, stageResult, worktreePath, roles.StageTest, func(msg string) {
 			fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
@@ -916,6 +986,7 @@ func ExecuteTestAgent(repoRoot, worktreePath string, task index.Task, cfg config
 	if err != nil {
 		return worker.IngestResult{}, fmt.Errorf("stage test environment: %w", err)
 	}
+	emitTaskPrompt(opts.Stdout, task.ID, string(task.Role), string(roles.StageTest), stageResult)
 
 	// Execute the test worker
 	execResult, err := worker.ExecuteWorkerFromConfigWithAudit(cfg, task, stageResult, worktreePath, func(msg string) {
@@ -938,7 +1009,7 @@ func ExecuteTestAgent(repoRoot, worktreePath string, task index.Task, cfg config
 			TimedOut:    execResult.TimedOut,
 		}
 	} else {
-		ingestResult, err = finalizeStageSuccess(worktreePath, stageResult.WorkerStateDir, task, roles.StageTest)
+		ingestResult, err = finalizeStageSuccess(repoRoot, worktreePath, stageResult.WorkerStateDir, task, roles.StageTest, newCommitProvenance(cfg, task.Role))
 		if err != nil {
 			return worker.IngestResult{}, fmt.Errorf("finalize test result: %w", err)
 		}
@@ -986,15 +1057,16 @@ func UpdateTaskStateFromTestResult(idx *index.Index, taskID string, testResult w
 
 // ReviewStageResult captures the outcome of review stage execution.
 type ReviewStageResult struct {
-	TasksDispatched int
-	TasksReviewed   int
-	TasksBlocked    int
-	InFlightUpdated bool
-	Metrics         map[string]index.ExecutionMetrics // Metrics by task ID
+	TasksDispatched  int
+	TasksReviewed    int
+	TasksBlocked     int
+	TasksRateLimited int
+	InFlightUpdated  bool
+	Metrics          map[string]index.ExecutionMetrics // Metrics by task ID
 }
 
 // ExecuteReviewStage processes tasks in the tested state through the review stage.
-func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, opts Options) (ReviewStageResult, error) {
+func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, changes worktreeChanges, opts Options) (ReviewStageResult, error) {
 	result := ReviewStageResult{
 		Metrics: map[string]index.ExecutionMetrics{},
 	}
@@ -1002,7 +1074,7 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 		inFlight = inflight.Set{}
 	}
 
-	manager, err := worktree.NewManager(repoRoot)
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
 	if err != nil {
 		return result, fmt.Errorf("create worktree manager: %w", err)
 	}
@@ -1025,18 +1097,24 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 			continue
 		}
 
-		exitStatus, finished, err := worker.ReadExitStatus(entry.WorkerStateDir, task.ID, roles.StageReview)
+		exitStatus, finished, err := collectWorkerExit(repoRoot, entry.WorkerStateDir, task.ID, roles.StageReview, func(message string) {
+			fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+		})
 		if err != nil {
 			fmt.Fprintf(opts.Stderr, "Warning: failed to read exit status for task %s: %v\n", task.ID, err)
 			continue
 		}
 		if !finished {
-			if startedAt, ok := startedAtForTask(inFlight, task.ID); ok && timedOut(startedAt, cfg.Timeouts.WorkerSeconds) {
+			expiry, expired := checkWorkerExpiry(inFlight, task.ID, entry.WorkerStateDir, worktreePath, worker.TimeoutSecondsForTask(cfg, task, roles.StageReview), cfg.Timeouts.StallSeconds, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s: %s\n", task.ID, message)
+			})
+			if expired {
 				failedResult := worker.IngestResult{
 					Success:     false,
 					NewState:    index.TaskStateTriaged,
-					BlockReason: formatTimeoutReason(cfg.Timeouts.WorkerSeconds),
-					TimedOut:    true,
+					BlockReason: expiry.reason,
+					TimedOut:    !expiry.stalled,
+					Stalled:     expiry.stalled,
 				}
 				logAgentOutcome(workerAuditor, task.ID, task.Role, roles.StageReview, statusFromIngestResult(failedResult), exitCodeForOutcome(-1, true), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
@@ -1045,14 +1123,12 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 					fmt.Fprintf(opts.Stderr, "Warning: failed to update task state for %s: %v\n", task.ID, err)
 				} else {
 					result.TasksBlocked++
-					emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview), failedResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+					emitWorkerExpiry(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview), expiry)
 				}
-				if workerAuditor != nil {
-					if auditErr := workerAuditor.LogWorkerTimeout(task.ID, string(task.Role), cfg.Timeouts.WorkerSeconds, worktreePath); auditErr != nil {
-						fmt.Fprintf(opts.Stderr, "Warning: failed to log worker timeout for %s: %v\n", task.ID, auditErr)
-					}
+				if auditErr := logWorkerExpiry(workerAuditor, task.ID, string(task.Role), worktreePath, expiry); auditErr != nil {
+					fmt.Fprintf(opts.Stderr, "Warning: failed to log worker %s for %s: %v\n", expiry.kind(), task.ID, auditErr)
 				}
-				killWorkerProcess(task.PID, entry.WorkerStateDir, func(message string) {
+				killWorkerProcess(repoRoot, task.PID, entry.WorkerStateDir, expiry.kind(), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
 				})
 				if err := inFlight.Remove(task.ID); err == nil {
@@ -1062,15 +1138,22 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 			continue
 		}
 
+		if exitStatus.ExitCode != 0 && deferRateLimitedTask(repoRoot, idx, cfg, task, roles.StageReview, entry.WorkerStateDir, exitStatus.ExitCode, workerAuditor, opts) {
+			result.TasksRateLimited++
+			if err := inFlight.Remove(task.ID); err == nil {
+				result.InFlightUpdated = true
+			}
+			continue
+		}
+
 		var reviewResult worker.IngestResult
 		if exitStatus.ExitCode != 0 {
-			reviewResult = worker.IngestResult{
-				Success:     false,
-				NewState:    index.TaskStateTriaged,
-				BlockReason: fmt.Sprintf("worker process exited with code %d", exitStatus.ExitCode),
-			}
+			reviewResult = worker.FailedExitResult(entry.WorkerStateDir, exitStatus.ExitCode, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+			})
+			reviewResult.NewState = index.TaskStateTriaged
 		} else {
-			reviewResult, err = finalizeStageSuccess(worktreePath, entry.WorkerStateDir, task, roles.StageReview)
+			reviewResult, err = finalizeStageSuccess(repoRoot, worktreePath, entry.WorkerStateDir, task, roles.StageReview, newCommitProvenance(cfg, index.Role(entry.Role)))
 			if err != nil {
 				reviewResult = worker.IngestResult{
 					Success:     false,
@@ -1095,16 +1178,34 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 			result.TasksReviewed++
 			emitTaskComplete(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview))
 
+			if mergeQueueEnabled(cfg) {
+				// The merge stage lands queued tasks together.
+				if err := applyTaskStateTransition(idx, task.ID, index.TaskStateMergeable, transitionAuditor); err != nil {
+					fmt.Fprintf(opts.Stderr, "Warning: failed to mark %s as mergeable: %v\n", task.ID, err)
+				}
+				if err := inFlight.Remove(task.ID); err == nil {
+					result.InFlightUpdated = true
+				}
+				continue
+			}
 			emitTaskStart(opts.Stdout, task.ID, string(task.Role), mergeStageName)
 			if err := applyTaskStateTransition(idx, task.ID, index.TaskStateMergeable, transitionAuditor); err != nil {
 				fmt.Fprintf(opts.Stderr, "Warning: failed to mark %s as mergeable: %v\n", task.ID, err)
 			}
 			mergeInput := MergeFlowInput{
-				RepoRoot:     repoRoot,
-				WorktreePath: worktreePath,
-				Task:         task,
-				MainBranch:   baseBranchName(cfg),
-				Auditor:      workerAuditor,
+				RepoRoot:             repoRoot,
+				WorktreePath:         worktreePath,
+				Task:                 task,
+				MainBranch:           baseBranchName(cfg),
+				WorktreesRoot:        cfg.Worktrees.Root,
+				Strategy:             cfg.Branches.MergeStrategy,
+				Remote:               cfg.Branches.Remote,
+				VerifyCommand:        cfg.Verify.Command,
+				VerifyTimeoutSeconds: cfg.Verify.TimeoutSeconds,
+				Commits:              cfg.Commits,
+				Identity:             cfg.Git.Merge,
+				Signing:              cfg.Git.Signing,
+				Auditor:              workerAuditor,
 			}
 
 			mergeResult, err := ExecuteReviewMergeFlow(mergeInput)
@@ -1149,7 +1250,7 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 			} else {
 				result.TasksBlocked++
 				if reviewResult.TimedOut {
-					emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview), reviewResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+					emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview), reviewResult.BlockReason, worker.TimeoutSecondsForTask(cfg, task, roles.StageReview))
 				} else {
 					emitTaskFailure(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview), reviewResult.BlockReason)
 				}
@@ -1165,7 +1266,9 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 	if opts.DisableDispatch {
 		return result, nil
 	}
-	selectedTasks, err := selectTasksForStage(*idx, adjustedCaps, inFlight, index.TaskStateTested)
+	selectedTasks, err := selectTasksForStageExcluding(*idx, adjustedCaps, inFlight, baseBranchName(cfg), changes, cooldownExclusion(repoRoot, cfg, func(msg string) {
+		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
+	}), index.TaskStateTested)
 	if err != nil {
 		return result, fmt.Errorf("schedule review tasks: %w", err)
 	}
@@ -1175,6 +1278,7 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 	}
 
 	for _, task := range selectedTasks {
+		refreshTaskTimeout(repoRoot, idx, &task)
 		worktreePath, err := resolveWorktreePath(manager, task, worktreeOverrides)
 		if err != nil {
 			fmt.Fprintf(opts.Stderr, "Warning: failed to get worktree path for task %s: %v\n", task.ID, err)
@@ -1210,6 +1314,7 @@ func ExecuteReviewStage(repoRoot string, idx *index.Index, cfg config.Config, ca
 			}
 			continue
 		}
+		emitTaskPrompt(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview), stageResult)
 
 		dispatchResult, err := worker.DispatchWorkerFromConfig(cfg, task, stageResult, worktreePath, roles.StageReview, func(msg string) {
 			fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
@@ -1272,6 +1377,7 @@ func ExecuteReviewAgent(repoRoot, worktreePath string, task index.Task, cfg conf
 	if err != nil {
 		return worker.IngestResult{}, fmt.Errorf("stage review environment: %w", err)
 	}
+	emitTaskPrompt(opts.Stdout, task.ID, string(task.Role), string(roles.StageReview), stageResult)
 
 	// Execute the review worker
 	execResult, err := worker.ExecuteWorkerFromConfigWithAudit(cfg, task, stageResult, worktreePath, func(msg string) {
@@ -1294,7 +1400,7 @@ func ExecuteReviewAgent(repoRoot, worktreePath string, task index.Task, cfg conf
 			TimedOut:    execResult.TimedOut,
 		}
 	} else {
-		ingestResult, err = finalizeStageSuccess(worktreePath, stageResult.WorkerStateDir, task, roles.StageReview)
+		ingestResult, err = finalizeStageSuccess(repoRoot, worktreePath, stageResult.WorkerStateDir, task, roles.StageReview, newCommitProvenance(cfg, task.Role))
 		if err != nil {
 			return worker.IngestResult{}, fmt.Errorf("finalize review result: %w", err)
 		}
@@ -1342,21 +1448,22 @@ func UpdateTaskStateFromReviewResult(idx *index.Index, taskID string, reviewResu
 
 // ConflictResolutionStageResult captures the outcome of conflict resolution stage execution.
 type ConflictResolutionStageResult struct {
-	TasksDispatched int
-	TasksResolved   int
-	TasksBlocked    int
-	InFlightUpdated bool
-	Metrics         map[string]index.ExecutionMetrics // Metrics by task ID
+	TasksDispatched  int
+	TasksResolved    int
+	TasksBlocked     int
+	TasksRateLimited int
+	InFlightUpdated  bool
+	Metrics          map[string]index.ExecutionMetrics // Metrics by task ID
 }
 
 // ExecuteConflictResolutionStage processes tasks in the conflict state by dispatching conflict resolution agents.
-func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, opts Options) (ConflictResolutionStageResult, error) {
+func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, inFlight inflight.Set, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, changes worktreeChanges, opts Options) (ConflictResolutionStageResult, error) {
 	result := ConflictResolutionStageResult{}
 	if inFlight == nil {
 		inFlight = inflight.Set{}
 	}
 
-	manager, err := worktree.NewManager(repoRoot)
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
 	if err != nil {
 		return result, fmt.Errorf("create worktree manager: %w", err)
 	}
@@ -1376,18 +1483,24 @@ func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg confi
 			continue
 		}
 
-		exitStatus, finished, err := worker.ReadExitStatus(entry.WorkerStateDir, task.ID, roles.StageResolve)
+		exitStatus, finished, err := collectWorkerExit(repoRoot, entry.WorkerStateDir, task.ID, roles.StageResolve, func(message string) {
+			fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+		})
 		if err != nil {
 			fmt.Fprintf(opts.Stderr, "Warning: failed to read exit status for task %s: %v\n", task.ID, err)
 			continue
 		}
 		if !finished {
-			if startedAt, ok := startedAtForTask(inFlight, task.ID); ok && timedOut(startedAt, cfg.Timeouts.WorkerSeconds) {
+			expiry, expired := checkWorkerExpiry(inFlight, task.ID, entry.WorkerStateDir, worktreePath, worker.TimeoutSecondsForTask(cfg, task, roles.StageResolve), cfg.Timeouts.StallSeconds, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s: %s\n", task.ID, message)
+			})
+			if expired {
 				failedResult := worker.IngestResult{
 					Success:     false,
 					NewState:    index.TaskStateBlocked,
-					BlockReason: formatTimeoutReason(cfg.Timeouts.WorkerSeconds),
-					TimedOut:    true,
+					BlockReason: expiry.reason,
+					TimedOut:    !expiry.stalled,
+					Stalled:     expiry.stalled,
 				}
 				logAgentOutcome(workerAuditor, task.ID, task.Role, roles.StageResolve, statusFromIngestResult(failedResult), exitCodeForOutcome(-1, true), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
@@ -1399,14 +1512,12 @@ func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg confi
 					fmt.Fprintf(opts.Stderr, "Warning: failed to update task state for %s: %v\n", task.ID, updateErr)
 				} else {
 					result.TasksBlocked++
-					emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageResolve), failedResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+					emitWorkerExpiry(opts.Stdout, task.ID, string(task.Role), string(roles.StageResolve), expiry)
 				}
-				if workerAuditor != nil {
-					if auditErr := workerAuditor.LogWorkerTimeout(task.ID, string(task.Role), cfg.Timeouts.WorkerSeconds, worktreePath); auditErr != nil {
-						fmt.Fprintf(opts.Stderr, "Warning: failed to log worker timeout for %s: %v\n", task.ID, auditErr)
-					}
+				if auditErr := logWorkerExpiry(workerAuditor, task.ID, string(task.Role), worktreePath, expiry); auditErr != nil {
+					fmt.Fprintf(opts.Stderr, "Warning: failed to log worker %s for %s: %v\n", expiry.kind(), task.ID, auditErr)
 				}
-				killWorkerProcess(task.PID, entry.WorkerStateDir, func(message string) {
+				killWorkerProcess(repoRoot, task.PID, entry.WorkerStateDir, expiry.kind(), func(message string) {
 					fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
 				})
 				if err := inFlight.Remove(task.ID); err == nil {
@@ -1416,15 +1527,21 @@ func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg confi
 			continue
 		}
 
+		if exitStatus.ExitCode != 0 && deferRateLimitedTask(repoRoot, idx, cfg, task, roles.StageResolve, entry.WorkerStateDir, exitStatus.ExitCode, workerAuditor, opts) {
+			result.TasksRateLimited++
+			if err := inFlight.Remove(task.ID); err == nil {
+				result.InFlightUpdated = true
+			}
+			continue
+		}
+
 		var ingestResult worker.IngestResult
 		if exitStatus.ExitCode != 0 {
-			ingestResult = worker.IngestResult{
-				Success:     false,
-				NewState:    index.TaskStateBlocked,
-				BlockReason: fmt.Sprintf("worker process exited with code %d", exitStatus.ExitCode),
-			}
+			ingestResult = worker.FailedExitResult(entry.WorkerStateDir, exitStatus.ExitCode, func(message string) {
+				fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+			})
 		} else {
-			ingestResult, err = finalizeStageSuccess(worktreePath, entry.WorkerStateDir, task, roles.StageResolve)
+			ingestResult, err = finalizeStageSuccess(repoRoot, worktreePath, entry.WorkerStateDir, task, roles.StageResolve, newCommitProvenance(cfg, index.Role(entry.Role)))
 			if err != nil {
 				ingestResult = worker.IngestResult{
 					Success:     false,
@@ -1453,7 +1570,7 @@ func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg confi
 			emitTaskComplete(opts.Stdout, task.ID, string(task.Role), string(roles.StageResolve))
 		} else if ingestResult.TimedOut {
 			result.TasksBlocked++
-			emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageResolve), ingestResult.BlockReason, cfg.Timeouts.WorkerSeconds)
+			emitTaskTimeout(opts.Stdout, task.ID, string(task.Role), string(roles.StageResolve), ingestResult.BlockReason, worker.TimeoutSecondsForTask(cfg, task, roles.StageResolve))
 		} else {
 			result.TasksBlocked++
 			emitTaskFailure(opts.Stdout, task.ID, string(task.Role), string(roles.StageResolve), ingestResult.BlockReason)
@@ -1468,7 +1585,9 @@ func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg confi
 	if opts.DisableDispatch {
 		return result, nil
 	}
-	selectedTasks, err := selectTasksForStage(*idx, adjustedCaps, inFlight, index.TaskStateConflict)
+	selectedTasks, err := selectTasksForStageExcluding(*idx, adjustedCaps, inFlight, baseBranchName(cfg), changes, cooldownExclusion(repoRoot, cfg, func(msg string) {
+		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
+	}), index.TaskStateConflict)
 	if err != nil {
 		return result, fmt.Errorf("schedule conflict resolution tasks: %w", err)
 	}
@@ -1478,6 +1597,7 @@ func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg confi
 	}
 
 	for _, task := range selectedTasks {
+		refreshTaskTimeout(repoRoot, idx, &task)
 		worktreePath, err := resolveWorktreePath(manager, task, worktreeOverrides)
 		if err != nil {
 			fmt.Fprintf(opts.Stderr, "Warning: failed to get worktree path for task %s: %v\n", task.ID, err)
@@ -1557,6 +1677,7 @@ func ExecuteConflictResolutionStage(repoRoot string, idx *index.Index, cfg confi
 			}
 			continue
 		}
+		emitTaskPrompt(opts.Stdout, task.ID, roleForLogs, string(roles.StageResolve), stageResult)
 
 		resolveDispatchTask := task
 		resolveDispatchTask.Path = conflictResolutionPromptPath
@@ -1626,6 +1747,7 @@ func ExecuteConflictResolutionAgent(repoRoot, worktreePath string, task index.Ta
 	if err != nil {
 		return worker.IngestResult{}, roleResult, fmt.Errorf("stage conflict resolution environment: %w", err)
 	}
+	emitTaskPrompt(opts.Stdout, task.ID, resolveRoleForLogs(roleResult.Role, task.Role), string(roles.StageResolve), stageResult)
 
 	roleForLogs := resolveRoleForLogs(roleResult.Role, task.Role)
 	emitTaskStart(opts.Stdout, task.ID, roleForLogs, string(roles.StageResolve))
@@ -1657,7 +1779,7 @@ func ExecuteConflictResolutionAgent(repoRoot, worktreePath string, task index.Ta
 			TimedOut:    execResult.TimedOut,
 		}
 	} else {
-		ingestResult, err = finalizeStageSuccess(worktreePath, stageResult.WorkerStateDir, task, roles.StageResolve)
+		ingestResult, err = finalizeStageSuccess(repoRoot, worktreePath, stageResult.WorkerStateDir, task, roles.StageResolve, newCommitProvenance(cfg, roleResult.Role))
 		if err != nil {
 			return worker.IngestResult{}, roleResult, fmt.Errorf("finalize conflict resolution result: %w", err)
 		}
@@ -1724,10 +1846,11 @@ func configureConflictResolutionStageInput(repoRoot string, task index.Task, sta
 		return fmt.Errorf("stage input is required")
 	}
 	stageInput.TaskPromptPath = conflictResolutionPromptPath
-	stageInput.ExtraEnv = map[string]string{
-		"GOVERNATOR_CONFLICT_BRANCH":    TaskBranchName(task),
-		"GOVERNATOR_CONFLICT_TASK_PATH": task.Path,
+	if stageInput.ExtraEnv == nil {
+		stageInput.ExtraEnv = map[string]string{}
 	}
+	stageInput.ExtraEnv["GOVERNATOR_CONFLICT_BRANCH"] = TaskBranchName(task)
+	stageInput.ExtraEnv["GOVERNATOR_CONFLICT_TASK_PATH"] = task.Path
 
 	contextPromptPath, err := ensureConflictContextPrompt(repoRoot, stageInput.WorkerStateDir, task)
 	if err != nil {
@@ -1739,18 +1862,12 @@ func configureConflictResolutionStageInput(repoRoot string, task index.Task, sta
 
 // ensureConflictContextPrompt writes a deterministic context prompt for resolve workers.
 func ensureConflictContextPrompt(repoRoot string, workerStateDir string, task index.Task) (string, error) {
-	if strings.TrimSpace(workerStateDir) == "" {
-		return "", fmt.Errorf("worker state dir is required")
-	}
-	absoluteWorkerStateDir, err := filepath.Abs(workerStateDir)
-	if err != nil {
-		return "", fmt.Errorf("resolve worker state dir %s: %w", workerStateDir, err)
-	}
-	if err := os.MkdirAll(absoluteWorkerStateDir, 0o755); err != nil {
-		return "", fmt.Errorf("create worker state dir %s: %w", absoluteWorkerStateDir, err)
-	}
+	return writeWorkerContextPrompt(repoRoot, workerStateDir, conflictContextFileName, conflictContextContent(task))
+}
 
-	contextPath := filepath.Join(absoluteWorkerStateDir, "conflict-context.md")
+// conflictContextContent renders the resolve-stage context prompt for a task, including the
+// recorded merge failure such as git conflict output or a failed post-merge verify.
+func conflictContextContent(task index.Task) string {
 	content := fmt.Sprintf(
 		"# Conflict Context\n- Task ID: `%s`\n- Task Title: `%s`\n- Conflicted branch: `%s`\n- Original task file: `%s`\n",
 		task.ID,
@@ -1758,22 +1875,10 @@ func ensureConflictContextPrompt(repoRoot string, workerStateDir string, task in
 		TaskBranchName(task),
 		task.Path,
 	)
-	if err := os.WriteFile(contextPath, []byte(content), 0o644); err != nil {
-		return "", fmt.Errorf("write conflict context prompt %s: %w", contextPath, err)
+	if reason := strings.TrimSpace(task.BlockedReason); reason != "" {
+		content += fmt.Sprintf("\n## Merge Failure\n\n```\n%s\n```\n", reason)
 	}
-
-	absoluteRepoRoot, err := filepath.Abs(repoRoot)
-	if err != nil {
-		return "", fmt.Errorf("resolve repo root %s: %w", repoRoot, err)
-	}
-	relativePath, err := filepath.Rel(absoluteRepoRoot, contextPath)
-	if err != nil {
-		return contextPath, nil
-	}
-	if strings.HasPrefix(relativePath, "..") {
-		return contextPath, nil
-	}
-	return filepath.ToSlash(relativePath), nil
+	return content
 }
 
 func baseBranchName(cfg config.Config) string {
@@ -1900,6 +2005,8 @@ type MergeStageResult struct {
 }
 
 // ExecuteMergeStage processes tasks in the resolved state through the merge flow.
+// With the merge queue enabled, resolved tasks join the reviewed tasks already waiting in
+// mergeable and are landed in batches instead.
 func ExecuteMergeStage(repoRoot string, idx *index.Index, cfg config.Config, caps scheduler.RoleCaps, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, opts Options) (MergeStageResult, error) {
 	result := MergeStageResult{}
 
@@ -1908,16 +2015,25 @@ func ExecuteMergeStage(repoRoot string, idx *index.Index, cfg config.Config, cap
 		return result, fmt.Errorf("schedule merge tasks: %w", err)
 	}
 
-	if len(selectedTasks) == 0 {
+	if len(selectedTasks) == 0 && !mergeQueueEnabled(cfg) {
 		return result, nil
 	}
 
 	// Set up worktree manager
-	manager, err := worktree.NewManager(repoRoot)
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
 	if err != nil {
 		return result, fmt.Errorf("create worktree manager: %w", err)
 	}
 
+	if mergeQueueEnabled(cfg) {
+		for _, task := range selectedTasks {
+			if err := applyTaskStateTransition(idx, task.ID, index.TaskStateMergeable, transitionAuditor); err != nil {
+				fmt.Fprintf(opts.Stderr, "Warning: failed to mark %s as mergeable before merge stage: %v\n", task.ID, err)
+			}
+		}
+		return executeMergeQueueStage(repoRoot, idx, cfg, manager, worktreeOverrides, transitionAuditor, workerAuditor, opts)
+	}
+
 	// Process each resolved task through merge flow
 	for _, task := range selectedTasks {
 		result.TasksProcessed++
@@ -1936,11 +2052,19 @@ func ExecuteMergeStage(repoRoot string, idx *index.Index, cfg config.Config, cap
 
 		// Execute conflict resolution merge flow
 		mergeInput := MergeFlowInput{
-			RepoRoot:     repoRoot,
-			WorktreePath: worktreePath,
-			Task:         task,
-			MainBranch:   baseBranchName(cfg),
-			Auditor:      workerAuditor,
+			RepoRoot:             repoRoot,
+			WorktreePath:         worktreePath,
+			Task:                 task,
+			MainBranch:           baseBranchName(cfg),
+			WorktreesRoot:        cfg.Worktrees.Root,
+			Strategy:             cfg.Branches.MergeStrategy,
+			Remote:               cfg.Branches.Remote,
+			VerifyCommand:        cfg.Verify.Command,
+			VerifyTimeoutSeconds: cfg.Verify.TimeoutSeconds,
+			Commits:              cfg.Commits,
+			Identity:             cfg.Git.Merge,
+			Signing:              cfg.Git.Signing,
+			Auditor:              workerAuditor,
 		}
 
 		mergeResult, err := ExecuteConflictResolutionMergeFlow(mergeInput)
@@ -1984,7 +2108,106 @@ func ExecuteMergeStage(repoRoot string, idx *index.Index, cfg config.Config, cap
 	return result, nil
 }
 
+// mergeQueueEnabled reports whether mergeable tasks are batched behind one verify run.
+// Without a verify command there is nothing to share, so tasks merge one at a time.
+func mergeQueueEnabled(cfg config.Config) bool {
+	return cfg.Verify.BatchSize > 1 && len(cfg.Verify.Command) > 0
+}
+
+// executeMergeQueueStage lands every mergeable task through the merge queue, at most
+// verify.batch_size tasks per batch, in index order.
+func executeMergeQueueStage(repoRoot string, idx *index.Index, cfg config.Config, manager worktree.Manager, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, opts Options) (MergeStageResult, error) {
+	result := MergeStageResult{}
+	var entries []MergeQueueEntry
+	for _, task := range idx.Tasks {
+		if task.State != index.TaskStateMergeable {
+			continue
+		}
+		worktreePath, err := resolveWorktreePath(manager, task, worktreeOverrides)
+		if err != nil {
+			fmt.Fprintf(opts.Stderr, "Warning: failed to get worktree path for task %s: %v\n", task.ID, err)
+			continue
+		}
+		entries = append(entries, MergeQueueEntry{Task: task, WorktreePath: worktreePath})
+	}
+
+	flow := MergeFlowInput{
+		RepoRoot:             repoRoot,
+		MainBranch:           baseBranchName(cfg),
+		WorktreesRoot:        cfg.Worktrees.Root,
+		Strategy:             cfg.Branches.MergeStrategy,
+		Remote:               cfg.Branches.Remote,
+		VerifyCommand:        cfg.Verify.Command,
+		VerifyTimeoutSeconds: cfg.Verify.TimeoutSeconds,
+		Commits:              cfg.Commits,
+		Identity:             cfg.Git.Merge,
+		Signing:              cfg.Git.Signing,
+		Auditor:              workerAuditor,
+	}
+	for start := 0; start < len(entries); start += cfg.Verify.BatchSize {
+		end := start + cfg.Verify.BatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		batch := entries[start:end]
+		roleByID := make(map[string]string, len(batch))
+		for _, entry := range batch {
+			roleByID[entry.Task.ID] = string(entry.Task.Role)
+			result.TasksProcessed++
+			emitTaskStart(opts.Stdout, entry.Task.ID, string(entry.Task.Role), mergeStageName)
+		}
+		outcomes, queueErr := ExecuteMergeQueue(flow, batch)
+		decided := make(map[string]struct{}, len(outcomes))
+		for _, outcome := range outcomes {
+			decided[outcome.TaskID] = struct{}{}
+		}
+		for _, entry := range batch {
+			if _, ok := decided[entry.Task.ID]; !ok {
+				err := queueErr
+				if err == nil {
+					err = fmt.Errorf("merge queue left task undecided")
+				}
+				outcomes = append(outcomes, MergeQueueOutcome{TaskID: entry.Task.ID, Err: err})
+			}
+		}
+		for _, outcome := range outcomes {
+			role := roleByID[outcome.TaskID]
+			finalResult := worker.IngestResult{
+				Success:     outcome.Result.Success,
+				NewState:    outcome.Result.NewState,
+				BlockReason: outcome.Result.ConflictError,
+			}
+			if outcome.Err != nil {
+				fmt.Fprintf(opts.Stderr, "Warning: failed to execute merge flow for task %s: %v\n", outcome.TaskID, outcome.Err)
+				finalResult = worker.IngestResult{
+					NewState:    index.TaskStateBlocked,
+					BlockReason: fmt.Sprintf("merge flow failed: %v", outcome.Err),
+				}
+			}
+			if err := UpdateTaskStateFromMerge(idx, outcome.TaskID, finalResult, transitionAuditor); err != nil {
+				fmt.Fprintf(opts.Stderr, "Warning: failed to update task state for %s: %v\n", outcome.TaskID, err)
+				continue
+			}
+			if finalResult.Success {
+				result.TasksMerged++
+				emitTaskComplete(opts.Stdout, outcome.TaskID, role, mergeStageName)
+			} else {
+				result.TasksConflict++
+				emitTaskFailure(opts.Stdout, outcome.TaskID, role, mergeStageName, finalResult.BlockReason)
+			}
+		}
+	}
+	return result, nil
+}
+
 func selectTasksForStage(idx index.Index, caps scheduler.RoleCaps, inFlight inflight.Set, states ...index.TaskState) ([]index.Task, error) {
+	return selectTasksForStageExcluding(idx, caps, inFlight, "", nil, nil, states...)
+}
+
+// selectTasksForStageExcluding selects eligible tasks, dropping those rejected by exclude before caps apply.
+// Tasks whose overlap conflicts with in-flight work, including paths already changed in
+// in-flight worktrees relative to baseBranch (read through changes), are skipped.
+func selectTasksForStageExcluding(idx index.Index, caps scheduler.RoleCaps, inFlight inflight.Set, baseBranch string, changes worktreeChanges, exclude func(index.Task) bool, states ...index.TaskState) ([]index.Task, error) {
 	if len(states) == 0 {
 		return nil, nil
 	}
@@ -1998,6 +2221,9 @@ func selectTasksForStage(idx index.Index, caps scheduler.RoleCaps, inFlight infl
 	}
 	filtered := make([]index.Task, 0, len(ordered))
 	for _, task := range ordered {
+		if exclude != nil && exclude(task) {
+			continue
+		}
 		if _, ok := stateSet[task.State]; ok {
 			filtered = append(filtered, task)
 		}
@@ -2005,7 +2231,7 @@ func selectTasksForStage(idx index.Index, caps scheduler.RoleCaps, inFlight infl
 	if len(filtered) == 0 {
 		return nil, nil
 	}
-	result := scheduler.RouteOrderedTasks(filtered, caps)
+	result := scheduler.RouteOrderedTasksAround(filtered, caps, inFlightOverlap(idx, inFlight, baseBranch, changes))
 	return result.Selected, nil
 }
 
@@ -5,6 +5,9 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/cmtonkinson/governator/internal/worker"
 )
 
 const (
@@ -15,10 +18,13 @@ const (
 )
 
 const (
-	eventStatusStart    = "start"
-	eventStatusComplete = "complete"
-	eventStatusFailure  = "failure"
-	eventStatusTimeout  = "timeout"
+	eventStatusStart       = "start"
+	eventStatusComplete    = "complete"
+	eventStatusFailure     = "failure"
+	eventStatusTimeout     = "timeout"
+	eventStatusRateLimited = "rate_limited"
+	eventStatusStalled     = "stalled"
+	eventStatusPrompt      = "prompt"
 )
 
 type taskEventAttr struct {
@@ -32,6 +38,19 @@ func emitTaskStart(out io.Writer, taskID string, role string, stage string) {
 	emitTaskStatus(out, taskID, role, stage, eventStatusStart, "", nil)
 }
 
+// emitTaskPrompt records the final staged prompt size for a worker dispatch so prompt
+// size can be correlated with failures.
+func emitTaskPrompt(out io.Writer, taskID string, role string, stage string, stageResult worker.StageResult) {
+	attrs := []taskEventAttr{
+		{key: "prompt_bytes", value: strconv.Itoa(stageResult.PromptBytes)},
+		{key: "prompt_tokens", value: strconv.Itoa(stageResult.PromptTokens)},
+	}
+	if len(stageResult.DroppedPrompts) > 0 {
+		attrs = append(attrs, taskEventAttr{key: "dropped_prompts", value: strings.Join(stageResult.DroppedPrompts, ","), quote: true})
+	}
+	emitTaskStatus(out, taskID, role, stage, eventStatusPrompt, "", attrs)
+}
+
 // emitTaskComplete reports that a worker stage completed successfully for a task.
 func emitTaskComplete(out io.Writer, taskID string, role string, stage string) {
 	emitTaskStatus(out, taskID, role, stage, eventStatusComplete, "", nil)
@@ -56,6 +75,29 @@ func emitTaskTimeout(out io.Writer, taskID string, role string, stage string, re
 	emitTaskStatus(out, taskID, role, stage, eventStatusTimeout, reason, attrs)
 }
 
+// emitTaskStalled reports that a worker stage was killed for producing no activity.
+func emitTaskStalled(out io.Writer, taskID string, role string, stage string, reason string, stallSeconds int) {
+	if strings.TrimSpace(reason) == "" {
+		reason = "stalled"
+	}
+	attrs := []taskEventAttr{
+		{key: "stall_seconds", value: strconv.Itoa(stallSeconds)},
+	}
+	emitTaskStatus(out, taskID, role, stage, eventStatusStalled, reason, attrs)
+}
+
+// emitTaskRateLimited reports that a worker stage hit a rate limit and its CLI is cooling down.
+func emitTaskRateLimited(out io.Writer, taskID string, role string, stage string, reason string, cli string, until time.Time) {
+	if strings.TrimSpace(reason) == "" {
+		reason = "rate limited"
+	}
+	attrs := []taskEventAttr{
+		{key: "cli", value: normalizeToken(cli)},
+		{key: "cooldown_until", value: until.UTC().Format(time.RFC3339)},
+	}
+	emitTaskStatus(out, taskID, role, stage, eventStatusRateLimited, reason, attrs)
+}
+
 // emitPlanningDriftMessage reports that planning drift was detected.
 func emitPlanningDriftMessage(out io.Writer, detail string) {
 	if out == nil {
@@ -99,6 +141,27 @@ func emitDriftReplanMessage(out io.Writer, detail string) {
 	))
 }
 
+// emitDriftNotifyMessage reports planning drift that drift.mode "notify" leaves in place.
+func emitDriftNotifyMessage(out io.Writer, detail string) {
+	if out == nil {
+		return
+	}
+	_, _ = out.Write([]byte("planning=drift status=notify reason=" + strconv.Quote(strings.TrimSpace(detail)) + "\n"))
+}
+
+// emitDriftRestartMessage reports the planning step a drift replan restarts from, or that no
+// step consumes the drifted artifacts and only the digests were refreshed.
+func emitDriftRestartMessage(out io.Writer, stepID string) {
+	if out == nil {
+		return
+	}
+	if strings.TrimSpace(stepID) == "" {
+		_, _ = out.Write([]byte("planning=drift status=refreshed reason=\"no planning step consumes the drifted artifacts\"\n"))
+		return
+	}
+	_, _ = out.Write([]byte("planning=drift status=replan restart_step=" + normalizeToken(stepID) + "\n"))
+}
+
 func emitTaskStatus(out io.Writer, taskID string, role string, stage string, status string, reason string, attrs []taskEventAttr) {
 	if out == nil {
 		return
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/worker"
 )
 
 func TestTaskEventFormatting(t *testing.T) {
@@ -36,6 +37,26 @@ func TestTaskTimeoutFormatting(t *testing.T) {
 	}
 }
 
+func TestTaskPromptFormatting(t *testing.T) {
+	var buf bytes.Buffer
+	emitTaskPrompt(&buf, "T-003", "worker", string(roles.StageWork), worker.StageResult{PromptBytes: 4096, PromptTokens: 1024})
+	emitTaskPrompt(&buf, "T-003", "worker", string(roles.StageWork), worker.StageResult{
+		PromptBytes:    800,
+		PromptTokens:   200,
+		DroppedPrompts: []string{"_governator/custom-prompts/_global.md"},
+	})
+	lines := splitLines(buf.String())
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0] != "task=T-003 role=worker stage=work status=prompt prompt_bytes=4096 prompt_tokens=1024" {
+		t.Fatalf("prompt line = %q", lines[0])
+	}
+	if lines[1] != "task=T-003 role=worker stage=work status=prompt prompt_bytes=800 prompt_tokens=200 dropped_prompts=\"_governator/custom-prompts/_global.md\"" {
+		t.Fatalf("dropped prompt line = %q", lines[1])
+	}
+}
+
 func TestPlanningDriftMessage(t *testing.T) {
 	var buf bytes.Buffer
 	emitPlanningDriftMessage(&buf, "Planning drift detected")
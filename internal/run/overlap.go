@@ -0,0 +1,87 @@
+// Package run provides overlap tracking for in-flight task worktrees.
+package run
+
+import (
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/inflight"
+	"github.com/cmtonkinson/governator/internal/scheduler"
+)
+
+// worktreeChanges caches the paths each in-flight worktree has changed during one tick, so
+// every stage routes around the same snapshot without inspecting a worktree twice.
+type worktreeChanges map[string][]string
+
+// pathsFor returns the paths changed in worktreePath, inspecting the worktree only on first
+// use. A nil cache inspects it every time; failures are never cached.
+func (changes worktreeChanges) pathsFor(worktreePath string, baseBranch string) ([]string, error) {
+	if changed, ok := changes[worktreePath]; ok {
+		return changed, nil
+	}
+	changed, err := worktreeChangedPaths(worktreePath, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	if changes != nil {
+		changes[worktreePath] = changed
+	}
+	return changed, nil
+}
+
+// inFlightOverlap returns the overlap held by in-flight tasks: the entries recorded in the
+// index plus the paths each task has already changed in its worktree, read through changes.
+// Worktrees that cannot be inspected contribute only their recorded entries.
+func inFlightOverlap(idx index.Index, inFlight inflight.Set, baseBranch string, changes worktreeChanges) []string {
+	if len(inFlight) == 0 {
+		return nil
+	}
+	var busy []string
+	for _, task := range idx.Tasks {
+		if !inFlight.Contains(task.ID) {
+			continue
+		}
+		busy = append(busy, task.Overlap...)
+		worktreePath, ok := worktreePathForTask(inFlight, task.ID)
+		if !ok {
+			continue
+		}
+		changed, err := changes.pathsFor(worktreePath, baseBranch)
+		if err != nil {
+			continue
+		}
+		taskPath := canonicalTaskPath(task.Path)
+		for _, path := range changed {
+			// Every task edits its own task file; that never conflicts with other work.
+			if path != taskPath {
+				busy = append(busy, path)
+			}
+		}
+	}
+	return scheduler.NormalizeOverlap(busy)
+}
+
+// worktreeChangedPaths lists the files a worktree changed relative to where it branched from
+// baseBranch, including uncommitted and untracked files.
+func worktreeChangedPaths(worktreePath string, baseBranch string) ([]string, error) {
+	commands := [][]string{
+		{"diff", "--name-only", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard"},
+	}
+	if strings.TrimSpace(baseBranch) != "" {
+		commands = append(commands, []string{"diff", "--name-only", baseBranch + "...HEAD"})
+	}
+	var changed []string
+	for _, args := range commands {
+		output, err := runGitOutput(worktreePath, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(output, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				changed = append(changed, line)
+			}
+		}
+	}
+	return scheduler.NormalizeOverlap(changed), nil
+}
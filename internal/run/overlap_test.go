@@ -0,0 +1,86 @@
+// Tests for in-flight overlap tracking.
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/inflight"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+)
+
+// TestInFlightOverlapIncludesWorktreeChanges combines recorded overlap with committed,
+// modified, and untracked worktree paths, ignoring the task's own task file.
+func TestInFlightOverlapIncludesWorktreeChanges(t *testing.T) {
+	repo := testrepos.New(t)
+	writeRepoFile(t, repo.Root, "shared.go", "package shared\n")
+	repo.RunGit(t, "add", "shared.go")
+	repo.RunGit(t, "commit", "-m", "Add shared file")
+
+	worktreePath := filepath.Join(t.TempDir(), "task-a")
+	repo.RunGit(t, "worktree", "add", "-b", "task-a", worktreePath, "main")
+	writeRepoFile(t, worktreePath, "internal/api/handler.go", "package api\n")
+	writeRepoFile(t, worktreePath, "_governator/tasks/task-a.md", "# Task A\n")
+	repo.RunGitInDir(t, worktreePath, "add", "-A")
+	repo.RunGitInDir(t, worktreePath, "commit", "-m", "Work on task A")
+	writeRepoFile(t, worktreePath, "shared.go", "package shared\n\nconst Changed = true\n")
+	writeRepoFile(t, worktreePath, "notes/todo.txt", "draft\n")
+
+	idx := index.Index{Tasks: []index.Task{
+		{ID: "task-a", Path: "_governator/tasks/task-a.md", Overlap: []string{"db"}},
+		{ID: "task-b", Path: "_governator/tasks/task-b.md", Overlap: []string{"api"}},
+	}}
+	inFlight := inflight.Set{}
+	if err := inFlight.AddWithStartAndPath("task-a", time.Now(), worktreePath, "", "work", "default"); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+
+	got := inFlightOverlap(idx, inFlight, "main", nil)
+	want := []string{"db", "internal/api/handler.go", "notes/todo.txt", "shared.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("inFlightOverlap = %#v, want %#v", got, want)
+	}
+}
+
+// TestInFlightOverlapReusesTickSnapshot inspects each in-flight worktree once per cache, so
+// later stages in a tick see the same overlap without running git again.
+func TestInFlightOverlapReusesTickSnapshot(t *testing.T) {
+	repo := testrepos.New(t)
+	worktreePath := filepath.Join(t.TempDir(), "task-a")
+	repo.RunGit(t, "worktree", "add", "-b", "task-a", worktreePath, "main")
+	writeRepoFile(t, worktreePath, "first.go", "package first\n")
+
+	idx := index.Index{Tasks: []index.Task{{ID: "task-a", Path: "_governator/tasks/task-a.md"}}}
+	inFlight := inflight.Set{}
+	if err := inFlight.AddWithStartAndPath("task-a", time.Now(), worktreePath, "", "work", "default"); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+
+	changes := worktreeChanges{}
+	if got := inFlightOverlap(idx, inFlight, "main", changes); !reflect.DeepEqual(got, []string{"first.go"}) {
+		t.Fatalf("first overlap = %#v", got)
+	}
+	writeRepoFile(t, worktreePath, "second.go", "package second\n")
+	if got := inFlightOverlap(idx, inFlight, "main", changes); !reflect.DeepEqual(got, []string{"first.go"}) {
+		t.Fatalf("cached overlap = %#v, want the tick snapshot", got)
+	}
+	if got := inFlightOverlap(idx, inFlight, "main", nil); !reflect.DeepEqual(got, []string{"first.go", "second.go"}) {
+		t.Fatalf("uncached overlap = %#v", got)
+	}
+}
+
+// writeRepoFile writes a file beneath root, creating parent directories.
+func writeRepoFile(t *testing.T, root string, relPath string, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", relPath, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", relPath, err)
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/cmtonkinson/governator/internal/audit"
 	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/cooldown"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/phase"
@@ -56,7 +58,7 @@ func (runner *phaseRunner) ensureWorktreeManager() error {
 	if runner.worktreeManagerInit {
 		return nil
 	}
-	manager, err := worktree.NewManager(runner.repoRoot)
+	manager, err := worktree.NewManagerWithRoot(runner.repoRoot, runner.cfg.Worktrees.Root)
 	if err != nil {
 		return err
 	}
@@ -80,7 +82,35 @@ func (runner *phaseRunner) EnsurePlanningPhases(idx *index.Index) (bool, error)
 	}
 	controller := newPlanningController(runner, idx)
 	worker := newWorkstreamRunner()
-	return worker.Run(controller)
+	handled, err := worker.Run(controller)
+	if err != nil {
+		return handled, err
+	}
+	dispatched, err := controller.DispatchConcurrent()
+	if err != nil {
+		return handled, err
+	}
+	return handled || dispatched, nil
+}
+
+// newValidationEngine builds a validation engine whose prompt validations run the configured
+// worker command as the step role, reviewing the step outputs, and are recorded in the audit log.
+func (runner *phaseRunner) newValidationEngine(step workstreamStep) *ValidationEngine {
+	warn := func(msg string) {
+		fmt.Fprintf(runner.stderr, "Warning: %s\n", msg)
+	}
+	agent := &validationAgent{
+		cfg:       runner.cfg,
+		role:      step.role,
+		artifacts: step.outputs,
+		warn:      warn,
+	}
+	if auditor, err := audit.NewLogger(runner.repoRoot, runner.stderr); err != nil {
+		warn(fmt.Sprintf("prompt validations for step %s will not be audited: %v", step.name, err))
+	} else {
+		agent.auditor = auditor
+	}
+	return NewValidationEngine(runner.repoRoot).withAgent(agent)
 }
 
 // resolvePlanningPaths derives the worktree and worker state paths for a planning step.
@@ -115,6 +145,21 @@ func (runner *phaseRunner) runningPlanningPID(workerStateDir string, taskID stri
 	return 0
 }
 
+// enforcePlanningTimeout kills a planning agent that outlived its step timeout or stalled.
+func (runner *phaseRunner) enforcePlanningTimeout(step workstreamStep, worktreePath string, workerStateDir string, pid int) error {
+	taskID := step.workstreamID()
+	timeoutSecs := runner.cfg.Timeouts.SecondsFor(string(step.role), config.StageKeyPlanning, step.name)
+	warn := func(msg string) {
+		fmt.Fprintf(runner.stderr, "Warning: %s\n", msg)
+	}
+	expiry, expired := checkWorkerExpiry(runner.inFlight, taskID, workerStateDir, worktreePath, timeoutSecs, runner.cfg.Timeouts.StallSeconds, warn)
+	if !expired {
+		return nil
+	}
+	killWorkerProcess(runner.repoRoot, pid, workerStateDir, expiry.kind(), warn)
+	return fmt.Errorf("planning step %s: %s", step.name, expiry.reason)
+}
+
 // persistInFlight writes the in-flight set to durable local state.
 func (runner *phaseRunner) persistInFlight() error {
 	if runner.inFlight == nil {
@@ -127,6 +172,19 @@ func (runner *phaseRunner) persistInFlight() error {
 }
 
 func (runner *phaseRunner) dispatchPhase(step workstreamStep) error {
+	repair, repairing, err := loadPlanningRepairState(runner.repoRoot, step.name)
+	if err != nil {
+		return err
+	}
+	if repair.Exhausted {
+		return planningRepairExhaustedError(step.name, repair)
+	}
+	if entry, cooling := roleCooldown(runner.repoRoot, runner.cfg, step.role, func(msg string) {
+		fmt.Fprintf(runner.stderr, "Warning: %s\n", msg)
+	}); cooling {
+		runner.emitPhaseCoolingDown(stepToPhase(step.name), entry)
+		return nil
+	}
 	taskID := step.workstreamID()
 	task := index.Task{
 		ID:   taskID,
@@ -150,6 +208,13 @@ func (runner *phaseRunner) dispatchPhase(step workstreamStep) error {
 	if err != nil {
 		return fmt.Errorf("ensure worktree for step %s: %w", step.name, err)
 	}
+	if worktreeResult.Reused && runner.planning.concurrent() {
+		// Concurrent steps merge through the base branch, so bring a reused worktree up to date
+		// before the next step reads its inputs.
+		if err := runGit(worktreeResult.Path, "merge", "--no-edit", baseBranch); err != nil {
+			return fmt.Errorf("sync worktree for step %s with %s: %w", step.name, baseBranch, err)
+		}
+	}
 
 	stageInput := newWorkerStageInput(
 		runner.repoRoot,
@@ -167,11 +232,24 @@ func (runner *phaseRunner) dispatchPhase(step workstreamStep) error {
 		},
 	)
 	stageInput.WorkerStateDir = planningWorkerStateDir(worktreeResult.Path, step)
+	stageInput.ExtraEnv = runner.cfg.Env.VarsFor(string(step.role), config.StageKeyPlanning)
+	stageInput.ExtraEnv["GOVERNATOR_PLANNING_STEP"] = step.name
+
+	if repairing {
+		// Each repair pass gets a fresh state dir so the previous pass's exit status is not collected.
+		stageInput.WorkerStateDir = planningRepairWorkerStateDir(stageInput.WorkerStateDir, repair.Repairs)
+		repairPromptPath, err := writeWorkerContextPrompt(runner.repoRoot, stageInput.WorkerStateDir, planningRepairPromptFileName, repair.Prompt)
+		if err != nil {
+			return err
+		}
+		stageInput.ExtraPromptPath = append(stageInput.ExtraPromptPath, repairPromptPath)
+	}
 
 	stageResult, err := worker.StageEnvAndPrompts(stageInput)
 	if err != nil {
 		return fmt.Errorf("stage planning prompts: %w", err)
 	}
+	emitTaskPrompt(runner.stdout, step.name, string(step.role), config.StageKeyPlanning, stageResult)
 
 	dispatchResult, err := worker.DispatchWorkerFromConfig(runner.cfg, task, stageResult, worktreeResult.Path, roles.StageWork, func(msg string) {
 		if msg == "" {
@@ -243,7 +321,9 @@ func (runner *phaseRunner) collectPhaseCompletion(step workstreamStep, worktreeP
 	if baseBranch == "" {
 		baseBranch = config.Defaults().Branches.Base
 	}
-	exitStatus, finished, err := worker.ReadExitStatus(workerStateDir, taskID, roles.StageWork)
+	exitStatus, finished, err := collectWorkerExit(runner.repoRoot, workerStateDir, taskID, roles.StageWork, func(msg string) {
+		fmt.Fprintf(runner.stderr, "Warning: %s\n", msg)
+	})
 	if err != nil {
 		return fmt.Errorf("read phase exit status: %w", err)
 	}
@@ -261,10 +341,10 @@ func (runner *phaseRunner) collectPhaseCompletion(step workstreamStep, worktreeP
 		Kind:  index.TaskKindPlanning,
 		Role:  step.role,
 	}
-	if _, err := finalizeStageSuccess(worktreePath, workerStateDir, phaseTask, roles.StageWork); err != nil {
+	if _, err := finalizeStageSuccess(runner.repoRoot, worktreePath, workerStateDir, phaseTask, roles.StageWork, newCommitProvenance(runner.cfg, step.role)); err != nil {
 		return fmt.Errorf("finalize step %s: %w", step.name, err)
 	}
-	if err := UpdatePlanningIndex(worktreePath, step); err != nil {
+	if err := UpdatePlanningIndex(worktreePath, step, runner.cfg.Git.Default); err != nil {
 		return fmt.Errorf("update planning index: %w", err)
 	}
 	if step.actions.mergeToBase {
@@ -283,17 +363,18 @@ func (runner *phaseRunner) mergePlanningBranch(baseBranch string, phaseBranch st
 	if err := runGitInRepo(runner.repoRoot, "checkout", baseBranch); err != nil {
 		return fmt.Errorf("checkout base branch %s: %w", baseBranch, err)
 	}
-	if err := commitPlanningIndexIfDirty(runner.repoRoot, stepTitle); err != nil {
+	if err := commitPlanningIndexIfDirty(runner.repoRoot, stepTitle, runner.cfg.Git.Merge, runner.cfg.Git.Signing); err != nil {
 		return err
 	}
-	if err := runGitInRepo(runner.repoRoot, "merge", "--no-ff", "--no-edit", phaseBranch); err != nil {
+	if err := runBaseBranchCommit(runner.repoRoot, runner.cfg.Git.Merge, runner.cfg.Git.Signing, "merge", "--no-ff", "--no-edit", phaseBranch); err != nil {
 		return fmt.Errorf("merge phase branch %s: %w", phaseBranch, err)
 	}
 	return nil
 }
 
-// commitPlanningIndexIfDirty commits the planning index on the base branch when modified.
-func commitPlanningIndexIfDirty(repoRoot string, stepTitle string) error {
+// commitPlanningIndexIfDirty commits the planning index on the base branch when modified,
+// under the merge identity and signing settings like any other base branch commit.
+func commitPlanningIndexIfDirty(repoRoot string, stepTitle string, identity config.GitIdentity, signing config.GitSigning) error {
 	status, err := runGitOutput(repoRoot, "status", "--porcelain", "--", indexFilePath)
 	if err != nil {
 		return err
@@ -309,12 +390,7 @@ func commitPlanningIndexIfDirty(repoRoot string, stepTitle string) error {
 		subject = "planning"
 	}
 	message := fmt.Sprintf("[planning] %s index", subject)
-	return runGitWithEnv(repoRoot, []string{
-		"GIT_AUTHOR_NAME=Governator CLI",
-		"GIT_AUTHOR_EMAIL=governator@localhost",
-		"GIT_COMMITTER_NAME=Governator CLI",
-		"GIT_COMMITTER_EMAIL=governator@localhost",
-	}, "commit", "-m", message)
+	return runBaseBranchCommit(repoRoot, identity, signing, "commit", "-m", message)
 }
 
 // ensureCleanRepoRoot verifies the repository root has no uncommitted changes (ignoring local-state).
@@ -378,6 +454,14 @@ func (runner *phaseRunner) emitPhaseAgentComplete(p phase.Phase, pid int) {
 	fmt.Fprintf(runner.stdout, "phase %d agent %d complete\n", p.Number(), pid)
 }
 
+func (runner *phaseRunner) emitPhaseCoolingDown(p phase.Phase, entry cooldown.Entry) {
+	fmt.Fprintf(runner.stdout, "phase %d deferred: %s cooling down until %s\n", p.Number(), entry.CLI, entry.Until.UTC().Format(time.RFC3339))
+}
+
+func (runner *phaseRunner) emitPhaseRepair(p phase.Phase, attempt int, maxAttempts int) {
+	fmt.Fprintf(runner.stdout, "phase %d validation failed; repairing (attempt %d of %d)\n", p.Number(), attempt, maxAttempts)
+}
+
 func (runner *phaseRunner) emitPhaseComplete(p phase.Phase) {
 	fmt.Fprintf(runner.stdout, "phase %d complete\n", p.Number())
 }
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/cooldown"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/phase"
@@ -156,3 +158,156 @@ func waitForPlanningExitStatus(t *testing.T, workerStateDir string, taskID strin
 	}
 	t.Fatalf("exit status not found for %s", taskID)
 }
+
+// TestPhaseRunnerDispatchesConcurrentPlanningSteps verifies independent v3 steps run in their own
+// worktrees and merge in spec order before a joining step dispatches.
+func TestPhaseRunnerDispatchesConcurrentPlanningSteps(t *testing.T) {
+	t.Parallel()
+
+	repo := testrepos.New(t)
+	repoRoot := repo.Root
+	setupPlanningRepo(t, repoRoot, repo)
+
+	spec := `{
+  "version": 3,
+  "steps": [
+    {"id": "architecture-baseline", "name": "Architecture", "prompt": "_governator/prompts/architecture-baseline.md", "role": "architect"},
+    {"id": "threat-model", "name": "Threat Model", "prompt": "_governator/prompts/gap-analysis.md", "role": "default", "depends_on": ["architecture-baseline"]},
+    {"id": "data-model", "name": "Data Model", "prompt": "_governator/prompts/gap-analysis.md", "role": "default", "depends_on": ["architecture-baseline"]},
+    {"id": "task-planning", "name": "Tasks", "prompt": "_governator/prompts/task-planning.md", "role": "planner", "depends_on": ["threat-model", "data-model"]}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(repoRoot, planningSpecFilePath), []byte(spec), 0o644); err != nil {
+		t.Fatalf("write planning spec: %v", err)
+	}
+	cfg, err := config.Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Concurrency.Global = 2
+	command := []string{"sh", "-c", `echo done > "_governator/docs/$GOVERNATOR_PLANNING_STEP.md" # {task_path}`}
+	cfg.Workers.Commands.Default = command
+	cfg.Workers.Commands.Roles = map[string][]string{"architect": command, "default": command, "planner": command}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "_governator", "_durable-state", "config.json"), data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	repo.RunGit(t, "add", "-A")
+	repo.RunGit(t, "commit", "-m", "concurrent planning spec")
+
+	inFlightStore, err := inflight.NewStore(repoRoot)
+	if err != nil {
+		t.Fatalf("new in-flight store: %v", err)
+	}
+	planning, err := newPlanningTask(repoRoot)
+	if err != nil {
+		t.Fatalf("load planning spec: %v", err)
+	}
+	runner := newPhaseRunner(repoRoot, cfg, Options{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}, inFlightStore, inflight.Set{}, planning)
+	idx, err := index.Load(filepath.Join(repoRoot, indexFilePath))
+	if err != nil {
+		t.Fatalf("load task index: %v", err)
+	}
+	step := func(id string) workstreamStep {
+		found, ok := planning.stepForID(id)
+		if !ok {
+			t.Fatalf("missing step %s", id)
+		}
+		return found
+	}
+	ensure := func() inflight.Set {
+		if _, err := runner.EnsurePlanningPhases(&idx); err != nil {
+			t.Fatalf("ensure planning phases: %v", err)
+		}
+		saved, err := inFlightStore.Load()
+		if err != nil {
+			t.Fatalf("load in-flight: %v", err)
+		}
+		return saved
+	}
+	waitFor := func(set inflight.Set, s workstreamStep) inflight.Entry {
+		entry, ok := set.Entry(s.workstreamID())
+		if !ok {
+			t.Fatalf("expected %s to be in-flight", s.name)
+		}
+		waitForPlanningExitStatus(t, entry.WorkerStateDir, s.workstreamID(), roles.StageWork)
+		return entry
+	}
+
+	saved := ensure()
+	if len(saved) != 1 {
+		t.Fatalf("in-flight = %v, want only the architecture step", saved.IDs())
+	}
+	archEntry := waitFor(saved, step("architecture-baseline"))
+
+	saved = ensure()
+	threat := waitFor(saved, step("threat-model"))
+	dataModel := waitFor(saved, step("data-model"))
+	if threat.Worktree == dataModel.Worktree || threat.Worktree == archEntry.Worktree {
+		t.Fatalf("expected separate worktrees, got %q and %q", threat.Worktree, dataModel.Worktree)
+	}
+
+	saved = ensure()
+	tasksEntry, ok := saved.Entry(step("task-planning").workstreamID())
+	if !ok || len(saved) != 1 {
+		t.Fatalf("in-flight = %v, want only the task planning step", saved.IDs())
+	}
+	if tasksEntry.Worktree != archEntry.Worktree {
+		t.Fatalf("task planning worktree = %q, want shared %q", tasksEntry.Worktree, archEntry.Worktree)
+	}
+	for _, id := range []string{"threat-model", "data-model"} {
+		docPath := filepath.Join("_governator", "docs", id+".md")
+		if _, err := os.Stat(filepath.Join(repoRoot, docPath)); err != nil {
+			t.Fatalf("expected %s merged into base: %v", docPath, err)
+		}
+		if _, err := os.Stat(filepath.Join(tasksEntry.Worktree, docPath)); err != nil {
+			t.Fatalf("expected %s synced into the task planning worktree: %v", docPath, err)
+		}
+	}
+}
+
+// TestPhaseRunnerDefersPlanningWhileCLICoolsDown verifies planning does not dispatch to a CLI
+// that is cooling down after a rate limit.
+func TestPhaseRunnerDefersPlanningWhileCLICoolsDown(t *testing.T) {
+	t.Parallel()
+
+	repo := testrepos.New(t)
+	repoRoot := repo.Root
+	setupPlanningRepo(t, repoRoot, repo)
+
+	cfg, err := config.Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := startCLICooldown(repoRoot, cooldown.Entry{CLI: config.RateLimitPatternKeyDefault, StartedAt: now, Until: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("start cooldown: %v", err)
+	}
+	inFlightStore, err := inflight.NewStore(repoRoot)
+	if err != nil {
+		t.Fatalf("new in-flight store: %v", err)
+	}
+	planning, err := newPlanningTask(repoRoot)
+	if err != nil {
+		t.Fatalf("load planning spec: %v", err)
+	}
+	stdout := &bytes.Buffer{}
+	runner := newPhaseRunner(repoRoot, cfg, Options{Stdout: stdout, Stderr: &bytes.Buffer{}}, inFlightStore, inflight.Set{}, planning)
+	idx, err := index.Load(filepath.Join(repoRoot, indexFilePath))
+	if err != nil {
+		t.Fatalf("load task index: %v", err)
+	}
+
+	if _, err := runner.EnsurePlanningPhases(&idx); err != nil {
+		t.Fatalf("ensure planning phases: %v", err)
+	}
+	if len(runner.inFlight) != 0 {
+		t.Fatalf("in-flight = %v, want nothing dispatched during cooldown", runner.inFlight.IDs())
+	}
+	if !strings.Contains(stdout.String(), "cooling down") {
+		t.Fatalf("stdout = %q, want a cooldown deferral", stdout.String())
+	}
+}
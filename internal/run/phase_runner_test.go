@@ -8,11 +8,13 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cmtonkinson/governator/internal/bootstrap"
 	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/phase"
+	"github.com/cmtonkinson/governator/internal/testrepos"
 )
 
 func TestPhaseRunnerEnsurePhasePrereqsBlocksMissingArtifacts(t *testing.T) {
@@ -72,6 +74,50 @@ func TestPhaseRunnerCompletePhaseAdvancesState(t *testing.T) {
 	}
 }
 
+// TestMergePlanningBranchUsesMergeIdentity verifies the planning index commit and the phase
+// merge are made under git.merge like other base branch commits.
+func TestMergePlanningBranchUsesMergeIdentity(t *testing.T) {
+	t.Parallel()
+
+	repo := testrepos.New(t)
+	indexPath := filepath.Join(repo.Root, indexFilePath)
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0o755); err != nil {
+		t.Fatalf("mkdir governator dir: %v", err)
+	}
+	if err := os.WriteFile(indexPath, []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	repo.RunGit(t, "add", "-A")
+	repo.RunGit(t, "commit", "-m", "Add index")
+	repo.RunGit(t, "checkout", "-b", "phase-architecture", "main")
+	if err := os.WriteFile(filepath.Join(repo.Root, "arch.md"), []byte("# Architecture\n"), 0o644); err != nil {
+		t.Fatalf("write phase doc: %v", err)
+	}
+	repo.RunGit(t, "add", "arch.md")
+	repo.RunGit(t, "commit", "-m", "Add architecture")
+	repo.RunGit(t, "checkout", "main")
+	if err := os.WriteFile(indexPath, []byte("{\"tasks\": []}\n"), 0o644); err != nil {
+		t.Fatalf("update index: %v", err)
+	}
+
+	cfg := config.Defaults()
+	cfg.Git.Merge = config.GitIdentity{Name: "Merge Bot", Email: "merge@example.com"}
+	runner := &phaseRunner{repoRoot: repo.Root, cfg: cfg, stdout: io.Discard, stderr: io.Discard}
+	if err := runner.mergePlanningBranch("main", "phase-architecture", "Architecture"); err != nil {
+		t.Fatalf("merge planning branch: %v", err)
+	}
+
+	authors := strings.Fields(repo.RunGit(t, "log", "-2", "--format=%ae/%ce"))
+	if len(authors) != 2 {
+		t.Fatalf("log = %v, want two commits", authors)
+	}
+	for _, author := range authors {
+		if author != "merge@example.com/merge@example.com" {
+			t.Fatalf("commit identities = %v, want git.merge", authors)
+		}
+	}
+}
+
 func writeRequiredDocs(t *testing.T, repoRoot string) {
 	t.Helper()
 	docsDir := filepath.Join(repoRoot, "_governator", "docs")
@@ -113,3 +159,42 @@ func writeRequiredDocs(t *testing.T, repoRoot string) {
 		t.Fatalf("mkdir tasks: %v", err)
 	}
 }
+
+func TestPhaseRunnerEnforcePlanningTimeoutUsesStepOverride(t *testing.T) {
+	t.Parallel()
+
+	repoRoot := t.TempDir()
+	writeTestPlanningSpec(t, repoRoot)
+	inFlightStore, err := inflight.NewStore(repoRoot)
+	if err != nil {
+		t.Fatalf("new in-flight store: %v", err)
+	}
+	planning, err := newPlanningTask(repoRoot)
+	if err != nil {
+		t.Fatalf("load planning spec: %v", err)
+	}
+	step, ok := planning.stepForPhase(phase.PhaseArchitectureBaseline)
+	if !ok {
+		t.Fatalf("missing architecture baseline step")
+	}
+	inFlight := inflight.Set{}
+	if err := inFlight.AddWithStart(step.workstreamID(), time.Now().Add(-10*time.Minute)); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+
+	cfg := config.Defaults()
+	cfg.Timeouts.WorkerSeconds = 300
+	cfg.Timeouts.PlanningSteps[step.name] = 2400
+	runner := newPhaseRunner(repoRoot, cfg, Options{Stdout: io.Discard, Stderr: io.Discard}, inFlightStore, inFlight, planning)
+	if err := runner.enforcePlanningTimeout(step, t.TempDir(), t.TempDir(), 0); err != nil {
+		t.Fatalf("step override should keep the agent running: %v", err)
+	}
+
+	cfg.Timeouts.PlanningSteps[step.name] = 0
+	cfg.Timeouts.Stages[config.StageKeyPlanning] = 120
+	runner = newPhaseRunner(repoRoot, cfg, Options{Stdout: io.Discard, Stderr: io.Discard}, inFlightStore, inFlight, planning)
+	err = runner.enforcePlanningTimeout(step, t.TempDir(), t.TempDir(), 0)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 120 seconds") {
+		t.Fatalf("expected planning stage timeout, got %v", err)
+	}
+}
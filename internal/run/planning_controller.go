@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/cmtonkinson/governator/internal/digests"
 	"github.com/cmtonkinson/governator/internal/index"
 
 	"github.com/cmtonkinson/governator/internal/roles"
@@ -63,6 +62,9 @@ func (controller *planningController) Collect(step workstreamStep) (workstreamCo
 
 	runningPID := controller.runner.runningPlanningPID(workerStateDir, taskID, roles.StageWork)
 	if runningPID != 0 {
+		if err := controller.runner.enforcePlanningTimeout(step, worktreePath, workerStateDir, runningPID); err != nil {
+			return result, err
+		}
 		result.RunningPIDs = []int{runningPID}
 		return result, nil
 	}
@@ -97,26 +99,26 @@ func (controller *planningController) Advance(step workstreamStep, collect works
 
 	// Run validations after worker completion
 	if len(step.validations) > 0 {
-		validationEngine := NewValidationEngine(controller.runner.repoRoot)
+		validationEngine := controller.runner.newValidationEngine(step)
 		results, err := validationEngine.RunValidations(step.name, step.displayName, step.validations)
 		if err != nil {
 			return false, fmt.Errorf("validation execution failed: %w", err)
 		}
 
-		// Check if all validations passed
-		allValid := true
+		var failures []ValidationResult
 		for _, result := range results {
 			if !result.Valid {
-				allValid = false
 				controller.runner.logf("Validation failed for step %s: %s (%s)", step.name, result.Message, result.Type)
-				break
+				failures = append(failures, result)
 			}
 		}
-
-		if !allValid {
-			return false, fmt.Errorf("validation failed for step %s: planning cannot advance", step.name)
+		if len(failures) > 0 {
+			return false, controller.repairStep(step, failures)
 		}
 	}
+	if err := clearPlanningRepairState(controller.runner.repoRoot, step.name); err != nil {
+		return false, err
+	}
 
 	// Complete the phase and update planning state
 	if err := controller.runner.completePhase(step); err != nil {
@@ -159,6 +161,34 @@ func (controller *planningController) Advance(step workstreamStep, collect works
 	return true, nil
 }
 
+// repairStep records a validation failures prompt so the runner re-dispatches the step, or
+// fails once the step has used its configured attempts.
+func (controller *planningController) repairStep(step workstreamStep, failures []ValidationResult) error {
+	repoRoot := controller.runner.repoRoot
+	state, _, err := loadPlanningRepairState(repoRoot, step.name)
+	if err != nil {
+		return err
+	}
+	maxAttempts := controller.runner.cfg.Retries.PlanningValidationAttempts
+	attempt := state.Repairs + 2
+	if attempt > maxAttempts || state.Exhausted {
+		// Keep the record so later ticks fail the same way instead of starting a fresh
+		// round of repairs; ResetPlanningToStep clears it.
+		state.Exhausted = true
+		if err := savePlanningRepairState(repoRoot, step.name, state); err != nil {
+			return err
+		}
+		return planningRepairExhaustedError(step.name, state)
+	}
+	state.Repairs++
+	state.Prompt = planningRepairPromptContent(step, failures, attempt, maxAttempts)
+	if err := savePlanningRepairState(repoRoot, step.name, state); err != nil {
+		return err
+	}
+	controller.runner.emitPhaseRepair(stepToPhase(step.name), attempt, maxAttempts)
+	return nil
+}
+
 // GateBeforeDispatch checks the configured gate before dispatching the step.
 func (controller *planningController) GateBeforeDispatch(step workstreamStep) error {
 	// New validation engine doesn't use phase-based gating
@@ -174,16 +204,90 @@ func (controller *planningController) Dispatch(step workstreamStep) (workstreamD
 	return workstreamDispatchResult{Handled: true}, nil
 }
 
+// DispatchConcurrent starts planning steps ahead of the current one whose dependencies have all
+// merged, up to the global concurrency limit. Finished steps stay in flight until the current
+// step reaches them, so branches still merge into the base branch in spec order.
+func (controller *planningController) DispatchConcurrent() (bool, error) {
+	current, ok, err := controller.CurrentStep()
+	if err != nil || !ok {
+		return false, err
+	}
+	handled := false
+	merged := map[string]struct{}{}
+	ahead := false
+	for _, step := range controller.runner.planning.ordered {
+		if step.name == current.name {
+			ahead = true
+			continue
+		}
+		if !ahead {
+			merged[step.name] = struct{}{}
+			continue
+		}
+		taskID := step.workstreamID()
+		if controller.runner.inFlight.Contains(taskID) {
+			if err := controller.enforceConcurrentTimeout(step); err != nil {
+				return handled, err
+			}
+			continue
+		}
+		if !step.concurrent || !planningDependenciesMerged(step, merged) {
+			continue
+		}
+		if len(controller.runner.inFlight) >= controller.runner.cfg.Concurrency.Global {
+			break
+		}
+		skip, err := controller.shouldSkipStep(step)
+		if err != nil {
+			return handled, err
+		}
+		if skip {
+			continue
+		}
+		if err := controller.runner.dispatchPhase(step); err != nil {
+			return handled, err
+		}
+		handled = true
+	}
+	return handled, nil
+}
+
+// enforceConcurrentTimeout applies the step timeout to a step running ahead of the current one.
+func (controller *planningController) enforceConcurrentTimeout(step workstreamStep) error {
+	entry, _ := controller.runner.inFlight.Entry(step.workstreamID())
+	worktreePath, workerStateDir, err := controller.runner.resolvePlanningPaths(step, entry)
+	if err != nil {
+		return err
+	}
+	runningPID := controller.runner.runningPlanningPID(workerStateDir, step.workstreamID(), roles.StageWork)
+	if runningPID == 0 {
+		return nil
+	}
+	return controller.runner.enforcePlanningTimeout(step, worktreePath, workerStateDir, runningPID)
+}
+
+// planningDependenciesMerged reports whether every dependency of the step has merged.
+func planningDependenciesMerged(step workstreamStep, merged map[string]struct{}) bool {
+	for _, dependency := range step.dependsOn {
+		if _, ok := merged[dependency]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // shouldSkipStep reports whether a planning step can be advanced without dispatching a worker.
+// It runs every tick, so prompt validations, which start a worker, are left to Advance.
 func (controller *planningController) shouldSkipStep(step workstreamStep) (bool, error) {
 	if controller.idx == nil || !hasExecutionTasks(*controller.idx) {
 		return false, nil
 	}
-	if len(step.validations) == 0 {
+	validations := withoutPromptValidations(step.validations)
+	if len(validations) == 0 {
 		return false, nil
 	}
 	validationEngine := NewValidationEngine(controller.runner.repoRoot)
-	results, err := validationEngine.RunValidations(step.name, step.displayName, step.validations)
+	results, err := validationEngine.RunValidations(step.name, step.displayName, validations)
 	if err != nil {
 		return false, fmt.Errorf("validation execution failed: %w", err)
 	}
@@ -195,6 +299,17 @@ func (controller *planningController) shouldSkipStep(step workstreamStep) (bool,
 	return true, nil
 }
 
+// withoutPromptValidations returns the validations that need no worker to run.
+func withoutPromptValidations(validations []PlanningValidationSpec) []PlanningValidationSpec {
+	var filtered []PlanningValidationSpec
+	for _, validation := range validations {
+		if validation.Type != "prompt" {
+			filtered = append(filtered, validation)
+		}
+	}
+	return filtered
+}
+
 // hasExecutionTasks reports whether the index already contains execution work from a prior plan.
 func hasExecutionTasks(idx index.Index) bool {
 	for _, task := range idx.Tasks {
@@ -244,7 +359,7 @@ func (controller *planningController) persistPlanningState(nextStepID string) er
 	if err != nil {
 		return fmt.Errorf("reload task index: %w", err)
 	}
-	digestsMap, err := digests.Compute(controller.runner.repoRoot)
+	digestsMap, err := computePlanningDigests(controller.runner.repoRoot)
 	if err != nil {
 		return fmt.Errorf("compute digests: %w", err)
 	}
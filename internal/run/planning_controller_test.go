@@ -158,3 +158,48 @@ func writeInventoryTaskMarkdown(t *testing.T, repoRoot string, name string, cont
 	}
 	return filepath.ToSlash(filepath.Join("_governator", "tasks", name))
 }
+
+// TestPlanningControllerSkipCheckIgnoresPromptValidations verifies the per-tick skip check never
+// starts a prompt validation worker.
+func TestPlanningControllerSkipCheckIgnoresPromptValidations(t *testing.T) {
+	t.Parallel()
+
+	repo := testrepos.New(t)
+	writeTestPlanningSpec(t, repo.Root)
+	writeTestFile(t, filepath.Join(repo.Root, "docs", "arch.md"), "# Architecture\n")
+	writeTestFile(t, filepath.Join(repo.Root, "prompts", "review.md"), "Review the architecture.\n")
+
+	existingTask := index.Task{
+		ID:    "001-existing",
+		Title: "Existing Task",
+		Kind:  index.TaskKindExecution,
+		State: index.TaskStateBacklog,
+		Role:  index.Role("default"),
+	}
+	idx := seedPlanningControllerIndex(t, repo.Root, []index.Task{existingTask})
+	controller, step := newPlanningControllerFixture(t, repo.Root, idx)
+	markerPath := filepath.Join(t.TempDir(), "validator-ran")
+	controller.runner.cfg.Workers.Commands.Default = []string{"sh", "-c", `touch "$0"; echo "VERDICT: PASS" # {task_path}`, markerPath}
+
+	prompt := PlanningValidationSpec{Type: "prompt", PromptPath: "prompts/review.md"}
+	step.validations = []PlanningValidationSpec{{Type: "file", Path: "docs/arch.md"}, prompt}
+	skip, err := controller.shouldSkipStep(step)
+	if err != nil {
+		t.Fatalf("shouldSkipStep: %v", err)
+	}
+	if !skip {
+		t.Fatal("expected step with passing file validation to be skipped")
+	}
+
+	step.validations = []PlanningValidationSpec{prompt}
+	skip, err = controller.shouldSkipStep(step)
+	if err != nil {
+		t.Fatalf("shouldSkipStep: %v", err)
+	}
+	if skip {
+		t.Fatal("expected step with only prompt validations not to be skipped")
+	}
+	if _, err := os.Stat(markerPath); !os.IsNotExist(err) {
+		t.Fatalf("skip check ran the prompt validation worker: %v", err)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/cmtonkinson/governator/internal/digests"
+	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/index"
 )
 
@@ -52,7 +52,7 @@ func SeedPlanningIndex(repoRoot string) error {
 		},
 	}
 
-	digestsMap, err := digests.Compute(repoRoot)
+	digestsMap, err := computePlanningDigests(repoRoot)
 	if err != nil {
 		return fmt.Errorf("compute digests: %w", err)
 	}
@@ -70,7 +70,7 @@ func SeedPlanningIndex(repoRoot string) error {
 }
 
 // UpdatePlanningIndex refreshes digests after a completed planning step.
-func UpdatePlanningIndex(worktreePath string, step workstreamStep) error {
+func UpdatePlanningIndex(worktreePath string, step workstreamStep, identity config.GitIdentity) error {
 	if strings.TrimSpace(worktreePath) == "" {
 		return fmt.Errorf("worktree path is required")
 	}
@@ -103,7 +103,7 @@ func UpdatePlanningIndex(worktreePath string, step workstreamStep) error {
 		}
 	}
 
-	digestsMap, err := digests.Compute(worktreePath)
+	digestsMap, err := computePlanningDigests(worktreePath)
 	if err != nil {
 		return fmt.Errorf("compute digests: %w", err)
 	}
@@ -112,7 +112,7 @@ func UpdatePlanningIndex(worktreePath string, step workstreamStep) error {
 	if err := index.Save(indexPath, idx); err != nil {
 		return fmt.Errorf("save task index: %w", err)
 	}
-	return commitPlanningIndex(worktreePath, step.title())
+	return commitPlanningIndex(worktreePath, step.title(), identity)
 }
 
 // planningStepWorkstreamID builds the stable, worktree-safe id for a planning step workstream.
@@ -121,7 +121,7 @@ func planningStepWorkstreamID(step workstreamStep) string {
 }
 
 // commitPlanningIndex records index updates on the planning branch when needed.
-func commitPlanningIndex(worktreePath string, title string) error {
+func commitPlanningIndex(worktreePath string, title string, identity config.GitIdentity) error {
 	status, err := runGitOutput(worktreePath, "status", "--porcelain", "--", indexFilePath)
 	if err != nil {
 		return err
@@ -137,10 +137,5 @@ func commitPlanningIndex(worktreePath string, title string) error {
 		subject = "planning"
 	}
 	message := fmt.Sprintf("[planning] %s index", subject)
-	return runGitWithEnv(worktreePath, []string{
-		"GIT_AUTHOR_NAME=Governator CLI",
-		"GIT_AUTHOR_EMAIL=governator@localhost",
-		"GIT_COMMITTER_NAME=Governator CLI",
-		"GIT_COMMITTER_EMAIL=governator@localhost",
-	}, "commit", "-m", message)
+	return runGitWithEnv(worktreePath, gitIdentityEnv(stageCommitIdentity(identity)), "commit", "-m", message)
 }
@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/cmtonkinson/governator/internal/digests"
 	"github.com/cmtonkinson/governator/internal/index"
 )
 
@@ -86,23 +85,36 @@ func planningTaskState(idx index.Index) (string, error) {
 
 // ResetPlanningToStep updates planning state to restart the planning pipeline at a specific step.
 func ResetPlanningToStep(repoRoot string, nextStepID string) error {
-	if strings.TrimSpace(repoRoot) == "" {
-		return fmt.Errorf("repo root is required")
-	}
 	if strings.TrimSpace(nextStepID) == "" {
 		return fmt.Errorf("planning step id is required")
 	}
+	if err := clearPlanningRepairStates(repoRoot); err != nil {
+		return err
+	}
+	return refreshPlanningDigests(repoRoot, func(idx *index.Index) {
+		updatePlanningState(idx, nextStepID)
+	})
+}
+
+// refreshPlanningDigests recomputes the stored planning digests, applying update to the index
+// before it is saved.
+func refreshPlanningDigests(repoRoot string, update func(idx *index.Index)) error {
+	if strings.TrimSpace(repoRoot) == "" {
+		return fmt.Errorf("repo root is required")
+	}
 	indexPath := filepath.Join(repoRoot, indexFilePath)
 	updated, err := index.Load(indexPath)
 	if err != nil {
 		return fmt.Errorf("reload task index: %w", err)
 	}
-	digestsMap, err := digests.Compute(repoRoot)
+	digestsMap, err := computePlanningDigests(repoRoot)
 	if err != nil {
 		return fmt.Errorf("compute digests: %w", err)
 	}
 	updated.Digests = digestsMap
-	updatePlanningState(&updated, nextStepID)
+	if update != nil {
+		update(&updated)
+	}
 	if err := index.Save(indexPath, updated); err != nil {
 		return fmt.Errorf("save task index: %w", err)
 	}
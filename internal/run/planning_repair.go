@@ -0,0 +1,121 @@
+// Package run provides validation repair passes for planning steps.
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// planningRepairStateDir holds one repair record per planning step with failed validations.
+	planningRepairStateDir = "_governator/_local-state/planning-repairs"
+	// planningRepairPromptFileName is the generated validation failures prompt in the worker state dir.
+	planningRepairPromptFileName = "validation-repair.md"
+)
+
+// planningRepairState records the repair passes spent on a planning step and the prompt for
+// the next one. Exhausted marks a step that failed its last attempt; it stays set until
+// planning is reset.
+type planningRepairState struct {
+	Repairs   int    `json:"repairs"`
+	Prompt    string `json:"prompt"`
+	Exhausted bool   `json:"exhausted,omitempty"`
+}
+
+// planningRepairExhaustedError reports a step that failed validation on every attempt.
+func planningRepairExhaustedError(stepID string, state planningRepairState) error {
+	return fmt.Errorf("validation failed for step %s after %d attempts: planning cannot advance", stepID, state.Repairs+1)
+}
+
+// planningRepairStatePath returns the repair record path for a planning step.
+func planningRepairStatePath(repoRoot string, stepID string) string {
+	return filepath.Join(repoRoot, planningRepairStateDir, stepID+".json")
+}
+
+// loadPlanningRepairState reads the repair record for a step, reporting false when none exists.
+func loadPlanningRepairState(repoRoot string, stepID string) (planningRepairState, bool, error) {
+	path := planningRepairStatePath(repoRoot, stepID)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return planningRepairState{}, false, nil
+		}
+		return planningRepairState{}, false, fmt.Errorf("read planning repair state %s: %w", path, err)
+	}
+	var state planningRepairState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return planningRepairState{}, false, fmt.Errorf("decode planning repair state %s: %w", path, err)
+	}
+	return state, true, nil
+}
+
+// savePlanningRepairState writes the repair record for a step.
+func savePlanningRepairState(repoRoot string, stepID string, state planningRepairState) error {
+	path := planningRepairStatePath(repoRoot, stepID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create planning repair dir: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode planning repair state: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write planning repair state %s: %w", path, err)
+	}
+	return nil
+}
+
+// clearPlanningRepairState removes the repair record for a step, if any.
+func clearPlanningRepairState(repoRoot string, stepID string) error {
+	path := planningRepairStatePath(repoRoot, stepID)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove planning repair state %s: %w", path, err)
+	}
+	return nil
+}
+
+// clearPlanningRepairStates removes every planning repair record, e.g. when planning restarts.
+func clearPlanningRepairStates(repoRoot string) error {
+	dir := filepath.Join(repoRoot, planningRepairStateDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove planning repair state dir %s: %w", dir, err)
+	}
+	return nil
+}
+
+// planningRepairWorkerStateDir returns the worker state dir for a step's numbered repair pass.
+func planningRepairWorkerStateDir(workerStateDir string, repair int) string {
+	return fmt.Sprintf("%s-repair-%d", workerStateDir, repair)
+}
+
+// planningRepairPromptContent renders the validation failures prompt for the next pass at a step.
+func planningRepairPromptContent(step workstreamStep, failures []ValidationResult, attempt int, maxAttempts int) string {
+	var b strings.Builder
+	b.WriteString("# Validation Failures\n\n")
+	fmt.Fprintf(&b, "Your previous pass at planning step `%s` finished, but the validations below failed. ", step.name)
+	b.WriteString("Fix the artifacts so every check passes; work that already passes does not need to change.\n\n")
+	fmt.Fprintf(&b, "This is attempt %d of %d for this step.\n", attempt, maxAttempts)
+	for i, failure := range failures {
+		validation := failure.Validation
+		fmt.Fprintf(&b, "\n## Failure %d: %s\n\n", i+1, failure.Type)
+		writeRepairField(&b, "Path", validation.Path)
+		writeRepairField(&b, "Regex", validation.FileRegex)
+		writeRepairField(&b, "Command", validation.Command)
+		writeRepairField(&b, "Prompt", validation.PromptPath)
+		writeRepairField(&b, "Expected stdout to contain", validation.StdoutContains)
+		writeRepairField(&b, "Expected stdout to match", validation.StdoutRegex)
+		fmt.Fprintf(&b, "- Result: %s\n", failure.Message)
+	}
+	return b.String()
+}
+
+// writeRepairField writes one labelled validation field, skipping empty values.
+func writeRepairField(b *strings.Builder, label string, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	fmt.Fprintf(b, "- %s: `%s`\n", label, value)
+}
@@ -0,0 +1,206 @@
+// Tests for validation repair passes at planning steps.
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/inflight"
+	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+)
+
+// TestPlanningRepairPromptContentListsFailures verifies every failed validation is listed with its details.
+func TestPlanningRepairPromptContentListsFailures(t *testing.T) {
+	t.Parallel()
+
+	step := workstreamStep{name: "architecture-baseline"}
+	failures := []ValidationResult{
+		{
+			Type:       "file",
+			Message:    "file content does not match regex",
+			Validation: PlanningValidationSpec{Type: "file", Path: "_governator/docs/arch.md", FileRegex: "^# Architecture"},
+		},
+		{
+			Type:       "command",
+			Message:    `stdout does not contain "ok"; stdout: "nope"`,
+			Validation: PlanningValidationSpec{Type: "command", Command: "make check", StdoutContains: "ok"},
+		},
+	}
+
+	content := planningRepairPromptContent(step, failures, 2, 3)
+
+	for _, want := range []string{
+		"# Validation Failures",
+		"`architecture-baseline`",
+		"attempt 2 of 3",
+		"## Failure 1: file",
+		"- Path: `_governator/docs/arch.md`",
+		"- Regex: `^# Architecture`",
+		"## Failure 2: command",
+		"- Command: `make check`",
+		"- Expected stdout to contain: `ok`",
+		`- Result: stdout does not contain "ok"; stdout: "nope"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("repair prompt missing %q:\n%s", want, content)
+		}
+	}
+}
+
+// TestPhaseRunnerRepairsFailedPlanningValidations verifies a step with failed validations is
+// re-dispatched with the failures prompt and advances once a repair pass fixes them.
+func TestPhaseRunnerRepairsFailedPlanningValidations(t *testing.T) {
+	t.Parallel()
+
+	runsPath := filepath.Join(t.TempDir(), "runs")
+	// The first pass writes nothing; later passes write the document the validation expects.
+	command := []string{"sh", "-c", `echo run >> "$0"; if [ "$(wc -l < "$0")" -ge 2 ]; then echo fixed > _governator/docs/fixed.md; fi # {task_path}`, runsPath}
+	runner, idx := newRepairTestRunner(t, command, 3)
+	step, ok := runner.planning.stepForID("architecture-baseline")
+	if !ok {
+		t.Fatalf("missing architecture step")
+	}
+
+	entry := ensureRepairTestStep(t, runner, &idx, step)
+	entry = ensureRepairTestStep(t, runner, &idx, step)
+	if want := planningRepairWorkerStateDir(planningWorkerStateDir(entry.Worktree, step), 1); entry.WorkerStateDir != want {
+		t.Fatalf("repair worker state dir = %q, want %q", entry.WorkerStateDir, want)
+	}
+	prompt, err := os.ReadFile(filepath.Join(entry.WorkerStateDir, planningRepairPromptFileName))
+	if err != nil {
+		t.Fatalf("read repair prompt: %v", err)
+	}
+	if !strings.Contains(string(prompt), "- Path: `_governator/docs/fixed.md`") || !strings.Contains(string(prompt), "attempt 2 of 3") {
+		t.Fatalf("unexpected repair prompt:\n%s", prompt)
+	}
+
+	if _, err := runner.EnsurePlanningPhases(&idx); err != nil {
+		t.Fatalf("ensure planning phases after repair: %v", err)
+	}
+	state, err := planningTaskState(idx)
+	if err != nil {
+		t.Fatalf("planning state: %v", err)
+	}
+	if state != "task-planning" {
+		t.Fatalf("planning state = %q, want task-planning after repair", state)
+	}
+	if _, found, err := loadPlanningRepairState(runner.repoRoot, step.name); err != nil || found {
+		t.Fatalf("expected repair state cleared, found=%v err=%v", found, err)
+	}
+}
+
+// TestPhaseRunnerFailsAfterPlanningRepairAttempts verifies planning fails once a step has used
+// its configured validation attempts, and keeps failing until planning is reset.
+func TestPhaseRunnerFailsAfterPlanningRepairAttempts(t *testing.T) {
+	t.Parallel()
+
+	runner, idx := newRepairTestRunner(t, []string{"sh", "-c", "true {task_path}"}, 2)
+	step, ok := runner.planning.stepForID("architecture-baseline")
+	if !ok {
+		t.Fatalf("missing architecture step")
+	}
+
+	ensureRepairTestStep(t, runner, &idx, step)
+	ensureRepairTestStep(t, runner, &idx, step)
+
+	_, err := runner.EnsurePlanningPhases(&idx)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Fatalf("error = %v, want failure after 2 attempts", err)
+	}
+	if _, found, err := loadPlanningRepairState(runner.repoRoot, step.name); err != nil || !found {
+		t.Fatalf("expected repair state kept, found=%v err=%v", found, err)
+	}
+	if _, err := runner.EnsurePlanningPhases(&idx); err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Fatalf("second error = %v, want the failure to stick", err)
+	}
+
+	if err := ResetPlanningToStep(runner.repoRoot, step.name); err != nil {
+		t.Fatalf("reset planning: %v", err)
+	}
+	if _, found, err := loadPlanningRepairState(runner.repoRoot, step.name); err != nil || found {
+		t.Fatalf("expected repair state cleared by reset, found=%v err=%v", found, err)
+	}
+}
+
+// newRepairTestRunner builds a phase runner over a spec whose first step has a file validation
+// the worker command must satisfy.
+func newRepairTestRunner(t *testing.T, command []string, attempts int) (*phaseRunner, index.Index) {
+	t.Helper()
+
+	repo := testrepos.New(t)
+	repoRoot := repo.Root
+	setupPlanningRepo(t, repoRoot, repo)
+
+	spec := `{
+  "version": 3,
+  "steps": [
+    {
+      "id": "architecture-baseline",
+      "name": "Architecture",
+      "prompt": "_governator/prompts/architecture-baseline.md",
+      "role": "architect",
+      "validations": [{"type": "file", "path": "_governator/docs/fixed.md"}]
+    },
+    {"id": "task-planning", "name": "Tasks", "prompt": "_governator/prompts/task-planning.md", "role": "planner"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(repoRoot, "_governator", "planning.json"), []byte(spec), 0o644); err != nil {
+		t.Fatalf("write planning spec: %v", err)
+	}
+	cfg, err := config.Load(repoRoot, nil, nil)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Retries.PlanningValidationAttempts = attempts
+	cfg.Workers.Commands.Default = command
+	cfg.Workers.Commands.Roles = map[string][]string{"architect": command, "planner": command}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "_governator", "_durable-state", "config.json"), data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	repo.RunGit(t, "add", "-A")
+	repo.RunGit(t, "commit", "-m", "repair planning spec")
+
+	inFlightStore, err := inflight.NewStore(repoRoot)
+	if err != nil {
+		t.Fatalf("new in-flight store: %v", err)
+	}
+	planning, err := newPlanningTask(repoRoot)
+	if err != nil {
+		t.Fatalf("load planning spec: %v", err)
+	}
+	runner := newPhaseRunner(repoRoot, cfg, Options{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}, inFlightStore, inflight.Set{}, planning)
+	idx, err := index.Load(filepath.Join(repoRoot, indexFilePath))
+	if err != nil {
+		t.Fatalf("load task index: %v", err)
+	}
+	return runner, idx
+}
+
+// ensureRepairTestStep runs one planning pass and waits for the dispatched step to exit.
+func ensureRepairTestStep(t *testing.T, runner *phaseRunner, idx *index.Index, step workstreamStep) inflight.Entry {
+	t.Helper()
+	if _, err := runner.EnsurePlanningPhases(idx); err != nil {
+		t.Fatalf("ensure planning phases: %v", err)
+	}
+	saved, err := runner.inFlightStore.Load()
+	if err != nil {
+		t.Fatalf("load in-flight: %v", err)
+	}
+	entry, ok := saved.Entry(step.workstreamID())
+	if !ok {
+		t.Fatalf("expected %s to be in-flight", step.name)
+	}
+	waitForPlanningExitStatus(t, entry.WorkerStateDir, step.workstreamID(), roles.StageWork)
+	return entry
+}
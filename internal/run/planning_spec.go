@@ -16,7 +16,9 @@ import (
 
 const (
 	planningSpecFilePath = "_governator/planning.json"
-	planningSpecVersion  = 2
+	planningSpecVersion  = 3
+	// planningSpecSerialVersion is the legacy schema whose steps always run one after another.
+	planningSpecSerialVersion = 2
 )
 
 // PlanningSpec defines the JSON schema for the planning workstream.
@@ -31,6 +33,9 @@ type PlanningStepSpec struct {
 	Name        string                   `json:"name"`
 	Prompt      string                   `json:"prompt"`
 	Role        string                   `json:"role"`
+	Inputs      []string                 `json:"inputs,omitempty"`     // artifacts the step reads; drift in these restarts planning here
+	Outputs     []string                 `json:"outputs,omitempty"`    // artifacts the step writes
+	DependsOn   []string                 `json:"depends_on,omitempty"` // earlier step ids that must merge first (v3)
 	Validations []PlanningValidationSpec `json:"validations,omitempty"`
 }
 
@@ -65,7 +70,7 @@ func LoadPlanningSpec(repoRoot string) (PlanningSpec, error) {
 	return spec, nil
 }
 
-// ParsePlanningSpec decodes and validates a planning spec payload.
+// ParsePlanningSpec decodes and validates a planning spec payload, migrating v2 specs to v3.
 func ParsePlanningSpec(data []byte) (PlanningSpec, error) {
 	decoder := json.NewDecoder(bytes.NewReader(data))
 	decoder.DisallowUnknownFields()
@@ -76,12 +81,34 @@ func ParsePlanningSpec(data []byte) (PlanningSpec, error) {
 	if err := ensureNoTrailingJSON(decoder); err != nil {
 		return PlanningSpec{}, err
 	}
+	spec, err := migratePlanningSpec(spec)
+	if err != nil {
+		return PlanningSpec{}, err
+	}
 	if err := validatePlanningSpec(spec); err != nil {
 		return PlanningSpec{}, err
 	}
 	return spec, nil
 }
 
+// migratePlanningSpec upgrades a serial v2 spec to v3 by making each step depend on the one before it.
+func migratePlanningSpec(spec PlanningSpec) (PlanningSpec, error) {
+	if spec.Version != planningSpecSerialVersion {
+		return spec, nil
+	}
+	steps := make([]PlanningStepSpec, len(spec.Steps))
+	for i, step := range spec.Steps {
+		if len(step.DependsOn) > 0 {
+			return PlanningSpec{}, fmt.Errorf("planning step %q depends_on requires planning spec version %d", step.ID, planningSpecVersion)
+		}
+		if i > 0 {
+			step.DependsOn = []string{spec.Steps[i-1].ID}
+		}
+		steps[i] = step
+	}
+	return PlanningSpec{Version: planningSpecVersion, Steps: steps}, nil
+}
+
 // ensureNoTrailingJSON rejects extra tokens after a parsed JSON object.
 func ensureNoTrailingJSON(decoder *json.Decoder) error {
 	var trailer any
@@ -127,6 +154,15 @@ func validatePlanningSpec(spec PlanningSpec) error {
 		if strings.TrimSpace(step.Role) == "" {
 			return fmt.Errorf("planning step %q role is required", step.ID)
 		}
+		if err := validatePlanningDependencies(step, ids); err != nil {
+			return fmt.Errorf("planning step %q %w", step.ID, err)
+		}
+		if err := validatePlanningArtifacts("inputs", step.Inputs); err != nil {
+			return fmt.Errorf("planning step %q %w", step.ID, err)
+		}
+		if err := validatePlanningArtifacts("outputs", step.Outputs); err != nil {
+			return fmt.Errorf("planning step %q %w", step.ID, err)
+		}
 		if err := validatePlanningValidations(step.ID, step.Validations); err != nil {
 			return fmt.Errorf("planning step %q validations: %w", step.ID, err)
 		}
@@ -134,6 +170,35 @@ func validatePlanningSpec(spec PlanningSpec) error {
 	return nil
 }
 
+// validatePlanningDependencies checks that depends_on only names steps declared earlier, which
+// keeps the spec order a valid topological order and rules out cycles.
+func validatePlanningDependencies(step PlanningStepSpec, declared map[string]struct{}) error {
+	seen := make(map[string]struct{}, len(step.DependsOn))
+	for i, dependency := range step.DependsOn {
+		if dependency == step.ID {
+			return fmt.Errorf("depends_on[%d] must not reference the step itself", i)
+		}
+		if _, ok := declared[dependency]; !ok {
+			return fmt.Errorf("depends_on[%d] %q must reference an earlier step", i, dependency)
+		}
+		if _, ok := seen[dependency]; ok {
+			return fmt.Errorf("depends_on[%d] %q is duplicated", i, dependency)
+		}
+		seen[dependency] = struct{}{}
+	}
+	return nil
+}
+
+// validatePlanningArtifacts checks that declared input or output paths are usable artifact patterns.
+func validatePlanningArtifacts(field string, artifacts []string) error {
+	for i, artifact := range artifacts {
+		if _, err := normalizePlanningArtifactPath(artifact); err != nil {
+			return fmt.Errorf("%s[%d] %q: %w", field, i, artifact, err)
+		}
+	}
+	return nil
+}
+
 // validatePlanningValidations checks that validation specs are well-formed.
 func validatePlanningValidations(stepID string, validations []PlanningValidationSpec) error {
 	for i, validation := range validations {
@@ -233,6 +298,7 @@ func rejectValidationFields(label string, validation PlanningValidationSpec, fie
 func planningTaskFromSpec(spec PlanningSpec) (planningTask, error) {
 	ordered := make([]workstreamStep, 0, len(spec.Steps))
 	byID := make(map[string]workstreamStep, len(spec.Steps))
+	ancestors := planningStepAncestors(spec.Steps)
 
 	for i, stepSpec := range spec.Steps {
 		promptPath, _ := normalizePlanningPromptPath(stepSpec.Prompt)
@@ -251,6 +317,10 @@ func planningTaskFromSpec(spec PlanningSpec) (planningTask, error) {
 			promptPath:  promptPath,
 			role:        index.Role(stepSpec.Role),
 			validations: stepSpec.Validations,
+			inputs:      normalizePlanningArtifacts(stepSpec.Inputs),
+			outputs:     normalizePlanningArtifacts(stepSpec.Outputs),
+			dependsOn:   stepSpec.DependsOn,
+			concurrent:  planningStepConcurrent(spec.Steps, i, ancestors),
 			actions: workstreamStepActions{
 				mergeToBase:  true,
 				advancePhase: true,
@@ -266,6 +336,41 @@ func planningTaskFromSpec(spec PlanningSpec) (planningTask, error) {
 	}, nil
 }
 
+// planningStepAncestors returns, for each step id, the set of step ids it transitively depends on.
+func planningStepAncestors(steps []PlanningStepSpec) map[string]map[string]struct{} {
+	ancestors := make(map[string]map[string]struct{}, len(steps))
+	for _, step := range steps {
+		set := map[string]struct{}{}
+		for _, dependency := range step.DependsOn {
+			set[dependency] = struct{}{}
+			for ancestor := range ancestors[dependency] {
+				set[ancestor] = struct{}{}
+			}
+		}
+		ancestors[step.ID] = set
+	}
+	return ancestors
+}
+
+// planningStepConcurrent reports whether the step at position i is unordered relative to some
+// other step, meaning the two may run at the same time and need separate worktrees.
+func planningStepConcurrent(steps []PlanningStepSpec, i int, ancestors map[string]map[string]struct{}) bool {
+	id := steps[i].ID
+	for j, other := range steps {
+		if j == i {
+			continue
+		}
+		if _, ok := ancestors[id][other.ID]; ok {
+			continue
+		}
+		if _, ok := ancestors[other.ID][id]; ok {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
 // normalizePlanningPromptPath validates and normalizes a planning prompt path.
 func normalizePlanningPromptPath(value string) (string, error) {
 	trimmed := strings.TrimSpace(value)
@@ -285,6 +390,55 @@ func normalizePlanningPromptPath(value string) (string, error) {
 	return filepath.ToSlash(cleaned), nil
 }
 
+// normalizePlanningArtifactPath validates and normalizes a step input or output path. A trailing
+// slash marks a directory that covers everything beneath it; otherwise the path may be a glob.
+func normalizePlanningArtifactPath(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if strings.Contains(trimmed, "\\") {
+		return "", fmt.Errorf("path must use forward slashes")
+	}
+	if strings.HasPrefix(trimmed, "/") {
+		return "", fmt.Errorf("path must be relative")
+	}
+	cleaned := path.Clean(trimmed)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("path must not escape the repo")
+	}
+	if _, err := path.Match(cleaned, ""); err != nil {
+		return "", fmt.Errorf("invalid glob: %w", err)
+	}
+	if strings.HasSuffix(trimmed, "/") {
+		cleaned += "/"
+	}
+	return cleaned, nil
+}
+
+// normalizePlanningArtifacts normalizes validated artifact paths, dropping any that are invalid.
+func normalizePlanningArtifacts(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		if cleaned, err := normalizePlanningArtifactPath(value); err == nil {
+			normalized = append(normalized, cleaned)
+		}
+	}
+	return normalized
+}
+
+// planningArtifactMatches reports whether a repo-relative path is covered by an artifact pattern.
+func planningArtifactMatches(pattern string, relPath string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(relPath, pattern)
+	}
+	matched, err := path.Match(pattern, relPath)
+	return err == nil && matched
+}
+
 // validatePlanningStepID enforces the requirements for planning step ids.
 func validatePlanningStepID(value string) error {
 	trimmed := strings.TrimSpace(value)
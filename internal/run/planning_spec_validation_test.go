@@ -50,3 +50,88 @@ func TestValidatePlanningValidationsAcceptsDirectoryPath(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestValidatePlanningArtifactsRejectsUnsafePaths(t *testing.T) {
+	for _, artifact := range []string{"", "/etc/passwd", "../outside.md", "docs\\adr", "docs/[.md"} {
+		if err := validatePlanningArtifacts("inputs", []string{artifact}); err == nil {
+			t.Fatalf("expected error for %q", artifact)
+		}
+	}
+	if err := validatePlanningArtifacts("inputs", []string{"GOVERNATOR.md", "_governator/docs/adr/", "_governator/docs/arch-*.md"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParsePlanningSpecMigratesSerialSpec(t *testing.T) {
+	spec, err := ParsePlanningSpec([]byte(`{"version": 2, "steps": [
+		{"id": "a", "name": "A", "prompt": "_governator/prompts/a.md", "role": "architect"},
+		{"id": "b", "name": "B", "prompt": "_governator/prompts/b.md", "role": "default"}
+	]}`))
+	if err != nil {
+		t.Fatalf("parse v2 spec: %v", err)
+	}
+	if spec.Version != planningSpecVersion {
+		t.Fatalf("version = %d, want %d", spec.Version, planningSpecVersion)
+	}
+	if len(spec.Steps[0].DependsOn) != 0 || len(spec.Steps[1].DependsOn) != 1 || spec.Steps[1].DependsOn[0] != "a" {
+		t.Fatalf("depends_on = %v / %v, want serial chain", spec.Steps[0].DependsOn, spec.Steps[1].DependsOn)
+	}
+	task, err := planningTaskFromSpec(spec)
+	if err != nil {
+		t.Fatalf("planning task: %v", err)
+	}
+	if task.concurrent() {
+		t.Fatalf("expected migrated spec to stay serial")
+	}
+}
+
+func TestParsePlanningSpecRejectsInvalidDependencies(t *testing.T) {
+	tests := map[string]string{
+		"v2 depends_on": `{"version": 2, "steps": [
+			{"id": "a", "name": "A", "prompt": "p.md", "role": "r"},
+			{"id": "b", "name": "B", "prompt": "p.md", "role": "r", "depends_on": ["a"]}]}`,
+		"self": `{"version": 3, "steps": [
+			{"id": "a", "name": "A", "prompt": "p.md", "role": "r", "depends_on": ["a"]}]}`,
+		"later step": `{"version": 3, "steps": [
+			{"id": "a", "name": "A", "prompt": "p.md", "role": "r", "depends_on": ["b"]},
+			{"id": "b", "name": "B", "prompt": "p.md", "role": "r"}]}`,
+		"duplicate": `{"version": 3, "steps": [
+			{"id": "a", "name": "A", "prompt": "p.md", "role": "r"},
+			{"id": "b", "name": "B", "prompt": "p.md", "role": "r", "depends_on": ["a", "a"]}]}`,
+	}
+	for name, payload := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParsePlanningSpec([]byte(payload)); err == nil {
+				t.Fatalf("expected dependency error")
+			}
+		})
+	}
+}
+
+func TestPlanningTaskFromSpecMarksConcurrentSteps(t *testing.T) {
+	spec, err := ParsePlanningSpec([]byte(`{"version": 3, "steps": [
+		{"id": "arch", "name": "Arch", "prompt": "p.md", "role": "r"},
+		{"id": "threat", "name": "Threat", "prompt": "p.md", "role": "r", "depends_on": ["arch"]},
+		{"id": "data", "name": "Data", "prompt": "p.md", "role": "r", "depends_on": ["arch"]},
+		{"id": "tasks", "name": "Tasks", "prompt": "p.md", "role": "r", "depends_on": ["threat", "data"]}
+	]}`))
+	if err != nil {
+		t.Fatalf("parse v3 spec: %v", err)
+	}
+	task, err := planningTaskFromSpec(spec)
+	if err != nil {
+		t.Fatalf("planning task: %v", err)
+	}
+	want := map[string]string{
+		"arch":   planningIndexTaskID,
+		"threat": "planning-threat",
+		"data":   "planning-data",
+		"tasks":  planningIndexTaskID,
+	}
+	for id, workstreamID := range want {
+		step, _ := task.stepForID(id)
+		if step.workstreamID() != workstreamID {
+			t.Fatalf("step %s workstream = %q, want %q", id, step.workstreamID(), workstreamID)
+		}
+	}
+}
@@ -24,6 +24,16 @@ func (task planningTask) stepForID(id string) (workstreamStep, bool) {
 	return step, ok
 }
 
+// concurrent reports whether any planning step may run alongside another.
+func (task planningTask) concurrent() bool {
+	for _, step := range task.ordered {
+		if step.concurrent {
+			return true
+		}
+	}
+	return false
+}
+
 // stepForPhase returns the planning step that corresponds to the given phase enum value.
 func (task planningTask) stepForPhase(p phase.Phase) (workstreamStep, bool) {
 	// Map phase values to planning step IDs.
@@ -0,0 +1,51 @@
+// Package run provides prompt previews for inspecting worker prompt stacks.
+package run
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// RenderTaskPrompt assembles the prompt a worker would receive for task at stage.
+// The role is resolved the way dispatch resolves it unless roleOverride is set, and
+// generated context (dependency summaries for work, conflict context for resolve) is
+// included, and budgeted, without writing it.
+func RenderTaskPrompt(repoRoot string, tasks []index.Task, task index.Task, stage roles.Stage, roleOverride index.Role, cfg config.Config, warn func(string)) (worker.RenderedPrompt, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return worker.RenderedPrompt{}, errors.New("repo root is required")
+	}
+	if !stage.Valid() {
+		return worker.RenderedPrompt{}, fmt.Errorf("unsupported stage %q", stage)
+	}
+
+	role := task.Role
+	if stage == roles.StageResolve {
+		role = SelectRoleForConflictResolution(task).Role
+	}
+	if strings.TrimSpace(string(roleOverride)) != "" {
+		role = roleOverride
+	}
+
+	stageInput := newTaskStageInput(repoRoot, repoRoot, tasks, task, stage, role, maxInt(task.Attempts.Total, 1), cfg, warn)
+	if stage == roles.StageResolve {
+		stageDir := path.Join(localStateDirName, workerStateDirName(maxInt(task.Attempts.Total, 1), stage, role))
+		stageInput.TaskPromptPath = conflictResolutionPromptPath
+		stageInput.ContextPrompts = append(stageInput.ContextPrompts, worker.PromptSection{
+			Path:    path.Join(stageDir, conflictContextFileName),
+			Kind:    config.PromptSectionContext,
+			Content: conflictContextContent(task),
+		})
+	}
+	rendered, err := worker.RenderPrompt(stageInput)
+	if err != nil {
+		return worker.RenderedPrompt{}, err
+	}
+	return rendered, nil
+}
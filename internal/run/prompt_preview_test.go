@@ -0,0 +1,122 @@
+// Tests for rendering task prompt previews.
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// writePreviewFile writes a prompt fixture relative to the repo root.
+func writePreviewFile(t *testing.T, repoRoot string, relPath string, content string) {
+	t.Helper()
+	path := filepath.Join(repoRoot, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", relPath, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", relPath, err)
+	}
+}
+
+// TestRenderTaskPromptResolvesRoles follows dispatch role selection and honors overrides.
+func TestRenderTaskPromptResolvesRoles(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+	writePreviewFile(t, repoRoot, "_governator/worker-contract.md", "contract")
+	writePreviewFile(t, repoRoot, "_governator/roles/engineer.md", "engineer")
+	writePreviewFile(t, repoRoot, "_governator/roles/reviewer.md", "reviewer")
+	writePreviewFile(t, repoRoot, "_governator/reasoning/high.md", "think hard")
+	writePreviewFile(t, repoRoot, "_governator/tasks/010-task.md", "task body")
+	writePreviewFile(t, repoRoot, conflictResolutionPromptPath, "resolve conflicts")
+
+	task := index.Task{ID: "010-task", Title: "Task", Path: "_governator/tasks/010-task.md", Role: "engineer"}
+	cfg := config.Config{ReasoningEffort: config.ReasoningEffortConfig{Default: "high"}}
+
+	rendered, err := RenderTaskPrompt(repoRoot, []index.Task{task}, task, roles.StageWork, "", cfg, nil)
+	if err != nil {
+		t.Fatalf("RenderTaskPrompt work: %v", err)
+	}
+	gotPaths := sectionPaths(rendered.Sections)
+	wantPaths := "_governator/reasoning/high.md,_governator/worker-contract.md,_governator/roles/engineer.md,_governator/tasks/010-task.md"
+	if gotPaths != wantPaths {
+		t.Fatalf("work sections = %s, want %s", gotPaths, wantPaths)
+	}
+
+	rendered, err = RenderTaskPrompt(repoRoot, []index.Task{task}, task, roles.StageReview, "reviewer", cfg, nil)
+	if err != nil {
+		t.Fatalf("RenderTaskPrompt review: %v", err)
+	}
+	if rendered.Role != "reviewer" || rendered.Sections[2].Path != "_governator/roles/reviewer.md" {
+		t.Fatalf("review override = %s (%s)", rendered.Role, sectionPaths(rendered.Sections))
+	}
+
+	rendered, err = RenderTaskPrompt(repoRoot, []index.Task{task}, task, roles.StageResolve, "", cfg, nil)
+	if err != nil {
+		t.Fatalf("RenderTaskPrompt resolve: %v", err)
+	}
+	last := rendered.Sections[len(rendered.Sections)-1]
+	if !strings.HasSuffix(last.Path, conflictContextFileName) || !strings.Contains(last.Content, TaskBranchName(task)) {
+		t.Fatalf("resolve context section = %#v", last)
+	}
+	if rendered.Sections[len(rendered.Sections)-2].Path != conflictResolutionPromptPath {
+		t.Fatalf("resolve sections = %s", sectionPaths(rendered.Sections))
+	}
+	if _, err := os.Stat(filepath.Join(repoRoot, "_governator", "_local-state")); !os.IsNotExist(err) {
+		t.Fatalf("prompt preview should not write local state, stat err = %v", err)
+	}
+}
+
+// TestRenderTaskPromptBudgetsDependencyContext applies the prompt budget to the dependency
+// summary the way staging does, instead of appending it after the budget.
+func TestRenderTaskPromptBudgetsDependencyContext(t *testing.T) {
+	t.Parallel()
+	repo := testrepos.New(t)
+	writePreviewFile(t, repo.Root, "_governator/worker-contract.md", "contract")
+	writePreviewFile(t, repo.Root, "_governator/roles/engineer.md", "engineer")
+	writePreviewFile(t, repo.Root, "_governator/tasks/030-api.md", "task body")
+	storage := index.Task{ID: "010-storage", Title: "Add storage layer", State: index.TaskStateMerged}
+	commitMergedDependency(t, repo, storage, "storage.go", "package storage\n")
+	task := index.Task{ID: "030-api", Title: "API", Path: "_governator/tasks/030-api.md", Role: "engineer", Dependencies: []string{"010-storage"}}
+	tasks := []index.Task{storage, task}
+
+	rendered, err := RenderTaskPrompt(repo.Root, tasks, task, roles.StageWork, "", config.Config{}, nil)
+	if err != nil {
+		t.Fatalf("RenderTaskPrompt: %v", err)
+	}
+	last := rendered.Sections[len(rendered.Sections)-1]
+	if !strings.HasSuffix(last.Path, dependencyContextFileName) || !strings.Contains(last.Content, "## 010-storage: Add storage layer") {
+		t.Fatalf("work context section = %#v", last)
+	}
+
+	cfg := config.Config{PromptBudget: config.PromptBudgetConfig{
+		Default:   config.PromptBudget{MaxTokens: 10, OnExceed: config.PromptBudgetDrop},
+		DropOrder: []string{config.PromptSectionContext},
+	}}
+	rendered, err = RenderTaskPrompt(repo.Root, tasks, task, roles.StageWork, "", cfg, nil)
+	if err != nil {
+		t.Fatalf("RenderTaskPrompt with budget: %v", err)
+	}
+	if len(rendered.Dropped) != 1 || !strings.HasSuffix(rendered.Dropped[0].Path, dependencyContextFileName) {
+		t.Fatalf("dropped = %s, want the dependency context", sectionPaths(rendered.Dropped))
+	}
+	if strings.Contains(sectionPaths(rendered.Sections), dependencyContextFileName) {
+		t.Fatalf("sections = %s, want dependency context dropped", sectionPaths(rendered.Sections))
+	}
+}
+
+// sectionPaths joins section paths for compact assertions.
+func sectionPaths(sections []worker.PromptSection) string {
+	paths := make([]string, 0, len(sections))
+	for _, section := range sections {
+		paths = append(paths, section.Path)
+	}
+	return strings.Join(paths, ",")
+}
@@ -0,0 +1,145 @@
+// Package run provides rate-limit cooldown handling for worker dispatch.
+package run
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/cooldown"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// rateLimitNow supplies the clock for cooldown decisions; tests may override it.
+var rateLimitNow = func() time.Time {
+	return time.Now().UTC()
+}
+
+// cooldownKeyForRole returns the cooldown key for the CLI that serves the role.
+func cooldownKeyForRole(cfg config.Config, role index.Role) string {
+	if cli := worker.CLIForRole(cfg, role); cli != "" {
+		return cli
+	}
+	return config.RateLimitPatternKeyDefault
+}
+
+// deferRateLimitedTask checks a failed worker's logs for rate-limit signatures.
+// On a match it starts a cooldown for the task's CLI, clears the dispatch metadata, counts a
+// deferral without a failed attempt, and leaves the task in its current state. Once the task
+// has used rate_limits.max_deferrals it reports false so the caller handles it as a failure.
+func deferRateLimitedTask(repoRoot string, idx *index.Index, cfg config.Config, task index.Task, stage roles.Stage, workerStateDir string, exitCode int, workerAuditor *audit.Logger, opts Options) bool {
+	warn := func(message string) {
+		fmt.Fprintf(opts.Stderr, "Warning: %s\n", message)
+	}
+	cli := cooldownKeyForRole(cfg, task.Role)
+	match, found, err := worker.DetectRateLimit(workerStateDir, cfg.RateLimits.PatternsForCLI(cli))
+	if err != nil {
+		warn(fmt.Sprintf("failed to scan worker logs for task %s: %v", task.ID, err))
+		return false
+	}
+	if !found {
+		return false
+	}
+	// Deferrals have their own budget so a provider outage does not spend the task's retries.
+	if task.Attempts.RateLimited >= cfg.RateLimits.MaxDeferrals {
+		return false
+	}
+	if err := index.IncrementTaskRateLimitedAttempt(idx, task.ID); err != nil {
+		warn(fmt.Sprintf("failed to count rate-limit deferral for %s: %v", task.ID, err))
+	}
+
+	now := rateLimitNow()
+	until := now.Add(time.Duration(cfg.RateLimits.CooldownSeconds) * time.Second)
+	reason := fmt.Sprintf("rate limited: %s", match.Line)
+	if err := startCLICooldown(repoRoot, cooldown.Entry{
+		CLI:       cli,
+		StartedAt: now,
+		Until:     until,
+		TaskID:    task.ID,
+		Reason:    match.Line,
+	}); err != nil {
+		warn(fmt.Sprintf("failed to record cooldown for %s: %v", cli, err))
+	}
+	if workerAuditor != nil {
+		if err := workerAuditor.LogCLICooldown(task.ID, string(task.Role), cli, until, match.Line); err != nil {
+			warn(fmt.Sprintf("failed to log cooldown for %s: %v", task.ID, err))
+		}
+	}
+
+	result := worker.IngestResult{
+		Success:     false,
+		NewState:    task.State,
+		BlockReason: reason,
+		RateLimited: true,
+	}
+	logAgentOutcome(workerAuditor, task.ID, task.Role, stage, statusFromIngestResult(result), exitCode, warn)
+	if err := updateIndexTask(idx, task.ID, func(task *index.Task) {
+		task.PID = 0
+	}); err != nil {
+		warn(fmt.Sprintf("failed to clear dispatch metadata for %s: %v", task.ID, err))
+	}
+	emitTaskRateLimited(opts.Stdout, task.ID, string(task.Role), string(stage), reason, cli, until)
+	return true
+}
+
+// startCLICooldown persists a cooldown window for a CLI.
+func startCLICooldown(repoRoot string, entry cooldown.Entry) error {
+	store, err := cooldown.NewStore(repoRoot)
+	if err != nil {
+		return err
+	}
+	set, err := store.Load()
+	if err != nil {
+		return err
+	}
+	set.Prune(entry.StartedAt)
+	if err := set.Start(entry); err != nil {
+		return err
+	}
+	return store.Save(set)
+}
+
+// loadActiveCooldowns returns the cooldown set when any CLI is cooling down.
+func loadActiveCooldowns(repoRoot string, now time.Time, warn func(string)) (cooldown.Set, bool) {
+	store, err := cooldown.NewStore(repoRoot)
+	if err != nil {
+		warn(fmt.Sprintf("failed to open cooldown store: %v", err))
+		return nil, false
+	}
+	set, err := store.Load()
+	if err != nil {
+		warn(fmt.Sprintf("failed to load cooldowns: %v", err))
+		return nil, false
+	}
+	if len(set.ActiveEntries(now)) == 0 {
+		return nil, false
+	}
+	return set, true
+}
+
+// cooldownExclusion returns a filter that rejects tasks whose CLI is cooling down, or nil when none are.
+func cooldownExclusion(repoRoot string, cfg config.Config, warn func(string)) func(index.Task) bool {
+	now := rateLimitNow()
+	set, ok := loadActiveCooldowns(repoRoot, now, warn)
+	if !ok {
+		return nil
+	}
+	return func(task index.Task) bool {
+		_, active := set.Active(cooldownKeyForRole(cfg, task.Role), now)
+		return active
+	}
+}
+
+// roleCooldown returns the active cooldown for the CLI that serves the role, if any. Planning
+// and triage check it before dispatching their single worker.
+func roleCooldown(repoRoot string, cfg config.Config, role index.Role, warn func(string)) (cooldown.Entry, bool) {
+	now := rateLimitNow()
+	set, ok := loadActiveCooldowns(repoRoot, now, warn)
+	if !ok {
+		return cooldown.Entry{}, false
+	}
+	return set.Active(cooldownKeyForRole(cfg, role), now)
+}
@@ -0,0 +1,219 @@
+// Tests for rate-limit cooldown handling.
+package run
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/cooldown"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/inflight"
+	"github.com/cmtonkinson/governator/internal/scheduler"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+)
+
+// TestExecuteWorkStageRateLimitStartsCooldown ensures rate-limited workers defer the task without
+// spending a failed attempt, even on the task's last retry.
+func TestExecuteWorkStageRateLimitStartsCooldown(t *testing.T) {
+	repo := testrepos.New(t)
+	repoRoot := repo.Root
+
+	workerStateDir := filepath.Join(repoRoot, "_governator", "_local-state", "worker-1-work-worker")
+	if err := os.MkdirAll(workerStateDir, 0o755); err != nil {
+		t.Fatalf("create worker state dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workerStateDir, "stderr.log"), []byte("ERROR: You've hit your usage limit. Try again later.\n"), 0o644); err != nil {
+		t.Fatalf("write stderr log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workerStateDir, "exit.json"), []byte(`{"exit_code":1,"finished_at":"2026-01-02T03:04:05Z","pid":123}`), 0o644); err != nil {
+		t.Fatalf("write exit status: %v", err)
+	}
+
+	cfg := config.Defaults()
+	cfg.RateLimits.CooldownSeconds = 600
+
+	idx := index.Index{
+		Tasks: []index.Task{
+			{
+				ID:       "T-001",
+				Path:     "_governator/tasks/T-001-work.md",
+				Kind:     index.TaskKindExecution,
+				State:    index.TaskStateTriaged,
+				Role:     "worker",
+				PID:      123,
+				Attempts: index.AttemptCounters{Total: 2, Failed: 1},
+			},
+		},
+	}
+	inFlight := inflight.Set{}
+	if err := inFlight.AddWithStartAndPath("T-001", time.Now().UTC(), repoRoot, workerStateDir, "work", "worker"); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+
+	fixedNow := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	previousNow := rateLimitNow
+	rateLimitNow = func() time.Time { return fixedNow }
+	t.Cleanup(func() { rateLimitNow = previousNow })
+
+	var stdout, stderr bytes.Buffer
+	opts := Options{Stdout: &stdout, Stderr: &stderr}
+	caps := scheduler.RoleCapsFromConfig(cfg)
+	result, err := ExecuteWorkStage(repoRoot, &idx, cfg, caps, inFlight, nil, nil, nil, nil, opts)
+	if err != nil {
+		t.Fatalf("execute work stage: %v", err)
+	}
+	if result.TasksRateLimited != 1 {
+		t.Fatalf("tasks rate limited = %d, want 1", result.TasksRateLimited)
+	}
+	if result.TasksDispatched != 0 {
+		t.Fatalf("tasks dispatched = %d, want 0 during cooldown", result.TasksDispatched)
+	}
+	task := idx.Tasks[0]
+	if task.State != index.TaskStateTriaged {
+		t.Fatalf("task state = %q, want %q", task.State, index.TaskStateTriaged)
+	}
+	if task.Attempts.Failed != 1 {
+		t.Fatalf("failed attempts = %d, want 1 left untouched", task.Attempts.Failed)
+	}
+	if task.Attempts.RateLimited != 1 {
+		t.Fatalf("rate-limited attempts = %d, want 1", task.Attempts.RateLimited)
+	}
+	if task.PID != 0 {
+		t.Fatalf("task pid = %d, want 0", task.PID)
+	}
+	if inFlight.Contains("T-001") {
+		t.Fatal("expected task to be removed from in-flight")
+	}
+	if !strings.Contains(stdout.String(), "status=rate_limited") {
+		t.Fatalf("expected rate_limited event, got %q", stdout.String())
+	}
+
+	store, err := cooldown.NewStore(repoRoot)
+	if err != nil {
+		t.Fatalf("new cooldown store: %v", err)
+	}
+	set, err := store.Load()
+	if err != nil {
+		t.Fatalf("load cooldowns: %v", err)
+	}
+	entry, ok := set.Active(config.CLICodex, fixedNow)
+	if !ok {
+		t.Fatalf("expected codex cooldown, got %+v", set)
+	}
+	if want := fixedNow.Add(600 * time.Second); !entry.Until.Equal(want) {
+		t.Fatalf("cooldown until = %v, want %v", entry.Until, want)
+	}
+}
+
+// TestExecuteWorkStageRateLimitFailsAfterMaxDeferrals ensures a task that has used its
+// rate-limit deferrals is handled as a failure instead of being deferred again.
+func TestExecuteWorkStageRateLimitFailsAfterMaxDeferrals(t *testing.T) {
+	repo := testrepos.New(t)
+	repoRoot := repo.Root
+
+	workerStateDir := filepath.Join(repoRoot, "_governator", "_local-state", "worker-3-work-worker")
+	if err := os.MkdirAll(workerStateDir, 0o755); err != nil {
+		t.Fatalf("create worker state dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workerStateDir, "stderr.log"), []byte("ERROR: You've hit your usage limit. Try again later.\n"), 0o644); err != nil {
+		t.Fatalf("write stderr log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workerStateDir, "exit.json"), []byte(`{"exit_code":1,"finished_at":"2026-01-02T03:04:05Z","pid":123}`), 0o644); err != nil {
+		t.Fatalf("write exit status: %v", err)
+	}
+
+	cfg := config.Defaults()
+	cfg.Retries.MaxAttempts = 1
+	cfg.RateLimits.MaxDeferrals = 2
+	idx := index.Index{
+		Tasks: []index.Task{
+			{
+				ID:       "T-001",
+				Path:     "_governator/tasks/T-001-work.md",
+				Kind:     index.TaskKindExecution,
+				State:    index.TaskStateTriaged,
+				Role:     "worker",
+				PID:      123,
+				Attempts: index.AttemptCounters{Total: 3, RateLimited: 2},
+			},
+		},
+	}
+	inFlight := inflight.Set{}
+	if err := inFlight.AddWithStartAndPath("T-001", time.Now().UTC(), repoRoot, workerStateDir, "work", "worker"); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	result, err := ExecuteWorkStage(repoRoot, &idx, cfg, scheduler.RoleCapsFromConfig(cfg), inFlight, nil, nil, nil, nil, Options{Stdout: &stdout, Stderr: &stderr, DisableDispatch: true})
+	if err != nil {
+		t.Fatalf("execute work stage: %v", err)
+	}
+	if result.TasksRateLimited != 0 {
+		t.Fatalf("tasks rate limited = %d, want 0 once deferrals are spent", result.TasksRateLimited)
+	}
+	if task := idx.Tasks[0]; task.State != index.TaskStateBlocked {
+		t.Fatalf("task state = %q, want %q", task.State, index.TaskStateBlocked)
+	}
+}
+
+// TestRoleCooldownReportsCoolingCLI ensures planning and triage see the cooldown for their role's CLI.
+func TestRoleCooldownReportsCoolingCLI(t *testing.T) {
+	repoRoot := t.TempDir()
+	fixedNow := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	previousNow := rateLimitNow
+	rateLimitNow = func() time.Time { return fixedNow }
+	t.Cleanup(func() { rateLimitNow = previousNow })
+
+	cfg := config.Defaults()
+	cfg.Workers.CLI.Roles = map[string]string{"reviewer": config.CLIClaude}
+	warn := func(message string) { t.Fatalf("unexpected warning: %s", message) }
+
+	if _, cooling := roleCooldown(repoRoot, cfg, "architect", warn); cooling {
+		t.Fatal("expected no cooldown before one starts")
+	}
+	if err := startCLICooldown(repoRoot, cooldown.Entry{CLI: config.CLICodex, StartedAt: fixedNow, Until: fixedNow.Add(time.Minute)}); err != nil {
+		t.Fatalf("start cooldown: %v", err)
+	}
+	if entry, cooling := roleCooldown(repoRoot, cfg, "architect", warn); !cooling || entry.CLI != config.CLICodex {
+		t.Fatalf("cooldown = %+v, %v; want codex cooling down", entry, cooling)
+	}
+	if _, cooling := roleCooldown(repoRoot, cfg, "reviewer", warn); cooling {
+		t.Fatal("expected claude-backed role to remain eligible")
+	}
+}
+
+// TestCooldownExclusionSkipsCoolingCLI ensures only tasks served by a cooling CLI are excluded.
+func TestCooldownExclusionSkipsCoolingCLI(t *testing.T) {
+	repoRoot := t.TempDir()
+	fixedNow := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	previousNow := rateLimitNow
+	rateLimitNow = func() time.Time { return fixedNow }
+	t.Cleanup(func() { rateLimitNow = previousNow })
+
+	cfg := config.Defaults()
+	cfg.Workers.CLI.Roles = map[string]string{"reviewer": config.CLIClaude}
+
+	warn := func(message string) { t.Fatalf("unexpected warning: %s", message) }
+	if exclude := cooldownExclusion(repoRoot, cfg, warn); exclude != nil {
+		t.Fatal("expected no exclusion without cooldowns")
+	}
+
+	if err := startCLICooldown(repoRoot, cooldown.Entry{CLI: config.CLICodex, StartedAt: fixedNow, Until: fixedNow.Add(time.Minute)}); err != nil {
+		t.Fatalf("start cooldown: %v", err)
+	}
+	exclude := cooldownExclusion(repoRoot, cfg, warn)
+	if exclude == nil {
+		t.Fatal("expected exclusion while codex cools down")
+	}
+	if !exclude(index.Task{ID: "T-1", Role: "worker"}) {
+		t.Fatal("expected codex-backed task to be excluded")
+	}
+	if exclude(index.Task{ID: "T-2", Role: "reviewer"}) {
+		t.Fatal("expected claude-backed task to remain eligible")
+	}
+}
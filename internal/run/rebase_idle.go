@@ -0,0 +1,226 @@
+// Package run provides proactive rebasing of idle task worktrees.
+package run
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/inflight"
+	"github.com/cmtonkinson/governator/internal/worktree"
+)
+
+// baseHeadFileName records the base branch commit idle worktrees were last rebased onto.
+const baseHeadFileName = "base-head"
+
+// RebaseIdleResult summarizes a proactive rebase pass.
+type RebaseIdleResult struct {
+	TasksRebased    int
+	TasksRetest     int
+	TasksConflicted int
+}
+
+// rebaseIdleWorktrees rebases the worktrees of idle implemented, tested, reviewed, and
+// mergeable tasks onto the base branch when it has advanced since the last pass, whether by a
+// merge or an operator commit. A rebase that conflicts is aborted and the task is flagged with
+// a merge conflict so the problem shows up before merge time. A task past implemented whose
+// own patch changed goes back to implemented so it is tested and reviewed again against the
+// new base. The new base head is only recorded once every eligible task is rebased or flagged,
+// so tasks that were busy or dirty are retried on the next pass.
+func rebaseIdleWorktrees(repoRoot string, idx *index.Index, cfg config.Config, inFlight inflight.Set, worktreeOverrides map[string]string, transitionAuditor index.TransitionAuditor, workerAuditor *audit.Logger, opts Options) (RebaseIdleResult, error) {
+	result := RebaseIdleResult{}
+	baseBranch := baseBranchName(cfg)
+	head, err := runGitOutput(repoRoot, "rev-parse", "--verify", baseBranch+"^{commit}")
+	if err != nil {
+		return result, fmt.Errorf("resolve base branch %s: %w", baseBranch, err)
+	}
+	head = strings.TrimSpace(head)
+	recorded, err := readBaseHead(repoRoot)
+	if err != nil {
+		return result, err
+	}
+	if recorded == head {
+		return result, nil
+	}
+
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
+	if err != nil {
+		return result, fmt.Errorf("create worktree manager: %w", err)
+	}
+	settled := true
+	for _, task := range idx.Tasks {
+		if !rebaseIdleEligible(task.State) {
+			continue
+		}
+		if inFlight.Contains(task.ID) {
+			settled = false
+			continue
+		}
+		worktreePath, err := resolveWorktreePath(manager, task, worktreeOverrides)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(worktreePath); err != nil {
+			continue
+		}
+		if runGitInWorktree(worktreePath, "merge-base", "--is-ancestor", head, "HEAD") == nil {
+			continue
+		}
+		if err := ensureCleanWorktree(worktreePath); err != nil {
+			fmt.Fprintf(opts.Stderr, "Warning: skipping rebase of %s: %v\n", task.ID, err)
+			settled = false
+			continue
+		}
+		before, err := getWorktreeCommit(worktreePath)
+		if err != nil {
+			fmt.Fprintf(opts.Stderr, "Warning: skipping rebase of %s: %v\n", task.ID, err)
+			settled = false
+			continue
+		}
+		forkPoint, err := runGitOutput(worktreePath, "merge-base", head, before)
+		if err != nil {
+			fmt.Fprintf(opts.Stderr, "Warning: skipping rebase of %s: %v\n", task.ID, err)
+			settled = false
+			continue
+		}
+		patchBefore, err := taskPatchID(worktreePath, strings.TrimSpace(forkPoint), before)
+		if err != nil {
+			return result, err
+		}
+
+		if rebaseErr := runGitInWorktree(worktreePath, "rebase", head); rebaseErr != nil {
+			_ = runGitInWorktree(worktreePath, "rebase", "--abort")
+			if !isRebaseConflict(rebaseErr) {
+				fmt.Fprintf(opts.Stderr, "Warning: failed to rebase %s onto %s: %v\n", task.ID, baseBranch, rebaseErr)
+				settled = false
+				continue
+			}
+			reason := fmt.Sprintf("rebase onto %s conflicts: %v", baseBranch, rebaseErr)
+			if err := updateIndexTask(idx, task.ID, func(task *index.Task) {
+				task.MergeConflict = true
+				task.BlockedReason = reason
+			}); err != nil {
+				return result, err
+			}
+			logIdleRebase(workerAuditor, task, "worktree.rebase.conflict", before, reason)
+			fmt.Fprintf(opts.Stderr, "Warning: %s %s\n", task.ID, reason)
+			result.TasksConflicted++
+			continue
+		}
+
+		after, err := getWorktreeCommit(worktreePath)
+		if err != nil {
+			return result, err
+		}
+		result.TasksRebased++
+		logIdleRebase(workerAuditor, task, "worktree.rebase", before, after)
+		if err := updateIndexTask(idx, task.ID, func(task *index.Task) {
+			if task.MergeConflict {
+				task.MergeConflict = false
+				task.BlockedReason = ""
+			}
+		}); err != nil {
+			return result, err
+		}
+		patchAfter, err := taskPatchID(worktreePath, head, after)
+		if err != nil {
+			return result, err
+		}
+		if patchAfter != patchBefore && task.State != index.TaskStateImplemented {
+			if err := applyTaskStateTransition(idx, task.ID, index.TaskStateImplemented, transitionAuditor); err != nil {
+				return result, err
+			}
+			result.TasksRetest++
+		}
+	}
+	if !settled {
+		return result, nil
+	}
+	return result, writeBaseHead(repoRoot, head)
+}
+
+// rebaseIdleEligible reports whether a task in state is rebased while idle.
+func rebaseIdleEligible(state index.TaskState) bool {
+	switch state {
+	case index.TaskStateImplemented, index.TaskStateTested, index.TaskStateReviewed, index.TaskStateMergeable:
+		return true
+	}
+	return false
+}
+
+// taskPatchID returns the stable patch id of the changes a task made on top of base, or ""
+// when it made none. Line offsets do not affect the id, so it only changes when a rebase
+// altered the task's own diff.
+func taskPatchID(worktreePath string, base string, tip string) (string, error) {
+	diff, err := runGitOutput(worktreePath, "diff", "--full-index", base, tip)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return "", nil
+	}
+	cmd := exec.Command("git", "patch-id", "--stable")
+	cmd.Dir = worktreePath
+	cmd.Stdin = strings.NewReader(diff)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git patch-id failed: %w", err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+// logIdleRebase records the outcome of a proactive rebase in the audit log.
+func logIdleRebase(auditor *audit.Logger, task index.Task, event string, from string, detail string) {
+	if auditor == nil {
+		return
+	}
+	key := "to"
+	if event != "worktree.rebase" {
+		key = "error"
+	}
+	_ = auditor.Log(audit.Entry{
+		TaskID: task.ID,
+		Role:   string(task.Role),
+		Event:  event,
+		Fields: []audit.Field{{Key: "from", Value: from}, {Key: key, Value: detail}},
+	})
+}
+
+// baseHeadPath returns the path of the recorded base branch commit.
+func baseHeadPath(repoRoot string) string {
+	return filepath.Join(repoRoot, localStateDirName, baseHeadFileName)
+}
+
+// readBaseHead returns the recorded base branch commit, or "" before the first pass.
+func readBaseHead(repoRoot string) (string, error) {
+	data, err := os.ReadFile(baseHeadPath(repoRoot))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read base head: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writeBaseHead records the base branch commit idle worktrees now sit on.
+func writeBaseHead(repoRoot string, head string) error {
+	path := baseHeadPath(repoRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create local state dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(head+"\n"), 0o644); err != nil {
+		return fmt.Errorf("write base head: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Tests for proactive rebasing of idle task worktrees.
+package run
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/inflight"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+)
+
+// TestRebaseIdleWorktreesKeepsUnchangedPatches rebases idle tasks onto the new base, keeps
+// tasks whose own patch is unchanged in their state, and retries in-flight tasks later.
+func TestRebaseIdleWorktreesKeepsUnchangedPatches(t *testing.T) {
+	repo := testrepos.New(t)
+	tested := addQueueTask(t, repo, "T-RB-001", "one.txt")
+	tested.Task.State = index.TaskStateTested
+	implemented := addQueueTask(t, repo, "T-RB-002", "two.txt")
+	implemented.Task.State = index.TaskStateImplemented
+	busy := addQueueTask(t, repo, "T-RB-003", "three.txt")
+	busy.Task.State = index.TaskStateTested
+	commitOnMain(t, repo, "operator.txt", "operator change\n")
+
+	idx := &index.Index{Tasks: []index.Task{tested.Task, implemented.Task, busy.Task}}
+	overrides := map[string]string{
+		tested.Task.ID:      tested.WorktreePath,
+		implemented.Task.ID: implemented.WorktreePath,
+		busy.Task.ID:        busy.WorktreePath,
+	}
+	inFlight := inflight.Set{busy.Task.ID: inflight.Entry{ID: busy.Task.ID}}
+	result, err := rebaseIdleWorktrees(repo.Root, idx, config.Defaults(), inFlight, overrides, &mockTransitionAuditor{}, nil, Options{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("rebaseIdleWorktrees: %v", err)
+	}
+	if result.TasksRebased != 2 || result.TasksRetest != 0 || result.TasksConflicted != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	for _, entry := range []MergeQueueEntry{tested, implemented} {
+		if _, err := os.Stat(filepath.Join(entry.WorktreePath, "operator.txt")); err != nil {
+			t.Fatalf("%s not rebased onto main: %v", entry.Task.ID, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(busy.WorktreePath, "operator.txt")); !os.IsNotExist(err) {
+		t.Fatalf("in-flight worktree was rebased: %v", err)
+	}
+	states := map[string]index.TaskState{}
+	for _, task := range idx.Tasks {
+		states[task.ID] = task.State
+	}
+	if states["T-RB-001"] != index.TaskStateTested || states["T-RB-002"] != index.TaskStateImplemented || states["T-RB-003"] != index.TaskStateTested {
+		t.Fatalf("states = %v", states)
+	}
+
+	// The in-flight task is rebased once it is idle.
+	result, err = rebaseIdleWorktrees(repo.Root, idx, config.Defaults(), nil, overrides, &mockTransitionAuditor{}, nil, Options{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("second rebaseIdleWorktrees: %v", err)
+	}
+	if result.TasksRebased != 1 {
+		t.Fatalf("second pass result = %+v, want the idle task rebased", result)
+	}
+
+	// Nothing happens again until the base branch moves.
+	result, err = rebaseIdleWorktrees(repo.Root, idx, config.Defaults(), nil, overrides, &mockTransitionAuditor{}, nil, Options{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("third rebaseIdleWorktrees: %v", err)
+	}
+	if result != (RebaseIdleResult{}) {
+		t.Fatalf("third pass result = %+v, want no work", result)
+	}
+}
+
+// TestRebaseIdleWorktreesRetestsChangedPatches sends a mergeable task back to implemented
+// when the rebase changed its own patch, and leaves a reviewed task with an unrelated patch.
+func TestRebaseIdleWorktreesRetestsChangedPatches(t *testing.T) {
+	repo := testrepos.New(t)
+	lines := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n"
+	commitOnMain(t, repo, "lines.txt", lines)
+	changed := addQueueTask(t, repo, "T-RB-201", "own.txt")
+	if err := os.WriteFile(filepath.Join(changed.WorktreePath, "lines.txt"), []byte(strings.Replace(lines, "two", "TWO", 1)), 0o644); err != nil {
+		t.Fatalf("write task edit: %v", err)
+	}
+	repo.RunGitInDir(t, changed.WorktreePath, "commit", "-am", "Edit line two")
+	unrelated := addQueueTask(t, repo, "T-RB-202", "other.txt")
+	unrelated.Task.State = index.TaskStateReviewed
+	commitOnMain(t, repo, "lines.txt", strings.Replace(lines, "five", "FIVE", 1))
+
+	idx := &index.Index{Tasks: []index.Task{changed.Task, unrelated.Task}}
+	overrides := map[string]string{changed.Task.ID: changed.WorktreePath, unrelated.Task.ID: unrelated.WorktreePath}
+	result, err := rebaseIdleWorktrees(repo.Root, idx, config.Defaults(), nil, overrides, &mockTransitionAuditor{}, nil, Options{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("rebaseIdleWorktrees: %v", err)
+	}
+	if result.TasksRebased != 2 || result.TasksRetest != 1 || result.TasksConflicted != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	if idx.Tasks[0].State != index.TaskStateImplemented || idx.Tasks[1].State != index.TaskStateReviewed {
+		t.Fatalf("states = %q, %q", idx.Tasks[0].State, idx.Tasks[1].State)
+	}
+}
+
+// TestRebaseIdleWorktreesFlagsConflicts aborts a conflicting rebase and flags the task.
+func TestRebaseIdleWorktreesFlagsConflicts(t *testing.T) {
+	repo := testrepos.New(t)
+	entry := addQueueTask(t, repo, "T-RB-101", "shared.txt")
+	entry.Task.State = index.TaskStateTested
+	before := strings.TrimSpace(repo.RunGitInDir(t, entry.WorktreePath, "rev-parse", "HEAD"))
+	commitOnMain(t, repo, "shared.txt", "operator version\n")
+
+	idx := &index.Index{Tasks: []index.Task{entry.Task}}
+	result, err := rebaseIdleWorktrees(repo.Root, idx, config.Defaults(), nil, map[string]string{entry.Task.ID: entry.WorktreePath}, &mockTransitionAuditor{}, nil, Options{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("rebaseIdleWorktrees: %v", err)
+	}
+	if result.TasksConflicted != 1 {
+		t.Fatalf("result = %+v, want one conflict", result)
+	}
+	task := idx.Tasks[0]
+	if !task.MergeConflict || !strings.Contains(task.BlockedReason, "rebase onto main conflicts") {
+		t.Fatalf("task = %+v, want merge conflict flag", task)
+	}
+	if task.State != index.TaskStateTested {
+		t.Fatalf("state = %q, want tested", task.State)
+	}
+	if after := strings.TrimSpace(repo.RunGitInDir(t, entry.WorktreePath, "rev-parse", "HEAD")); after != before {
+		t.Fatalf("worktree moved from %s to %s after aborted rebase", before, after)
+	}
+	if err := ensureCleanWorktree(entry.WorktreePath); err != nil {
+		t.Fatalf("worktree left dirty: %v", err)
+	}
+}
+
+// commitOnMain commits a file on main in the repo root, as an operator would.
+func commitOnMain(t *testing.T, repo *testrepos.TempRepo, name string, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo.Root, name), []byte(contents), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	repo.RunGit(t, "add", name)
+	repo.RunGit(t, "commit", "-m", "Operator commit "+name)
+}
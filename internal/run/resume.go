@@ -30,7 +30,7 @@ func DetectResumeCandidates(repoRoot string, idx index.Index, cfg config.Config)
 		return nil, fmt.Errorf("repo root is required")
 	}
 
-	manager, err := worktree.NewManager(repoRoot)
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
 	if err != nil {
 		return nil, fmt.Errorf("create worktree manager: %w", err)
 	}
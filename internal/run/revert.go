@@ -0,0 +1,306 @@
+// Package run provides the revert flow for undoing merged tasks.
+package run
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+)
+
+// RevertOptions configures a task revert.
+type RevertOptions struct {
+	// Requeue moves the reverted task back to triaged so it is implemented again.
+	Requeue bool
+	Stderr  io.Writer
+}
+
+// RevertOutcome describes the revert commit and the index changes made for a task.
+type RevertOutcome struct {
+	TaskID       string
+	MergeCommit  string
+	BaseCommit   string
+	RevertCommit string
+	Requeued     bool
+	// MergedDependents lists merged tasks that depend on the reverted task, directly or not.
+	MergedDependents []string
+}
+
+// RevertTask reverts a merged task on the base branch and moves it to reverted, or on to
+// triaged when requeued. The supervisor must not be running.
+func RevertTask(repoRoot string, taskID string, opts RevertOptions) (RevertOutcome, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return RevertOutcome{}, errors.New("repo root is required")
+	}
+	if strings.TrimSpace(taskID) == "" {
+		return RevertOutcome{}, errors.New("task id is required")
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+
+	cfg, err := config.Load(repoRoot, nil, nil)
+	if err != nil {
+		return RevertOutcome{}, fmt.Errorf("load config: %w", err)
+	}
+	auditor, err := audit.NewLogger(repoRoot, opts.Stderr)
+	if err != nil {
+		return RevertOutcome{}, fmt.Errorf("create audit logger: %w", err)
+	}
+
+	indexPath := filepath.Join(repoRoot, indexFilePath)
+	lock, err := index.AcquireWriteLock(indexPath)
+	if err != nil {
+		return RevertOutcome{}, err
+	}
+	defer func() {
+		_ = lock.Release()
+	}()
+	idx, err := index.Load(indexPath)
+	if err != nil {
+		return RevertOutcome{}, fmt.Errorf("load task index: %w", err)
+	}
+
+	var task index.Task
+	found := false
+	for _, candidate := range idx.Tasks {
+		if candidate.ID == taskID {
+			task = candidate
+			found = true
+			break
+		}
+	}
+	if !found {
+		return RevertOutcome{}, fmt.Errorf("task %q not found", taskID)
+	}
+	if task.State != index.TaskStateMerged {
+		return RevertOutcome{}, fmt.Errorf("task %s is %s; only merged tasks can be reverted", task.ID, task.State)
+	}
+
+	result, err := revertMergedTask(revertInput{
+		RepoRoot:      repoRoot,
+		Task:          task,
+		MainBranch:    baseBranchName(cfg),
+		Remote:        cfg.Branches.Remote,
+		WorktreesRoot: cfg.Worktrees.Root,
+		Identity:      cfg.Git.Merge,
+		Signing:       cfg.Git.Signing,
+		Auditor:       auditor,
+	})
+	if err != nil {
+		return RevertOutcome{}, err
+	}
+	result.MergedDependents = mergedDependents(idx.Tasks, task.ID)
+
+	if err := applyTaskStateTransition(&idx, task.ID, index.TaskStateReverted, auditor); err != nil {
+		return result, err
+	}
+	if opts.Requeue {
+		if err := applyTaskStateTransition(&idx, task.ID, index.TaskStateTriaged, auditor); err != nil {
+			return result, err
+		}
+		result.Requeued = true
+	}
+	if err := updateIndexTask(&idx, task.ID, func(task *index.Task) {
+		task.BlockedReason = ""
+		task.MergeConflict = false
+		task.PID = 0
+		if opts.Requeue {
+			task.Attempts = index.AttemptCounters{}
+		}
+	}); err != nil {
+		return result, err
+	}
+	if err := index.SaveWithLock(indexPath, idx, lock); err != nil {
+		return result, fmt.Errorf("save task index: %w", err)
+	}
+	return result, nil
+}
+
+// revertInput defines the git inputs for reverting one merged task.
+type revertInput struct {
+	RepoRoot   string
+	Task       index.Task
+	MainBranch string
+	Remote     string
+	// WorktreesRoot is the configured worktrees.root that holds the revert worktree.
+	WorktreesRoot string
+	// Identity and Signing apply to the revert commit as they do to merge commits.
+	Identity config.GitIdentity
+	Signing  config.GitSigning
+	Auditor  *audit.Logger
+}
+
+// revertMergedTask adds a commit to the base branch that undoes everything the task's merge
+// added. It works in an isolated worktree and only moves local main once the commit exists
+// and, with a remote, has been pushed. A revert commit left by an earlier run that failed
+// after committing is reused rather than reverted twice, so the operation can be rerun.
+func revertMergedTask(input revertInput) (RevertOutcome, error) {
+	if err := ensureCleanWorktree(input.RepoRoot); err != nil {
+		return RevertOutcome{}, err
+	}
+	branch, err := runGitOutput(input.RepoRoot, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return RevertOutcome{}, err
+	}
+	if strings.TrimSpace(branch) != input.MainBranch {
+		return RevertOutcome{}, fmt.Errorf("repo root is on %s; check out %s before reverting", strings.TrimSpace(branch), input.MainBranch)
+	}
+
+	mergeCommit, baseCommit, err := locateTaskMerge(input.RepoRoot, input.MainBranch, input.Task)
+	if err != nil {
+		return RevertOutcome{}, err
+	}
+
+	remoteBase := ""
+	if input.Remote != "" {
+		found, err := fetchRemoteBase(input.RepoRoot, input.Remote, input.MainBranch)
+		if err != nil {
+			return RevertOutcome{}, err
+		}
+		if found {
+			remoteBase = input.Remote + "/" + input.MainBranch
+		}
+	}
+
+	worktreePath, cleanup, err := createMergeWorktree(input.RepoRoot, input.WorktreesRoot, input.MainBranch, input.Task.ID)
+	if err != nil {
+		return RevertOutcome{}, err
+	}
+	defer func() {
+		_ = cleanup()
+	}()
+
+	if remoteBase != "" {
+		if err := runGitInWorktree(worktreePath, "rebase", remoteBase); err != nil {
+			_ = runGitInWorktree(worktreePath, "rebase", "--abort")
+			return RevertOutcome{}, fmt.Errorf("rebase local %s onto %s: %w", input.MainBranch, remoteBase, err)
+		}
+	}
+
+	body := revertCommitBody(baseCommit, mergeCommit)
+	revertCommit, err := runGitOutput(worktreePath, "log", "-1", "--format=%H", "--fixed-strings", "--grep", body, mergeCommit+"..HEAD")
+	if err != nil {
+		return RevertOutcome{}, fmt.Errorf("look for an earlier revert of %s: %w", input.Task.ID, err)
+	}
+	revertCommit = strings.TrimSpace(revertCommit)
+	if revertCommit == "" {
+		patch, err := runGitOutput(worktreePath, "diff", "--binary", "--full-index", mergeCommit, baseCommit)
+		if err != nil {
+			return RevertOutcome{}, err
+		}
+		if strings.TrimSpace(patch) == "" {
+			return RevertOutcome{}, fmt.Errorf("merge of %s (%.12s..%.12s) changed nothing to revert", input.Task.ID, baseCommit, mergeCommit)
+		}
+		if err := applyPatch(worktreePath, patch); err != nil {
+			return RevertOutcome{}, fmt.Errorf("revert of %s conflicts with later changes on %s; revert it manually: %w", input.Task.ID, input.MainBranch, err)
+		}
+		subject := fmt.Sprintf("Revert %q", mergeCommitSubject(input.Task))
+		if err := runBaseBranchCommit(worktreePath, input.Identity, input.Signing, "commit", "-m", subject, "-m", body); err != nil {
+			return RevertOutcome{}, fmt.Errorf("commit revert: %w", err)
+		}
+		revertCommit, err = getWorktreeCommit(worktreePath)
+		if err != nil {
+			return RevertOutcome{}, fmt.Errorf("get revert commit: %w", err)
+		}
+	}
+	head, err := getWorktreeCommit(worktreePath)
+	if err != nil {
+		return RevertOutcome{}, fmt.Errorf("get revert head: %w", err)
+	}
+
+	if input.Remote != "" && (remoteBase == "" || runGitInWorktree(worktreePath, "merge-base", "--is-ancestor", head, remoteBase) != nil) {
+		if err := runGitInWorktree(worktreePath, "push", input.Remote, head+":refs/heads/"+input.MainBranch); err != nil {
+			if isPushRejected(err) {
+				return RevertOutcome{}, fmt.Errorf("push to %s/%s rejected because the remote moved; run the revert again: %w", input.Remote, input.MainBranch, err)
+			}
+			return RevertOutcome{}, fmt.Errorf("push %s to %s: %w", input.MainBranch, input.Remote, err)
+		}
+	}
+	if err := runGitInRepo(input.RepoRoot, "reset", "--hard", head); err != nil {
+		return RevertOutcome{}, fmt.Errorf("update main to revert commit: %w", err)
+	}
+	if input.Auditor != nil {
+		_ = input.Auditor.LogTaskRevert(input.Task.ID, string(input.Task.Role), mergeCommit, revertCommit)
+	}
+
+	return RevertOutcome{
+		TaskID:       input.Task.ID,
+		MergeCommit:  mergeCommit,
+		BaseCommit:   baseCommit,
+		RevertCommit: revertCommit,
+	}, nil
+}
+
+// revertCommitBody returns the revert commit body that names the reverted range, which is also
+// how an earlier revert of the same merge is recognized.
+func revertCommitBody(baseCommit string, mergeCommit string) string {
+	return fmt.Sprintf("This reverts the changes from %s..%s.", baseCommit, mergeCommit)
+}
+
+// locateTaskMerge returns the commit a task's merge produced and the base commit it landed on.
+func locateTaskMerge(repoRoot string, mainBranch string, task index.Task) (string, string, error) {
+	mergeCommit, baseCommit, err := findTaskMerge(repoRoot, mainBranch, task)
+	if err != nil {
+		return "", "", err
+	}
+	if mergeCommit == "" {
+		return "", "", fmt.Errorf("no merge commit found for %s in the audit log or on %s", task.ID, mainBranch)
+	}
+	if err := runGitInRepo(repoRoot, "merge-base", "--is-ancestor", mergeCommit, mainBranch); err != nil {
+		return "", "", fmt.Errorf("merge commit %.12s for %s is not on %s", mergeCommit, task.ID, mainBranch)
+	}
+	return mergeCommit, baseCommit, nil
+}
+
+// applyPatch applies a patch to the index and worktree, falling back to a three-way merge.
+func applyPatch(dir string, patch string) error {
+	cmd := exec.Command("git", "apply", "--3way", "--index")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(patch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git apply failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// mergedDependents returns merged tasks that depend on taskID directly or through other
+// merged tasks, sorted by ID.
+func mergedDependents(tasks []index.Task, taskID string) []string {
+	seen := map[string]struct{}{}
+	queue := []string{taskID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, task := range tasks {
+			if task.State != index.TaskStateMerged {
+				continue
+			}
+			if _, ok := seen[task.ID]; ok {
+				continue
+			}
+			for _, dependency := range task.Dependencies {
+				if dependency == current {
+					seen[task.ID] = struct{}{}
+					queue = append(queue, task.ID)
+					break
+				}
+			}
+		}
+	}
+	dependents := make([]string, 0, len(seen))
+	for id := range seen {
+		dependents = append(dependents, id)
+	}
+	sort.Strings(dependents)
+	return dependents
+}
@@ -0,0 +1,190 @@
+// Tests for reverting merged tasks.
+package run
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/audit"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+)
+
+// TestRevertTaskUndoesMergeForEachStrategy reverts squash, merge, and rebase merges found via the audit log.
+func TestRevertTaskUndoesMergeForEachStrategy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, strategy := range []string{config.MergeStrategySquash, config.MergeStrategyMerge, config.MergeStrategyRebase} {
+		t.Run(strategy, func(t *testing.T) {
+			repo, task, worktreePath := setupMergeTaskRepo(t, "T-REVERT-001")
+			mergeTaskForRevert(t, repo, task, worktreePath, strategy, true)
+			writeRevertIndex(t, repo, []index.Task{mergedTask(task)})
+
+			outcome, err := RevertTask(repo.Root, task.ID, RevertOptions{Stderr: io.Discard})
+			if err != nil {
+				t.Fatalf("RevertTask: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(repo.Root, "FEATURE.md")); !os.IsNotExist(err) {
+				t.Fatalf("FEATURE.md still present after revert: %v", err)
+			}
+			if head := strings.TrimSpace(repo.RunGit(t, "rev-parse", "main")); head != outcome.RevertCommit {
+				t.Fatalf("main = %s, want revert commit %s", head, outcome.RevertCommit)
+			}
+			subject := strings.TrimSpace(repo.RunGit(t, "log", "-1", "--format=%s", "main"))
+			if subject != `Revert "governator: T-REVERT-001 - Merge flow coverage"` {
+				t.Fatalf("revert subject = %q", subject)
+			}
+			if state := loadRevertIndexTask(t, repo, task.ID).State; state != index.TaskStateReverted {
+				t.Fatalf("state = %q, want reverted", state)
+			}
+			fields, ok, err := audit.LastTaskEvent(repo.Root, task.ID, audit.EventTaskRevert)
+			if err != nil || !ok || fields["revert_commit"] != outcome.RevertCommit {
+				t.Fatalf("task.revert audit entry = %v, %v, %v", fields, ok, err)
+			}
+		})
+	}
+}
+
+// TestRevertTaskRequeuesAndWarnsAboutDependents falls back to the merge commit message,
+// requeues the task, and reports merged dependents.
+func TestRevertTaskRequeuesAndWarnsAboutDependents(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo, task, worktreePath := setupMergeTaskRepo(t, "T-REVERT-002")
+	mergeTaskForRevert(t, repo, task, worktreePath, config.MergeStrategySquash, false)
+
+	merged := mergedTask(task)
+	merged.Attempts = index.AttemptCounters{Total: 3, Failed: 1}
+	writeRevertIndex(t, repo, []index.Task{
+		merged,
+		{ID: "T-DEP-A", Path: "tasks/a.md", Kind: index.TaskKindExecution, State: index.TaskStateMerged, Role: "default", Dependencies: []string{task.ID}},
+		{ID: "T-DEP-B", Path: "tasks/b.md", Kind: index.TaskKindExecution, State: index.TaskStateMerged, Role: "default", Dependencies: []string{"T-DEP-A"}},
+		{ID: "T-DEP-C", Path: "tasks/c.md", Kind: index.TaskKindExecution, State: index.TaskStateTriaged, Role: "default", Dependencies: []string{task.ID}},
+	})
+
+	outcome, err := RevertTask(repo.Root, task.ID, RevertOptions{Requeue: true, Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("RevertTask: %v", err)
+	}
+	if !outcome.Requeued {
+		t.Fatal("expected task to be requeued")
+	}
+	if got := strings.Join(outcome.MergedDependents, ","); got != "T-DEP-A,T-DEP-B" {
+		t.Fatalf("merged dependents = %q, want T-DEP-A,T-DEP-B", got)
+	}
+	requeued := loadRevertIndexTask(t, repo, task.ID)
+	if requeued.State != index.TaskStateTriaged {
+		t.Fatalf("state = %q, want triaged", requeued.State)
+	}
+	if requeued.Attempts != (index.AttemptCounters{}) {
+		t.Fatalf("attempts = %+v, want reset", requeued.Attempts)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Root, "FEATURE.md")); !os.IsNotExist(err) {
+		t.Fatalf("FEATURE.md still present after revert: %v", err)
+	}
+}
+
+// TestRevertTaskRerunReusesEarlierRevert finishes a revert whose index update was lost without
+// reverting the merge a second time.
+func TestRevertTaskRerunReusesEarlierRevert(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo, task, worktreePath := setupMergeTaskRepo(t, "T-REVERT-003")
+	mergeTaskForRevert(t, repo, task, worktreePath, config.MergeStrategySquash, true)
+	writeRevertIndex(t, repo, []index.Task{mergedTask(task)})
+
+	first, err := RevertTask(repo.Root, task.ID, RevertOptions{Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("RevertTask: %v", err)
+	}
+	// Simulate a run that committed the revert but never saved the index.
+	writeRevertIndex(t, repo, []index.Task{mergedTask(task)})
+
+	second, err := RevertTask(repo.Root, task.ID, RevertOptions{Stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("rerun RevertTask: %v", err)
+	}
+	if second.RevertCommit != first.RevertCommit {
+		t.Fatalf("rerun revert commit = %s, want earlier %s", second.RevertCommit, first.RevertCommit)
+	}
+	if head := strings.TrimSpace(repo.RunGit(t, "rev-parse", "main")); head != first.RevertCommit {
+		t.Fatalf("main = %s, want earlier revert commit %s", head, first.RevertCommit)
+	}
+	if state := loadRevertIndexTask(t, repo, task.ID).State; state != index.TaskStateReverted {
+		t.Fatalf("state = %q, want reverted", state)
+	}
+}
+
+// TestRevertTaskRejectsUnmergedTask leaves non-merged tasks alone.
+func TestRevertTaskRejectsUnmergedTask(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := testrepos.New(t)
+	writeRevertIndex(t, repo, []index.Task{
+		{ID: "T-OPEN", Path: "tasks/open.md", Kind: index.TaskKindExecution, State: index.TaskStateTriaged, Role: "default"},
+	})
+	_, err := RevertTask(repo.Root, "T-OPEN", RevertOptions{Stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "only merged tasks can be reverted") {
+		t.Fatalf("error = %v, want merged-only rejection", err)
+	}
+}
+
+// mergeTaskForRevert merges the task with the given strategy, optionally recording audit entries.
+func mergeTaskForRevert(t *testing.T, repo *testrepos.TempRepo, task index.Task, worktreePath string, strategy string, withAudit bool) {
+	t.Helper()
+	var auditor *audit.Logger
+	if withAudit {
+		var err error
+		auditor, err = audit.NewLogger(repo.Root, io.Discard)
+		if err != nil {
+			t.Fatalf("new audit logger: %v", err)
+		}
+	}
+	result, err := ExecuteReviewMergeFlow(MergeFlowInput{
+		RepoRoot:     repo.Root,
+		WorktreePath: worktreePath,
+		Task:         task,
+		MainBranch:   "main",
+		Strategy:     strategy,
+		Auditor:      auditor,
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("merge flow: %+v, %v", result, err)
+	}
+}
+
+// mergedTask returns the index entry for a task after it merged.
+func mergedTask(task index.Task) index.Task {
+	task.Path = "tasks/" + task.ID + ".md"
+	task.Kind = index.TaskKindExecution
+	task.State = index.TaskStateMerged
+	return task
+}
+
+// writeRevertIndex writes the task index for a revert test repo.
+func writeRevertIndex(t *testing.T, repo *testrepos.TempRepo, tasks []index.Task) {
+	t.Helper()
+	indexPath := filepath.Join(repo.Root, indexFilePath)
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0o755); err != nil {
+		t.Fatalf("mkdir index dir: %v", err)
+	}
+	if err := index.Save(indexPath, index.Index{SchemaVersion: 1, Tasks: tasks}); err != nil {
+		t.Fatalf("save index: %v", err)
+	}
+}
+
+// loadRevertIndexTask reads one task back from the revert test index.
+func loadRevertIndexTask(t *testing.T, repo *testrepos.TempRepo, taskID string) index.Task {
+	t.Helper()
+	idx, err := index.Load(filepath.Join(repo.Root, indexFilePath))
+	if err != nil {
+		t.Fatalf("load index: %v", err)
+	}
+	for _, task := range idx.Tasks {
+		if task.ID == taskID {
+			return task
+		}
+	}
+	t.Fatalf("task %s missing from index", taskID)
+	return index.Task{}
+}
@@ -11,19 +11,27 @@ import (
 	"time"
 
 	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/roles"
 	"github.com/cmtonkinson/governator/internal/worker"
 )
 
 const (
 	roleAssignmentStateDir   = "_governator/_local-state/role-assignment"
+	promptValidationStateDir = "_governator/_local-state/prompt-validation"
 	roleAssignmentPromptMode = 0o644
 )
 
-// workerCommandInvoker executes the configured worker command against the role assignment prompt.
+// errWorkerCommandTimeout marks an invocation killed because it outlived its timeout.
+var errWorkerCommandTimeout = errors.New("worker command timed out")
+
+// workerCommandInvoker executes the configured worker command against a one-shot prompt.
 type workerCommandInvoker struct {
 	cfg      config.Config
 	repoRoot string
+	role     index.Role
+	purpose  string // names the invocation in errors and warnings
+	stateDir string // repo-relative directory for prompt files
 	timeout  int
 	warn     func(string)
 }
@@ -40,16 +48,37 @@ func newWorkerCommandInvoker(cfg config.Config, repoRoot string, warn func(strin
 	return &workerCommandInvoker{
 		cfg:      cfg,
 		repoRoot: repoRoot,
+		purpose:  "role assignment",
+		stateDir: roleAssignmentStateDir,
+		timeout:  timeout,
+		warn:     warn,
+	}, nil
+}
+
+// newPromptValidationInvoker constructs an invoker that runs a prompt validation as the given role.
+func newPromptValidationInvoker(cfg config.Config, repoRoot string, role index.Role, timeout int, warn func(string)) (*workerCommandInvoker, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return nil, errors.New("repo root is required")
+	}
+	if timeout <= 0 {
+		timeout = config.Defaults().Timeouts.WorkerSeconds
+	}
+	return &workerCommandInvoker{
+		cfg:      cfg,
+		repoRoot: repoRoot,
+		role:     role,
+		purpose:  "prompt validation",
+		stateDir: promptValidationStateDir,
 		timeout:  timeout,
 		warn:     warn,
 	}, nil
 }
 
-// Invoke runs the worker command with the role assignment prompt.
+// Invoke runs the worker command with the prompt and returns its trimmed stdout.
 func (inv *workerCommandInvoker) Invoke(ctx context.Context, prompt string) (string, error) {
 	trimmed := strings.TrimSpace(prompt)
 	if trimmed == "" {
-		return "", errors.New("role assignment prompt is required")
+		return "", fmt.Errorf("%s prompt is required", inv.purpose)
 	}
 
 	promptPath, err := inv.writePrompt(trimmed)
@@ -62,9 +91,9 @@ func (inv *workerCommandInvoker) Invoke(ctx context.Context, prompt string) (str
 		relativePath = filepath.ToSlash(promptPath)
 	}
 
-	command, err := worker.ResolveCommand(inv.cfg, "", relativePath, inv.repoRoot, "")
+	command, err := worker.ResolveCommand(inv.cfg, inv.role, relativePath, inv.repoRoot, "")
 	if err != nil {
-		return "", fmt.Errorf("resolve role assignment command: %w", err)
+		return "", fmt.Errorf("resolve %s command: %w", inv.purpose, err)
 	}
 
 	execCtx := ctx
@@ -85,28 +114,31 @@ func (inv *workerCommandInvoker) Invoke(ctx context.Context, prompt string) (str
 	err = cmd.Run()
 	stderrText := strings.TrimSpace(stderr.String())
 	if stderrText != "" {
-		inv.warnf("role assignment command stderr: %s", stderrText)
+		inv.warnf("%s command stderr: %s", inv.purpose, stderrText)
+	}
+	if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("%s command: %w after %ds", inv.purpose, errWorkerCommandTimeout, inv.timeout)
 	}
 	if err != nil {
-		return "", fmt.Errorf("role assignment command failed: %w", err)
+		return "", fmt.Errorf("%s command failed: %w", inv.purpose, err)
 	}
 
 	output := strings.TrimSpace(stdout.String())
 	if output == "" {
-		return "", fmt.Errorf("role assignment command returned empty output")
+		return "", fmt.Errorf("%s command returned empty output", inv.purpose)
 	}
 	return output, nil
 }
 
 func (inv *workerCommandInvoker) writePrompt(content string) (string, error) {
-	dir := filepath.Join(inv.repoRoot, roleAssignmentStateDir)
+	dir := filepath.Join(inv.repoRoot, filepath.FromSlash(inv.stateDir))
 	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", fmt.Errorf("create role assignment state dir %s: %w", dir, err)
+		return "", fmt.Errorf("create %s state dir %s: %w", inv.purpose, dir, err)
 	}
 	filename := fmt.Sprintf("prompt-%d.md", time.Now().UnixNano())
 	path := filepath.Join(dir, filename)
 	if err := os.WriteFile(path, []byte(content+"\n"), roleAssignmentPromptMode); err != nil {
-		return "", fmt.Errorf("write role assignment prompt %s: %w", path, err)
+		return "", fmt.Errorf("write %s prompt %s: %w", inv.purpose, path, err)
 	}
 	return path, nil
 }
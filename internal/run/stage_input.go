@@ -31,11 +31,25 @@ func newWorkerStageInput(repoRoot, worktreeRoot string, task index.Task, stage r
 		Role:            role,
 		ReasoningEffort: cfg.ReasoningEffort.LevelForRole(string(role)),
 		AgentUsesCodex:  agentUsesCodex,
+		PromptBudget:    worker.PromptBudgetForRole(cfg, role),
+		ExtraEnv:        cfg.Env.VarsFor(string(role), string(stage)),
 		Warn:            warn,
 		WorkerStateDir:  workerStateDirPath(worktreeRoot, attempt, stage, role),
 	}
 }
 
+// newTaskStageInput builds the stage input for task and adds its generated prompt context,
+// so dispatch, direct execution, and prompt previews budget the same prompt stack.
+func newTaskStageInput(repoRoot, worktreeRoot string, tasks []index.Task, task index.Task, stage roles.Stage, role index.Role, attempt int, cfg config.Config, warn func(string)) worker.StageInput {
+	stageInput := newWorkerStageInput(repoRoot, worktreeRoot, task, stage, role, attempt, cfg, warn)
+	if stage == roles.StageWork {
+		if err := configureDependencyContextStageInput(repoRoot, tasks, task, cfg, &stageInput); err != nil && warn != nil {
+			warn(fmt.Sprintf("failed to summarize dependencies for task %s: %v", task.ID, err))
+		}
+	}
+	return stageInput
+}
+
 func workerStateDirPath(worktreeRoot string, attempt int, stage roles.Stage, role index.Role) string {
 	dirName := workerStateDirName(attempt, stage, role)
 	return filepath.Join(worktreeRoot, localStateDirName, dirName)
@@ -0,0 +1,130 @@
+// Tests for stalled worker detection.
+package run
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/inflight"
+	"github.com/cmtonkinson/governator/internal/scheduler"
+	"github.com/cmtonkinson/governator/internal/testrepos"
+)
+
+// writeAgedFile writes a file and backdates its modification time.
+func writeAgedFile(t *testing.T, path string, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create dir for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("chtimes %s: %v", path, err)
+	}
+}
+
+// TestExecuteWorkStageKillsStalledWorker ensures an idle worker is blocked before its hard timeout.
+func TestExecuteWorkStageKillsStalledWorker(t *testing.T) {
+	repo := testrepos.New(t)
+	repoRoot := repo.Root
+	idleSince := time.Now().Add(-30 * time.Minute)
+
+	workerStateDir := filepath.Join(repoRoot, "_governator", "_local-state", "worker-1-work-worker")
+	writeAgedFile(t, filepath.Join(workerStateDir, "stdout.log"), "thinking...\n", idleSince)
+	writeAgedFile(t, filepath.Join(workerStateDir, "stderr.log"), "", idleSince)
+	worktreePath := t.TempDir()
+	writeAgedFile(t, filepath.Join(worktreePath, "main.go"), "package main\n", idleSince)
+	if err := os.Chtimes(worktreePath, idleSince, idleSince); err != nil {
+		t.Fatalf("chtimes worktree: %v", err)
+	}
+
+	cfg := config.Defaults()
+	cfg.Timeouts.WorkerSeconds = 7200
+	cfg.Timeouts.StallSeconds = 600
+
+	idx := index.Index{
+		Tasks: []index.Task{
+			{
+				ID:       "T-001",
+				Path:     "_governator/tasks/T-001-work.md",
+				Kind:     index.TaskKindExecution,
+				State:    index.TaskStateTriaged,
+				Role:     "worker",
+				Attempts: index.AttemptCounters{Total: 1},
+			},
+		},
+	}
+	inFlight := inflight.Set{}
+	if err := inFlight.AddWithStartAndPath("T-001", time.Now().Add(-time.Hour).UTC(), worktreePath, workerStateDir, "work", "worker"); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	opts := Options{Stdout: &stdout, Stderr: &stderr}
+	result, err := ExecuteWorkStage(repoRoot, &idx, cfg, scheduler.RoleCapsFromConfig(cfg), inFlight, nil, nil, nil, nil, opts)
+	if err != nil {
+		t.Fatalf("execute work stage: %v", err)
+	}
+	if result.TasksBlocked != 1 {
+		t.Fatalf("tasks blocked = %d, want 1", result.TasksBlocked)
+	}
+	task := idx.Tasks[0]
+	if task.State != index.TaskStateBlocked {
+		t.Fatalf("task state = %q, want %q", task.State, index.TaskStateBlocked)
+	}
+	if !strings.Contains(task.BlockedReason, "worker stalled") {
+		t.Fatalf("blocked reason = %q, want stall reason", task.BlockedReason)
+	}
+	if inFlight.Contains("T-001") {
+		t.Fatal("expected stalled task to be removed from in-flight")
+	}
+	if !strings.Contains(stdout.String(), "status=stalled") || !strings.Contains(stdout.String(), "stall_seconds=600") {
+		t.Fatalf("expected stalled event, got %q", stdout.String())
+	}
+}
+
+// TestCheckWorkerExpiry covers the hard timeout, stall, and recent-activity cases.
+func TestCheckWorkerExpiry(t *testing.T) {
+	stateDir := t.TempDir()
+	worktreePath := t.TempDir()
+	idleSince := time.Now().Add(-20 * time.Minute)
+	writeAgedFile(t, filepath.Join(stateDir, "stdout.log"), "", idleSince)
+	writeAgedFile(t, filepath.Join(worktreePath, "notes.md"), "", idleSince)
+	if err := os.Chtimes(worktreePath, idleSince, idleSince); err != nil {
+		t.Fatalf("chtimes worktree: %v", err)
+	}
+	set := inflight.Set{}
+	if err := set.AddWithStart("T-1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+	warn := func(message string) { t.Fatalf("unexpected warning: %s", message) }
+
+	expiry, expired := checkWorkerExpiry(set, "T-1", stateDir, worktreePath, 1800, 600, warn)
+	if !expired || expiry.stalled {
+		t.Fatalf("expiry = %+v (%v), want hard timeout to win", expiry, expired)
+	}
+
+	expiry, expired = checkWorkerExpiry(set, "T-1", stateDir, worktreePath, 7200, 600, warn)
+	if !expired || !expiry.stalled || expiry.kind() != "stall" {
+		t.Fatalf("expiry = %+v (%v), want stall", expiry, expired)
+	}
+	if expiry.lastActivity.Sub(idleSince).Abs() > time.Second {
+		t.Fatalf("last activity = %v, want %v", expiry.lastActivity, idleSince)
+	}
+
+	if _, expired := checkWorkerExpiry(set, "T-1", stateDir, worktreePath, 7200, 0, warn); expired {
+		t.Fatal("expected stall detection to be disabled when stall_seconds is 0")
+	}
+
+	writeAgedFile(t, filepath.Join(worktreePath, "src", "new.go"), "package src\n", time.Now())
+	if _, expired := checkWorkerExpiry(set, "T-1", stateDir, worktreePath, 7200, 600, warn); expired {
+		t.Fatal("expected recent worktree changes to count as activity")
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/scheduler"
 )
 
 // TaskInventoryResult captures the outcome of task inventory.
@@ -109,19 +111,123 @@ func (inventory *TaskInventory) parseTaskFile(filePath, taskPath string) (index.
 		return index.Task{}, fmt.Errorf("read task file: %w", err)
 	}
 
+	frontMatter := parseTaskFrontMatter(string(content))
 	return index.Task{
-		ID:       taskIDFromPath(taskPath),
-		Title:    extractTitleFromMarkdown(string(content)),
-		Path:     taskPath,
-		Kind:     index.TaskKindExecution,
-		State:    index.TaskStateBacklog,
-		Role:     index.Role("default"),
-		Retries:  index.RetryPolicy{MaxAttempts: 3},
-		Attempts: index.AttemptCounters{Total: 0, Failed: 0},
-		Order:    len(inventory.idx.Tasks) + 1,
+		ID:             taskIDFromPath(taskPath),
+		Title:          extractTitleFromMarkdown(string(content)),
+		Path:           taskPath,
+		Kind:           index.TaskKindExecution,
+		State:          index.TaskStateBacklog,
+		Role:           index.Role("default"),
+		Retries:        index.RetryPolicy{MaxAttempts: 3},
+		Attempts:       index.AttemptCounters{Total: 0, Failed: 0},
+		Order:          len(inventory.idx.Tasks) + 1,
+		TimeoutSeconds: frontMatterTimeout(frontMatter),
+		Overlap:        frontMatterOverlap(frontMatter),
 	}, nil
 }
 
+// refreshTaskTimeout re-reads the task's timeout_seconds front matter so edits made after
+// inventory apply to the next dispatch. An unreadable task file keeps the indexed value.
+func refreshTaskTimeout(repoRoot string, idx *index.Index, task *index.Task) {
+	if task == nil || strings.TrimSpace(task.Path) == "" {
+		return
+	}
+	content, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(task.Path)))
+	if err != nil {
+		return
+	}
+	seconds := frontMatterTimeout(parseTaskFrontMatter(string(content)))
+	task.TimeoutSeconds = seconds
+	_ = updateIndexTask(idx, task.ID, func(indexed *index.Task) {
+		indexed.TimeoutSeconds = seconds
+	})
+}
+
+// parseTaskFrontMatter reads flat "key: value" pairs from a leading YAML front matter block.
+// Block list items ("- item" lines under an empty key) are joined with newlines; read them
+// with frontMatterList. Nested maps are not interpreted.
+func parseTaskFrontMatter(content string) map[string]string {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil
+	}
+	values := map[string]string{}
+	listKey := ""
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" {
+			return values
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && listKey != "" {
+			item = strings.Trim(strings.TrimSpace(item), `"'`)
+			if values[listKey] != "" {
+				values[listKey] += "\n"
+			}
+			values[listKey] += item
+			continue
+		}
+		if line != strings.TrimLeft(line, " \t") {
+			listKey = ""
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		values[key] = value
+		listKey = ""
+		if value == "" {
+			listKey = key
+		}
+	}
+	// An unterminated block is body text, not front matter.
+	return nil
+}
+
+// frontMatterTimeout returns the task's timeout_seconds override, or 0 when unset or invalid.
+func frontMatterTimeout(frontMatter map[string]string) int {
+	seconds, err := strconv.Atoi(frontMatter["timeout_seconds"])
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return seconds
+}
+
+// frontMatterList returns the items of a front matter list, written either as a block list
+// or inline as "[a, b]". A plain scalar yields a single item.
+func frontMatterList(frontMatter map[string]string, key string) []string {
+	value := strings.TrimSpace(frontMatter[key])
+	if value == "" {
+		return nil
+	}
+	separator := "\n"
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+		separator = ","
+	}
+	var items []string
+	for _, item := range strings.Split(value, separator) {
+		item = strings.Trim(strings.TrimSpace(item), `"'`)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// frontMatterOverlap returns the normalized paths and globs a task declares under its
+// files or paths front matter keys.
+func frontMatterOverlap(frontMatter map[string]string) []string {
+	declared := append(frontMatterList(frontMatter, "files"), frontMatterList(frontMatter, "paths")...)
+	return scheduler.NormalizeOverlap(declared)
+}
+
 // canonicalTaskPath normalizes a task path for identity comparisons.
 func canonicalTaskPath(path string) string {
 	trimmed := strings.TrimSpace(path)
@@ -350,6 +350,117 @@ func TestTaskInventoryDefaultValues(t *testing.T) {
 	}
 }
 
+func TestTaskInventoryFrontMatterTimeout(t *testing.T) {
+	repo := testrepos.New(t)
+
+	tasksDir := filepath.Join(repo.Root, "_governator", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		t.Fatalf("create tasks dir: %v", err)
+	}
+
+	files := map[string]string{
+		"001-slow.md":    "---\nmilestone: m1\ntask: 001\ntimeout_seconds: 2400\ndepends_on: []\n---\n\n# Task: Slow Task\n",
+		"002-invalid.md": "---\ntimeout_seconds: soon\n---\n\n# Task: Invalid Timeout\n",
+		"003-plain.md":   "# Task: Plain\n\ntimeout_seconds: 60\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tasksDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write task file: %v", err)
+		}
+	}
+
+	idx := &index.Index{Tasks: []index.Task{}}
+	if _, err := NewTaskInventory(repo.Root, idx).InventoryTasks(); err != nil {
+		t.Fatalf("inventory error: %v", err)
+	}
+
+	want := map[string]int{"001-slow": 2400, "002-invalid": 0, "003-plain": 0}
+	for _, task := range idx.Tasks {
+		if task.TimeoutSeconds != want[task.ID] {
+			t.Fatalf("%s TimeoutSeconds = %d, want %d", task.ID, task.TimeoutSeconds, want[task.ID])
+		}
+	}
+	if idx.Tasks[0].Title != "Slow Task" {
+		t.Fatalf("title = %q, want front matter to be skipped", idx.Tasks[0].Title)
+	}
+}
+
+// TestRefreshTaskTimeoutReadsEditedFrontMatter picks up timeout_seconds edits made after inventory.
+func TestRefreshTaskTimeoutReadsEditedFrontMatter(t *testing.T) {
+	repo := testrepos.New(t)
+
+	tasksDir := filepath.Join(repo.Root, "_governator", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		t.Fatalf("create tasks dir: %v", err)
+	}
+	taskFile := filepath.Join(tasksDir, "001-slow.md")
+	if err := os.WriteFile(taskFile, []byte("---\ntimeout_seconds: 600\n---\n\n# Task: Slow Task\n"), 0644); err != nil {
+		t.Fatalf("write task file: %v", err)
+	}
+
+	idx := &index.Index{Tasks: []index.Task{}}
+	if _, err := NewTaskInventory(repo.Root, idx).InventoryTasks(); err != nil {
+		t.Fatalf("inventory error: %v", err)
+	}
+	if err := os.WriteFile(taskFile, []byte("---\ntimeout_seconds: 2400\n---\n\n# Task: Slow Task\n"), 0644); err != nil {
+		t.Fatalf("rewrite task file: %v", err)
+	}
+
+	task := idx.Tasks[0]
+	refreshTaskTimeout(repo.Root, idx, &task)
+	if task.TimeoutSeconds != 2400 || idx.Tasks[0].TimeoutSeconds != 2400 {
+		t.Fatalf("TimeoutSeconds = %d (index %d), want 2400", task.TimeoutSeconds, idx.Tasks[0].TimeoutSeconds)
+	}
+
+	if err := os.Remove(taskFile); err != nil {
+		t.Fatalf("remove task file: %v", err)
+	}
+	refreshTaskTimeout(repo.Root, idx, &task)
+	if idx.Tasks[0].TimeoutSeconds != 2400 {
+		t.Fatalf("TimeoutSeconds = %d after task file removal, want 2400 kept", idx.Tasks[0].TimeoutSeconds)
+	}
+}
+
+// TestTaskInventoryFrontMatterOverlap records declared files and paths as task overlap.
+func TestTaskInventoryFrontMatterOverlap(t *testing.T) {
+	repo := testrepos.New(t)
+
+	tasksDir := filepath.Join(repo.Root, "_governator", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		t.Fatalf("create tasks dir: %v", err)
+	}
+
+	files := map[string]string{
+		"001-block.md":  "---\nfiles:\n  - internal/run/merge.go\n  - \"./internal/run/*_test.go\"\npaths: docs/\n---\n\n# Task: Block List\n",
+		"002-inline.md": "---\nfiles: [README.md, 'docs/*.md']\ntimeout_seconds: 60\n---\n\n# Task: Inline List\n",
+		"003-plain.md":  "# Task: Plain\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tasksDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write task file: %v", err)
+		}
+	}
+
+	idx := &index.Index{Tasks: []index.Task{}}
+	if _, err := NewTaskInventory(repo.Root, idx).InventoryTasks(); err != nil {
+		t.Fatalf("inventory error: %v", err)
+	}
+
+	want := map[string]string{
+		"001-block":  "docs,internal/run/*_test.go,internal/run/merge.go",
+		"002-inline": "README.md,docs/*.md",
+		"003-plain":  "",
+	}
+	for _, task := range idx.Tasks {
+		if got := strings.Join(task.Overlap, ","); got != want[task.ID] {
+			t.Fatalf("%s Overlap = %q, want %q", task.ID, got, want[task.ID])
+		}
+	}
+	if idx.Tasks[1].TimeoutSeconds != 60 {
+		t.Fatalf("TimeoutSeconds = %d, want 60 alongside an inline list", idx.Tasks[1].TimeoutSeconds)
+	}
+}
+
 func TestTaskInventoryOrderIncrement(t *testing.T) {
 	repo := testrepos.New(t)
 
@@ -66,7 +66,7 @@ func TestExecuteTestStageHappyPath(t *testing.T) {
 	// Execute test stage - this will fail because we don't have proper worktrees set up,
 	// but we can verify that it processes the worked task
 	inFlight := inflight.Set{}
-	result, err := ExecuteTestStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, opts)
+	result, err := ExecuteTestStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("execute test stage: %v", err)
 	}
@@ -127,7 +127,7 @@ func TestExecuteTestStageNoWorkedTasks(t *testing.T) {
 	caps := scheduler.RoleCapsFromConfig(cfg)
 
 	inFlight := inflight.Set{}
-	result, err := ExecuteTestStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, opts)
+	result, err := ExecuteTestStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("execute test stage: %v", err)
 	}
@@ -325,7 +325,7 @@ func TestExecuteReviewStageHappyPath(t *testing.T) {
 	// Execute review stage - this will fail because we don't have proper worktrees set up,
 	// but we can verify that it processes the tested task
 	inFlight := inflight.Set{}
-	result, err := ExecuteReviewStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, opts)
+	result, err := ExecuteReviewStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("execute review stage: %v", err)
 	}
@@ -386,7 +386,7 @@ func TestExecuteReviewStageNoTestedTasks(t *testing.T) {
 	caps := scheduler.RoleCapsFromConfig(cfg)
 
 	inFlight := inflight.Set{}
-	result, err := ExecuteReviewStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, opts)
+	result, err := ExecuteReviewStage(repoRoot, &idx, cfg, caps, inFlight, nil, auditor, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("execute review stage: %v", err)
 	}
@@ -574,7 +574,7 @@ func TestExecuteConflictResolutionStage_NoResolvedTasks(t *testing.T) {
 	}
 
 	inFlight := inflight.Set{}
-	result, err := ExecuteConflictResolutionStage("/tmp", idx, cfg, caps, inFlight, nil, auditor, nil, opts)
+	result, err := ExecuteConflictResolutionStage("/tmp", idx, cfg, caps, inFlight, nil, auditor, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("ExecuteConflictResolutionStage failed: %v", err)
 	}
@@ -661,7 +661,7 @@ func TestExecuteConflictResolutionStage_WithConflictTasks(t *testing.T) {
 	// This will fail because we don't have actual worktrees set up,
 	// but we can verify the function processes the conflict tasks
 	inFlight := inflight.Set{}
-	result, err := ExecuteConflictResolutionStage(tempDir, idx, cfg, caps, inFlight, nil, auditor, nil, opts)
+	result, err := ExecuteConflictResolutionStage(tempDir, idx, cfg, caps, inFlight, nil, auditor, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("ExecuteConflictResolutionStage failed: %v", err)
 	}
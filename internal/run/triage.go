@@ -12,9 +12,9 @@ import (
 	"time"
 
 	"github.com/cmtonkinson/governator/internal/config"
-	"github.com/cmtonkinson/governator/internal/digests"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/scheduler"
 	"github.com/cmtonkinson/governator/internal/templates"
 	"github.com/cmtonkinson/governator/internal/worker"
 )
@@ -24,8 +24,10 @@ const (
 	triageDirName        = "triage"
 	triageStateFileName  = "state.json"
 	triageOutputFileName = "dag.json"
+	triagePathsFileName  = "paths.json"
 	triageTaskFileName   = "dag-order-task.md"
 	triageMaxAttempts    = 2
+	triageRole           = "default"
 )
 
 // TriageState tracks the execution backlog triage lifecycle.
@@ -70,6 +72,12 @@ func RunBacklogTriage(repoRoot string, idx *index.Index, cfg config.Config, opts
 		if alive, err := processAlive(state.RunningPID); err != nil {
 			return TriageCycleResult{}, err
 		} else if alive {
+			if timeoutSecs := cfg.Timeouts.SecondsFor(triageRole, config.StageKeyTriage, ""); timedOut(state.LastAttemptAt, timeoutSecs) {
+				killWorkerProcess(repoRoot, state.RunningPID, state.WorkerStateDir, "timeout", func(msg string) {
+					fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
+				})
+				return failTriageAttempt(repoRoot, state, errors.New(formatTimeoutReason(timeoutSecs)), opts)
+			}
 			return TriageCycleResult{
 				Running:        true,
 				WorkerPID:      state.RunningPID,
@@ -145,13 +153,19 @@ func ClearTriageState(repoRoot string) error {
 
 // dispatchTriageAttempt starts the triage agent and records state.
 func dispatchTriageAttempt(repoRoot string, idx *index.Index, cfg config.Config, opts Options, state TriageState) (TriageCycleResult, error) {
+	if entry, cooling := roleCooldown(repoRoot, cfg, index.Role(triageRole), func(msg string) {
+		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
+	}); cooling {
+		fmt.Fprintf(opts.Stdout, "triage deferred: %s cooling down until %s\n", entry.CLI, entry.Until.UTC().Format(time.RFC3339))
+		return TriageCycleResult{}, nil
+	}
 	attempt := state.Attempt + 1
 	attemptState := state
 	attemptState.Attempt = attempt
 	if err := prepareTriageTask(repoRoot, *idx); err != nil {
 		return failTriageAttempt(repoRoot, attemptState, err, opts)
 	}
-	role := index.Role("default")
+	role := index.Role(triageRole)
 	task := index.Task{
 		ID:   triageTaskID,
 		Path: triageTaskRelativePath(),
@@ -161,13 +175,19 @@ func dispatchTriageAttempt(repoRoot string, idx *index.Index, cfg config.Config,
 		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
 	})
 	stageInput.WorkerStateDir = triageWorkerStateDir(repoRoot, attempt, role)
+	stageInput.ExtraEnv = cfg.Env.VarsFor(string(role), config.StageKeyTriage)
 
 	stageResult, err := worker.StageEnvAndPrompts(stageInput)
 	if err != nil {
 		return failTriageAttempt(repoRoot, attemptState, fmt.Errorf("stage triage agent: %w", err), opts)
 	}
+	emitTaskPrompt(opts.Stdout, task.ID, string(role), config.StageKeyTriage, stageResult)
 
-	dispatchResult, err := worker.DispatchWorkerFromConfig(cfg, task, stageResult, repoRoot, roles.StageWork, func(msg string) {
+	dispatchCfg, err := triageSandboxConfig(repoRoot, cfg, role)
+	if err != nil {
+		return failTriageAttempt(repoRoot, attemptState, err, opts)
+	}
+	dispatchResult, err := worker.DispatchWorkerFromConfig(dispatchCfg, task, stageResult, repoRoot, roles.StageWork, func(msg string) {
 		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
 	})
 	if err != nil {
@@ -193,7 +213,9 @@ func dispatchTriageAttempt(repoRoot string, idx *index.Index, cfg config.Config,
 
 // finalizeTriageAttempt collects triage results, applies DAG ordering, and clears state.
 func finalizeTriageAttempt(repoRoot string, idx *index.Index, cfg config.Config, opts Options, state TriageState) (TriageCycleResult, error) {
-	exitStatus, finished, err := worker.ReadExitStatus(state.WorkerStateDir, triageTaskID, roles.StageWork)
+	exitStatus, finished, err := collectWorkerExit(repoRoot, state.WorkerStateDir, triageTaskID, roles.StageWork, func(msg string) {
+		fmt.Fprintf(opts.Stderr, "Warning: %s\n", msg)
+	})
 	if err != nil {
 		return failTriageAttempt(repoRoot, state, fmt.Errorf("read triage exit status: %w", err), opts)
 	}
@@ -213,8 +235,13 @@ func finalizeTriageAttempt(repoRoot string, idx *index.Index, cfg config.Config,
 	for _, warning := range warnings {
 		fmt.Fprintf(opts.Stderr, "Warning: %s\n", warning)
 	}
+	paths, err := readTriagePaths(triagePathsPath(repoRoot))
+	if err != nil {
+		fmt.Fprintf(opts.Stderr, "Warning: ignoring triage paths: %v\n", err)
+	}
+	applyTriagePaths(repoRoot, idx, paths)
 
-	digestsMap, err := digests.Compute(repoRoot)
+	digestsMap, err := computePlanningDigests(repoRoot)
 	if err != nil {
 		return failTriageAttempt(repoRoot, state, fmt.Errorf("compute digests: %w", err), opts)
 	}
@@ -288,6 +315,35 @@ func applyDagMapping(idx *index.Index, mapping map[string][]string) []string {
 	return warnings
 }
 
+// applyTriagePaths sets the overlap of triaged tasks to the paths declared in their task
+// front matter plus the paths the triage agent expects them to touch.
+func applyTriagePaths(repoRoot string, idx *index.Index, paths map[string][]string) {
+	for i := range idx.Tasks {
+		task := &idx.Tasks[i]
+		if !isTriageEligible(*task) {
+			continue
+		}
+		declared := task.Overlap
+		if content, err := os.ReadFile(filepath.Join(repoRoot, task.Path)); err == nil {
+			declared = frontMatterOverlap(parseTaskFrontMatter(string(content)))
+		}
+		task.Overlap = scheduler.NormalizeOverlap(append(append([]string{}, declared...), paths[task.ID]...))
+	}
+}
+
+// readTriagePaths parses the optional likely-touched paths the triage agent writes next to
+// the DAG mapping. A missing or empty file yields no paths.
+func readTriagePaths(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && strings.TrimSpace(string(data)) == "") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read triage paths %s: %w", path, err)
+	}
+	return readDagMapping(path)
+}
+
 // readDagMapping parses the triage DAG mapping from disk, tolerating extra text.
 func readDagMapping(path string) (map[string][]string, error) {
 	data, err := os.ReadFile(path)
@@ -357,13 +413,39 @@ func prepareTriageTask(repoRoot string, idx index.Index) error {
 	if err := os.WriteFile(taskPath, []byte(content), 0o644); err != nil {
 		return fmt.Errorf("write triage task: %w", err)
 	}
-	outputPath := triageOutputPath(repoRoot)
-	if err := os.Remove(outputPath); err != nil && !errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("clear triage output: %w", err)
+	for _, outputPath := range []string{triageOutputPath(repoRoot), triagePathsPath(repoRoot)} {
+		if err := os.Remove(outputPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clear triage output: %w", err)
+		}
 	}
 	return nil
 }
 
+// triageSandboxConfig lets a sandboxed triage agent write its DAG and paths output, which
+// live in otherwise read-only local state. The output files are created up front so they
+// can be bound.
+func triageSandboxConfig(repoRoot string, cfg config.Config, role index.Role) (config.Config, error) {
+	policy := cfg.Sandbox.PolicyForRole(string(role))
+	if !policy.Enabled() {
+		return cfg, nil
+	}
+	policy.WritablePaths = append([]string{}, policy.WritablePaths...)
+	for _, name := range []string{triageOutputFileName, triagePathsFileName} {
+		outputPath := filepath.Join(repoRoot, localStateDirName, name)
+		if err := os.WriteFile(outputPath, nil, 0o644); err != nil {
+			return cfg, fmt.Errorf("create triage output %s: %w", outputPath, err)
+		}
+		policy.WritablePaths = append(policy.WritablePaths, filepath.Join(localStateDirName, name))
+	}
+	rolePolicies := make(map[string]config.SandboxPolicy, len(cfg.Sandbox.Roles)+1)
+	for name, rolePolicy := range cfg.Sandbox.Roles {
+		rolePolicies[name] = rolePolicy
+	}
+	rolePolicies[string(role)] = policy
+	cfg.Sandbox.Roles = rolePolicies
+	return cfg, nil
+}
+
 // buildTriageTaskContent renders the prompt used by the DAG ordering agent.
 func buildTriageTaskContent(template string, idx index.Index) string {
 	var b strings.Builder
@@ -375,6 +457,9 @@ func buildTriageTaskContent(template string, idx index.Index) string {
 	b.WriteString("\nTRUE dependency = Task X needs Y's output/code/feature to proceed\n")
 	b.WriteString("FALSE dependency = Tasks touch same file but don't share code, or are just conceptually related\n")
 	b.WriteString("\nWhen in doubt, prefer NO dependency (parallel execution) over serial ordering.\n")
+	b.WriteString("\nAlso write the files each task will likely touch to `_governator/_local-state/paths.json`, using repo-relative paths or globs.\n")
+	b.WriteString("Schema example: {\"task-07\": [\"internal/api/handler.go\", \"internal/api/*_test.go\"], \"task-08\": [\"docs/\"]}\n")
+	b.WriteString("Tasks with overlapping paths are never run in parallel, so keep each list tight; omit tasks you cannot predict.\n")
 	b.WriteString("\nCurrent backlog + triaged tasks:\n")
 	for _, task := range idx.Tasks {
 		if !isTriageEligible(task) {
@@ -410,6 +495,11 @@ func triageOutputPath(repoRoot string) string {
 	return filepath.Join(repoRoot, localStateDirName, triageOutputFileName)
 }
 
+// triagePathsPath returns the path to the likely-touched paths output.
+func triagePathsPath(repoRoot string) string {
+	return filepath.Join(repoRoot, localStateDirName, triagePathsFileName)
+}
+
 // triageTaskPath returns the absolute path to the triage task prompt file.
 func triageTaskPath(repoRoot string) string {
 	return filepath.Join(repoRoot, triageTaskRelativePath())
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -37,6 +38,64 @@ func TestReadDagMappingWithCodeFence(t *testing.T) {
 	}
 }
 
+// TestApplyTriagePathsMergesFrontMatter combines declared and triage-predicted paths.
+func TestApplyTriagePathsMergesFrontMatter(t *testing.T) {
+	repoRoot := t.TempDir()
+	tasksDir := filepath.Join(repoRoot, "_governator", "tasks")
+	if err := os.MkdirAll(tasksDir, 0o755); err != nil {
+		t.Fatalf("create tasks dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tasksDir, "task-01.md"), []byte("---\nfiles: [internal/api/handler.go]\n---\n# Task\n"), 0o644); err != nil {
+		t.Fatalf("write task file: %v", err)
+	}
+	pathsPath := triagePathsPath(repoRoot)
+	if err := os.MkdirAll(filepath.Dir(pathsPath), 0o755); err != nil {
+		t.Fatalf("create output dir: %v", err)
+	}
+	if err := os.WriteFile(pathsPath, []byte("{\"task-01\": [\"internal/api/*_test.go\"], \"task-02\": [\"docs/\"], \"task-03\": [\"cmd/\"]}\n"), 0o644); err != nil {
+		t.Fatalf("write paths output: %v", err)
+	}
+
+	idx := index.Index{
+		Tasks: []index.Task{
+			{ID: "task-01", Path: "_governator/tasks/task-01.md", Kind: index.TaskKindExecution, State: index.TaskStateBacklog},
+			{ID: "task-02", Path: "_governator/tasks/missing.md", Kind: index.TaskKindExecution, State: index.TaskStateTriaged, Overlap: []string{"stale"}},
+			{ID: "task-03", Path: "_governator/tasks/task-03.md", Kind: index.TaskKindExecution, State: index.TaskStateImplemented, Overlap: []string{"keep"}},
+		},
+	}
+	paths, err := readTriagePaths(pathsPath)
+	if err != nil {
+		t.Fatalf("read triage paths: %v", err)
+	}
+	applyTriagePaths(repoRoot, &idx, paths)
+
+	want := [][]string{
+		{"internal/api/*_test.go", "internal/api/handler.go"},
+		{"docs", "stale"},
+		{"keep"},
+	}
+	for i, task := range idx.Tasks {
+		if !reflect.DeepEqual(task.Overlap, want[i]) {
+			t.Fatalf("%s Overlap = %#v, want %#v", task.ID, task.Overlap, want[i])
+		}
+	}
+}
+
+// TestReadTriagePathsOptional treats a missing or empty paths file as no paths.
+func TestReadTriagePathsOptional(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "paths.json")
+	if paths, err := readTriagePaths(path); err != nil || paths != nil {
+		t.Fatalf("missing file = %#v, %v; want nil, nil", paths, err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("write paths: %v", err)
+	}
+	if paths, err := readTriagePaths(path); err != nil || paths != nil {
+		t.Fatalf("empty file = %#v, %v; want nil, nil", paths, err)
+	}
+}
+
 // TestRunBacklogTriageFinalizesMapping ensures triage applies dependencies and clears state.
 func TestRunBacklogTriageFinalizesMapping(t *testing.T) {
 	repoRoot := t.TempDir()
@@ -237,6 +296,52 @@ func TestFailTriageAttemptLimitsRetries(t *testing.T) {
 	}
 }
 
+// TestRunBacklogTriageKillsTimedOutAgent ensures the triage stage timeout kills a running agent.
+func TestRunBacklogTriageKillsTimedOutAgent(t *testing.T) {
+	repoRoot := t.TempDir()
+	agent := exec.Command("sleep", "60")
+	if err := agent.Start(); err != nil {
+		t.Fatalf("start agent: %v", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- agent.Wait() }()
+	t.Cleanup(func() { _ = agent.Process.Kill() })
+
+	if err := SaveTriageState(repoRoot, TriageState{
+		Attempt:        1,
+		RunningPID:     agent.Process.Pid,
+		WorkerStateDir: t.TempDir(),
+		LastAttemptAt:  time.Now().Add(-2 * time.Minute).UTC(),
+	}); err != nil {
+		t.Fatalf("save triage state: %v", err)
+	}
+
+	cfg := config.Defaults()
+	cfg.Timeouts.Stages[config.StageKeyTriage] = 60
+	idx := index.Index{}
+	var stderr bytes.Buffer
+	result, err := RunBacklogTriage(repoRoot, &idx, cfg, Options{Stdout: ioDiscard{}, Stderr: &stderr})
+	if err != nil {
+		t.Fatalf("run backlog triage: %v", err)
+	}
+	if result.Running {
+		t.Fatal("expected timed-out triage to stop running")
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected triage agent to be killed")
+	}
+
+	loaded, _, err := LoadTriageState(repoRoot)
+	if err != nil {
+		t.Fatalf("load triage state: %v", err)
+	}
+	if loaded.LastError != formatTimeoutReason(60) || loaded.RunningPID != 0 {
+		t.Fatalf("triage state = %+v, want timeout recorded", loaded)
+	}
+}
+
 // TestApplyDagMappingTriagesEligibleTasks ensures backlog/triaged tasks are updated.
 func TestApplyDagMappingTriagesEligibleTasks(t *testing.T) {
 	idx := index.Index{
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/dryrun"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/supervisor"
@@ -27,6 +28,8 @@ type UnifiedSupervisorOptions struct {
 	Stderr       io.Writer
 	PollInterval time.Duration
 	LogPath      string
+	// DryRun, when set, replaces every worker command with the scripted fake agent.
+	DryRun *dryrun.Settings
 }
 
 var (
@@ -71,12 +74,19 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
+	if opts.DryRun != nil {
+		cfg = opts.DryRun.Apply(cfg)
+	}
 	inFlightStore, err := inflight.NewStore(repoRoot)
 	if err != nil {
 		return fmt.Errorf("create in-flight store: %w", err)
 	}
 
 	state := newUnifiedSupervisorState(repoRoot, opts.LogPath)
+	if opts.DryRun != nil {
+		state.DryRunScript = opts.DryRun.ScriptPath
+		fmt.Fprintf(stdout, "dry run: workers replaced by the scripted fake agent (%s)\n", opts.DryRun.ScriptPath)
+	}
 	if err := supervisor.SaveState(repoRoot, state); err != nil {
 		return err
 	}
@@ -85,6 +95,7 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 	signal.Notify(stopSignals, os.Interrupt, syscall.SIGTERM)
 	defer signal.Stop(stopSignals)
 
+	notifiedDrift := ""
 	for {
 		select {
 		case <-stopSignals:
@@ -111,11 +122,20 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 			inFlight = inflight.Set{}
 		}
 
-		planningDrift, err := detectPlanningDriftFn(repoRoot, idx.Digests)
+		planningDrift, err := detectPlanningDriftFn(repoRoot, cfg, idx.Digests)
 		if err != nil {
 			return failUnifiedSupervisor(repoRoot, &state, err)
 		}
-		if planningDrift.HasDrift {
+		if !planningDrift.HasDrift {
+			notifiedDrift = ""
+		}
+		if planningDrift.HasDrift && cfg.Drift.Mode == config.DriftModeNotify {
+			// Notify-only drift is reported once per change and never drains; status keeps showing it.
+			if planningDrift.Message != notifiedDrift {
+				emitDriftNotifyMessage(stdout, planningDrift.Message)
+				notifiedDrift = planningDrift.Message
+			}
+		} else if planningDrift.HasDrift {
 			state.StepID = "drain"
 			state.StepName = "Drain"
 			state.WorkerPID = 0
@@ -125,15 +145,17 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 			}
 			emitDriftReplanMessage(stdout, planningDrift.Message)
 			if len(inFlight) > 0 {
-				if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, DisableDispatch: true, SkipPlanningDrift: true}); err != nil {
+				if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, DisableDispatch: true, SkipPlanningDrift: true, DryRun: opts.DryRun}); err != nil {
 					return failUnifiedSupervisor(repoRoot, &state, err)
 				}
 				time.Sleep(opts.PollInterval)
 				continue
 			}
-			if err := ResetPlanningToStep(repoRoot, "gap-analysis"); err != nil {
+			restartStepID, err := replanAfterDrift(repoRoot, idx, planning, planningDrift.ChangedPaths)
+			if err != nil {
 				return failUnifiedSupervisor(repoRoot, &state, err)
 			}
+			emitDriftRestartMessage(stdout, restartStepID)
 			time.Sleep(opts.PollInterval)
 			continue
 		}
@@ -150,7 +172,7 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 			if err := maybePersistUnifiedSupervisorState(repoRoot, &state); err != nil {
 				return err
 			}
-			if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, SkipPlanningDrift: true}); err != nil {
+			if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, SkipPlanningDrift: true, DryRun: opts.DryRun}); err != nil {
 				return failUnifiedSupervisor(repoRoot, &state, err)
 			}
 			time.Sleep(opts.PollInterval)
@@ -165,7 +187,7 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 				if err := maybePersistUnifiedSupervisorState(repoRoot, &state); err != nil {
 					return err
 				}
-				if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, DisableDispatch: true, SkipPlanningDrift: true}); err != nil {
+				if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, DisableDispatch: true, SkipPlanningDrift: true, DryRun: opts.DryRun}); err != nil {
 					return failUnifiedSupervisor(repoRoot, &state, err)
 				}
 				time.Sleep(opts.PollInterval)
@@ -177,7 +199,7 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 			if err := maybePersistUnifiedSupervisorState(repoRoot, &state); err != nil {
 				return err
 			}
-			triageResult, err := runBacklogTriageFunc(repoRoot, &idx, cfg, Options{Stdout: stdout, Stderr: stderr})
+			triageResult, err := runBacklogTriageFunc(repoRoot, &idx, cfg, Options{Stdout: stdout, Stderr: stderr, DryRun: opts.DryRun})
 			if err != nil {
 				return failUnifiedSupervisor(repoRoot, &state, err)
 			}
@@ -205,7 +227,7 @@ func RunUnifiedSupervisor(repoRoot string, opts UnifiedSupervisorOptions) error
 		if err := maybePersistUnifiedSupervisorState(repoRoot, &state); err != nil {
 			return err
 		}
-		if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, SkipPlanningDrift: true}); err != nil {
+		if _, err := runFunc(repoRoot, Options{Stdout: stdout, Stderr: stderr, SkipPlanningDrift: true, DryRun: opts.DryRun}); err != nil {
 			return failUnifiedSupervisor(repoRoot, &state, err)
 		}
 
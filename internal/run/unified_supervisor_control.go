@@ -67,7 +67,7 @@ func stopExecutionWorkers(repoRoot string) error {
 	}
 	for _, entry := range set {
 		wrapperPID, _ := readDispatchWrapperPID(entry.WorkerStateDir)
-		killWorkerProcess(wrapperPID, entry.WorkerStateDir, nil)
+		killWorkerProcess(repoRoot, wrapperPID, entry.WorkerStateDir, "stop", nil)
 	}
 	return nil
 }
@@ -2,9 +2,12 @@
 package run
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,13 +77,14 @@ func TestRunUnifiedSupervisor_PlanningDriftDrainsAndReplans(t *testing.T) {
 		}
 		return Result{}, nil
 	}
-	detectPlanningDriftFn = func(repoRoot string, base index.Digests) (PlanningDriftReport, error) {
+	detectPlanningDriftFn = func(repoRoot string, cfg config.Config, base index.Digests) (PlanningDriftReport, error) {
 		if driftChecks == 0 {
 			driftChecks++
 			return PlanningDriftReport{
-				HasDrift: true,
-				Details:  []string{"planning doc changed: _governator/docs/roadmap.md"},
-				Message:  "planning drift detected",
+				HasDrift:     true,
+				Details:      []string{"planning doc added: _governator/docs/adr/adr-0001-test.md"},
+				Message:      "planning drift detected",
+				ChangedPaths: []string{"_governator/docs/adr/adr-0001-test.md"},
 			}, nil
 		}
 		return PlanningDriftReport{}, nil
@@ -114,6 +118,67 @@ func TestRunUnifiedSupervisor_PlanningDriftDrainsAndReplans(t *testing.T) {
 	}
 }
 
+func TestRunUnifiedSupervisor_NotifyDriftDoesNotReplan(t *testing.T) {
+	repoRoot := setupUnifiedTestRepo(t)
+	setPlanningState(t, repoRoot, PlanningCompleteState)
+
+	cfg := config.Defaults()
+	cfg.Drift.Mode = config.DriftModeNotify
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	configPath := filepath.Join(repoRoot, "_governator", "_durable-state", "config.json")
+	if err := os.WriteFile(configPath, append(data, '\n'), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	prevRun := runFunc
+	prevDetect := detectPlanningDriftFn
+	drained := false
+	runFunc = func(repoRoot string, opts Options) (Result, error) {
+		if opts.DisableDispatch {
+			drained = true
+		}
+		return Result{}, nil
+	}
+	detectPlanningDriftFn = func(repoRoot string, cfg config.Config, base index.Digests) (PlanningDriftReport, error) {
+		return PlanningDriftReport{
+			HasDrift:     true,
+			Details:      []string{"GOVERNATOR.md changed"},
+			Message:      "planning drift detected",
+			ChangedPaths: []string{"GOVERNATOR.md"},
+		}, nil
+	}
+	t.Cleanup(func() {
+		runFunc = prevRun
+		detectPlanningDriftFn = prevDetect
+	})
+
+	var stdout bytes.Buffer
+	if err := RunUnifiedSupervisor(repoRoot, UnifiedSupervisorOptions{
+		Stdout:       &stdout,
+		Stderr:       io.Discard,
+		PollInterval: 10 * time.Millisecond,
+	}); err != nil {
+		t.Fatalf("RunUnifiedSupervisor failed: %v", err)
+	}
+
+	if drained {
+		t.Fatal("expected notify-only drift not to drain workers")
+	}
+	if !strings.Contains(stdout.String(), "planning=drift status=notify") {
+		t.Fatalf("expected drift notification, got %q", stdout.String())
+	}
+	idx, err := index.Load(filepath.Join(repoRoot, indexFilePath))
+	if err != nil {
+		t.Fatalf("load index: %v", err)
+	}
+	if stateID, err := planningTaskState(idx); err != nil || stateID != PlanningCompleteState {
+		t.Fatalf("planning state = %q, %v; want complete", stateID, err)
+	}
+}
+
 func TestRunUnifiedSupervisor_PlanningIncompleteRunsPlanning(t *testing.T) {
 	repoRoot := setupUnifiedTestRepo(t)
 
@@ -3,28 +3,51 @@ package run
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/dlclark/regexp2"
 )
 
+const (
+	// promptValidationAgentName identifies prompt validation runs in the audit log.
+	promptValidationAgentName = "validator"
+	// validationOutputLimit caps the command or agent output quoted in a failure message.
+	validationOutputLimit = 2000
+)
+
 // ValidationResult captures the outcome of a single validation check.
 type ValidationResult struct {
-	Type     string
-	Valid    bool
-	Message  string
-	StepID   string
-	StepName string
+	Type       string
+	Valid      bool
+	Message    string
+	StepID     string
+	StepName   string
+	Validation PlanningValidationSpec // the check that produced this result
 }
 
 // ValidationEngine executes validation checks against the repository.
 type ValidationEngine struct {
 	repoRoot string
+	agent    *validationAgent // runs prompt validations; nil when no worker is configured
+}
+
+// validationAgent carries what prompt validations need to run the configured worker command.
+type validationAgent struct {
+	cfg       config.Config
+	auditor   AgentAuditor
+	role      index.Role // used when a prompt validation names no role
+	artifacts []string   // step outputs the prompt is asked to review
+	warn      func(string)
 }
 
 // NewValidationEngine creates a new validation engine for the given repository.
@@ -32,46 +55,74 @@ func NewValidationEngine(repoRoot string) *ValidationEngine {
 	return &ValidationEngine{repoRoot: repoRoot}
 }
 
+// withAgent returns the engine configured to run prompt validations through a worker command.
+func (engine *ValidationEngine) withAgent(agent *validationAgent) *ValidationEngine {
+	engine.agent = agent
+	return engine
+}
+
 // RunValidations executes all validations for a planning step and returns the results.
+// File, directory, and command checks all run first; prompt checks run only when every
+// one of them passed, since asking an agent to judge output already known to be broken
+// costs a worker run and adds nothing to the repair.
 func (engine *ValidationEngine) RunValidations(stepID string, stepName string, validations []PlanningValidationSpec) ([]ValidationResult, error) {
 	var results []ValidationResult
+	var prompts []int
+	checksFailed := false
 
 	for i, validation := range validations {
-		result := ValidationResult{
-			Type:     validation.Type,
-			StepID:   stepID,
-			StepName: stepName,
-		}
-
-		var err error
-		switch validation.Type {
-		case "command":
-			result.Valid, result.Message, err = engine.runCommandValidation(validation)
-		case "file":
-			result.Valid, result.Message, err = engine.runFileValidation(validation)
-		case "directory":
-			result.Valid, result.Message, err = engine.runDirectoryValidation(validation)
-		case "prompt":
-			result.Valid, result.Message, err = engine.runPromptValidation(validation)
-		default:
-			err = fmt.Errorf("unknown validation type: %s", validation.Type)
+		if validation.Type == "prompt" {
+			prompts = append(prompts, i)
+			continue
 		}
-
+		result, err := engine.runValidation(stepID, stepName, validation)
 		if err != nil {
 			return nil, fmt.Errorf("validation[%d] failed: %w", i, err)
 		}
-
+		// Keep going after a failure so a repair pass sees every problem at once.
+		checksFailed = checksFailed || !result.Valid
 		results = append(results, result)
+	}
+	if checksFailed {
+		return results, nil
+	}
 
-		// If any validation fails, we can stop early (AND logic)
-		if !result.Valid {
-			break
+	for _, i := range prompts {
+		result, err := engine.runValidation(stepID, stepName, validations[i])
+		if err != nil {
+			return nil, fmt.Errorf("validation[%d] failed: %w", i, err)
 		}
+		results = append(results, result)
 	}
 
 	return results, nil
 }
 
+// runValidation executes one validation and reports its result.
+func (engine *ValidationEngine) runValidation(stepID string, stepName string, validation PlanningValidationSpec) (ValidationResult, error) {
+	result := ValidationResult{
+		Type:       validation.Type,
+		StepID:     stepID,
+		StepName:   stepName,
+		Validation: validation,
+	}
+
+	var err error
+	switch validation.Type {
+	case "command":
+		result.Valid, result.Message, err = engine.runCommandValidation(validation)
+	case "file":
+		result.Valid, result.Message, err = engine.runFileValidation(validation)
+	case "directory":
+		result.Valid, result.Message, err = engine.runDirectoryValidation(validation)
+	case "prompt":
+		result.Valid, result.Message, err = engine.runPromptValidation(stepID, validation)
+	default:
+		err = fmt.Errorf("unknown validation type: %s", validation.Type)
+	}
+	return result, err
+}
+
 // runCommandValidation executes a command validation.
 func (engine *ValidationEngine) runCommandValidation(validation PlanningValidationSpec) (bool, string, error) {
 	cmd := exec.Command("bash", "-lc", validation.Command)
@@ -101,7 +152,7 @@ func (engine *ValidationEngine) runCommandValidation(validation PlanningValidati
 	// Check stdout expectations
 	if validation.StdoutContains != "" {
 		if !strings.Contains(stdout, validation.StdoutContains) {
-			return false, fmt.Sprintf("stdout does not contain %q", validation.StdoutContains), nil
+			return false, fmt.Sprintf("stdout does not contain %q; stdout: %q", validation.StdoutContains, validationOutputExcerpt(stdout)), nil
 		}
 	}
 
@@ -116,13 +167,22 @@ func (engine *ValidationEngine) runCommandValidation(validation PlanningValidati
 			return false, "", fmt.Errorf("regex match failed: %w", err)
 		}
 		if !match {
-			return false, fmt.Sprintf("stdout does not match regex %q", validation.StdoutRegex), nil
+			return false, fmt.Sprintf("stdout does not match regex %q; stdout: %q", validation.StdoutRegex, validationOutputExcerpt(stdout)), nil
 		}
 	}
 
 	return true, "command validation passed", nil
 }
 
+// validationOutputExcerpt clips command or agent output quoted in a failure message.
+func validationOutputExcerpt(output string) string {
+	excerpt, truncated := truncateBytes(output, validationOutputLimit)
+	if truncated {
+		excerpt += "\n[truncated]"
+	}
+	return excerpt
+}
+
 // runFileValidation executes a file validation.
 func (engine *ValidationEngine) runFileValidation(validation PlanningValidationSpec) (bool, string, error) {
 	if hasGlobMeta(validation.Path) {
@@ -308,11 +368,9 @@ func matchFileRegex(path string, regexPattern string, displayPath string) (bool,
 	return true, "", nil
 }
 
-// runPromptValidation executes a prompt validation using an inline agent.
-func (engine *ValidationEngine) runPromptValidation(validation PlanningValidationSpec) (bool, string, error) {
-	// This would typically call out to an agent CLI, but for now we'll implement
-	// a basic structure that could be extended
-
+// runPromptValidation runs the validation role against the prompt, with the step's artifacts in
+// context, and checks its stdout. A failed or timed-out agent fails the validation.
+func (engine *ValidationEngine) runPromptValidation(stepID string, validation PlanningValidationSpec) (bool, string, error) {
 	var promptContent string
 	if validation.Inline != "" {
 		promptContent = validation.Inline
@@ -324,42 +382,151 @@ func (engine *ValidationEngine) runPromptValidation(validation PlanningValidatio
 		}
 		promptContent = string(content)
 	}
-	if promptContent == "" {
+	if strings.TrimSpace(promptContent) == "" {
 		return false, "", fmt.Errorf("prompt validation requires either inline or prompt_path")
 	}
+	if engine.agent == nil {
+		return false, "", fmt.Errorf("prompt validation requires a configured worker")
+	}
+	var stdoutRegex *regexp2.Regexp
+	if validation.StdoutRegex != "" {
+		regex, err := regexp2.Compile(validation.StdoutRegex, regexp2.RE2)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid regex: %w", err)
+		}
+		stdoutRegex = regex
+	}
 
-	// In a real implementation, this would call an agent CLI like:
-	// cmd := exec.Command("codex", "exec", "--role", validation.PromptRole, "--prompt", promptContent)
-	// cmd.Dir = engine.repoRoot
-	// var stdoutBuf bytes.Buffer
-	// cmd.Stdout = &stdoutBuf
-	// err := cmd.Run()
-	// stdout := strings.TrimSpace(stdoutBuf.String())
-
-	// For now, we'll simulate a successful prompt execution
-	stdout := "[SIMULATED PROMPT OUTPUT]"
+	artifacts, err := resolvePlanningArtifacts(engine.repoRoot, engine.agent.artifacts)
+	if err != nil {
+		return false, "", err
+	}
+	role := index.Role(strings.TrimSpace(validation.PromptRole))
+	if role == "" {
+		role = engine.agent.role
+	}
+	stdout, ok, message := engine.invokePromptValidation(stepID, role, promptValidationContent(promptContent, artifacts))
+	if !ok {
+		return false, message, nil
+	}
 
 	// Check stdout expectations
 	if validation.StdoutContains != "" {
 		if !strings.Contains(stdout, validation.StdoutContains) {
-			return false, fmt.Sprintf("prompt output does not contain %q", validation.StdoutContains), nil
+			return false, fmt.Sprintf("prompt output does not contain %q; output: %q", validation.StdoutContains, validationOutputExcerpt(stdout)), nil
 		}
 	}
 
-	if validation.StdoutRegex != "" {
-		regex, err := regexp2.Compile(validation.StdoutRegex, regexp2.RE2)
-		if err != nil {
-			return false, "", fmt.Errorf("invalid regex: %w", err)
-		}
-
-		match, err := regex.MatchString(stdout)
+	if stdoutRegex != nil {
+		match, err := stdoutRegex.MatchString(stdout)
 		if err != nil {
 			return false, "", fmt.Errorf("regex match failed: %w", err)
 		}
 		if !match {
-			return false, fmt.Sprintf("prompt output does not match regex %q", validation.StdoutRegex), nil
+			return false, fmt.Sprintf("prompt output does not match regex %q; output: %q", validation.StdoutRegex, validationOutputExcerpt(stdout)), nil
 		}
 	}
 
 	return true, "prompt validation passed", nil
 }
+
+// invokePromptValidation runs the worker command for a prompt validation and audits the run. It
+// returns the agent's stdout, or false with a failure message when the agent did not succeed.
+func (engine *ValidationEngine) invokePromptValidation(stepID string, role index.Role, prompt string) (string, bool, string) {
+	agent := engine.agent
+	warn := agent.warn
+	if warn == nil {
+		warn = func(string) {}
+	}
+	auditID := fmt.Sprintf("planning-%s", stepID)
+	timeout := agent.cfg.Timeouts.SecondsFor(string(role), config.StageKeyPlanning, stepID)
+	invoker, err := newPromptValidationInvoker(agent.cfg, engine.repoRoot, role, timeout, warn)
+	if err != nil {
+		return "", false, fmt.Sprintf("prompt validation agent unavailable: %v", err)
+	}
+
+	if agent.auditor != nil {
+		if err := agent.auditor.LogAgentInvoke(auditID, string(role), promptValidationAgentName, 1); err != nil {
+			warn(fmt.Sprintf("failed to log agent invoke for %s: %v", auditID, err))
+		}
+	}
+	stdout, err := invoker.Invoke(context.Background(), prompt)
+	status, exitCode := "success", 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(err, errWorkerCommandTimeout):
+		status, exitCode = "timeout", exitCodeForOutcome(0, true)
+	case errors.As(err, &exitErr):
+		status, exitCode = "failed", exitErr.ExitCode()
+	case err != nil:
+		status = "failed"
+	}
+	if agent.auditor != nil {
+		if err := agent.auditor.LogAgentOutcome(auditID, string(role), promptValidationAgentName, status, exitCode); err != nil {
+			warn(fmt.Sprintf("failed to log agent outcome for %s: %v", auditID, err))
+		}
+	}
+	if err != nil {
+		return "", false, fmt.Sprintf("prompt validation agent failed: %v", err)
+	}
+	return stdout, true, ""
+}
+
+// promptValidationContent appends the artifacts under review to a prompt validation.
+func promptValidationContent(prompt string, artifacts []string) string {
+	if len(artifacts) == 0 {
+		return prompt
+	}
+	var builder strings.Builder
+	builder.WriteString(strings.TrimSpace(prompt))
+	builder.WriteString("\n\n## Artifacts\n")
+	builder.WriteString("Review these files produced by the planning step (paths relative to the repository root):\n")
+	for _, artifact := range artifacts {
+		builder.WriteString("- ")
+		builder.WriteString(artifact)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// resolvePlanningArtifacts expands step output patterns into the sorted repo-relative files that exist.
+func resolvePlanningArtifacts(repoRoot string, patterns []string) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			dir := filepath.Join(repoRoot, filepath.FromSlash(pattern))
+			err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+				if walkErr != nil {
+					return walkErr
+				}
+				if entry.Type().IsRegular() {
+					if rel, err := repoRelativePath(repoRoot, path); err == nil {
+						seen[rel] = struct{}{}
+					}
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("walk artifacts %s: %w", pattern, err)
+			}
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(repoRoot, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("glob artifacts %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+				if rel, err := repoRelativePath(repoRoot, match); err == nil {
+					seen[rel] = struct{}{}
+				}
+			}
+		}
+	}
+	artifacts := make([]string, 0, len(seen))
+	for artifact := range seen {
+		artifacts = append(artifacts, artifact)
+	}
+	sort.Strings(artifacts)
+	return artifacts, nil
+}
@@ -2,15 +2,44 @@
 package run
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
 )
 
+// recordingAgentAuditor captures agent audit events for assertions.
+type recordingAgentAuditor struct {
+	events []string
+}
+
+func (auditor *recordingAgentAuditor) LogAgentInvoke(taskID string, role string, agent string, attempt int) error {
+	auditor.events = append(auditor.events, fmt.Sprintf("invoke %s %s %s %d", taskID, role, agent, attempt))
+	return nil
+}
+
+func (auditor *recordingAgentAuditor) LogAgentOutcome(taskID string, role string, agent string, status string, exitCode int) error {
+	auditor.events = append(auditor.events, fmt.Sprintf("outcome %s %s %s %s %d", taskID, role, agent, status, exitCode))
+	return nil
+}
+
+// newPromptValidationTestEngine returns an engine whose worker echoes the prompt followed by a verdict.
+func newPromptValidationTestEngine(repoRoot string, auditor AgentAuditor) *ValidationEngine {
+	cfg := config.Defaults()
+	cfg.Workers.Commands.Default = []string{"sh", "-c", `cat "$0"; echo "VERDICT: PASS"`, "{task_path}"}
+	return NewValidationEngine(repoRoot).withAgent(&validationAgent{
+		cfg:     cfg,
+		auditor: auditor,
+		role:    "architect",
+	})
+}
+
 func TestValidationEnginePromptValidation(t *testing.T) {
 	repoRoot := t.TempDir()
-	engine := NewValidationEngine(repoRoot)
+	engine := newPromptValidationTestEngine(repoRoot, nil)
 
 	tests := []struct {
 		name       string
@@ -34,7 +63,7 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 			validation: PlanningValidationSpec{
 				Type:           "prompt",
 				Inline:         "Check the system status",
-				StdoutContains: "SIMULATED",
+				StdoutContains: "VERDICT: PASS",
 			},
 			wantValid: true,
 			wantMsg:   "prompt validation passed",
@@ -54,7 +83,7 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 			validation: PlanningValidationSpec{
 				Type:        "prompt",
 				Inline:      "Generate report",
-				StdoutRegex: "SIMULATED.*OUTPUT",
+				StdoutRegex: "(?m)^VERDICT: (PASS|FAIL)$",
 			},
 			wantValid: true,
 			wantMsg:   "prompt validation passed",
@@ -83,11 +112,11 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 			wantMsg:   "prompt validation passed",
 		},
 		{
-			name: "file_based_prompt_with_stdout_validation",
+			name: "file_based_prompt_content_reaches_agent",
 			validation: PlanningValidationSpec{
 				Type:           "prompt",
 				PromptPath:     "prompts/analysis.md",
-				StdoutContains: "SIMULATED",
+				StdoutContains: "Perform analysis",
 			},
 			setupFiles: func(t *testing.T) {
 				promptPath := filepath.Join(repoRoot, "prompts", "analysis.md")
@@ -137,8 +166,8 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 			validation: PlanningValidationSpec{
 				Type:           "prompt",
 				Inline:         "Test",
-				StdoutContains: "SIMULATED",
-				StdoutRegex:    "OUTPUT",
+				StdoutContains: "VERDICT",
+				StdoutRegex:    "PASS",
 			},
 			wantValid: true,
 			wantMsg:   "prompt validation passed",
@@ -149,7 +178,7 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 				Type:           "prompt",
 				Inline:         "Test",
 				StdoutContains: "MISSING",
-				StdoutRegex:    "OUTPUT",
+				StdoutRegex:    "PASS",
 			},
 			wantValid: false,
 			wantMsg:   "does not contain",
@@ -159,7 +188,7 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 			validation: PlanningValidationSpec{
 				Type:           "prompt",
 				Inline:         "Test",
-				StdoutContains: "SIMULATED",
+				StdoutContains: "VERDICT",
 				StdoutRegex:    "\\d{10}",
 			},
 			wantValid: false,
@@ -173,7 +202,7 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 				tt.setupFiles(t)
 			}
 
-			ok, msg, err := engine.runPromptValidation(tt.validation)
+			ok, msg, err := engine.runPromptValidation("architecture-baseline", tt.validation)
 
 			if tt.wantErr {
 				if err == nil {
@@ -200,7 +229,7 @@ func TestValidationEnginePromptValidation(t *testing.T) {
 // TestPromptValidationFileContent verifies file content is read correctly
 func TestPromptValidationFileContent(t *testing.T) {
 	repoRoot := t.TempDir()
-	engine := NewValidationEngine(repoRoot)
+	engine := newPromptValidationTestEngine(repoRoot, nil)
 
 	t.Run("prompt_file_with_complex_content", func(t *testing.T) {
 		promptPath := filepath.Join(repoRoot, "prompts", "complex.md")
@@ -218,9 +247,10 @@ The system uses microservices architecture with event-driven communication.
 `
 		writeTestFile(t, promptPath, complexContent)
 
-		ok, msg, err := engine.runPromptValidation(PlanningValidationSpec{
-			Type:       "prompt",
-			PromptPath: "prompts/complex.md",
+		ok, msg, err := engine.runPromptValidation("step", PlanningValidationSpec{
+			Type:           "prompt",
+			PromptPath:     "prompts/complex.md",
+			StdoutContains: "event-driven communication",
 		})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -235,9 +265,10 @@ The system uses microservices architecture with event-driven communication.
 		specialContent := "Prompt with special chars: $VAR, @user, #tag, 100% complete"
 		writeTestFile(t, promptPath, specialContent)
 
-		ok, msg, err := engine.runPromptValidation(PlanningValidationSpec{
-			Type:       "prompt",
-			PromptPath: "prompts/special.md",
+		ok, msg, err := engine.runPromptValidation("step", PlanningValidationSpec{
+			Type:           "prompt",
+			PromptPath:     "prompts/special.md",
+			StdoutContains: specialContent,
 		})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -251,7 +282,7 @@ The system uses microservices architecture with event-driven communication.
 		promptPath := filepath.Join(repoRoot, "prompts", "empty.md")
 		writeTestFile(t, promptPath, "")
 
-		_, _, err := engine.runPromptValidation(PlanningValidationSpec{
+		_, _, err := engine.runPromptValidation("step", PlanningValidationSpec{
 			Type:       "prompt",
 			PromptPath: "prompts/empty.md",
 		})
@@ -268,13 +299,13 @@ The system uses microservices architecture with event-driven communication.
 // TestPromptValidationPathResolution tests path handling
 func TestPromptValidationPathResolution(t *testing.T) {
 	repoRoot := t.TempDir()
-	engine := NewValidationEngine(repoRoot)
+	engine := newPromptValidationTestEngine(repoRoot, nil)
 
 	t.Run("nested_directory_structure", func(t *testing.T) {
 		promptPath := filepath.Join(repoRoot, "deep", "nested", "path", "prompt.md")
 		writeTestFile(t, promptPath, "Deep prompt")
 
-		ok, msg, err := engine.runPromptValidation(PlanningValidationSpec{
+		ok, msg, err := engine.runPromptValidation("step", PlanningValidationSpec{
 			Type:       "prompt",
 			PromptPath: "deep/nested/path/prompt.md",
 		})
@@ -290,7 +321,7 @@ func TestPromptValidationPathResolution(t *testing.T) {
 		promptPath := filepath.Join(repoRoot, "prompts with spaces", "my prompt.md")
 		writeTestFile(t, promptPath, "Prompt content")
 
-		ok, msg, err := engine.runPromptValidation(PlanningValidationSpec{
+		ok, msg, err := engine.runPromptValidation("step", PlanningValidationSpec{
 			Type:       "prompt",
 			PromptPath: "prompts with spaces/my prompt.md",
 		})
@@ -316,7 +347,7 @@ func TestPromptValidationPathResolution(t *testing.T) {
 		}
 		defer os.Chmod(promptPath, 0644) // Restore for cleanup
 
-		_, _, err := engine.runPromptValidation(PlanningValidationSpec{
+		_, _, err := engine.runPromptValidation("step", PlanningValidationSpec{
 			Type:       "prompt",
 			PromptPath: "prompts/unreadable.md",
 		})
@@ -326,37 +357,139 @@ func TestPromptValidationPathResolution(t *testing.T) {
 	})
 }
 
-// TestPromptValidationSimulatedOutput validates the simulated behavior
-func TestPromptValidationSimulatedOutput(t *testing.T) {
-	repoRoot := t.TempDir()
-	engine := NewValidationEngine(repoRoot)
+// TestPromptValidationAgent covers role selection, artifact context, auditing, and agent failures.
+func TestPromptValidationAgent(t *testing.T) {
+	t.Run("requires_configured_worker", func(t *testing.T) {
+		engine := NewValidationEngine(t.TempDir())
+		if _, _, err := engine.runPromptValidation("step", PlanningValidationSpec{Type: "prompt", Inline: "check"}); err == nil {
+			t.Fatalf("expected error without a configured worker")
+		}
+	})
 
-	t.Run("simulated_output_contains_expected_string", func(t *testing.T) {
-		// The current implementation returns "[SIMULATED PROMPT OUTPUT]"
-		ok, _, err := engine.runPromptValidation(PlanningValidationSpec{
-			Type:           "prompt",
-			Inline:         "test",
-			StdoutContains: "[SIMULATED PROMPT OUTPUT]",
+	t.Run("lists_step_artifacts_in_prompt", func(t *testing.T) {
+		repoRoot := t.TempDir()
+		writeTestFile(t, filepath.Join(repoRoot, "_governator", "docs", "arch-arc42.md"), "# arc42")
+		writeTestFile(t, filepath.Join(repoRoot, "_governator", "docs", "adr", "0001-use-go.md"), "# ADR")
+		engine := newPromptValidationTestEngine(repoRoot, nil)
+		engine.agent.artifacts = []string{"_governator/docs/arch-*.md", "_governator/docs/adr/"}
+
+		ok, msg, err := engine.runPromptValidation("architecture-baseline", PlanningValidationSpec{
+			Type:        "prompt",
+			Inline:      "Is the architecture coherent?",
+			StdoutRegex: `(?s)- _governator/docs/adr/0001-use-go\.md.*- _governator/docs/arch-arc42\.md`,
 		})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 		if !ok {
-			t.Fatalf("expected simulated output to contain marker string")
+			t.Fatalf("expected artifacts in prompt, got message: %q", msg)
 		}
 	})
 
-	t.Run("simulated_output_matches_regex", func(t *testing.T) {
-		ok, _, err := engine.runPromptValidation(PlanningValidationSpec{
-			Type:        "prompt",
-			Inline:      "test",
-			StdoutRegex: "\\[SIMULATED.*OUTPUT\\]",
+	t.Run("uses_validation_role_and_audits", func(t *testing.T) {
+		repoRoot := t.TempDir()
+		auditor := &recordingAgentAuditor{}
+		engine := newPromptValidationTestEngine(repoRoot, auditor)
+		engine.agent.cfg.Workers.Commands.Roles = map[string][]string{
+			"reviewer": {"sh", "-c", `echo "reviewed by {role}"`, "{task_path}"},
+		}
+
+		ok, msg, err := engine.runPromptValidation("architecture-baseline", PlanningValidationSpec{
+			Type:           "prompt",
+			Inline:         "Review the docs",
+			PromptRole:     "reviewer",
+			StdoutContains: "reviewed by reviewer",
 		})
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 		if !ok {
-			t.Fatalf("expected simulated output to match regex")
+			t.Fatalf("expected reviewer command to run, got message: %q", msg)
+		}
+		want := []string{
+			"invoke planning-architecture-baseline reviewer validator 1",
+			"outcome planning-architecture-baseline reviewer validator success 0",
+		}
+		if strings.Join(auditor.events, "\n") != strings.Join(want, "\n") {
+			t.Fatalf("audit events = %v, want %v", auditor.events, want)
 		}
 	})
+
+	t.Run("failed_agent_fails_validation", func(t *testing.T) {
+		repoRoot := t.TempDir()
+		auditor := &recordingAgentAuditor{}
+		engine := newPromptValidationTestEngine(repoRoot, auditor)
+		engine.agent.cfg.Workers.Commands.Default = []string{"sh", "-c", "exit 3", "{task_path}"}
+
+		ok, msg, err := engine.runPromptValidation("step", PlanningValidationSpec{Type: "prompt", Inline: "check"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok || !strings.Contains(msg, "prompt validation agent failed") {
+			t.Fatalf("valid = %v, message = %q, want agent failure", ok, msg)
+		}
+		if len(auditor.events) != 2 || auditor.events[1] != "outcome planning-step architect validator failed 3" {
+			t.Fatalf("audit events = %v", auditor.events)
+		}
+	})
+
+	t.Run("timed_out_agent_fails_validation", func(t *testing.T) {
+		repoRoot := t.TempDir()
+		auditor := &recordingAgentAuditor{}
+		engine := newPromptValidationTestEngine(repoRoot, auditor)
+		engine.agent.cfg.Workers.Commands.Default = []string{"sh", "-c", "exec sleep 5", "{task_path}"}
+		engine.agent.cfg.Timeouts.PlanningSteps = map[string]int{"step": 1}
+
+		ok, msg, err := engine.runPromptValidation("step", PlanningValidationSpec{Type: "prompt", Inline: "check"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok || !strings.Contains(msg, "timed out") {
+			t.Fatalf("valid = %v, message = %q, want timeout", ok, msg)
+		}
+		if len(auditor.events) != 2 || auditor.events[1] != "outcome planning-step architect validator timeout -1" {
+			t.Fatalf("audit events = %v", auditor.events)
+		}
+	})
+}
+
+// TestRunValidationsSkipsPromptsAfterFailedChecks runs every non-prompt check but no prompt
+// check once one of them fails.
+func TestRunValidationsSkipsPromptsAfterFailedChecks(t *testing.T) {
+	repoRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(repoRoot, "present.md"), "# present")
+	auditor := &recordingAgentAuditor{}
+	engine := newPromptValidationTestEngine(repoRoot, auditor)
+	validations := []PlanningValidationSpec{
+		{Type: "prompt", Inline: "Is the design coherent?"},
+		{Type: "file", Path: "missing.md"},
+		{Type: "file", Path: "absent.md"},
+		{Type: "file", Path: "present.md"},
+	}
+
+	results, err := engine.RunValidations("architecture-baseline", "Architecture", validations)
+	if err != nil {
+		t.Fatalf("RunValidations: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("results = %d, want the 3 file checks", len(results))
+	}
+	for _, result := range results {
+		if result.Type != "file" {
+			t.Fatalf("unexpected %s result after failed checks", result.Type)
+		}
+	}
+	if len(auditor.events) != 0 {
+		t.Fatalf("prompt validation ran after failed checks: %v", auditor.events)
+	}
+
+	writeTestFile(t, filepath.Join(repoRoot, "missing.md"), "# fixed")
+	writeTestFile(t, filepath.Join(repoRoot, "absent.md"), "# fixed")
+	results, err = engine.RunValidations("architecture-baseline", "Architecture", validations)
+	if err != nil {
+		t.Fatalf("RunValidations after fix: %v", err)
+	}
+	if len(results) != 4 || results[3].Type != "prompt" || !results[3].Valid {
+		t.Fatalf("results = %+v, want the prompt check to run last and pass", results)
+	}
 }
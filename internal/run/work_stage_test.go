@@ -72,7 +72,7 @@ func TestExecuteWorkStageHappyPath(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	opts := Options{Stdout: &stdout, Stderr: &stderr}
 	inFlight := inflight.Set{}
-	result, err := ExecuteWorkStage(repoRoot, &idx, cfg, caps, inFlight, nil, nil, nil, opts)
+	result, err := ExecuteWorkStage(repoRoot, &idx, cfg, caps, inFlight, nil, nil, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("execute work stage: %v", err)
 	}
@@ -92,7 +92,7 @@ func TestExecuteWorkStageHappyPath(t *testing.T) {
 	worktreePath := result.WorktreePaths["T-001"]
 	waitForExitStatus(t, worktreePath, "T-001", roles.StageWork)
 
-	result, err = ExecuteWorkStage(repoRoot, &idx, cfg, caps, inFlight, nil, nil, nil, opts)
+	result, err = ExecuteWorkStage(repoRoot, &idx, cfg, caps, inFlight, nil, nil, nil, nil, opts)
 	if err != nil {
 		t.Fatalf("execute work stage collect: %v", err)
 	}
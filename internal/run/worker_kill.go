@@ -1,4 +1,4 @@
-// Package run provides helpers for terminating timed-out worker processes.
+// Package run provides helpers for terminating timed-out, stalled, or stopped worker processes.
 package run
 
 import (
@@ -12,16 +12,32 @@ import (
 )
 
 // killWorkerProcess sends SIGKILL to the agent pid when available, falling back to the wrapper pid.
-func killWorkerProcess(wrapperPID int, workerStateDir string, warn func(string)) {
+// The reason names why the worker is being killed and prefixes any warnings. Killed workers are
+// never collected, so their logs are redacted here.
+func killWorkerProcess(repoRoot string, wrapperPID int, workerStateDir string, reason string, warn func(string)) {
+	if strings.TrimSpace(reason) != "" {
+		inner := warn
+		warn = func(message string) {
+			emitKillWarning(inner, fmt.Sprintf("%s kill: %s", reason, message))
+		}
+	}
 	if pid, found := resolveAgentPID(workerStateDir, warn); found {
 		// Let the wrapper observe the child exit and write exit.json.
 		killPID(pid, warn)
+		redactWorkerLogs(repoRoot, workerStateDir, warn)
 		return
 	}
 	if strings.TrimSpace(workerStateDir) != "" {
 		emitKillWarning(warn, fmt.Sprintf("agent pidfile missing; killing wrapper pid %d", wrapperPID))
 	}
 	killPID(wrapperPID, warn)
+	redactWorkerLogs(repoRoot, workerStateDir, warn)
+	// A killed wrapper cannot remove the worker cgroup itself.
+	if strings.TrimSpace(workerStateDir) != "" {
+		if err := worker.ReleaseResources(workerStateDir); err != nil {
+			emitKillWarning(warn, fmt.Sprintf("failed to release worker resources: %v", err))
+		}
+	}
 }
 
 // resolveAgentPID polls briefly for an agent pidfile to avoid killing the wrapper prematurely.
@@ -0,0 +1,37 @@
+// Package run provides secret redaction for collected worker logs.
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// collectWorkerExit reads a worker's exit status and, once the worker has finished, redacts
+// secrets from its logs. Every collection path goes through here, so failed and rate-limited
+// runs leave redacted logs just like successful ones.
+func collectWorkerExit(repoRoot string, workerStateDir string, taskID string, stage roles.Stage, warn func(string)) (worker.ExitStatus, bool, error) {
+	exitStatus, finished, err := worker.ReadExitStatus(workerStateDir, taskID, stage)
+	if err != nil || !finished {
+		return exitStatus, finished, err
+	}
+	redactWorkerLogs(repoRoot, workerStateDir, warn)
+	return exitStatus, true, nil
+}
+
+// redactWorkerLogs replaces secret values in the worker stdout and stderr logs, warning
+// instead of failing so an unreadable secrets file never strands a worker.
+func redactWorkerLogs(repoRoot string, workerStateDir string, warn func(string)) {
+	if strings.TrimSpace(repoRoot) == "" || strings.TrimSpace(workerStateDir) == "" {
+		return
+	}
+	redactor, err := worker.LoadRedactor(repoRoot)
+	if err == nil {
+		err = redactor.RedactWorkerLogs(workerStateDir)
+	}
+	if err != nil {
+		emitKillWarning(warn, fmt.Sprintf("failed to redact worker logs in %s: %v", workerStateDir, err))
+	}
+}
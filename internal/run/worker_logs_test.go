@@ -0,0 +1,71 @@
+// Tests for worker log redaction on collection.
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/worker"
+)
+
+// TestCollectWorkerExitRedactsFailedRunLogs keeps secrets out of logs left by a failed worker.
+func TestCollectWorkerExitRedactsFailedRunLogs(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+
+	secret := "pg-pass-8c41f"
+	secretsPath := worker.SecretsPath(repoRoot)
+	if err := os.MkdirAll(filepath.Dir(secretsPath), 0o755); err != nil {
+		t.Fatalf("mkdir local state: %v", err)
+	}
+	if err := os.WriteFile(secretsPath, []byte("DATABASE_PASSWORD="+secret+"\n"), 0o600); err != nil {
+		t.Fatalf("write secrets: %v", err)
+	}
+
+	workerStateDir := filepath.Join(repoRoot, "_governator", "_local-state", "worker-test")
+	if err := os.MkdirAll(workerStateDir, 0o755); err != nil {
+		t.Fatalf("mkdir worker state: %v", err)
+	}
+	stderrPath := filepath.Join(workerStateDir, "stderr.log")
+	if err := os.WriteFile(stderrPath, []byte("auth failed for "+secret+"\n"), 0o644); err != nil {
+		t.Fatalf("write stderr log: %v", err)
+	}
+
+	var warnings []string
+	warn := func(message string) { warnings = append(warnings, message) }
+
+	if _, finished, err := collectWorkerExit(repoRoot, workerStateDir, "T-001", roles.StageWork, warn); err != nil || finished {
+		t.Fatalf("collect before exit: finished=%v err=%v", finished, err)
+	}
+	content, err := os.ReadFile(stderrPath)
+	if err != nil {
+		t.Fatalf("read stderr log: %v", err)
+	}
+	if !strings.Contains(string(content), secret) {
+		t.Fatalf("running worker logs should be left alone: %s", content)
+	}
+
+	if err := os.WriteFile(filepath.Join(workerStateDir, "exit.json"), []byte(`{"exit_code":1,"finished_at":"2026-01-02T03:04:05Z","pid":123}`), 0o644); err != nil {
+		t.Fatalf("write exit status: %v", err)
+	}
+	exitStatus, finished, err := collectWorkerExit(repoRoot, workerStateDir, "T-001", roles.StageWork, warn)
+	if err != nil || !finished {
+		t.Fatalf("collect after exit: finished=%v err=%v", finished, err)
+	}
+	if exitStatus.ExitCode != 1 {
+		t.Fatalf("exit code = %d, want 1", exitStatus.ExitCode)
+	}
+	content, err = os.ReadFile(stderrPath)
+	if err != nil {
+		t.Fatalf("read stderr log: %v", err)
+	}
+	if strings.Contains(string(content), secret) || !strings.Contains(string(content), "[REDACTED:DATABASE_PASSWORD]") {
+		t.Fatalf("stderr log not redacted: %s", content)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
@@ -33,12 +33,20 @@ type workstreamStep struct {
 	promptPath  string
 	role        index.Role
 	validations []PlanningValidationSpec
+	inputs      []string
+	outputs     []string
+	dependsOn   []string
+	concurrent  bool // unordered relative to another step, so it runs in its own worktree
 	actions     workstreamStepActions
 	nextStepID  string
 }
 
-// workstreamID returns the stable workstream identifier for the step.
+// workstreamID returns the stable workstream identifier for the step. Serial steps share the
+// planning workstream; steps that may run concurrently get a workstream of their own.
 func (step workstreamStep) workstreamID() string {
+	if step.concurrent {
+		return planningStepWorkstreamID(step)
+	}
 	return planningIndexTaskID
 }
 
@@ -0,0 +1,89 @@
+// Package run provides worktree placement and quota checks for task dispatch.
+package run
+
+import (
+	"fmt"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/worktree"
+)
+
+// bytesPerMB converts worktrees.max_total_mb to bytes.
+const bytesPerMB = 1 << 20
+
+// worktreeQuota tracks worktree count and disk usage for one dispatch tick. Existing worktrees
+// are measured once, on the first check, and worktrees created during the tick are added as
+// they appear.
+type worktreeQuota struct {
+	manager worktree.Manager
+	cfg     config.Config
+	loaded  bool
+	count   int
+	total   int64
+}
+
+// newWorktreeQuota returns a quota tracker for the current tick.
+func newWorktreeQuota(manager worktree.Manager, cfg config.Config) *worktreeQuota {
+	return &worktreeQuota{manager: manager, cfg: cfg}
+}
+
+// reached reports why no new task worktree may be created, or "" when the configured count
+// and size limits leave room for another.
+func (quota *worktreeQuota) reached() (string, error) {
+	limits := quota.cfg.Worktrees
+	if limits.MaxCount <= 0 && limits.MaxTotalMB <= 0 {
+		return "", nil
+	}
+	if err := quota.load(); err != nil {
+		return "", err
+	}
+	if limits.MaxCount > 0 && quota.count >= limits.MaxCount {
+		return fmt.Sprintf("%d worktrees exist, worktrees.max_count is %d", quota.count, limits.MaxCount), nil
+	}
+	if limits.MaxTotalMB > 0 && quota.total >= int64(limits.MaxTotalMB)*bytesPerMB {
+		return fmt.Sprintf("worktrees use %d MiB, worktrees.max_total_mb is %d", quota.total/bytesPerMB, limits.MaxTotalMB), nil
+	}
+	return "", nil
+}
+
+// add counts a worktree created after the quota was measured.
+func (quota *worktreeQuota) add(path string) error {
+	if !quota.loaded {
+		return nil
+	}
+	quota.count++
+	if quota.cfg.Worktrees.MaxTotalMB <= 0 {
+		return nil
+	}
+	size, err := worktree.DiskUsage(path)
+	if err != nil {
+		return err
+	}
+	quota.total += size
+	return nil
+}
+
+// load measures the existing worktrees once per tick.
+func (quota *worktreeQuota) load() error {
+	if quota.loaded {
+		return nil
+	}
+	infos, err := quota.manager.List()
+	if err != nil {
+		return err
+	}
+	var total int64
+	if quota.cfg.Worktrees.MaxTotalMB > 0 {
+		for _, info := range infos {
+			size, err := worktree.DiskUsage(info.Path)
+			if err != nil {
+				return err
+			}
+			total += size
+		}
+	}
+	quota.count = len(infos)
+	quota.total = total
+	quota.loaded = true
+	return nil
+}
@@ -0,0 +1,96 @@
+// Tests for worktree quota checks on task dispatch.
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/worktree"
+)
+
+// TestWorktreeQuotaReached covers the count and size limits on new task worktrees.
+func TestWorktreeQuotaReached(t *testing.T) {
+	repoRoot := t.TempDir()
+	manager, err := worktree.NewManager(repoRoot)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	writeQuotaWorktree(t, repoRoot, "T-001", 3*bytesPerMB)
+
+	tests := []struct {
+		name      string
+		worktrees config.WorktreesConfig
+		want      string
+	}{
+		{name: "unlimited", worktrees: config.WorktreesConfig{}},
+		{name: "count_room", worktrees: config.WorktreesConfig{MaxCount: 2}},
+		{name: "count_reached", worktrees: config.WorktreesConfig{MaxCount: 1}, want: "worktrees.max_count is 1"},
+		{name: "size_room", worktrees: config.WorktreesConfig{MaxTotalMB: 4}},
+		{name: "size_reached", worktrees: config.WorktreesConfig{MaxTotalMB: 3}, want: "worktrees.max_total_mb is 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := newWorktreeQuota(manager, config.Config{Worktrees: tt.worktrees}).reached()
+			if err != nil {
+				t.Fatalf("quota reached: %v", err)
+			}
+			if tt.want == "" && reason != "" {
+				t.Fatalf("reason = %q, want none", reason)
+			}
+			if tt.want != "" && !strings.Contains(reason, tt.want) {
+				t.Fatalf("reason = %q, want it to mention %q", reason, tt.want)
+			}
+		})
+	}
+}
+
+// TestWorktreeQuotaCountsWorktreesAddedDuringTick verifies worktrees created after the quota
+// was measured count toward it without re-measuring the existing ones.
+func TestWorktreeQuotaCountsWorktreesAddedDuringTick(t *testing.T) {
+	repoRoot := t.TempDir()
+	manager, err := worktree.NewManager(repoRoot)
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	writeQuotaWorktree(t, repoRoot, "T-001", bytesPerMB)
+
+	quota := newWorktreeQuota(manager, config.Config{Worktrees: config.WorktreesConfig{MaxCount: 3, MaxTotalMB: 4}})
+	if reason, err := quota.reached(); err != nil || reason != "" {
+		t.Fatalf("reached = %q, %v; want room", reason, err)
+	}
+	writeQuotaWorktree(t, repoRoot, "T-002", 3*bytesPerMB)
+	if err := quota.add(filepath.Join(repoRoot, "_governator", "_local-state", "task-T-002")); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	reason, err := quota.reached()
+	if err != nil {
+		t.Fatalf("quota reached: %v", err)
+	}
+	if !strings.Contains(reason, "worktrees.max_total_mb is 4") {
+		t.Fatalf("reason = %q, want the size limit", reason)
+	}
+}
+
+// writeQuotaWorktree records a fake task worktree holding size bytes.
+func writeQuotaWorktree(t *testing.T, repoRoot string, taskID string, size int) {
+	t.Helper()
+	localState := filepath.Join(repoRoot, "_governator", "_local-state")
+	worktreePath := filepath.Join(localState, "task-"+taskID)
+	if err := os.MkdirAll(worktreePath, 0o755); err != nil {
+		t.Fatalf("create worktree dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "data.bin"), make([]byte, size), 0o644); err != nil {
+		t.Fatalf("write worktree file: %v", err)
+	}
+	metaDir := filepath.Join(localState, "meta")
+	if err := os.MkdirAll(metaDir, 0o755); err != nil {
+		t.Fatalf("create meta dir: %v", err)
+	}
+	meta := `{"worktree_rel_path": "_governator/_local-state/task-` + taskID + `"}`
+	if err := os.WriteFile(filepath.Join(metaDir, taskID+".json"), []byte(meta), 0o644); err != nil {
+		t.Fatalf("write metadata: %v", err)
+	}
+}
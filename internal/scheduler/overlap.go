@@ -0,0 +1,101 @@
+// Package scheduler provides overlap matching for file paths and globs.
+package scheduler
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// globMeta lists the characters that make an overlap entry a glob pattern.
+const globMeta = "*?["
+
+// NormalizeOverlap cleans overlap entries into slash-separated, repo-relative form and
+// returns them sorted without duplicates or empty entries.
+func NormalizeOverlap(entries []string) []string {
+	seen := make(map[string]struct{}, len(entries))
+	normalized := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(strings.ReplaceAll(entry, "\\", "/"))
+		if entry == "" {
+			continue
+		}
+		entry = strings.TrimPrefix(path.Clean(entry), "/")
+		if entry == "." || entry == "" {
+			continue
+		}
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		seen[entry] = struct{}{}
+		normalized = append(normalized, entry)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// OverlapEntriesConflict reports whether two overlap entries may touch the same files.
+// Entries are plain tags, paths, or globs. Plain entries conflict when equal or when one
+// is a directory containing the other. A glob conflicts with a plain path it matches or
+// with a directory above it. Two globs conflict when their literal prefixes nest, which
+// errs on the side of serializing tasks.
+func OverlapEntriesConflict(left string, right string) bool {
+	if left == "" || right == "" {
+		return false
+	}
+	if left == right {
+		return true
+	}
+	leftGlob := strings.ContainsAny(left, globMeta)
+	rightGlob := strings.ContainsAny(right, globMeta)
+	switch {
+	case !leftGlob && !rightGlob:
+		return pathContains(left, right) || pathContains(right, left)
+	case leftGlob && !rightGlob:
+		return globOverlapsPath(left, right)
+	case !leftGlob && rightGlob:
+		return globOverlapsPath(right, left)
+	default:
+		leftPrefix, rightPrefix := literalPrefix(left), literalPrefix(right)
+		return strings.HasPrefix(leftPrefix, rightPrefix) || strings.HasPrefix(rightPrefix, leftPrefix)
+	}
+}
+
+// pathContains reports whether child lies beneath the directory dir.
+func pathContains(dir string, child string) bool {
+	return strings.HasPrefix(child, dir+"/")
+}
+
+// globOverlapsPath reports whether a glob matches a path or sits beneath it. Patterns
+// using "**" fall back to comparing the literal prefix.
+func globOverlapsPath(pattern string, target string) bool {
+	prefix := literalPrefix(pattern)
+	if strings.HasPrefix(prefix, target+"/") {
+		return true
+	}
+	if strings.Contains(pattern, "**") {
+		return strings.HasPrefix(target, prefix)
+	}
+	matched, err := path.Match(pattern, target)
+	if err != nil {
+		return strings.HasPrefix(target, prefix)
+	}
+	if matched {
+		return true
+	}
+	// A glob naming a directory covers the files inside it.
+	for dir := path.Dir(target); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if matched, _ := path.Match(pattern, dir); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// literalPrefix returns the part of a glob before its first wildcard.
+func literalPrefix(pattern string) string {
+	if index := strings.IndexAny(pattern, globMeta); index >= 0 {
+		return pattern[:index]
+	}
+	return pattern
+}
@@ -0,0 +1,66 @@
+// Package scheduler provides tests for overlap path and glob matching.
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/index"
+)
+
+// TestOverlapEntriesConflict covers tags, nested paths, and globs.
+func TestOverlapEntriesConflict(t *testing.T) {
+	tests := []struct {
+		left  string
+		right string
+		want  bool
+	}{
+		{left: "db", right: "db", want: true},
+		{left: "db", right: "api", want: false},
+		{left: "internal/run", right: "internal/run/merge.go", want: true},
+		{left: "internal/run", right: "internal/runner/main.go", want: false},
+		{left: "internal/run/*.go", right: "internal/run/merge.go", want: true},
+		{left: "internal/run/*.go", right: "internal/run/sub/merge.go", want: false},
+		{left: "internal/run/*.go", right: "internal", want: true},
+		{left: "internal/*", right: "internal/run/merge.go", want: true},
+		{left: "docs/**/*.md", right: "docs/guide/setup.md", want: true},
+		{left: "docs/**/*.md", right: "README.md", want: false},
+		{left: "internal/run/*_test.go", right: "internal/run/*.go", want: true},
+		{left: "internal/run/*.go", right: "internal/scheduler/*.go", want: false},
+		{left: "", right: "db", want: false},
+	}
+	for _, test := range tests {
+		if got := OverlapEntriesConflict(test.left, test.right); got != test.want {
+			t.Fatalf("OverlapEntriesConflict(%q, %q) = %v, want %v", test.left, test.right, got, test.want)
+		}
+		if got := OverlapEntriesConflict(test.right, test.left); got != test.want {
+			t.Fatalf("OverlapEntriesConflict(%q, %q) = %v, want %v", test.right, test.left, got, test.want)
+		}
+	}
+}
+
+// TestNormalizeOverlap cleans, dedupes, and sorts entries.
+func TestNormalizeOverlap(t *testing.T) {
+	got := NormalizeOverlap([]string{" ./internal/run/ ", "docs\\guide.md", "", ".", "internal/run", "/README.md"})
+	want := []string{"README.md", "docs/guide.md", "internal/run"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NormalizeOverlap = %#v, want %#v", got, want)
+	}
+}
+
+// TestRouteOrderedTasksAroundSkipsBusyPaths keeps tasks away from paths held by in-flight work.
+func TestRouteOrderedTasksAroundSkipsBusyPaths(t *testing.T) {
+	ordered := []index.Task{
+		{ID: "task-a", Kind: index.TaskKindExecution, Role: "worker", Overlap: []string{"internal/run/*.go"}},
+		{ID: "task-b", Kind: index.TaskKindExecution, Role: "worker", Overlap: []string{"docs"}},
+		{ID: "task-c", Kind: index.TaskKindExecution, Role: "worker"},
+	}
+	result := RouteOrderedTasksAround(ordered, RoleCaps{Global: 3, DefaultRole: 3}, []string{"internal/run/merge.go"})
+
+	if got := taskIDs(result.Selected); !reflect.DeepEqual(got, []string{"task-b", "task-c"}) {
+		t.Fatalf("selected = %v, want [task-b task-c]", got)
+	}
+	if result.Decisions[0].Selected || result.Decisions[0].Reason != reasonOverlapConflict {
+		t.Fatalf("decision for task-a = %+v, want overlap conflict", result.Decisions[0])
+	}
+}
@@ -35,6 +35,12 @@ func RouteEligibleTasks(idx index.Index, caps RoleCaps, inFlight map[string]stru
 
 // RouteOrderedTasks applies caps to ordered tasks and returns routing decisions.
 func RouteOrderedTasks(ordered []index.Task, caps RoleCaps) RoutingResult {
+	return RouteOrderedTasksAround(ordered, caps, nil)
+}
+
+// RouteOrderedTasksAround routes ordered tasks like RouteOrderedTasks while also skipping
+// tasks whose overlap conflicts with busy, the overlap held by tasks already in flight.
+func RouteOrderedTasksAround(ordered []index.Task, caps RoleCaps, busy []string) RoutingResult {
 	if caps.Global <= 0 || len(ordered) == 0 {
 		return RoutingResult{}
 	}
@@ -44,7 +50,7 @@ func RouteOrderedTasks(ordered []index.Task, caps RoleCaps) RoutingResult {
 		Selected:  make([]index.Task, 0, min(caps.Global, len(ordered))),
 	}
 	usage := map[index.Role]int{}
-	activeOverlap := map[string]struct{}{}
+	activeOverlap := append([]string{}, busy...)
 	for _, task := range ordered {
 		if len(result.Selected) >= caps.Global {
 			break
@@ -76,7 +82,7 @@ func RouteOrderedTasks(ordered []index.Task, caps RoleCaps) RoutingResult {
 		}
 		usage[task.Role]++
 		result.Selected = append(result.Selected, task)
-		recordOverlap(task, activeOverlap)
+		activeOverlap = recordOverlap(task, activeOverlap)
 		result.Decisions = append(result.Decisions, RoutingDecision{
 			Task:     task,
 			Selected: true,
@@ -86,28 +92,25 @@ func RouteOrderedTasks(ordered []index.Task, caps RoleCaps) RoutingResult {
 	return result
 }
 
-// overlapConflict reports whether the task shares any overlap tag with active tasks.
-func overlapConflict(task index.Task, activeOverlap map[string]struct{}) bool {
-	if len(task.Overlap) == 0 {
-		return false
-	}
+// overlapConflict reports whether any task overlap entry conflicts with an active entry.
+func overlapConflict(task index.Task, activeOverlap []string) bool {
 	for _, overlap := range task.Overlap {
-		if overlap == "" {
-			continue
-		}
-		if _, ok := activeOverlap[overlap]; ok {
-			return true
+		for _, active := range activeOverlap {
+			if OverlapEntriesConflict(overlap, active) {
+				return true
+			}
 		}
 	}
 	return false
 }
 
-// recordOverlap adds task overlap tags to the active overlap set.
-func recordOverlap(task index.Task, activeOverlap map[string]struct{}) {
+// recordOverlap appends task overlap entries to the active overlap list.
+func recordOverlap(task index.Task, activeOverlap []string) []string {
 	for _, overlap := range task.Overlap {
 		if overlap == "" {
 			continue
 		}
-		activeOverlap[overlap] = struct{}{}
+		activeOverlap = append(activeOverlap, overlap)
 	}
+	return activeOverlap
 }
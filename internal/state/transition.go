@@ -27,6 +27,8 @@ const (
 	TaskStateConflict TaskState = "conflict"
 	// TaskStateResolved indicates a previously conflicted task has been resolved.
 	TaskStateResolved TaskState = "resolved"
+	// TaskStateReverted indicates a merged task has been undone on the base branch.
+	TaskStateReverted TaskState = "reverted"
 )
 
 // allowedTransitions defines the permitted lifecycle state changes.
@@ -43,21 +45,26 @@ var allowedTransitions = map[TaskState]map[TaskState]struct{}{
 		TaskStateBlocked: {},
 	},
 	TaskStateTested: {
-		TaskStateReviewed: {},
-		TaskStateConflict: {},
-		TaskStateTriaged:  {},
-		TaskStateBlocked:  {},
+		TaskStateImplemented: {},
+		TaskStateReviewed:    {},
+		TaskStateConflict:    {},
+		TaskStateTriaged:     {},
+		TaskStateBlocked:     {},
 	},
 	TaskStateReviewed: {
-		TaskStateMergeable: {},
-		TaskStateBlocked:   {},
+		TaskStateImplemented: {},
+		TaskStateMergeable:   {},
+		TaskStateBlocked:     {},
 	},
 	TaskStateMergeable: {
-		TaskStateMerged:   {},
-		TaskStateConflict: {},
-		TaskStateBlocked:  {},
+		TaskStateImplemented: {},
+		TaskStateMerged:      {},
+		TaskStateConflict:    {},
+		TaskStateBlocked:     {},
+	},
+	TaskStateMerged: {
+		TaskStateReverted: {},
 	},
-	TaskStateMerged: {},
 	TaskStateConflict: {
 		TaskStateResolved: {},
 		TaskStateBlocked:  {},
@@ -69,6 +76,9 @@ var allowedTransitions = map[TaskState]map[TaskState]struct{}{
 	TaskStateBlocked: {
 		TaskStateTriaged: {},
 	},
+	TaskStateReverted: {
+		TaskStateTriaged: {},
+	},
 }
 
 const (
@@ -16,12 +16,15 @@ func TestValidTransitionsAcceptsAllowedPairs(t *testing.T) {
 		{TaskStateTriaged, TaskStateImplemented},
 		{TaskStateTriaged, TaskStateBlocked},
 		{TaskStateImplemented, TaskStateTested},
+		{TaskStateTested, TaskStateImplemented},
 		{TaskStateTested, TaskStateReviewed},
 		{TaskStateTested, TaskStateConflict},
 		{TaskStateTested, TaskStateTriaged},
 		{TaskStateTested, TaskStateBlocked},
+		{TaskStateReviewed, TaskStateImplemented},
 		{TaskStateReviewed, TaskStateMergeable},
 		{TaskStateReviewed, TaskStateBlocked},
+		{TaskStateMergeable, TaskStateImplemented},
 		{TaskStateMergeable, TaskStateMerged},
 		{TaskStateMergeable, TaskStateConflict},
 		{TaskStateConflict, TaskStateResolved},
@@ -29,6 +32,8 @@ func TestValidTransitionsAcceptsAllowedPairs(t *testing.T) {
 		{TaskStateResolved, TaskStateMergeable},
 		{TaskStateResolved, TaskStateConflict},
 		{TaskStateBlocked, TaskStateTriaged},
+		{TaskStateMerged, TaskStateReverted},
+		{TaskStateReverted, TaskStateTriaged},
 	}
 
 	for _, tc := range cases {
@@ -48,6 +53,7 @@ func TestInvalidTransitionsRejectsUnknownPairs(t *testing.T) {
 		to   TaskState
 	}{
 		{TaskStateMerged, TaskStateImplemented},
+		{TaskStateReverted, TaskStateMerged},
 		{TaskStateBlocked, TaskStateMerged},
 		{TaskStateResolved, TaskStateImplemented},
 		{TaskStateBacklog, TaskStateMerged},
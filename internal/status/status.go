@@ -10,12 +10,15 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/cooldown"
 	"github.com/cmtonkinson/governator/internal/format"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/run"
 	"github.com/cmtonkinson/governator/internal/supervisor"
 	"github.com/cmtonkinson/governator/internal/worker"
+	"github.com/cmtonkinson/governator/internal/worktree"
 	"golang.org/x/term"
 )
 
@@ -65,6 +68,9 @@ var statusStateOrder = map[index.TaskState]int{
 type Summary struct {
 	Supervisors   []SupervisorSummary
 	Workers       []WorkerSummary
+	Cooldowns     []CooldownSummary
+	Drift         *DriftSummary
+	Worktrees     []WorktreeSummary
 	PlanningSteps []PlanningStepSummary
 	Total         int
 	Backlog       int
@@ -121,6 +127,7 @@ type SupervisorSummary struct {
 	StartedAt      time.Time
 	LastTransition time.Time
 	LogPath        string
+	DryRunScript   string
 }
 
 // WorkerSummary captures the status output for an active worker.
@@ -130,6 +137,28 @@ type WorkerSummary struct {
 	StartedAt time.Time
 }
 
+// CooldownSummary captures an active CLI rate-limit cooldown.
+type CooldownSummary struct {
+	CLI    string
+	Until  time.Time
+	TaskID string
+	Reason string
+}
+
+// DriftSummary captures planning drift that has not yet been replanned.
+type DriftSummary struct {
+	Mode    string // drift.mode in effect: "replan" or "notify"
+	Details []string
+}
+
+// WorktreeSummary captures an existing task worktree and its disk usage.
+type WorktreeSummary struct {
+	TaskID    string
+	Branch    string
+	Path      string
+	SizeBytes int64
+}
+
 // PlanningStepSummary captures the status output for a planning step.
 type PlanningStepSummary struct {
 	ID        string
@@ -179,6 +208,37 @@ func (s Summary) plainString() string {
 			fmt.Fprintf(&b, "started_at=%s\n", formatTime(supervisor.StartedAt))
 			fmt.Fprintf(&b, "last_transition=%s\n", formatTime(supervisor.LastTransition))
 			fmt.Fprintf(&b, "log=%s\n", normalizeToken(supervisor.LogPath))
+			if supervisor.DryRunScript != "" {
+				fmt.Fprintf(&b, "dry_run_script=%s\n", supervisor.DryRunScript)
+			}
+		}
+	}
+	if len(s.Cooldowns) > 0 {
+		fmt.Fprintf(&b, "cooldowns=%d\n", len(s.Cooldowns))
+		for _, entry := range s.Cooldowns {
+			fmt.Fprintf(&b, "cli=%s until=%s remaining=%s task=%s reason=%q\n",
+				normalizeToken(entry.CLI),
+				formatTime(entry.Until),
+				formatCooldownRemaining(entry.Until),
+				normalizeToken(entry.TaskID),
+				entry.Reason,
+			)
+		}
+	}
+	if s.Drift != nil {
+		fmt.Fprintf(&b, "drift=%d mode=%s\n", len(s.Drift.Details), normalizeToken(s.Drift.Mode))
+		for _, detail := range s.Drift.Details {
+			fmt.Fprintf(&b, "- %s\n", detail)
+		}
+	}
+	if len(s.Worktrees) > 0 {
+		fmt.Fprintf(&b, "worktrees=%d total=%s\n", len(s.Worktrees), formatBytes(totalWorktreeBytes(s.Worktrees)))
+		for _, entry := range s.Worktrees {
+			fmt.Fprintf(&b, "task=%s size=%s path=%s\n",
+				normalizeToken(entry.TaskID),
+				formatBytes(entry.SizeBytes),
+				entry.Path,
+			)
 		}
 	}
 	if len(s.PlanningSteps) > 0 {
@@ -262,6 +322,35 @@ func (s Summary) styledString() string {
 		b.WriteString("\n\n")
 	}
 
+	// Cooldowns section
+	if len(s.Cooldowns) > 0 {
+		b.WriteString(headerStyle.Render(fmt.Sprintf("Cooldowns (%d)", len(s.Cooldowns))))
+		b.WriteString("\n")
+		cooldownsTable := renderCooldownsTable(s.Cooldowns, width)
+		b.WriteString(tableStyle.Render(cooldownsTable))
+		b.WriteString("\n\n")
+	}
+
+	// Planning drift section
+	if s.Drift != nil {
+		b.WriteString(headerStyle.Render(fmt.Sprintf("Planning Drift (%s)", s.Drift.Mode)))
+		b.WriteString("\n")
+		for _, detail := range s.Drift.Details {
+			b.WriteString(cellStyle.Render("- " + detail))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Worktrees section
+	if len(s.Worktrees) > 0 {
+		b.WriteString(headerStyle.Render(fmt.Sprintf("Worktrees (%d, %s)", len(s.Worktrees), formatBytes(totalWorktreeBytes(s.Worktrees)))))
+		b.WriteString("\n")
+		worktreesTable := renderWorktreesTable(s.Worktrees, width)
+		b.WriteString(tableStyle.Render(worktreesTable))
+		b.WriteString("\n\n")
+	}
+
 	// Planning steps section
 	if len(s.PlanningSteps) > 0 {
 		b.WriteString(headerStyle.Render(fmt.Sprintf("Planning Steps (%d)", len(s.PlanningSteps))))
@@ -294,6 +383,15 @@ func formatSupervisorRuntime(startedAt time.Time) string {
 	return format.DurationShort(time.Since(startedAt))
 }
 
+// formatCooldownRemaining formats the time left until a cooldown ends.
+func formatCooldownRemaining(until time.Time) string {
+	remaining := time.Until(until)
+	if remaining <= 0 {
+		return "-"
+	}
+	return format.DurationShort(remaining)
+}
+
 func formatTaskRuntime(startedAt time.Time) string {
 	if startedAt.IsZero() {
 		return "-"
@@ -383,6 +481,7 @@ func GetSummary(repoRoot string) (Summary, error) {
 				StartedAt:      state.StartedAt,
 				LastTransition: state.LastTransition,
 				LogPath:        state.LogPath,
+				DryRunScript:   state.DryRunScript,
 			})
 			if workerSummary, ok := activeWorkerSummary(state); ok {
 				summary.Workers = append(summary.Workers, workerSummary)
@@ -392,6 +491,30 @@ func GetSummary(repoRoot string) (Summary, error) {
 	summary.Workers = append(summary.Workers, workersFromInFlight(inflightSet)...)
 	summary.Workers = dedupeWorkers(summary.Workers)
 
+	cooldowns, err := activeCooldowns(repoRoot, time.Now())
+	if err != nil {
+		return Summary{}, err
+	}
+	summary.Cooldowns = cooldowns
+
+	cfg, err := config.Load(repoRoot, nil, nil)
+	if err != nil {
+		return Summary{}, fmt.Errorf("load config: %w", err)
+	}
+	drift, err := run.DetectPlanningDrift(repoRoot, cfg, idx.Digests)
+	if err != nil {
+		return Summary{}, err
+	}
+	if drift.HasDrift {
+		summary.Drift = &DriftSummary{Mode: cfg.Drift.Mode, Details: drift.Details}
+	}
+
+	worktrees, err := worktreeUsage(repoRoot, cfg)
+	if err != nil {
+		return Summary{}, err
+	}
+	summary.Worktrees = worktrees
+
 	var mergedRows []StatusRow
 	for _, task := range idx.Tasks {
 		if task.Kind != index.TaskKindExecution {
@@ -458,7 +581,7 @@ func GetSummary(repoRoot string) (Summary, error) {
 
 	summary.Rows = rows
 	summary.MergedRows = mergedRows
-	steps, err := planningStepSummary(repoRoot, idx, summary.Supervisors)
+	steps, err := planningStepSummary(repoRoot, idx, summary.Supervisors, inflightSet)
 	if err != nil {
 		return Summary{}, err
 	}
@@ -634,7 +757,91 @@ func dedupeWorkers(workers []WorkerSummary) []WorkerSummary {
 	return deduped
 }
 
-func planningStepSummary(repoRoot string, idx index.Index, supervisors []SupervisorSummary) ([]PlanningStepSummary, error) {
+// activeCooldowns loads the CLI cooldowns that have not yet expired.
+func activeCooldowns(repoRoot string, now time.Time) ([]CooldownSummary, error) {
+	store, err := cooldown.NewStore(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("create cooldown store: %w", err)
+	}
+	set, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load cooldowns: %w", err)
+	}
+	entries := set.ActiveEntries(now)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	cooldowns := make([]CooldownSummary, 0, len(entries))
+	for _, entry := range entries {
+		cooldowns = append(cooldowns, CooldownSummary{
+			CLI:    entry.CLI,
+			Until:  entry.Until,
+			TaskID: entry.TaskID,
+			Reason: entry.Reason,
+		})
+	}
+	return cooldowns, nil
+}
+
+// worktreeUsage lists the existing task worktrees under the configured worktree root with
+// their disk usage.
+func worktreeUsage(repoRoot string, cfg config.Config) ([]WorktreeSummary, error) {
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
+	if err != nil {
+		return nil, fmt.Errorf("create worktree manager: %w", err)
+	}
+	infos, err := manager.List()
+	if err != nil {
+		return nil, fmt.Errorf("list worktrees: %w", err)
+	}
+	if len(infos) == 0 {
+		return nil, nil
+	}
+	worktrees := make([]WorktreeSummary, 0, len(infos))
+	for _, info := range infos {
+		size, err := worktree.DiskUsage(info.Path)
+		if err != nil {
+			return nil, err
+		}
+		worktrees = append(worktrees, WorktreeSummary{
+			TaskID:    info.WorkstreamID,
+			Branch:    info.Branch,
+			Path:      info.Path,
+			SizeBytes: size,
+		})
+	}
+	return worktrees, nil
+}
+
+// totalWorktreeBytes sums the disk usage of the listed worktrees.
+func totalWorktreeBytes(worktrees []WorktreeSummary) int64 {
+	var total int64
+	for _, entry := range worktrees {
+		total += entry.SizeBytes
+	}
+	return total
+}
+
+// formatBytes formats a byte count with a binary unit suffix (e.g., "512B", "1.5MiB").
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value := float64(n)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+	suffix := ""
+	for _, next := range suffixes {
+		value /= unit
+		suffix = next
+		if value < unit {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f%s", value, suffix)
+}
+
+func planningStepSummary(repoRoot string, idx index.Index, supervisors []SupervisorSummary, inFlight inflight.Set) ([]PlanningStepSummary, error) {
 	stateID, found := planningTaskStateID(idx)
 	if !found {
 		return nil, nil
@@ -682,6 +889,15 @@ func planningStepSummary(repoRoot string, idx index.Index, supervisors []Supervi
 				pid = planningSupervisor.WorkerPID
 				startedAt = planningSupervisor.LastTransition
 			}
+		default:
+			// Steps that run concurrently ahead of the current one have their own in-flight entry.
+			if entry, ok := inFlight.Entry("planning-" + step.ID); ok {
+				status = "in-progress"
+				startedAt = entry.StartedAt
+				if foundPID, found, err := worker.ReadAgentPID(entry.WorkerStateDir); err == nil && found {
+					pid = foundPID
+				}
+			}
 		}
 		name := strings.TrimSpace(step.Name)
 		if name == "" {
@@ -855,6 +1071,9 @@ func renderSupervisorKV(supervisor SupervisorSummary) string {
 	renderKV("Started At", formatTime(supervisor.StartedAt))
 	renderKV("Last Transition", formatTime(supervisor.LastTransition))
 	renderKV("Log", normalizeToken(supervisor.LogPath))
+	if supervisor.DryRunScript != "" {
+		renderKV("Dry Run", supervisor.DryRunScript)
+	}
 
 	return buf.String()
 }
@@ -972,3 +1191,120 @@ func renderWorkersTable(workers []WorkerSummary, maxWidth int) string {
 
 	return strings.TrimRight(buf.String(), "\n")
 }
+
+// renderCooldownsTable renders the active CLI cooldowns with lipgloss styling.
+func renderCooldownsTable(cooldowns []CooldownSummary, maxWidth int) string {
+	if len(cooldowns) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+
+	// Column widths - reason takes the remaining space
+	minWidths := []int{10, 22, 10, 14, 20} // CLI, Until, Remaining, Task, Reason
+	totalFixed := minWidths[0] + minWidths[1] + minWidths[2] + minWidths[3]
+	overhead := 8
+	reasonWidth := minWidths[4]
+	if available := maxWidth - totalFixed - overhead; available > reasonWidth {
+		reasonWidth = available
+		if reasonWidth > 80 {
+			reasonWidth = 80
+		}
+	}
+	widths := []int{minWidths[0], minWidths[1], minWidths[2], minWidths[3], reasonWidth}
+
+	// Header row
+	headers := []string{"CLI", "Until", "Remaining", "Task", "Reason"}
+	headerCells := make([]string, len(headers))
+	for i, h := range headers {
+		headerCells[i] = headerStyle.Width(widths[i]).Render(h)
+	}
+	buf.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, headerCells...))
+	buf.WriteString("\n")
+
+	// Separator
+	totalWidth := 0
+	for _, w := range widths {
+		totalWidth += w
+	}
+	separator := separatorStyle.Render(strings.Repeat("─", totalWidth))
+	buf.WriteString(separator)
+	buf.WriteString("\n")
+
+	// Data rows
+	for _, entry := range cooldowns {
+		cells := []string{
+			entry.CLI,
+			formatTime(entry.Until),
+			formatCooldownRemaining(entry.Until),
+			entry.TaskID,
+			entry.Reason,
+		}
+		renderedCells := make([]string, len(cells))
+		for i, cell := range cells {
+			style := cellStyle.Width(widths[i]).MaxWidth(widths[i])
+			renderedCells[i] = style.Render(cell)
+		}
+		buf.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, renderedCells...))
+		buf.WriteString("\n")
+	}
+
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// renderWorktreesTable renders the existing task worktrees with lipgloss styling.
+func renderWorktreesTable(worktrees []WorktreeSummary, maxWidth int) string {
+	if len(worktrees) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+
+	// Column widths - path takes the remaining space
+	minWidths := []int{14, 10, 30} // Task, Size, Path
+	overhead := 8
+	pathWidth := minWidths[2]
+	if available := maxWidth - minWidths[0] - minWidths[1] - overhead; available > pathWidth {
+		pathWidth = available
+		if pathWidth > 100 {
+			pathWidth = 100
+		}
+	}
+	widths := []int{minWidths[0], minWidths[1], pathWidth}
+
+	// Header row
+	headers := []string{"Task", "Size", "Path"}
+	headerCells := make([]string, len(headers))
+	for i, h := range headers {
+		headerCells[i] = headerStyle.Width(widths[i]).Render(h)
+	}
+	buf.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, headerCells...))
+	buf.WriteString("\n")
+
+	// Separator
+	totalWidth := 0
+	for _, w := range widths {
+		totalWidth += w
+	}
+	separator := separatorStyle.Render(strings.Repeat("─", totalWidth))
+	buf.WriteString(separator)
+	buf.WriteString("\n")
+
+	// Data rows
+	for _, entry := range worktrees {
+		cells := []string{
+			entry.TaskID,
+			formatBytes(entry.SizeBytes),
+			entry.Path,
+		}
+		renderedCells := make([]string, len(cells))
+		for i, cell := range cells {
+			style := cellStyle.Width(widths[i]).MaxWidth(widths[i])
+			renderedCells[i] = style.Render(cell)
+		}
+		buf.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, renderedCells...))
+		buf.WriteString("\n")
+	}
+
+	return strings.TrimRight(buf.String(), "\n")
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cmtonkinson/governator/internal/cooldown"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/supervisor"
@@ -114,6 +115,126 @@ func TestGetSummary(t *testing.T) {
 	}
 }
 
+// TestGetSummaryActiveCooldowns ensures unexpired CLI cooldowns are reported and expired ones are hidden.
+func TestGetSummaryActiveCooldowns(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+
+	indexPath := filepath.Join(repoRoot, "_governator", "_local-state", "index.json")
+	if err := index.Save(indexPath, index.Index{SchemaVersion: 1}); err != nil {
+		t.Fatalf("save index: %v", err)
+	}
+
+	now := time.Now().UTC()
+	store, err := cooldown.NewStore(repoRoot)
+	if err != nil {
+		t.Fatalf("new cooldown store: %v", err)
+	}
+	if err := store.Save(cooldown.Set{
+		"claude": {CLI: "claude", StartedAt: now, Until: now.Add(10 * time.Minute), TaskID: "T-007", Reason: "usage limit reached"},
+		"codex":  {CLI: "codex", StartedAt: now.Add(-time.Hour), Until: now.Add(-time.Minute)},
+	}); err != nil {
+		t.Fatalf("save cooldowns: %v", err)
+	}
+
+	summary, err := GetSummary(repoRoot)
+	if err != nil {
+		t.Fatalf("GetSummary() failed: %v", err)
+	}
+	if len(summary.Cooldowns) != 1 {
+		t.Fatalf("expected 1 active cooldown, got %+v", summary.Cooldowns)
+	}
+	if summary.Cooldowns[0].CLI != "claude" || summary.Cooldowns[0].TaskID != "T-007" {
+		t.Fatalf("unexpected cooldown: %+v", summary.Cooldowns[0])
+	}
+
+	plain := summary.plainString()
+	if !strings.Contains(plain, "cooldowns=1") {
+		t.Fatalf("plain status missing cooldown count: %q", plain)
+	}
+	if !strings.Contains(plain, "cli=claude until="+formatTime(summary.Cooldowns[0].Until)) {
+		t.Fatalf("plain status missing cooldown end: %q", plain)
+	}
+}
+
+// TestGetSummaryWorktreeUsage ensures existing task worktrees are reported with their disk usage.
+func TestGetSummaryWorktreeUsage(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+
+	localState := filepath.Join(repoRoot, "_governator", "_local-state")
+	if err := index.Save(filepath.Join(localState, "index.json"), index.Index{SchemaVersion: 1}); err != nil {
+		t.Fatalf("save index: %v", err)
+	}
+	worktreePath := filepath.Join(localState, "task-T-005")
+	if err := os.MkdirAll(worktreePath, 0o755); err != nil {
+		t.Fatalf("create worktree dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "data.bin"), make([]byte, 2048), 0o644); err != nil {
+		t.Fatalf("write worktree file: %v", err)
+	}
+	metaDir := filepath.Join(localState, "meta")
+	if err := os.MkdirAll(metaDir, 0o755); err != nil {
+		t.Fatalf("create meta dir: %v", err)
+	}
+	meta := `{"worktree_rel_path": "_governator/_local-state/task-T-005", "branch": "task-T-005"}`
+	if err := os.WriteFile(filepath.Join(metaDir, "T-005.json"), []byte(meta), 0o644); err != nil {
+		t.Fatalf("write metadata: %v", err)
+	}
+
+	summary, err := GetSummary(repoRoot)
+	if err != nil {
+		t.Fatalf("GetSummary() failed: %v", err)
+	}
+	if len(summary.Worktrees) != 1 {
+		t.Fatalf("expected 1 worktree, got %+v", summary.Worktrees)
+	}
+	if summary.Worktrees[0].TaskID != "T-005" || summary.Worktrees[0].SizeBytes != 2048 {
+		t.Fatalf("unexpected worktree: %+v", summary.Worktrees[0])
+	}
+
+	plain := summary.plainString()
+	if !strings.Contains(plain, "worktrees=1 total=2.0KiB") {
+		t.Fatalf("plain status missing worktree total: %q", plain)
+	}
+	if !strings.Contains(plain, "task=T-005 size=2.0KiB path="+worktreePath) {
+		t.Fatalf("plain status missing worktree row: %q", plain)
+	}
+}
+
+// TestGetSummaryPlanningDrift ensures undrained planning drift is reported with the drift mode.
+func TestGetSummaryPlanningDrift(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+
+	configDir := filepath.Join(repoRoot, "_governator", "_durable-state")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatalf("create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"drift": {"mode": "notify"}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	indexPath := filepath.Join(repoRoot, "_governator", "_local-state", "index.json")
+	if err := index.Save(indexPath, index.Index{SchemaVersion: 1, Digests: index.Digests{GovernatorMD: "sha256:old"}}); err != nil {
+		t.Fatalf("save index: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, "GOVERNATOR.md"), []byte("intent\n"), 0o644); err != nil {
+		t.Fatalf("write GOVERNATOR.md: %v", err)
+	}
+
+	summary, err := GetSummary(repoRoot)
+	if err != nil {
+		t.Fatalf("GetSummary() failed: %v", err)
+	}
+	if summary.Drift == nil || summary.Drift.Mode != "notify" {
+		t.Fatalf("expected notify drift, got %+v", summary.Drift)
+	}
+	plain := summary.plainString()
+	if !strings.Contains(plain, "drift=1 mode=notify") || !strings.Contains(plain, "- GOVERNATOR.md changed") {
+		t.Fatalf("plain status missing drift: %q", plain)
+	}
+}
+
 // TestGetSummarySupervisorFiltering ensures status only reports running or failed supervisors.
 func TestGetSummarySupervisorFiltering(t *testing.T) {
 	t.Parallel()
@@ -661,3 +782,52 @@ func TestPlanningStepSummary_Complete(t *testing.T) {
 		t.Fatalf("output should not contain planning-steps header: %q", output)
 	}
 }
+
+// TestPlanningStepSummary_ConcurrentStepInProgress ensures steps running ahead of the current one show as in-progress.
+func TestPlanningStepSummary_ConcurrentStepInProgress(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+	planningSpecPath := filepath.Join(repoRoot, "_governator", "planning.json")
+	if err := os.MkdirAll(filepath.Dir(planningSpecPath), 0o755); err != nil {
+		t.Fatalf("create state dir: %v", err)
+	}
+	planningSpec := `{
+		"version": 3,
+		"steps": [
+			{"id": "architecture-baseline", "name": "Architecture Baseline", "prompt": "_governator/prompts/architecture-baseline.md", "role": "architect"},
+			{"id": "threat-model", "name": "Threat Model", "prompt": "_governator/prompts/threat-model.md", "role": "default", "depends_on": ["architecture-baseline"]},
+			{"id": "data-model", "name": "Data Model", "prompt": "_governator/prompts/data-model.md", "role": "default", "depends_on": ["architecture-baseline"]}
+		]
+	}`
+	if err := os.WriteFile(planningSpecPath, []byte(planningSpec), 0o644); err != nil {
+		t.Fatalf("write planning spec: %v", err)
+	}
+	testIndex := index.Index{
+		SchemaVersion: 1,
+		Tasks:         []index.Task{{ID: "planning", Kind: index.TaskKindPlanning, State: "threat-model"}},
+	}
+	if err := index.Save(filepath.Join(repoRoot, "_governator", "_local-state", "index.json"), testIndex); err != nil {
+		t.Fatalf("save index: %v", err)
+	}
+	store, err := inflight.NewStore(repoRoot)
+	if err != nil {
+		t.Fatalf("create in-flight store: %v", err)
+	}
+	if _, err := store.Add("planning-threat-model", "planning-data-model"); err != nil {
+		t.Fatalf("add in-flight: %v", err)
+	}
+
+	summary, err := GetSummary(repoRoot)
+	if err != nil {
+		t.Fatalf("GetSummary() failed: %v", err)
+	}
+	if len(summary.PlanningSteps) != 3 {
+		t.Fatalf("expected 3 planning steps, got %d", len(summary.PlanningSteps))
+	}
+	want := []string{"complete", "in-progress", "in-progress"}
+	for i, step := range summary.PlanningSteps {
+		if step.Status != want[i] {
+			t.Fatalf("step %s status = %q, want %q", step.ID, step.Status, want[i])
+		}
+	}
+}
@@ -52,6 +52,7 @@ type SupervisorStateInfo struct {
 	LogPath        string          `json:"log_path,omitempty"`
 	Error          string          `json:"error,omitempty"`
 	WorkerStateDir string          `json:"worker_state_dir,omitempty"`
+	DryRunScript   string          `json:"dry_run_script,omitempty"` // Set when running with the fake agent
 }
 
 // ErrSupervisorNotRunning indicates no supervisor is active.
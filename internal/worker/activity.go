@@ -0,0 +1,76 @@
+// Package worker provides worker activity tracking for stall detection.
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errActivityFound stops the worktree walk once recent activity is seen.
+var errActivityFound = errors.New("activity found")
+
+// LastActivity returns the latest modification time across the worker's stdout and stderr
+// logs and the files in its worktree. The scan stops early once anything newer than cutoff
+// is found, so callers should pass the oldest time they would still treat as active.
+func LastActivity(workerStateDir string, worktreePath string, cutoff time.Time) (time.Time, error) {
+	var latest time.Time
+	observe := func(modTime time.Time) bool {
+		if modTime.After(latest) {
+			latest = modTime
+		}
+		return latest.After(cutoff)
+	}
+
+	if strings.TrimSpace(workerStateDir) != "" {
+		for _, name := range []string{stdoutLogFileName, stderrLogFileName} {
+			info, err := os.Stat(filepath.Join(workerStateDir, name))
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return time.Time{}, fmt.Errorf("stat worker log %s: %w", name, err)
+			}
+			if observe(info.ModTime()) {
+				return latest, nil
+			}
+		}
+	}
+
+	if strings.TrimSpace(worktreePath) == "" {
+		return latest, nil
+	}
+	err := filepath.WalkDir(worktreePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.Name() == ".git" && path != worktreePath {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if observe(info.ModTime()) {
+			return errActivityFound
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errActivityFound) {
+		return latest, fmt.Errorf("scan worktree %s: %w", worktreePath, err)
+	}
+	return latest, nil
+}
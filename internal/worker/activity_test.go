@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLastActivity(t *testing.T) {
+	stateDir := t.TempDir()
+	worktreePath := t.TempDir()
+	old := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	logTime := old.Add(10 * time.Minute)
+	age := func(path string, modTime time.Time) {
+		t.Helper()
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("chtimes %s: %v", path, err)
+		}
+	}
+
+	writeTestFile(t, filepath.Join(stateDir, stdoutLogFileName), "working\n")
+	age(filepath.Join(stateDir, stdoutLogFileName), logTime)
+	writeTestFile(t, filepath.Join(worktreePath, "main.go"), "package main\n")
+	age(filepath.Join(worktreePath, "main.go"), old)
+	if err := os.MkdirAll(filepath.Join(worktreePath, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir .git: %v", err)
+	}
+	writeTestFile(t, filepath.Join(worktreePath, ".git", "index"), "")
+	age(filepath.Join(worktreePath, ".git"), old)
+	age(worktreePath, old)
+
+	latest, err := LastActivity(stateDir, worktreePath, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("last activity: %v", err)
+	}
+	if !latest.Equal(logTime) {
+		t.Fatalf("latest = %v, want log time %v (git metadata must be ignored)", latest, logTime)
+	}
+
+	writeTestFile(t, filepath.Join(worktreePath, "main.go"), "package main\n\nfunc main() {}\n")
+	latest, err = LastActivity(stateDir, worktreePath, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("last activity: %v", err)
+	}
+	if time.Since(latest) > time.Minute {
+		t.Fatalf("latest = %v, want recent worktree edit", latest)
+	}
+
+	if latest, err := LastActivity(filepath.Join(stateDir, "missing"), "", time.Now()); err != nil || !latest.IsZero() {
+		t.Fatalf("missing state = (%v, %v), want zero time", latest, err)
+	}
+}
@@ -32,6 +32,9 @@ type DispatchInput struct {
 	Warn           func(string)
 	WorkerStateDir string
 	SelectedCLI    string // The CLI name from config ("claude", "codex", "gemini", or "")
+	RepoRoot       string
+	Sandbox        config.SandboxPolicy
+	Resources      config.ResourceLimits
 }
 
 // DispatchResult captures the worker dispatch metadata.
@@ -55,6 +58,8 @@ type dispatchMetadata struct {
 	Command     []string  `json:"command"`
 	AgentName   string    `json:"agent_name,omitempty"`
 	PIDFiles    []string  `json:"pid_files"`
+	Sandbox     string    `json:"sandbox,omitempty"`
+	Resources   string    `json:"resources,omitempty"`
 	StartError  string    `json:"start_error,omitempty"`
 }
 
@@ -103,7 +108,15 @@ func DispatchWorker(input DispatchInput) (DispatchResult, error) {
 	}
 	agentName := detectAgentName(input.Command)
 	pidPaths := agentPIDPaths(input.WorkerStateDir, agentName)
-	wrapperPath, err := writeDispatchWrapper(input.WorkerStateDir, input.TaskID, input.Stage, input.Command, exitPath, pidPaths, input.SelectedCLI)
+	sandbox, err := resolveSandbox(input.Sandbox, input.RepoRoot, input.WorkDir, input.WorkerStateDir, input.SelectedCLI, input.Warn)
+	if err != nil {
+		return DispatchResult{}, fmt.Errorf("resolve worker sandbox: %w", err)
+	}
+	resources, err := planResourceLimits(input.Resources, input.TaskID, input.Stage, input.WorkerStateDir, input.Warn)
+	if err != nil {
+		return DispatchResult{}, fmt.Errorf("plan worker resource limits: %w", err)
+	}
+	wrapperPath, err := writeDispatchWrapper(input.WorkerStateDir, input.TaskID, input.Stage, input.Command, exitPath, pidPaths, input.SelectedCLI, sandbox, resources)
 	if err != nil {
 		return DispatchResult{}, err
 	}
@@ -132,6 +145,12 @@ func DispatchWorker(input DispatchInput) (DispatchResult, error) {
 		AgentName:   agentName,
 		PIDFiles:    pidPaths,
 	}
+	if sandbox != nil {
+		meta.Sandbox = sandbox.Backend
+	}
+	if resources != nil {
+		meta.Resources = resources.Mechanism
+	}
 	writeDispatchMetadata(input.WorkerStateDir, meta, input.Warn)
 	if err := cmd.Start(); err != nil {
 		meta.StartError = err.Error()
@@ -177,15 +196,23 @@ func DispatchWorkerFromConfig(cfg config.Config, task index.Task, stageResult St
 		}
 	}
 
+	secrets, err := LoadSecrets(stageResult.RepoRoot)
+	if err != nil {
+		return DispatchResult{}, err
+	}
+
 	input := DispatchInput{
 		Command:        command,
 		WorkDir:        workDir,
 		TaskID:         task.ID,
 		Stage:          stage,
-		EnvVars:        stageResult.Env,
+		EnvVars:        mergeSecretsEnv(stageResult.Env, secrets),
 		Warn:           warn,
 		WorkerStateDir: stageResult.WorkerStateDir,
 		SelectedCLI:    selectedCLI,
+		RepoRoot:       stageResult.RepoRoot,
+		Sandbox:        cfg.Sandbox.PolicyForRole(string(task.Role)),
+		Resources:      cfg.Resources.LimitsForRole(string(task.Role)),
 	}
 
 	return DispatchWorker(input)
@@ -262,7 +289,9 @@ func ReadExitStatus(workerStateDir string, taskID string, stage roles.Stage) (Ex
 }
 
 // writeDispatchWrapper writes a wrapper script that captures exit status and persists the agent pid.
-func writeDispatchWrapper(workerStateDir string, taskID string, stage roles.Stage, command []string, exitPath string, pidPaths []string, selectedCLI string) (string, error) {
+// When a sandbox is supplied the agent command runs inside it; the wrapper itself stays outside.
+// Resource limits are applied to the wrapper shell so the agent and its children inherit them.
+func writeDispatchWrapper(workerStateDir string, taskID string, stage roles.Stage, command []string, exitPath string, pidPaths []string, selectedCLI string, sandbox *sandboxSpec, resources *resourcePlan) (string, error) {
 	if strings.TrimSpace(taskID) == "" {
 		return "", errors.New("task id is required")
 	}
@@ -294,22 +323,35 @@ func writeDispatchWrapper(workerStateDir string, taskID string, stage roles.Stag
 	} else {
 		commandLine = shellCommandLine(command)
 	}
+	if sandbox != nil {
+		commandLine = sandbox.commandLine(commandLine)
+	}
+	if resources != nil {
+		commandLine = resources.agentCommandLine(commandLine)
+	}
 
 	exitPathEscaped := shellEscapeArg(exitPath)
 	pidWriteLines := buildPIDWriteLines(pidPaths)
-	content := strings.Join([]string{
-		"#!/bin/sh",
-		"set +e",
-		commandLine + " &",
+	lines := []string{"#!/bin/sh", "set +e"}
+	if resources != nil {
+		lines = append(lines, resources.wrapperLines()...)
+	}
+	lines = append(lines,
+		commandLine+" &",
 		"pid=$!",
 		pidWriteLines,
 		"wait $pid",
 		"code=$?",
+	)
+	if resources != nil {
+		lines = append(lines, resources.cleanupLines(workerStateDir)...)
+	}
+	content := strings.Join(append(lines,
 		"finished_at=$(date -u +\"%Y-%m-%dT%H:%M:%SZ\")",
-		"printf '{\"exit_code\":%d,\"finished_at\":\"%s\",\"pid\":%d}\\n' \"$code\" \"$finished_at\" \"$pid\" > " + exitPathEscaped,
+		"printf '{\"exit_code\":%d,\"finished_at\":\"%s\",\"pid\":%d}\\n' \"$code\" \"$finished_at\" \"$pid\" > "+exitPathEscaped,
 		"exit $code",
 		"",
-	}, "\n")
+	), "\n")
 	if err := os.WriteFile(wrapperPath, []byte(content), wrapperFileMode); err != nil {
 		return "", fmt.Errorf("write dispatch wrapper %s: %w", wrapperPath, err)
 	}
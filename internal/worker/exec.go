@@ -13,6 +13,7 @@ import (
 
 	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
 )
 
 const (
@@ -164,12 +165,17 @@ func ExecuteWorkerFromConfigWithAudit(cfg config.Config, task index.Task, stageR
 	}
 	command = applyCodexReasoningFlag(command, stageResult.ReasoningEffort)
 
+	secrets, err := LoadSecrets(stageResult.RepoRoot)
+	if err != nil {
+		return ExecResult{}, err
+	}
+
 	input := ExecInput{
 		Command:        command,
 		WorkDir:        workDir,
 		TaskID:         task.ID,
-		TimeoutSecs:    cfg.Timeouts.WorkerSeconds,
-		EnvVars:        stageResult.Env,
+		TimeoutSecs:    TimeoutSecondsForTask(cfg, task, stageResult.Stage),
+		EnvVars:        mergeSecretsEnv(stageResult.Env, secrets),
 		Warn:           warn,
 		AuditLogger:    auditLogger,
 		Role:           string(task.Role),
@@ -180,6 +186,15 @@ func ExecuteWorkerFromConfigWithAudit(cfg config.Config, task index.Task, stageR
 	return ExecuteWorker(input)
 }
 
+// TimeoutSecondsForTask resolves the hard timeout for a task stage. A timeout set on the
+// task itself wins; otherwise the configured stage, role, and default timeouts apply.
+func TimeoutSecondsForTask(cfg config.Config, task index.Task, stage roles.Stage) int {
+	if task.TimeoutSeconds > 0 {
+		return task.TimeoutSeconds
+	}
+	return cfg.Timeouts.SecondsFor(string(task.Role), string(stage), "")
+}
+
 // emitWarning sends a warning to the configured sink.
 func emitWarning(warn func(string), message string) {
 	if warn == nil {
@@ -197,8 +212,8 @@ func createWorkerLogFiles(workDir string, workerStateDir string, taskID string)
 		return workerLogFiles{}, fmt.Errorf("create worker state dir %s: %w", workerStateDir, err)
 	}
 
-	stdoutPath := filepath.Join(workerStateDir, "stdout.log")
-	stderrPath := filepath.Join(workerStateDir, "stderr.log")
+	stdoutPath := filepath.Join(workerStateDir, stdoutLogFileName)
+	stderrPath := filepath.Join(workerStateDir, stderrLogFileName)
 
 	stdoutFile, err := os.Create(stdoutPath)
 	if err != nil {
@@ -26,15 +26,18 @@ type IngestInput struct {
 
 // IngestResult captures the worker result ingestion outcome.
 type IngestResult struct {
-	Success      bool
-	NewState     index.TaskState
-	BlockReason  string
-	TimedOut     bool // TimedOut reports whether the worker execution timed out.
-	HasCommit    bool
-	HasMarker    bool
-	MarkerPath   string
-	MarkerExists bool
-	Metrics      index.ExecutionMetrics // Metrics captured from this execution stage
+	Success       bool
+	NewState      index.TaskState
+	BlockReason   string
+	TimedOut      bool   // TimedOut reports whether the worker execution timed out.
+	Stalled       bool   // Stalled reports whether the worker was killed for inactivity.
+	RateLimited   bool   // RateLimited reports whether the worker hit a provider rate limit.
+	ResourceLimit string // ResourceLimit names the resource limit the worker exceeded, if any.
+	HasCommit     bool
+	HasMarker     bool
+	MarkerPath    string
+	MarkerExists  bool
+	Metrics       index.ExecutionMetrics // Metrics captured from this execution stage
 }
 
 // IngestWorkerResult processes worker execution results and determines task state changes.
@@ -0,0 +1,103 @@
+// Package worker provides prompt size budgeting for staged worker prompts.
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+)
+
+// PromptBudget bounds the estimated token size of a prompt for the CLI that will run it.
+type PromptBudget struct {
+	// CLI names the worker CLI the budget applies to, for messages.
+	CLI string
+	config.PromptBudget
+	// DropOrder lists optional section kinds removed first to last when OnExceed is "drop".
+	DropOrder []string
+}
+
+// PromptBudgetForRole resolves the prompt budget for the CLI that serves role.
+func PromptBudgetForRole(cfg config.Config, role index.Role) PromptBudget {
+	cli := selectCLIName(cfg, role)
+	return PromptBudget{
+		CLI:          cli,
+		PromptBudget: cfg.PromptBudget.BudgetForCLI(cli),
+		DropOrder:    cfg.PromptBudget.DropOrder,
+	}
+}
+
+// label names the budget in warnings and block reasons.
+func (budget PromptBudget) label() string {
+	if cli := strings.TrimSpace(budget.CLI); cli != "" {
+		return cli
+	}
+	return "default"
+}
+
+// applyPromptBudget warns about, trims, or rejects a prompt that exceeds its budget.
+func applyPromptBudget(prompt RenderedPrompt, budget PromptBudget, warn func(string)) (RenderedPrompt, error) {
+	tokens := prompt.EstimatedTokens()
+	if budget.MaxTokens <= 0 || tokens <= budget.MaxTokens {
+		if budget.WarnTokens > 0 && tokens > budget.WarnTokens {
+			emitPromptBudgetWarning(warn, fmt.Sprintf("prompt is ~%d tokens, over the %s warn_tokens budget of %d", tokens, budget.label(), budget.WarnTokens))
+		}
+		return prompt, nil
+	}
+
+	over := fmt.Sprintf("prompt is ~%d tokens, over the %s max_tokens budget of %d", tokens, budget.label(), budget.MaxTokens)
+	switch budget.OnExceed {
+	case config.PromptBudgetBlock:
+		return RenderedPrompt{}, fmt.Errorf("%s", over)
+	case config.PromptBudgetDrop:
+		trimmed := dropPromptSections(prompt, budget.DropOrder, budget.MaxTokens)
+		dropped := sectionPathList(trimmed.Dropped)
+		if trimmed.EstimatedTokens() > budget.MaxTokens {
+			return RenderedPrompt{}, fmt.Errorf("%s; still ~%d tokens after dropping optional sections [%s]", over, trimmed.EstimatedTokens(), dropped)
+		}
+		emitPromptBudgetWarning(warn, fmt.Sprintf("%s; dropped [%s] to fit at ~%d tokens", over, dropped, trimmed.EstimatedTokens()))
+		return trimmed, nil
+	default:
+		emitPromptBudgetWarning(warn, over)
+		return prompt, nil
+	}
+}
+
+// dropPromptSections removes optional sections in drop order, last file first within a
+// kind, until the prompt fits maxTokens or nothing optional remains.
+func dropPromptSections(prompt RenderedPrompt, dropOrder []string, maxTokens int) RenderedPrompt {
+	for _, kind := range dropOrder {
+		for i := len(prompt.Sections) - 1; i >= 0; i-- {
+			if prompt.EstimatedTokens() <= maxTokens {
+				return prompt
+			}
+			section := prompt.Sections[i]
+			if section.Kind != kind {
+				continue
+			}
+			remaining := make([]PromptSection, 0, len(prompt.Sections)-1)
+			remaining = append(remaining, prompt.Sections[:i]...)
+			remaining = append(remaining, prompt.Sections[i+1:]...)
+			prompt.Sections = remaining
+			prompt.Dropped = append(prompt.Dropped, section)
+		}
+	}
+	return prompt
+}
+
+// sectionPathList joins section paths for messages.
+func sectionPathList(sections []PromptSection) string {
+	paths := make([]string, 0, len(sections))
+	for _, section := range sections {
+		paths = append(paths, section.Path)
+	}
+	return strings.Join(paths, ", ")
+}
+
+// emitPromptBudgetWarning forwards a budget warning when a sink is configured.
+func emitPromptBudgetWarning(warn func(string), message string) {
+	if warn != nil {
+		warn(message)
+	}
+}
@@ -0,0 +1,137 @@
+// Tests for prompt size budgeting.
+package worker
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+)
+
+// writeBudgetRepo creates prompt files of known sizes for budget tests.
+func writeBudgetRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "_governator", "worker-contract.md"), strings.Repeat("c", 400))
+	writeFile(t, filepath.Join(root, "_governator", "roles", "worker.md"), strings.Repeat("r", 400))
+	writeFile(t, filepath.Join(root, "_governator", "custom-prompts", "_global.md"), strings.Repeat("g", 400))
+	writeFile(t, filepath.Join(root, "_governator", "custom-prompts", "worker.md"), strings.Repeat("w", 400))
+	writeFile(t, filepath.Join(root, "_governator", "tasks", "T-020.md"), strings.Repeat("t", 400))
+	writeFile(t, filepath.Join(root, "_governator", "context.md"), strings.Repeat("x", 400))
+	return root
+}
+
+// budgetInput builds a stage input whose prompt is about 600 tokens.
+func budgetInput(root string, budget PromptBudget) StageInput {
+	return StageInput{
+		RepoRoot:        root,
+		WorktreeRoot:    root,
+		Task:            index.Task{ID: "T-020", Path: "_governator/tasks/T-020.md", Role: "worker"},
+		ExtraPromptPath: []string{"_governator/context.md"},
+		Stage:           roles.StageWork,
+		PromptBudget:    budget,
+		WorkerStateDir:  workerStateDirPath(root),
+	}
+}
+
+// TestPromptBudgetWarns reports oversized prompts without changing them.
+func TestPromptBudgetWarns(t *testing.T) {
+	t.Parallel()
+	root := writeBudgetRepo(t)
+	var warnings []string
+	input := budgetInput(root, PromptBudget{CLI: "codex", PromptBudget: config.PromptBudget{WarnTokens: 500}})
+	input.Warn = func(message string) { warnings = append(warnings, message) }
+
+	result, err := StageEnvAndPrompts(input)
+	if err != nil {
+		t.Fatalf("StageEnvAndPrompts: %v", err)
+	}
+	if len(result.PromptFiles) != 6 || len(result.DroppedPrompts) != 0 {
+		t.Fatalf("prompt files = %v, dropped = %v", result.PromptFiles, result.DroppedPrompts)
+	}
+	if result.PromptTokens != EstimateTokens(result.PromptBytes) || result.PromptBytes == 0 {
+		t.Fatalf("prompt size = %d bytes, %d tokens", result.PromptBytes, result.PromptTokens)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "codex warn_tokens budget of 500") {
+		t.Fatalf("warnings = %v", warnings)
+	}
+}
+
+// TestPromptBudgetDropsOptionalSections removes sections in drop order until the prompt fits.
+func TestPromptBudgetDropsOptionalSections(t *testing.T) {
+	t.Parallel()
+	root := writeBudgetRepo(t)
+	var warnings []string
+	input := budgetInput(root, PromptBudget{
+		CLI:          "gemini",
+		PromptBudget: config.PromptBudget{MaxTokens: 420, OnExceed: config.PromptBudgetDrop},
+		DropOrder:    []string{config.PromptSectionContext, config.PromptSectionCustomGlobal, config.PromptSectionCustomRole},
+	})
+	input.Warn = func(message string) { warnings = append(warnings, message) }
+
+	result, err := StageEnvAndPrompts(input)
+	if err != nil {
+		t.Fatalf("StageEnvAndPrompts: %v", err)
+	}
+	wantDropped := []string{"_governator/context.md", "_governator/custom-prompts/_global.md"}
+	if strings.Join(result.DroppedPrompts, ",") != strings.Join(wantDropped, ",") {
+		t.Fatalf("dropped = %v, want %v", result.DroppedPrompts, wantDropped)
+	}
+	for _, prompt := range result.PromptFiles {
+		if prompt == "_governator/context.md" || prompt == "_governator/custom-prompts/_global.md" {
+			t.Fatalf("dropped prompt %s still staged: %v", prompt, result.PromptFiles)
+		}
+	}
+	if result.PromptTokens > 420 {
+		t.Fatalf("prompt tokens = %d, want <= 420", result.PromptTokens)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "dropped [_governator/context.md, _governator/custom-prompts/_global.md]") {
+		t.Fatalf("warnings = %v", warnings)
+	}
+}
+
+// TestPromptBudgetBlocks rejects prompts over max_tokens with a clear reason.
+func TestPromptBudgetBlocks(t *testing.T) {
+	t.Parallel()
+	root := writeBudgetRepo(t)
+
+	_, err := StageEnvAndPrompts(budgetInput(root, PromptBudget{
+		CLI:          "claude",
+		PromptBudget: config.PromptBudget{MaxTokens: 100, OnExceed: config.PromptBudgetBlock},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "over the claude max_tokens budget of 100") {
+		t.Fatalf("block error = %v", err)
+	}
+
+	_, err = StageEnvAndPrompts(budgetInput(root, PromptBudget{
+		PromptBudget: config.PromptBudget{MaxTokens: 100, OnExceed: config.PromptBudgetDrop},
+		DropOrder:    config.PromptSectionDropKinds,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "default max_tokens budget of 100; still ~") {
+		t.Fatalf("drop-then-block error = %v", err)
+	}
+}
+
+// TestPromptBudgetForRole selects the budget for the role's CLI.
+func TestPromptBudgetForRole(t *testing.T) {
+	t.Parallel()
+	cfg := config.Config{
+		Workers: config.WorkersConfig{CLI: config.WorkerCLI{Default: "codex", Roles: map[string]string{"reviewer": "gemini"}}},
+		PromptBudget: config.PromptBudgetConfig{
+			Default:   config.PromptBudget{WarnTokens: 1000},
+			CLIs:      map[string]config.PromptBudget{"gemini": {MaxTokens: 500, OnExceed: config.PromptBudgetBlock}},
+			DropOrder: []string{config.PromptSectionContext},
+		},
+	}
+	reviewer := PromptBudgetForRole(cfg, "reviewer")
+	if reviewer.CLI != "gemini" || reviewer.MaxTokens != 500 || reviewer.OnExceed != config.PromptBudgetBlock {
+		t.Fatalf("reviewer budget = %+v", reviewer)
+	}
+	worker := PromptBudgetForRole(cfg, "worker")
+	if worker.CLI != "codex" || worker.WarnTokens != 1000 || len(worker.DropOrder) != 1 {
+		t.Fatalf("worker budget = %+v", worker)
+	}
+}
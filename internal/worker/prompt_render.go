@@ -0,0 +1,178 @@
+// Package worker provides prompt rendering for inspecting worker prompt stacks.
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+)
+
+// bytesPerToken approximates how many prompt bytes make up one model token.
+const bytesPerToken = 4
+
+// Required prompt section kinds; optional kinds are the config.PromptSection* constants.
+const (
+	PromptSectionContract = "contract"
+	PromptSectionRole     = "role"
+	PromptSectionTask     = "task"
+)
+
+// PromptSection is one file contributing to an assembled worker prompt.
+type PromptSection struct {
+	Path    string
+	Kind    string
+	Content string
+}
+
+// Bytes returns the size of the section content.
+func (section PromptSection) Bytes() int {
+	return len(section.Content)
+}
+
+// EstimatedTokens returns a rough token count for the section content.
+func (section PromptSection) EstimatedTokens() int {
+	return EstimateTokens(section.Bytes())
+}
+
+// RenderedPrompt is the full prompt stack a worker would receive, split by source file.
+type RenderedPrompt struct {
+	Role            string
+	Stage           roles.Stage
+	ReasoningEffort string
+	Sections        []PromptSection
+	// Dropped lists optional sections removed to fit the prompt budget.
+	Dropped []PromptSection
+}
+
+// Content returns the prompt text exactly as it is written to the staged prompt file.
+func (prompt RenderedPrompt) Content() string {
+	builder := &strings.Builder{}
+	for i, section := range prompt.Sections {
+		if i > 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString(section.Content)
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// Bytes returns the size of the assembled prompt.
+func (prompt RenderedPrompt) Bytes() int {
+	return len(prompt.Content())
+}
+
+// EstimatedTokens returns a rough token count for the assembled prompt.
+func (prompt RenderedPrompt) EstimatedTokens() int {
+	return EstimateTokens(prompt.Bytes())
+}
+
+// EstimateTokens approximates the token count for a prompt of the given byte size.
+func EstimateTokens(bytes int) int {
+	if bytes <= 0 {
+		return 0
+	}
+	return (bytes + bytesPerToken - 1) / bytesPerToken
+}
+
+// RenderPrompt assembles the prompt stack for input without staging any files.
+// It resolves prompt files and applies the prompt budget exactly as StageEnvAndPrompts
+// does; the worktree and worker state dir fields of input are ignored.
+func RenderPrompt(input StageInput) (RenderedPrompt, error) {
+	repoRoot := strings.TrimSpace(input.RepoRoot)
+	if repoRoot == "" {
+		return RenderedPrompt{}, errors.New("repo root is required")
+	}
+	if !input.Stage.Valid() {
+		return RenderedPrompt{}, fmt.Errorf("unsupported stage %q", input.Stage)
+	}
+	taskPath := strings.TrimSpace(input.TaskPromptPath)
+	if taskPath == "" {
+		taskPath = strings.TrimSpace(input.Task.Path)
+	}
+	if taskPath == "" {
+		return RenderedPrompt{}, errors.New("task path is required")
+	}
+	role := input.Role
+	if role == "" {
+		role = input.Task.Role
+	}
+	if role == "" {
+		return RenderedPrompt{}, errors.New("role is required")
+	}
+
+	absRepoRoot, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return RenderedPrompt{}, fmt.Errorf("resolve repo root %s: %w", repoRoot, err)
+	}
+	return assemblePrompt(absRepoRoot, input, role, taskPath)
+}
+
+// assemblePrompt reads the ordered prompt files for role and applies the input's prompt budget.
+func assemblePrompt(absRepoRoot string, input StageInput, role index.Role, taskPath string) (RenderedPrompt, error) {
+	registry, err := roles.LoadRegistry(absRepoRoot, input.Warn)
+	if err != nil {
+		return RenderedPrompt{}, fmt.Errorf("load role registry: %w", err)
+	}
+	reasoningLevel := normalizeReasoningLevel(input.ReasoningEffort)
+	includeReasoning := shouldIncludeReasoningPrompt(reasoningLevel, input.AgentUsesCodex)
+	promptFiles, err := orderedPromptFiles(absRepoRoot, registry, role, reasoningLevel, taskPath, input.ExtraPromptPath, includeReasoning)
+	if err != nil {
+		return RenderedPrompt{}, err
+	}
+
+	sections := make([]PromptSection, 0, len(promptFiles))
+	for _, prompt := range promptFiles {
+		data, err := os.ReadFile(resolvePromptPath(absRepoRoot, prompt))
+		if err != nil {
+			return RenderedPrompt{}, fmt.Errorf("read prompt %s: %w", prompt, err)
+		}
+		sections = append(sections, PromptSection{
+			Path:    prompt,
+			Kind:    promptSectionKind(registry, role, reasoningLevel, taskPath, prompt),
+			Content: string(data),
+		})
+	}
+	for _, prompt := range input.ContextPrompts {
+		if prompt.Kind == "" {
+			prompt.Kind = config.PromptSectionContext
+		}
+		prompt.Path = filepath.ToSlash(prompt.Path)
+		sections = append(sections, prompt)
+	}
+	rendered := RenderedPrompt{
+		Role:            string(role),
+		Stage:           input.Stage,
+		ReasoningEffort: reasoningLevel,
+		Sections:        sections,
+	}
+	return applyPromptBudget(rendered, input.PromptBudget, input.Warn)
+}
+
+// promptSectionKind classifies a prompt file so the budget can tell optional sections apart.
+func promptSectionKind(registry roles.Registry, role index.Role, reasoningLevel string, taskPath string, prompt string) string {
+	if prompt == reasoningPromptPath(reasoningLevel) {
+		return config.PromptSectionReasoning
+	}
+	if prompt == workerContractPath {
+		return PromptSectionContract
+	}
+	if rolePrompt, ok := registry.RolePromptPath(role); ok && prompt == rolePrompt {
+		return PromptSectionRole
+	}
+	if global, ok := registry.CustomGlobalPromptPath(); ok && prompt == global {
+		return config.PromptSectionCustomGlobal
+	}
+	if custom, ok := registry.CustomRolePromptPath(role); ok && prompt == custom {
+		return config.PromptSectionCustomRole
+	}
+	if prompt == filepath.ToSlash(taskPath) {
+		return PromptSectionTask
+	}
+	return config.PromptSectionContext
+}
@@ -0,0 +1,113 @@
+// Tests for rendering worker prompt stacks without staging.
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+)
+
+// TestRenderPromptMatchesStagedPrompt ensures rendered sections reproduce the staged prompt file.
+func TestRenderPromptMatchesStagedPrompt(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "_governator", "roles", "worker.md"), "role prompt")
+	writeFile(t, filepath.Join(root, "_governator", "custom-prompts", "_global.md"), "global prompt")
+	writeFile(t, filepath.Join(root, "_governator", "worker-contract.md"), "worker contract")
+	writeFile(t, filepath.Join(root, "_governator", "reasoning", "high.md"), "reasoning prompt")
+	writeFile(t, filepath.Join(root, "_governator", "tasks", "T-010.md"), "task content\n")
+
+	input := StageInput{
+		RepoRoot:        root,
+		WorktreeRoot:    root,
+		Task:            index.Task{ID: "T-010", Path: "_governator/tasks/T-010.md", Role: "worker"},
+		Stage:           roles.StageReview,
+		ReasoningEffort: "high",
+		WorkerStateDir:  workerStateDirPath(root),
+	}
+	rendered, err := RenderPrompt(input)
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	wantPaths := []string{
+		"_governator/reasoning/high.md",
+		"_governator/worker-contract.md",
+		"_governator/roles/worker.md",
+		"_governator/custom-prompts/_global.md",
+		"_governator/tasks/T-010.md",
+	}
+	if len(rendered.Sections) != len(wantPaths) {
+		t.Fatalf("sections = %d, want %d", len(rendered.Sections), len(wantPaths))
+	}
+	for i, want := range wantPaths {
+		if rendered.Sections[i].Path != want {
+			t.Fatalf("section %d path = %q, want %q", i, rendered.Sections[i].Path, want)
+		}
+	}
+	if rendered.Sections[4].Bytes() != len("task content\n") {
+		t.Fatalf("task bytes = %d", rendered.Sections[4].Bytes())
+	}
+	if rendered.Role != "worker" || rendered.ReasoningEffort != "high" {
+		t.Fatalf("rendered metadata = %#v", rendered)
+	}
+
+	staged, err := StageEnvAndPrompts(input)
+	if err != nil {
+		t.Fatalf("StageEnvAndPrompts: %v", err)
+	}
+	stagedPrompt, err := os.ReadFile(staged.PromptPath)
+	if err != nil {
+		t.Fatalf("read staged prompt: %v", err)
+	}
+	if rendered.Content()+"\n" != string(stagedPrompt) {
+		t.Fatalf("rendered content = %q, staged = %q", rendered.Content(), string(stagedPrompt))
+	}
+}
+
+// TestRenderPromptRoleOverride uses the requested role's prompts instead of the task role.
+func TestRenderPromptRoleOverride(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "_governator", "roles", "worker.md"), "role prompt")
+	writeFile(t, filepath.Join(root, "_governator", "roles", "reviewer.md"), "reviewer prompt")
+	writeFile(t, filepath.Join(root, "_governator", "worker-contract.md"), "worker contract")
+	writeFile(t, filepath.Join(root, "_governator", "tasks", "T-011.md"), "task content")
+
+	rendered, err := RenderPrompt(StageInput{
+		RepoRoot: root,
+		Task:     index.Task{ID: "T-011", Path: "_governator/tasks/T-011.md", Role: "worker"},
+		Stage:    roles.StageWork,
+		Role:     "reviewer",
+	})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	if rendered.Sections[1].Path != "_governator/roles/reviewer.md" {
+		t.Fatalf("role section = %q", rendered.Sections[1].Path)
+	}
+	if _, err := os.Stat(filepath.Join(root, "_governator", "_local-state")); !os.IsNotExist(err) {
+		t.Fatalf("RenderPrompt should not stage files, stat err = %v", err)
+	}
+
+	if _, err := RenderPrompt(StageInput{
+		RepoRoot: root,
+		Task:     index.Task{ID: "T-011", Path: "_governator/tasks/T-011.md", Role: "missing"},
+		Stage:    roles.StageWork,
+	}); err == nil {
+		t.Fatal("expected error for unknown role")
+	}
+}
+
+// TestEstimateTokens rounds partial tokens up.
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+	cases := map[int]int{0: 0, -1: 0, 1: 1, 4: 1, 5: 2, 400: 100}
+	for bytes, want := range cases {
+		if got := EstimateTokens(bytes); got != want {
+			t.Fatalf("EstimateTokens(%d) = %d, want %d", bytes, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package worker provides rate-limit detection for worker logs.
+package worker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+)
+
+const (
+	// stdoutLogFileName is the worker stdout capture inside the worker state dir.
+	stdoutLogFileName = "stdout.log"
+	// stderrLogFileName is the worker stderr capture inside the worker state dir.
+	stderrLogFileName = "stderr.log"
+	// rateLimitMatchMaxLen bounds the matched log line reported to callers.
+	rateLimitMatchMaxLen = 200
+)
+
+// RateLimitMatch describes a rate-limit signature found in worker logs.
+type RateLimitMatch struct {
+	LogPath string
+	Pattern string
+	Line    string
+}
+
+// CLIForRole returns the built-in CLI name used for the role, or an empty string for custom commands.
+func CLIForRole(cfg config.Config, role index.Role) string {
+	return selectCLIName(cfg, role)
+}
+
+// DetectRateLimit scans the worker stderr and stdout logs for the supplied rate-limit patterns.
+// Stdout also carries the agent's own output, so patterns should be anchored to the lines a CLI
+// prints as errors rather than match rate-limit text anywhere.
+func DetectRateLimit(workerStateDir string, patterns []string) (RateLimitMatch, bool, error) {
+	if strings.TrimSpace(workerStateDir) == "" {
+		return RateLimitMatch{}, false, errors.New("worker state dir is required")
+	}
+	if len(patterns) == 0 {
+		return RateLimitMatch{}, false, nil
+	}
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr, err := regexp.Compile(pattern)
+		if err != nil {
+			return RateLimitMatch{}, false, fmt.Errorf("compile rate-limit pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, expr)
+	}
+	for _, name := range []string{stderrLogFileName, stdoutLogFileName} {
+		match, found, err := scanLogForRateLimit(filepath.Join(workerStateDir, name), compiled)
+		if err != nil {
+			return RateLimitMatch{}, false, err
+		}
+		if found {
+			return match, true, nil
+		}
+	}
+	return RateLimitMatch{}, false, nil
+}
+
+// scanLogForRateLimit returns the first log line matching any of the expressions.
+func scanLogForRateLimit(path string, compiled []*regexp.Regexp) (RateLimitMatch, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return RateLimitMatch{}, false, nil
+		}
+		return RateLimitMatch{}, false, fmt.Errorf("open worker log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, expr := range compiled {
+			if expr.MatchString(line) {
+				return RateLimitMatch{
+					LogPath: path,
+					Pattern: expr.String(),
+					Line:    truncateRateLimitLine(line),
+				}, true, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return RateLimitMatch{}, false, fmt.Errorf("read worker log %s: %w", path, err)
+	}
+	return RateLimitMatch{}, false, nil
+}
+
+// truncateRateLimitLine trims and bounds a matched log line for reporting.
+func truncateRateLimitLine(line string) string {
+	line = strings.TrimSpace(line)
+	if len(line) > rateLimitMatchMaxLen {
+		return line[:rateLimitMatchMaxLen] + "..."
+	}
+	return line
+}
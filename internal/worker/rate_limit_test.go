@@ -0,0 +1,105 @@
+// Package worker provides tests for rate-limit detection.
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDetectRateLimitMatchesStderr ensures stderr signatures are detected.
+func TestDetectRateLimitMatchesStderr(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRateLimitLog(t, filepath.Join(dir, stdoutLogFileName), "working on the task\n")
+	writeRateLimitLog(t, filepath.Join(dir, stderrLogFileName), "error: 429 Too Many Requests\n")
+
+	match, found, err := DetectRateLimit(dir, []string{`(?i)\b429 too many requests\b`})
+	if err != nil {
+		t.Fatalf("DetectRateLimit returned error: %v", err)
+	}
+	if !found {
+		t.Fatal("expected rate limit match")
+	}
+	if match.Line != "error: 429 Too Many Requests" {
+		t.Fatalf("match line = %q", match.Line)
+	}
+	if match.LogPath != filepath.Join(dir, stderrLogFileName) {
+		t.Fatalf("match log path = %q", match.LogPath)
+	}
+}
+
+// TestDetectRateLimitNoMatch ensures unrelated output is not treated as a rate limit.
+func TestDetectRateLimitNoMatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRateLimitLog(t, filepath.Join(dir, stdoutLogFileName), "implemented the rate limiter module\n")
+
+	_, found, err := DetectRateLimit(dir, []string{`(?i)rate limit reached`})
+	if err != nil {
+		t.Fatalf("DetectRateLimit returned error: %v", err)
+	}
+	if found {
+		t.Fatal("expected no rate limit match")
+	}
+}
+
+// TestDetectRateLimitMatchesStdout ensures CLIs that report provider errors on stdout are detected.
+func TestDetectRateLimitMatchesStdout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRateLimitLog(t, filepath.Join(dir, stderrLogFileName), "")
+	writeRateLimitLog(t, filepath.Join(dir, stdoutLogFileName), "working on the task\nAPI Error: 429 rate_limit_error\n")
+
+	match, found, err := DetectRateLimit(dir, []string{`(?i)^\W*(api )?error\b.*\brate_limit_error\b`})
+	if err != nil {
+		t.Fatalf("DetectRateLimit returned error: %v", err)
+	}
+	if !found {
+		t.Fatal("expected rate limit match on stdout")
+	}
+	if match.LogPath != filepath.Join(dir, stdoutLogFileName) || match.Line != "API Error: 429 rate_limit_error" {
+		t.Fatalf("match = %+v", match)
+	}
+}
+
+// TestDetectRateLimitIgnoresQuotedStdout ensures anchored patterns skip agent output that quotes
+// rate-limit text.
+func TestDetectRateLimitIgnoresQuotedStdout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRateLimitLog(t, filepath.Join(dir, stdoutLogFileName), "handled HTTP 429 Too Many Requests in the client retry loop\n")
+
+	_, found, err := DetectRateLimit(dir, []string{`(?i)^\W*(\w+ )?error\b.*\b429 too many requests\b`})
+	if err != nil {
+		t.Fatalf("DetectRateLimit returned error: %v", err)
+	}
+	if found {
+		t.Fatal("expected quoted stdout text to be ignored")
+	}
+}
+
+// TestDetectRateLimitMissingLogs ensures missing logs are not an error.
+func TestDetectRateLimitMissingLogs(t *testing.T) {
+	t.Parallel()
+
+	_, found, err := DetectRateLimit(t.TempDir(), []string{`RESOURCE_EXHAUSTED`})
+	if err != nil {
+		t.Fatalf("DetectRateLimit returned error: %v", err)
+	}
+	if found {
+		t.Fatal("expected no rate limit match")
+	}
+}
+
+// writeRateLimitLog writes log content for rate-limit tests.
+func writeRateLimitLog(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write log %s: %v", path, err)
+	}
+}
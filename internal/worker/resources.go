@@ -0,0 +1,455 @@
+// Package worker provides resource limit enforcement and detection for worker processes.
+package worker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+)
+
+const (
+	resourcesFileName = "resources.json"
+
+	resourceMechanismCgroup = "cgroup"
+	resourceMechanismRlimit = "rlimit"
+
+	cgroupPeriodMicros = 100000
+	bytesPerMB         = 1024 * 1024
+
+	// exitCodeSIGXFSZ is the shell exit status of a process killed by SIGXFSZ.
+	exitCodeSIGXFSZ = 128 + 25
+	// exitCodeSignalBase is added to the signal number when a shell reports a signal death.
+	exitCodeSignalBase = 128
+
+	// rlimitStderrTailLines bounds how much of the end of stderr is searched for exhaustion messages.
+	rlimitStderrTailLines = 20
+	// cgroupSnapshotPrefix names the copies of cgroup event files kept in the worker state dir.
+	cgroupSnapshotPrefix = "cgroup-"
+)
+
+// cgroupEventFiles are copied into the worker state dir before the worker cgroup is removed.
+var cgroupEventFiles = []string{"memory.events", "pids.events"}
+
+// Resource names reported when a worker hits a limit.
+const (
+	ResourceMemory    = "memory"
+	ResourceProcesses = "processes"
+	ResourceFileSize  = "file_size"
+)
+
+// cgroupRoot, procSelfCgroup, and resourceLookPath are overridable for tests.
+var (
+	cgroupRoot       = "/sys/fs/cgroup"
+	procSelfCgroup   = "/proc/self/cgroup"
+	resourceLookPath = exec.LookPath
+)
+
+// rlimit exhaustion messages commonly printed by runtimes and shells.
+var (
+	memoryExhaustedPattern  = regexp.MustCompile(`(?i)cannot allocate memory|out of memory|heap out of memory|std::bad_alloc|MemoryError`)
+	processExhaustedPattern = regexp.MustCompile(`(?i)fork: (retry: )?resource temporarily unavailable|cannot fork|can't fork`)
+)
+
+// resourcePlan records how limits were applied to a worker so exits can be attributed later.
+type resourcePlan struct {
+	Limits     config.ResourceLimits `json:"limits"`
+	Mechanism  string                `json:"mechanism"`
+	CgroupPath string                `json:"cgroup_path,omitempty"`
+	Prlimit    bool                  `json:"prlimit,omitempty"`
+	Taskset    bool                  `json:"taskset,omitempty"`
+}
+
+// ResourceLimitHit describes the limit a worker exceeded.
+type ResourceLimitHit struct {
+	Resource string
+	Detail   string
+}
+
+// planResourceLimits prepares a cgroup v2 sub-group when one is writable and falls back to rlimits.
+func planResourceLimits(limits config.ResourceLimits, taskID string, stage roles.Stage, workerStateDir string, warn func(string)) (*resourcePlan, error) {
+	if !limits.Enabled() {
+		return nil, nil
+	}
+	plan := &resourcePlan{Limits: limits, Mechanism: resourceMechanismRlimit}
+	if limits.MemoryMB > 0 || limits.CPUs > 0 || limits.MaxProcesses > 0 {
+		if path, ok := createWorkerCgroup(limits, taskID, stage); ok {
+			plan.Mechanism = resourceMechanismCgroup
+			plan.CgroupPath = path
+		}
+	}
+	if plan.needsRlimits() {
+		_, err := resourceLookPath("prlimit")
+		plan.Prlimit = err == nil
+	}
+	if plan.Mechanism == resourceMechanismRlimit && limits.CPUs > 0 {
+		if _, err := resourceLookPath("taskset"); err == nil {
+			plan.Taskset = true
+		} else {
+			emitWarning(warn, fmt.Sprintf("task %s: cpu limit needs cgroup v2 or taskset; running without it", taskID))
+		}
+	}
+	if plan.Mechanism == resourceMechanismRlimit && limits.MaxProcesses > 0 && !plan.Prlimit {
+		emitWarning(warn, fmt.Sprintf("task %s: process limit needs cgroup v2 or prlimit; running without it", taskID))
+	}
+	if err := writeResourcePlan(workerStateDir, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// needsRlimits reports whether any limit must be applied through setrlimit.
+func (plan *resourcePlan) needsRlimits() bool {
+	if plan.Limits.MaxFileSizeMB > 0 {
+		return true
+	}
+	if plan.Mechanism == resourceMechanismCgroup {
+		return false
+	}
+	return plan.Limits.MemoryMB > 0 || plan.Limits.MaxProcesses > 0
+}
+
+// resourceLimitFailure is appended to wrapper lines so a limit that cannot be applied is logged.
+const resourceLimitFailure = ` || echo "governator: failed to apply resource limits" >&2`
+
+// wrapperLines returns the dispatch wrapper lines that confine the wrapper shell before the agent starts.
+// The worker cgroup is joined by the agent itself; see agentCommandLine.
+func (plan *resourcePlan) wrapperLines() []string {
+	lines := []string{}
+	failure := resourceLimitFailure
+	if plan.needsRlimits() {
+		limits := plan.Limits
+		if plan.Prlimit {
+			args := []string{"prlimit", "--pid", "$$"}
+			if limits.MaxFileSizeMB > 0 {
+				args = append(args, "--fsize="+rlimitPair(int64(limits.MaxFileSizeMB)*bytesPerMB))
+			}
+			if plan.Mechanism == resourceMechanismRlimit && limits.MemoryMB > 0 {
+				args = append(args, "--data="+rlimitPair(int64(limits.MemoryMB)*bytesPerMB))
+			}
+			if plan.Mechanism == resourceMechanismRlimit && limits.MaxProcesses > 0 {
+				args = append(args, "--nproc="+rlimitPair(int64(limits.MaxProcesses)))
+			}
+			lines = append(lines, strings.Join(args, " ")+failure)
+		} else {
+			if limits.MaxFileSizeMB > 0 {
+				lines = append(lines, "ulimit -f "+strconv.FormatInt(int64(limits.MaxFileSizeMB)*bytesPerMB/512, 10)+failure)
+			}
+			if plan.Mechanism == resourceMechanismRlimit && limits.MemoryMB > 0 {
+				lines = append(lines, "ulimit -d "+strconv.Itoa(limits.MemoryMB*1024)+failure)
+			}
+		}
+	}
+	if plan.Taskset {
+		lines = append(lines, "taskset -p -c 0-"+strconv.Itoa(plan.Limits.CPUs-1)+" $$ >/dev/null"+failure)
+	}
+	return lines
+}
+
+// agentCommandLine moves the agent into the worker cgroup before it starts. The wrapper stays
+// outside so it can remove the cgroup once the agent exits.
+func (plan *resourcePlan) agentCommandLine(commandLine string) string {
+	if plan.CgroupPath == "" {
+		return commandLine
+	}
+	script := `echo $$ > "$1"` + resourceLimitFailure + `; exec sh -c "$2"`
+	return "sh -c " + shellEscapeArg(script) + " sh " +
+		shellEscapeArg(filepath.Join(plan.CgroupPath, "cgroup.procs")) + " " + shellEscapeArg(commandLine)
+}
+
+// cleanupLines returns the wrapper lines run after the agent exits: they keep a copy of the
+// cgroup event counters for limit detection and remove the worker cgroup.
+func (plan *resourcePlan) cleanupLines(workerStateDir string) []string {
+	if plan.CgroupPath == "" {
+		return nil
+	}
+	lines := []string{}
+	for _, fileName := range cgroupEventFiles {
+		lines = append(lines, "cat "+shellEscapeArg(filepath.Join(plan.CgroupPath, fileName))+" > "+
+			shellEscapeArg(cgroupSnapshotPath(workerStateDir, fileName))+" 2>/dev/null")
+	}
+	return append(lines, "rmdir "+shellEscapeArg(plan.CgroupPath)+" 2>/dev/null")
+}
+
+// cgroupSnapshotPath returns where a cgroup event file is copied in the worker state dir.
+func cgroupSnapshotPath(workerStateDir string, fileName string) string {
+	return filepath.Join(workerStateDir, cgroupSnapshotPrefix+fileName)
+}
+
+// ReleaseResources removes the worker cgroup, if one was created, after copying its event
+// counters into the worker state dir. It is safe to call more than once and is a no-op for
+// cgroups the dispatch wrapper already removed. A cgroup that still holds processes is left
+// for a later call.
+func ReleaseResources(workerStateDir string) error {
+	plan, err := readResourcePlan(workerStateDir)
+	if err != nil || plan == nil {
+		return err
+	}
+	releaseCgroup(plan, workerStateDir)
+	return nil
+}
+
+// releaseCgroup snapshots the cgroup event files that have no copy yet and removes the cgroup.
+func releaseCgroup(plan *resourcePlan, workerStateDir string) {
+	if plan.CgroupPath == "" {
+		return
+	}
+	if _, err := os.Stat(plan.CgroupPath); err != nil {
+		return
+	}
+	for _, fileName := range cgroupEventFiles {
+		snapshot := cgroupSnapshotPath(workerStateDir, fileName)
+		if _, err := os.Stat(snapshot); err == nil {
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(plan.CgroupPath, fileName)); err == nil {
+			_ = os.WriteFile(snapshot, data, 0o644)
+		}
+	}
+	_ = os.Remove(plan.CgroupPath)
+}
+
+// rlimitPair formats a soft:hard limit pair for prlimit.
+func rlimitPair(value int64) string {
+	text := strconv.FormatInt(value, 10)
+	return text + ":" + text
+}
+
+// createWorkerCgroup makes a cgroup v2 child of the supervisor's cgroup when the needed
+// controllers are delegated to it. It reports false whenever any step is not permitted.
+func createWorkerCgroup(limits config.ResourceLimits, taskID string, stage roles.Stage) (string, bool) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", false
+	}
+	parent, ok := currentCgroupPath()
+	if !ok {
+		return "", false
+	}
+	data, err := os.ReadFile(filepath.Join(parent, "cgroup.subtree_control"))
+	if err != nil {
+		return "", false
+	}
+	enabled := strings.Fields(string(data))
+	settings := map[string]string{}
+	required := []string{}
+	if limits.MemoryMB > 0 {
+		required = append(required, "memory")
+		settings["memory.max"] = strconv.FormatInt(int64(limits.MemoryMB)*bytesPerMB, 10)
+	}
+	if limits.CPUs > 0 {
+		required = append(required, "cpu")
+		settings["cpu.max"] = fmt.Sprintf("%d %d", limits.CPUs*cgroupPeriodMicros, cgroupPeriodMicros)
+	}
+	if limits.MaxProcesses > 0 {
+		required = append(required, "pids")
+		settings["pids.max"] = strconv.Itoa(limits.MaxProcesses)
+	}
+	for _, controller := range required {
+		if !containsString(enabled, controller) {
+			return "", false
+		}
+	}
+
+	name := fmt.Sprintf("governator-%s-%s-%d", taskID, stage, time.Now().UnixNano())
+	path := filepath.Join(parent, name)
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return "", false
+	}
+	for file, value := range settings {
+		if err := os.WriteFile(filepath.Join(path, file), []byte(value), 0o644); err != nil {
+			_ = os.Remove(path)
+			return "", false
+		}
+	}
+	if limits.MemoryMB > 0 {
+		// Keep the limit from being softened by swap; not every kernel exposes the file.
+		_ = os.WriteFile(filepath.Join(path, "memory.swap.max"), []byte("0"), 0o644)
+	}
+	return path, true
+}
+
+// currentCgroupPath resolves this process's cgroup v2 directory.
+func currentCgroupPath() (string, bool) {
+	data, err := os.ReadFile(procSelfCgroup)
+	if err != nil {
+		return "", false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rel, ok := strings.CutPrefix(strings.TrimSpace(line), "0::"); ok {
+			return filepath.Join(cgroupRoot, filepath.Clean("/"+rel)), true
+		}
+	}
+	return "", false
+}
+
+// writeResourcePlan persists the resource plan beside the worker logs.
+func writeResourcePlan(workerStateDir string, plan *resourcePlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode resource plan: %w", err)
+	}
+	path := filepath.Join(workerStateDir, resourcesFileName)
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write resource plan %s: %w", path, err)
+	}
+	return nil
+}
+
+// readResourcePlan loads the resource plan for a worker, if one was written.
+func readResourcePlan(workerStateDir string) (*resourcePlan, error) {
+	path := filepath.Join(workerStateDir, resourcesFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read resource plan %s: %w", path, err)
+	}
+	var plan resourcePlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("decode resource plan %s: %w", path, err)
+	}
+	return &plan, nil
+}
+
+// DetectResourceLimit reports whether a failed worker was stopped by one of its resource limits.
+// Cgroup event counters are authoritative. Under rlimits a memory failure is only reported for a
+// worker killed by a signal whose stderr ends with an allocation failure, so output that merely
+// mentions running out of memory is not blamed on the limit. Any worker cgroup is released once
+// inspected.
+func DetectResourceLimit(workerStateDir string, exitCode int) (ResourceLimitHit, bool, error) {
+	plan, err := readResourcePlan(workerStateDir)
+	if err != nil || plan == nil {
+		return ResourceLimitHit{}, false, err
+	}
+	limits := plan.Limits
+	if plan.CgroupPath != "" {
+		releaseCgroup(plan, workerStateDir)
+		if limits.MemoryMB > 0 && cgroupEventCount(workerStateDir, "memory.events", "oom_kill") > 0 {
+			return memoryLimitHit(limits), true, nil
+		}
+		if limits.MaxProcesses > 0 && cgroupEventCount(workerStateDir, "pids.events", "max") > 0 {
+			return processLimitHit(limits), true, nil
+		}
+	}
+	if limits.MaxFileSizeMB > 0 && exitCode == exitCodeSIGXFSZ {
+		return ResourceLimitHit{
+			Resource: ResourceFileSize,
+			Detail:   fmt.Sprintf("max file size %d MB", limits.MaxFileSizeMB),
+		}, true, nil
+	}
+	if plan.Mechanism != resourceMechanismRlimit {
+		return ResourceLimitHit{}, false, nil
+	}
+	checkMemory := limits.MemoryMB > 0 && exitCode > exitCodeSignalBase
+	checkProcesses := limits.MaxProcesses > 0 && plan.Prlimit
+	if !checkMemory && !checkProcesses {
+		return ResourceLimitHit{}, false, nil
+	}
+	tail, err := stderrTail(workerStateDir, rlimitStderrTailLines)
+	if err != nil {
+		return ResourceLimitHit{}, false, err
+	}
+	for _, line := range tail {
+		if checkMemory && memoryExhaustedPattern.MatchString(line) {
+			return memoryLimitHit(limits), true, nil
+		}
+		if checkProcesses && processExhaustedPattern.MatchString(line) {
+			return processLimitHit(limits), true, nil
+		}
+	}
+	return ResourceLimitHit{}, false, nil
+}
+
+// stderrTail returns up to limit trailing lines of the worker stderr log.
+func stderrTail(workerStateDir string, limit int) ([]string, error) {
+	file, err := os.Open(filepath.Join(workerStateDir, stderrLogFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open worker stderr: %w", err)
+	}
+	defer file.Close()
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > limit {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan worker stderr: %w", err)
+	}
+	return lines, nil
+}
+
+func memoryLimitHit(limits config.ResourceLimits) ResourceLimitHit {
+	return ResourceLimitHit{Resource: ResourceMemory, Detail: fmt.Sprintf("memory limit %d MB", limits.MemoryMB)}
+}
+
+func processLimitHit(limits config.ResourceLimits) ResourceLimitHit {
+	return ResourceLimitHit{Resource: ResourceProcesses, Detail: fmt.Sprintf("process limit %d", limits.MaxProcesses)}
+}
+
+// cgroupEventCount reads a counter from the copy of a cgroup events file in the worker state
+// dir, returning zero when unavailable.
+func cgroupEventCount(workerStateDir string, fileName string, key string) int {
+	data, err := os.ReadFile(cgroupSnapshotPath(workerStateDir, fileName))
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			count, err := strconv.Atoi(fields[1])
+			if err == nil {
+				return count
+			}
+		}
+	}
+	return 0
+}
+
+// FailedExitResult builds the blocked ingest result for a worker that exited non-zero,
+// naming the resource limit when one was responsible.
+func FailedExitResult(workerStateDir string, exitCode int, warn func(string)) IngestResult {
+	result := IngestResult{
+		Success:     false,
+		NewState:    index.TaskStateBlocked,
+		BlockReason: fmt.Sprintf("worker process exited with code %d", exitCode),
+	}
+	hit, found, err := DetectResourceLimit(workerStateDir, exitCode)
+	if err != nil {
+		emitWarning(warn, fmt.Sprintf("failed to check worker resource limits: %v", err))
+		return result
+	}
+	if found {
+		result.ResourceLimit = hit.Resource
+		result.BlockReason = fmt.Sprintf("worker exceeded %s (exit code %d)", hit.Detail, exitCode)
+	}
+	return result
+}
+
+// containsString reports whether values contains target.
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+package worker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+)
+
+// stubResourceHost points cgroup discovery at a temp tree and controls which tools are found.
+func stubResourceHost(t *testing.T, tools ...string) string {
+	t.Helper()
+	restoreRoot, restoreProc, restoreLookPath := cgroupRoot, procSelfCgroup, resourceLookPath
+	t.Cleanup(func() {
+		cgroupRoot, procSelfCgroup, resourceLookPath = restoreRoot, restoreProc, restoreLookPath
+	})
+	root := t.TempDir()
+	cgroupRoot = filepath.Join(root, "cgroup")
+	procSelfCgroup = filepath.Join(root, "self-cgroup")
+	resourceLookPath = func(name string) (string, error) {
+		for _, tool := range tools {
+			if tool == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+	if err := os.MkdirAll(cgroupRoot, 0o755); err != nil {
+		t.Fatalf("mkdir cgroup root: %v", err)
+	}
+	return root
+}
+
+// enableFakeCgroupV2 lays out a delegated cgroup v2 parent with the given controllers.
+func enableFakeCgroupV2(t *testing.T, controllers string) string {
+	t.Helper()
+	parent := filepath.Join(cgroupRoot, "user.slice", "governator.scope")
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		t.Fatalf("mkdir cgroup parent: %v", err)
+	}
+	writeTestFile(t, filepath.Join(cgroupRoot, "cgroup.controllers"), "cpu memory pids\n")
+	writeTestFile(t, filepath.Join(parent, "cgroup.subtree_control"), controllers+"\n")
+	writeTestFile(t, procSelfCgroup, "0::/user.slice/governator.scope\n")
+	return parent
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestPlanResourceLimitsUsesDelegatedCgroup(t *testing.T) {
+	stubResourceHost(t, "prlimit", "taskset")
+	parent := enableFakeCgroupV2(t, "cpu memory pids")
+	stateDir := t.TempDir()
+
+	limits := config.ResourceLimits{MemoryMB: 512, CPUs: 2, MaxProcesses: 64, MaxFileSizeMB: 10}
+	plan, err := planResourceLimits(limits, "T-1", roles.StageWork, stateDir, nil)
+	if err != nil {
+		t.Fatalf("plan resource limits: %v", err)
+	}
+	if plan.Mechanism != resourceMechanismCgroup || filepath.Dir(plan.CgroupPath) != parent {
+		t.Fatalf("plan = %+v, want cgroup under %s", plan, parent)
+	}
+	for file, want := range map[string]string{
+		"memory.max": "536870912",
+		"cpu.max":    "200000 100000",
+		"pids.max":   "64",
+	} {
+		data, err := os.ReadFile(filepath.Join(plan.CgroupPath, file))
+		if err != nil || string(data) != want {
+			t.Fatalf("%s = %q (%v), want %q", file, data, err, want)
+		}
+	}
+
+	lines := strings.Join(plan.wrapperLines(), "\n")
+	if strings.Contains(lines, "cgroup.procs") {
+		t.Fatalf("wrapper shell should stay outside the cgroup:\n%s", lines)
+	}
+	if agent := plan.agentCommandLine("run-agent"); !strings.Contains(agent, filepath.Join(plan.CgroupPath, "cgroup.procs")) || !strings.HasSuffix(agent, " run-agent") {
+		t.Fatalf("agent command line should join the cgroup: %s", agent)
+	}
+	if cleanup := strings.Join(plan.cleanupLines(stateDir), "\n"); !strings.Contains(cleanup, "rmdir "+plan.CgroupPath) {
+		t.Fatalf("cleanup lines should remove the cgroup:\n%s", cleanup)
+	}
+	if !strings.Contains(lines, "prlimit --pid $$ --fsize=10485760:10485760 ||") {
+		t.Fatalf("wrapper lines should cap file size via prlimit only:\n%s", lines)
+	}
+	if strings.Contains(lines, "--data") || strings.Contains(lines, "taskset") {
+		t.Fatalf("cgroup-managed limits should not also use rlimits:\n%s", lines)
+	}
+	if saved, err := readResourcePlan(stateDir); err != nil || saved == nil || saved.CgroupPath != plan.CgroupPath {
+		t.Fatalf("saved plan = %+v (%v), want persisted plan", saved, err)
+	}
+}
+
+func TestPlanResourceLimitsFallsBackToRlimits(t *testing.T) {
+	stubResourceHost(t, "prlimit", "taskset")
+	enableFakeCgroupV2(t, "cpu pids") // memory not delegated
+
+	plan, err := planResourceLimits(config.ResourceLimits{MemoryMB: 256, CPUs: 1, MaxProcesses: 32}, "T-2", roles.StageTest, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("plan resource limits: %v", err)
+	}
+	if plan.Mechanism != resourceMechanismRlimit || plan.CgroupPath != "" {
+		t.Fatalf("plan = %+v, want rlimit fallback", plan)
+	}
+	lines := strings.Join(plan.wrapperLines(), "\n")
+	for _, want := range []string{
+		"prlimit --pid $$ --data=268435456:268435456 --nproc=32:32",
+		"taskset -p -c 0-0 $$",
+	} {
+		if !strings.Contains(lines, want) {
+			t.Fatalf("wrapper lines missing %q:\n%s", want, lines)
+		}
+	}
+
+	stubResourceHost(t)
+	var warnings []string
+	plan, err = planResourceLimits(config.ResourceLimits{MemoryMB: 256, MaxProcesses: 32, MaxFileSizeMB: 1}, "T-3", roles.StageWork, t.TempDir(), func(message string) {
+		warnings = append(warnings, message)
+	})
+	if err != nil {
+		t.Fatalf("plan resource limits: %v", err)
+	}
+	lines = strings.Join(plan.wrapperLines(), "\n")
+	if !strings.Contains(lines, "ulimit -f 2048") || !strings.Contains(lines, "ulimit -d 262144") {
+		t.Fatalf("expected ulimit fallback:\n%s", lines)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "process limit") {
+		t.Fatalf("warnings = %v, want process limit warning", warnings)
+	}
+}
+
+func TestDetectResourceLimit(t *testing.T) {
+	stubResourceHost(t, "prlimit")
+
+	t.Run("cgroup oom kill", func(t *testing.T) {
+		enableFakeCgroupV2(t, "memory")
+		stateDir := t.TempDir()
+		plan, err := planResourceLimits(config.ResourceLimits{MemoryMB: 128}, "T-1", roles.StageWork, stateDir, nil)
+		if err != nil {
+			t.Fatalf("plan resource limits: %v", err)
+		}
+		writeTestFile(t, filepath.Join(plan.CgroupPath, "memory.events"), "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n")
+		hit, found, err := DetectResourceLimit(stateDir, 137)
+		if err != nil || !found || hit.Resource != ResourceMemory {
+			t.Fatalf("detect = (%+v, %v, %v), want memory hit", hit, found, err)
+		}
+	})
+
+	t.Run("cgroup released by wrapper", func(t *testing.T) {
+		enableFakeCgroupV2(t, "memory")
+		stateDir := t.TempDir()
+		plan, err := planResourceLimits(config.ResourceLimits{MemoryMB: 128}, "T-4", roles.StageWork, stateDir, nil)
+		if err != nil {
+			t.Fatalf("plan resource limits: %v", err)
+		}
+		// The dispatch wrapper copies the counters and removes the cgroup once the agent exits.
+		writeTestFile(t, cgroupSnapshotPath(stateDir, "memory.events"), "oom 1\noom_kill 1\n")
+		if err := os.RemoveAll(plan.CgroupPath); err != nil {
+			t.Fatalf("remove cgroup: %v", err)
+		}
+		hit, found, err := DetectResourceLimit(stateDir, 137)
+		if err != nil || !found || hit.Resource != ResourceMemory {
+			t.Fatalf("detect = (%+v, %v, %v), want memory hit", hit, found, err)
+		}
+	})
+
+	t.Run("release resources", func(t *testing.T) {
+		enableFakeCgroupV2(t, "memory")
+		stateDir := t.TempDir()
+		plan, err := planResourceLimits(config.ResourceLimits{MemoryMB: 128}, "T-5", roles.StageWork, stateDir, nil)
+		if err != nil {
+			t.Fatalf("plan resource limits: %v", err)
+		}
+		for _, name := range []string{"memory.max", "memory.swap.max"} {
+			_ = os.Remove(filepath.Join(plan.CgroupPath, name))
+		}
+		if err := ReleaseResources(stateDir); err != nil {
+			t.Fatalf("release resources: %v", err)
+		}
+		if _, err := os.Stat(plan.CgroupPath); !os.IsNotExist(err) {
+			t.Fatalf("expected cgroup %s removed, stat err = %v", plan.CgroupPath, err)
+		}
+		if err := ReleaseResources(stateDir); err != nil {
+			t.Fatalf("second release: %v", err)
+		}
+	})
+
+	t.Run("file size signal", func(t *testing.T) {
+		stateDir := t.TempDir()
+		if _, err := planResourceLimits(config.ResourceLimits{MaxFileSizeMB: 5}, "T-2", roles.StageWork, stateDir, nil); err != nil {
+			t.Fatalf("plan resource limits: %v", err)
+		}
+		hit, found, err := DetectResourceLimit(stateDir, exitCodeSIGXFSZ)
+		if err != nil || !found || hit.Resource != ResourceFileSize {
+			t.Fatalf("detect = (%+v, %v, %v), want file size hit", hit, found, err)
+		}
+		if _, found, _ := DetectResourceLimit(stateDir, 1); found {
+			t.Fatal("plain failure should not be attributed to a limit")
+		}
+	})
+
+	t.Run("rlimit memory message", func(t *testing.T) {
+		stubResourceHost(t, "prlimit")
+		stateDir := t.TempDir()
+		if _, err := planResourceLimits(config.ResourceLimits{MemoryMB: 64}, "T-3", roles.StageWork, stateDir, nil); err != nil {
+			t.Fatalf("plan resource limits: %v", err)
+		}
+		writeTestFile(t, filepath.Join(stateDir, stderrLogFileName), "building...\nFATAL ERROR: Reached heap limit Allocation failed - JavaScript heap out of memory\n")
+		result := FailedExitResult(stateDir, 134, nil)
+		if result.ResourceLimit != ResourceMemory || result.NewState != index.TaskStateBlocked {
+			t.Fatalf("result = %+v, want memory limit block", result)
+		}
+		if result.BlockReason != "worker exceeded memory limit 64 MB (exit code 134)" {
+			t.Fatalf("block reason = %q", result.BlockReason)
+		}
+	})
+
+	t.Run("rlimit memory message without signal", func(t *testing.T) {
+		stubResourceHost(t, "prlimit")
+		stateDir := t.TempDir()
+		if _, err := planResourceLimits(config.ResourceLimits{MemoryMB: 64}, "T-6", roles.StageWork, stateDir, nil); err != nil {
+			t.Fatalf("plan resource limits: %v", err)
+		}
+		writeTestFile(t, filepath.Join(stateDir, stderrLogFileName), "FAIL test_alloc: expected MemoryError, got None\n")
+		if result := FailedExitResult(stateDir, 1, nil); result.ResourceLimit != "" {
+			t.Fatalf("result = %+v, want a plain failure", result)
+		}
+	})
+
+	t.Run("no plan", func(t *testing.T) {
+		result := FailedExitResult(t.TempDir(), 2, nil)
+		if result.ResourceLimit != "" || result.BlockReason != "worker process exited with code 2" {
+			t.Fatalf("result = %+v, want generic failure", result)
+		}
+	})
+}
+
+func TestDispatchWorkerEnforcesFileSizeLimit(t *testing.T) {
+	if _, err := exec.LookPath("prlimit"); err != nil {
+		t.Skip("prlimit not available")
+	}
+	stubResourceHost(t, "prlimit")
+	workDir := t.TempDir()
+	stateDir := filepath.Join(workDir, "worker-state")
+	input := DispatchInput{
+		Command:        []string{"sh", "-c", "head -c 3000000 /dev/zero > big.bin"},
+		WorkDir:        workDir,
+		TaskID:         "T-9",
+		Stage:          roles.StageWork,
+		WorkerStateDir: stateDir,
+		Resources:      config.ResourceLimits{MaxFileSizeMB: 1},
+	}
+	if _, err := DispatchWorker(input); err != nil {
+		t.Fatalf("dispatch worker: %v", err)
+	}
+	status := waitForDispatchExit(t, stateDir, input.TaskID, input.Stage)
+	if status.ExitCode == 0 {
+		t.Fatal("expected worker to fail once it exceeded the file size limit")
+	}
+	result := FailedExitResult(stateDir, status.ExitCode, nil)
+	if result.ResourceLimit != ResourceFileSize {
+		t.Fatalf("result = %+v (exit %d), want file size limit", result, status.ExitCode)
+	}
+	info, err := os.Stat(filepath.Join(workDir, "big.bin"))
+	if err != nil || info.Size() > 1024*1024 {
+		t.Fatalf("big.bin = %v (%v), want capped at 1 MB", info, err)
+	}
+}
@@ -0,0 +1,282 @@
+// Package worker provides sandbox helpers that confine worker processes on Linux.
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/cmtonkinson/governator/internal/config"
+)
+
+const (
+	sandboxBackendBwrap   = "bwrap"
+	sandboxBackendUnshare = "unshare"
+	governatorDirName     = "_governator"
+	sandboxStashPattern   = "/tmp/governator-sandbox.XXXXXX"
+)
+
+// sandboxLookPath and sandboxGOOS are overridable for tests.
+var (
+	sandboxLookPath = exec.LookPath
+	sandboxGOOS     = runtime.GOOS
+)
+
+// cliHomePaths lists the $HOME-relative credential and settings paths each built-in CLI needs.
+var cliHomePaths = map[string][]string{
+	config.CLICodex:  {".codex"},
+	config.CLIClaude: {".claude", ".claude.json"},
+	config.CLIGemini: {".gemini"},
+}
+
+// sandboxMount binds a host path onto itself inside the sandbox.
+type sandboxMount struct {
+	Path     string
+	Writable bool
+}
+
+// sandboxHomePath is a path under $HOME that stays visible once $HOME is hidden.
+type sandboxHomePath struct {
+	Path string
+	Dir  bool
+}
+
+// sandboxSpec is the resolved isolation plan for a single worker process.
+type sandboxSpec struct {
+	Backend        string
+	DisableNetwork bool
+	WorkDir        string
+	HomeDir        string
+	HomePaths      []sandboxHomePath
+	Mounts         []sandboxMount // ordered outermost first
+	UID            int
+	GID            int
+}
+
+// resolveSandbox turns a sandbox policy into a concrete plan, or nil when the worker runs unconfined.
+func resolveSandbox(policy config.SandboxPolicy, repoRoot string, workDir string, workerStateDir string, selectedCLI string, warn func(string)) (*sandboxSpec, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+	backend, err := selectSandboxBackend(policy.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if backend == "" {
+		emitWarning(warn, "sandbox mode auto found neither bwrap nor unshare; running worker without a sandbox")
+		return nil, nil
+	}
+	if strings.TrimSpace(repoRoot) == "" {
+		return nil, errors.New("repo root is required for sandboxed dispatch")
+	}
+	absRepoRoot, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("resolve repo root %s: %w", repoRoot, err)
+	}
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve work dir %s: %w", workDir, err)
+	}
+	absStateDir, err := filepath.Abs(workerStateDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve worker state dir %s: %w", workerStateDir, err)
+	}
+
+	spec := &sandboxSpec{
+		Backend:        backend,
+		DisableNetwork: policy.DisableNetwork,
+		WorkDir:        absWorkDir,
+		Mounts:         sandboxMounts(absRepoRoot, absWorkDir, absStateDir, policy.WritablePaths),
+		UID:            os.Getuid(),
+		GID:            os.Getgid(),
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil && filepath.Clean(homeDir) != "/" {
+		spec.HomeDir = filepath.Clean(homeDir)
+		spec.HomePaths = sandboxHomePaths(spec.HomeDir, absRepoRoot, absWorkDir, selectedCLI, policy.HomePaths)
+	}
+	return spec, nil
+}
+
+// selectSandboxBackend picks the isolation tool for a mode; auto returns "" when nothing is available.
+func selectSandboxBackend(mode string) (string, error) {
+	mode = strings.TrimSpace(mode)
+	if sandboxGOOS != "linux" {
+		if mode == config.SandboxModeAuto {
+			return "", nil
+		}
+		return "", fmt.Errorf("sandbox mode %q requires linux", mode)
+	}
+	switch mode {
+	case config.SandboxModeBwrap, config.SandboxModeUnshare:
+		if _, err := sandboxLookPath(mode); err != nil {
+			return "", fmt.Errorf("sandbox mode %q: %w", mode, err)
+		}
+		return mode, nil
+	case config.SandboxModeAuto:
+		for _, candidate := range []string{sandboxBackendBwrap, sandboxBackendUnshare} {
+			if _, err := sandboxLookPath(candidate); err == nil {
+				return candidate, nil
+			}
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported sandbox mode %q", mode)
+	}
+}
+
+// sandboxMounts lists the repo bind mounts in the order they must take effect.
+// The repo is read-only, its git directory and the worktree are writable, and
+// _governator stays read-only apart from the configured writable paths and the
+// worker's own state directory.
+func sandboxMounts(repoRoot string, workDir string, workerStateDir string, writablePaths []string) []sandboxMount {
+	mounts := make([]sandboxMount, 0, len(writablePaths)+5)
+	if workDir != repoRoot {
+		mounts = append(mounts, sandboxMount{Path: repoRoot})
+	}
+	if info, err := os.Stat(filepath.Join(repoRoot, ".git")); err == nil && info.IsDir() {
+		mounts = append(mounts, sandboxMount{Path: filepath.Join(repoRoot, ".git"), Writable: true})
+	}
+	mounts = append(mounts, sandboxMount{Path: workDir, Writable: true})
+	governatorDir := filepath.Join(repoRoot, governatorDirName)
+	governatorReadOnly := false
+	if _, err := os.Stat(governatorDir); err == nil && !pathWithin(workDir, governatorDir) {
+		mounts = append(mounts, sandboxMount{Path: governatorDir})
+		governatorReadOnly = true
+	}
+	for _, rel := range writablePaths {
+		path := filepath.Join(repoRoot, rel)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		mounts = append(mounts, sandboxMount{Path: path, Writable: true})
+	}
+	if !pathWithin(workerStateDir, workDir) || (governatorReadOnly && pathWithin(workerStateDir, governatorDir)) {
+		mounts = append(mounts, sandboxMount{Path: workerStateDir, Writable: true})
+	}
+	return mounts
+}
+
+// sandboxHomePaths lists the existing $HOME entries that stay visible inside the sandbox.
+func sandboxHomePaths(homeDir string, repoRoot string, workDir string, selectedCLI string, configured []string) []sandboxHomePath {
+	candidates := make([]string, 0, len(configured)+4)
+	for _, rel := range cliHomePaths[selectedCLI] {
+		candidates = append(candidates, filepath.Join(homeDir, rel))
+	}
+	for _, rel := range configured {
+		candidates = append(candidates, filepath.Join(homeDir, rel))
+	}
+	candidates = append(candidates, repoRoot, workDir)
+
+	seen := map[string]bool{}
+	paths := make([]sandboxHomePath, 0, len(candidates))
+	for _, path := range candidates {
+		if seen[path] || path == homeDir || !pathWithin(path, homeDir) {
+			continue
+		}
+		seen[path] = true
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, sandboxHomePath{Path: path, Dir: info.IsDir()})
+	}
+	return paths
+}
+
+// commandLine wraps a worker shell command so it runs inside the sandbox.
+func (spec *sandboxSpec) commandLine(commandLine string) string {
+	if spec.Backend == sandboxBackendBwrap {
+		return spec.bwrapCommandLine(commandLine)
+	}
+	return spec.unshareCommandLine(commandLine)
+}
+
+// bwrapCommandLine builds a bubblewrap invocation; later binds override earlier ones.
+func (spec *sandboxSpec) bwrapCommandLine(commandLine string) string {
+	args := []string{sandboxBackendBwrap, "--die-with-parent", "--unshare-pid"}
+	if spec.DisableNetwork {
+		args = append(args, "--unshare-net")
+	}
+	args = append(args, "--dev-bind", "/", "/", "--proc", "/proc")
+	if spec.HomeDir != "" {
+		args = append(args, "--tmpfs", spec.HomeDir)
+		for _, entry := range spec.HomePaths {
+			args = append(args, "--bind", entry.Path, entry.Path)
+		}
+	}
+	for _, mount := range spec.Mounts {
+		flag := "--ro-bind"
+		if mount.Writable {
+			flag = "--bind"
+		}
+		args = append(args, flag, mount.Path, mount.Path)
+	}
+	args = append(args, "--chdir", spec.WorkDir, "sh", "-c", commandLine)
+	return shellCommandLine(args)
+}
+
+// unshareCommandLine builds a user/mount namespace setup script for hosts without bubblewrap.
+// Read-only binds keep any mounts made beneath them, so the mounts are applied innermost first,
+// and a nested user namespace drops the mapped root back to the caller's uid before the agent starts.
+func (spec *sandboxSpec) unshareCommandLine(commandLine string) string {
+	lines := []string{
+		"set -e",
+		`rw() { mount --rbind "$1" "$1"; }`,
+		`ro() { mount --rbind "$1" "$1"; mount -o remount,bind,ro "$1" 2>/dev/null || mount -o remount,bind,ro,nosuid,nodev "$1" 2>/dev/null || mount -o remount,bind,ro,nosuid,nodev,noexec "$1"; }`,
+	}
+	if spec.HomeDir != "" {
+		home := shellEscapeArg(spec.HomeDir)
+		lines = append(lines,
+			"stash=$(mktemp -d "+sandboxStashPattern+")",
+			`mount --rbind `+home+` "$stash"`,
+			"mount -t tmpfs tmpfs "+home,
+		)
+		for _, entry := range spec.HomePaths {
+			rel, err := filepath.Rel(spec.HomeDir, entry.Path)
+			if err != nil {
+				continue
+			}
+			target := shellEscapeArg(entry.Path)
+			if entry.Dir {
+				lines = append(lines, "mkdir -p "+target)
+			} else {
+				lines = append(lines, "mkdir -p "+shellEscapeArg(filepath.Dir(entry.Path)), "touch "+target)
+			}
+			lines = append(lines, `mount --rbind "$stash"/`+shellEscapeArg(rel)+" "+target)
+		}
+		lines = append(lines, `umount -l "$stash"`, `rmdir "$stash"`)
+	}
+	for i := len(spec.Mounts) - 1; i >= 0; i-- {
+		mount := spec.Mounts[i]
+		if mount.Writable {
+			lines = append(lines, "rw "+shellEscapeArg(mount.Path))
+		} else {
+			lines = append(lines, "ro "+shellEscapeArg(mount.Path))
+		}
+	}
+	lines = append(lines,
+		"cd "+shellEscapeArg(spec.WorkDir),
+		"exec unshare --user --map-user="+strconv.Itoa(spec.UID)+" --map-group="+strconv.Itoa(spec.GID)+" sh -c "+shellEscapeArg(commandLine),
+	)
+
+	args := []string{sandboxBackendUnshare, "--user", "--map-root-user", "--mount", "--pid", "--fork", "--kill-child", "--mount-proc"}
+	if spec.DisableNetwork {
+		args = append(args, "--net")
+	}
+	args = append(args, "sh", "-c", strings.Join(lines, "\n"))
+	return shellCommandLine(args)
+}
+
+// pathWithin reports whether path equals base or sits beneath it.
+func pathWithin(path string, base string) bool {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, "../"))
+}
@@ -0,0 +1,284 @@
+package worker
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/roles"
+)
+
+func TestResolveSandboxDisabled(t *testing.T) {
+	spec, err := resolveSandbox(config.SandboxPolicy{Mode: config.SandboxModeOff}, t.TempDir(), t.TempDir(), t.TempDir(), "", nil)
+	if err != nil {
+		t.Fatalf("resolve sandbox: %v", err)
+	}
+	if spec != nil {
+		t.Fatalf("spec = %+v, want nil when sandbox is off", spec)
+	}
+}
+
+func TestResolveSandboxBackendSelection(t *testing.T) {
+	restoreLookPath := sandboxLookPath
+	restoreGOOS := sandboxGOOS
+	t.Cleanup(func() {
+		sandboxLookPath = restoreLookPath
+		sandboxGOOS = restoreGOOS
+	})
+	sandboxGOOS = "linux"
+	available := map[string]bool{}
+	sandboxLookPath = func(name string) (string, error) {
+		if available[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	repoRoot := t.TempDir()
+	var warnings []string
+	warn := func(message string) { warnings = append(warnings, message) }
+
+	spec, err := resolveSandbox(config.SandboxPolicy{Mode: config.SandboxModeAuto}, repoRoot, repoRoot, repoRoot, "", warn)
+	if err != nil || spec != nil {
+		t.Fatalf("auto without tools = (%+v, %v), want unsandboxed", spec, err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want one unavailable warning", warnings)
+	}
+
+	if _, err := resolveSandbox(config.SandboxPolicy{Mode: config.SandboxModeBwrap}, repoRoot, repoRoot, repoRoot, "", warn); !errors.Is(err, exec.ErrNotFound) {
+		t.Fatalf("explicit bwrap without binary error = %v, want not found", err)
+	}
+
+	available[sandboxBackendUnshare] = true
+	spec, err = resolveSandbox(config.SandboxPolicy{Mode: config.SandboxModeAuto}, repoRoot, repoRoot, repoRoot, "", warn)
+	if err != nil || spec == nil || spec.Backend != sandboxBackendUnshare {
+		t.Fatalf("auto with unshare = (%+v, %v), want unshare backend", spec, err)
+	}
+
+	available[sandboxBackendBwrap] = true
+	spec, err = resolveSandbox(config.SandboxPolicy{Mode: config.SandboxModeAuto}, repoRoot, repoRoot, repoRoot, "", warn)
+	if err != nil || spec == nil || spec.Backend != sandboxBackendBwrap {
+		t.Fatalf("auto with bwrap = (%+v, %v), want bwrap backend", spec, err)
+	}
+
+	sandboxGOOS = "darwin"
+	if _, err := resolveSandbox(config.SandboxPolicy{Mode: config.SandboxModeUnshare}, repoRoot, repoRoot, repoRoot, "", warn); err == nil {
+		t.Fatal("expected explicit sandbox mode to fail off linux")
+	}
+}
+
+func TestSandboxMountsProtectGovernatorDir(t *testing.T) {
+	repoRoot := t.TempDir()
+	workDir := filepath.Join(repoRoot, "_governator", "_local-state", "task-T-1")
+	stateDir := filepath.Join(workDir, "_governator", "_local-state", "worker-1-work-default")
+	docsDir := filepath.Join(repoRoot, "_governator", "docs")
+	for _, dir := range []string{filepath.Join(repoRoot, ".git"), stateDir, docsDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	mounts := sandboxMounts(repoRoot, workDir, stateDir, []string{"_governator/docs", "_governator/missing"})
+	want := []sandboxMount{
+		{Path: repoRoot},
+		{Path: filepath.Join(repoRoot, ".git"), Writable: true},
+		{Path: workDir, Writable: true},
+		{Path: docsDir, Writable: true},
+	}
+	if len(mounts) != len(want) {
+		t.Fatalf("mounts = %+v, want %+v", mounts, want)
+	}
+	for i := range want {
+		if mounts[i] != want[i] {
+			t.Fatalf("mount[%d] = %+v, want %+v", i, mounts[i], want[i])
+		}
+	}
+
+	// Working directly in the repo keeps _governator read-only but the worker state dir writable.
+	triageState := filepath.Join(repoRoot, "_governator", "_local-state", "triage", "worker-1")
+	if err := os.MkdirAll(triageState, 0o755); err != nil {
+		t.Fatalf("mkdir triage state: %v", err)
+	}
+	mounts = sandboxMounts(repoRoot, repoRoot, triageState, nil)
+	want = []sandboxMount{
+		{Path: filepath.Join(repoRoot, ".git"), Writable: true},
+		{Path: repoRoot, Writable: true},
+		{Path: filepath.Join(repoRoot, "_governator")},
+		{Path: triageState, Writable: true},
+	}
+	if len(mounts) != len(want) {
+		t.Fatalf("repo-root mounts = %+v, want %+v", mounts, want)
+	}
+	for i := range want {
+		if mounts[i] != want[i] {
+			t.Fatalf("repo-root mount[%d] = %+v, want %+v", i, mounts[i], want[i])
+		}
+	}
+}
+
+func TestSandboxHomePathsKeepsCLIConfig(t *testing.T) {
+	homeDir := t.TempDir()
+	for _, dir := range []string{".claude", ".cache", "work/repo"} {
+		if err := os.MkdirAll(filepath.Join(homeDir, dir), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(homeDir, ".claude.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write claude settings: %v", err)
+	}
+	repoRoot := filepath.Join(homeDir, "work", "repo")
+
+	paths := sandboxHomePaths(homeDir, repoRoot, repoRoot, config.CLIClaude, []string{".cache", ".missing"})
+	want := []sandboxHomePath{
+		{Path: filepath.Join(homeDir, ".claude"), Dir: true},
+		{Path: filepath.Join(homeDir, ".claude.json")},
+		{Path: filepath.Join(homeDir, ".cache"), Dir: true},
+		{Path: repoRoot, Dir: true},
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("home paths = %+v, want %+v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("home path[%d] = %+v, want %+v", i, paths[i], want[i])
+		}
+	}
+}
+
+func TestBwrapCommandLine(t *testing.T) {
+	spec := &sandboxSpec{
+		Backend:        sandboxBackendBwrap,
+		DisableNetwork: true,
+		WorkDir:        "/repo/_governator/_local-state/task-T-1",
+		HomeDir:        "/home/dev",
+		HomePaths:      []sandboxHomePath{{Path: "/home/dev/.codex", Dir: true}},
+		Mounts: []sandboxMount{
+			{Path: "/repo"},
+			{Path: "/repo/_governator/_local-state/task-T-1", Writable: true},
+		},
+	}
+	got := spec.commandLine("codex exec 'prompt file.md'")
+	want := "bwrap --die-with-parent --unshare-pid --unshare-net --dev-bind / / --proc /proc" +
+		" --tmpfs /home/dev --bind /home/dev/.codex /home/dev/.codex" +
+		" --ro-bind /repo /repo" +
+		" --bind /repo/_governator/_local-state/task-T-1 /repo/_governator/_local-state/task-T-1" +
+		" --chdir /repo/_governator/_local-state/task-T-1" +
+		` sh -c 'codex exec '"'"'prompt file.md'"'"''`
+	if got != want {
+		t.Fatalf("bwrap command line:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestUnshareCommandLineOrdersMountsInnermostFirst(t *testing.T) {
+	spec := &sandboxSpec{
+		Backend: sandboxBackendUnshare,
+		WorkDir: "/repo/wt",
+		Mounts: []sandboxMount{
+			{Path: "/repo"},
+			{Path: "/repo/wt", Writable: true},
+		},
+		UID: 1000,
+		GID: 1000,
+	}
+	got := spec.commandLine("true")
+	rwIndex := strings.Index(got, "rw /repo/wt")
+	roIndex := strings.Index(got, "ro /repo\n")
+	if rwIndex == -1 || roIndex == -1 || rwIndex > roIndex {
+		t.Fatalf("expected writable worktree bind before read-only repo bind:\n%s", got)
+	}
+	if strings.Contains(got, "--net") {
+		t.Fatalf("unexpected network isolation:\n%s", got)
+	}
+	if !strings.Contains(got, "--map-user=1000 --map-group=1000") {
+		t.Fatalf("expected agent to run as the caller's uid:\n%s", got)
+	}
+}
+
+func TestDispatchWorkerUnshareSandbox(t *testing.T) {
+	requireUnshareSandbox(t)
+
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	if err := os.WriteFile(filepath.Join(homeDir, "secret.txt"), []byte("token"), 0o600); err != nil {
+		t.Fatalf("write home secret: %v", err)
+	}
+	repoRoot := t.TempDir()
+	workDir := filepath.Join(repoRoot, "_governator", "_local-state", "task-T-1")
+	stateDir := filepath.Join(workDir, "_governator", "_local-state", "worker-1-work-default")
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		t.Fatalf("mkdir worker state: %v", err)
+	}
+
+	script := "{ " + strings.Join([]string{
+		"touch worktree.txt",
+		"touch ../../planning.txt 2>/dev/null && echo governator=writable || echo governator=readonly",
+		"test -e \"$HOME/secret.txt\" && echo home=visible || echo home=hidden",
+	}, "; ") + "; } > " + filepath.Join(stateDir, "report.txt")
+	input := DispatchInput{
+		Command:        []string{"sh", "-c", script},
+		WorkDir:        workDir,
+		TaskID:         "T-1",
+		Stage:          roles.StageWork,
+		WorkerStateDir: stateDir,
+		RepoRoot:       repoRoot,
+		Sandbox:        config.SandboxPolicy{Mode: config.SandboxModeUnshare, DisableNetwork: true},
+	}
+	if _, err := DispatchWorker(input); err != nil {
+		t.Fatalf("dispatch worker: %v", err)
+	}
+
+	status := waitForDispatchExit(t, stateDir, input.TaskID, input.Stage)
+	if status.ExitCode != 0 {
+		stderr, _ := os.ReadFile(filepath.Join(stateDir, stderrLogFileName))
+		t.Fatalf("exit code = %d, stderr: %s", status.ExitCode, stderr)
+	}
+	report, err := os.ReadFile(filepath.Join(stateDir, "report.txt"))
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(report)), "governator=readonly\nhome=hidden"; got != want {
+		t.Fatalf("report = %q, want %q", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "worktree.txt")); err != nil {
+		t.Fatalf("expected worktree write to land on the host: %v", err)
+	}
+}
+
+// requireUnshareSandbox skips unless unprivileged user namespaces can host the unshare sandbox.
+func requireUnshareSandbox(t *testing.T) {
+	t.Helper()
+	if sandboxGOOS != "linux" {
+		t.Skip("sandbox requires linux")
+	}
+	if _, err := exec.LookPath(sandboxBackendUnshare); err != nil {
+		t.Skip("unshare not available")
+	}
+	probe := exec.Command("unshare", "--user", "--map-root-user", "--mount", "sh", "-c", "exec unshare --user --map-user=0 --map-group=0 true")
+	if err := probe.Run(); err != nil {
+		t.Skipf("user namespaces unavailable: %v", err)
+	}
+}
+
+// waitForDispatchExit polls for the worker exit status file.
+func waitForDispatchExit(t *testing.T, workerStateDir string, taskID string, stage roles.Stage) ExitStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, found, err := ReadExitStatus(workerStateDir, taskID, stage)
+		if err != nil {
+			t.Fatalf("read exit status: %v", err)
+		}
+		if found {
+			return status
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+	t.Fatalf("exit status not found in %s", workerStateDir)
+	return ExitStatus{}
+}
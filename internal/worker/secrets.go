@@ -0,0 +1,190 @@
+// Package worker provides local secret loading and log redaction for workers.
+package worker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// secretsFileName is the dotenv-style secrets file inside the local state dir.
+	secretsFileName = "secrets.env"
+	// reservedEnvPrefix marks variables owned by governator that secrets may not override.
+	reservedEnvPrefix = "GOVERNATOR_"
+)
+
+// secretNamePattern matches valid environment variable names.
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SecretsPath returns the repo-local secrets file path. The file lives under the
+// gitignored local state dir so it is never committed.
+func SecretsPath(repoRoot string) string {
+	return filepath.Join(repoRoot, localStateDirName, secretsFileName)
+}
+
+// LoadSecrets reads NAME=value pairs from the repo-local secrets file. Blank lines and
+// lines starting with # are ignored, an optional "export " prefix is accepted, and
+// matching surrounding quotes are stripped. A missing file or repo root yields no secrets.
+func LoadSecrets(repoRoot string) (map[string]string, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return map[string]string{}, nil
+	}
+	path := SecretsPath(repoRoot)
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("open secrets file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	secrets := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !secretNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("parse secrets file %s line %d: expected NAME=value", path, lineNumber)
+		}
+		if strings.HasPrefix(name, reservedEnvPrefix) {
+			return nil, fmt.Errorf("parse secrets file %s line %d: %s uses the reserved %s prefix", path, lineNumber, name, reservedEnvPrefix)
+		}
+		secrets[name] = unquoteSecret(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read secrets file %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// unquoteSecret strips one pair of matching single or double quotes.
+func unquoteSecret(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first == last && (first == '"' || first == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// mergeSecretsEnv returns a copy of env with the secrets added. Secrets never replace
+// the governator-owned variables already present in env.
+func mergeSecretsEnv(env map[string]string, secrets map[string]string) map[string]string {
+	if len(secrets) == 0 {
+		return env
+	}
+	merged := make(map[string]string, len(env)+len(secrets))
+	for key, value := range env {
+		merged[key] = value
+	}
+	for key, value := range secrets {
+		if strings.HasPrefix(key, reservedEnvPrefix) {
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// Redactor replaces secret values with stable placeholders.
+type Redactor struct {
+	replacer *strings.Replacer
+}
+
+// NewRedactor builds a redactor for the supplied secrets. Empty values are skipped and
+// longer values are replaced first so overlapping secrets redact completely.
+func NewRedactor(secrets map[string]string) *Redactor {
+	names := make([]string, 0, len(secrets))
+	for name, value := range secrets {
+		if value == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return &Redactor{}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		left, right := secrets[names[i]], secrets[names[j]]
+		if len(left) != len(right) {
+			return len(left) > len(right)
+		}
+		return names[i] < names[j]
+	})
+	pairs := make([]string, 0, len(names)*2)
+	for _, name := range names {
+		pairs = append(pairs, secrets[name], fmt.Sprintf("[REDACTED:%s]", name))
+	}
+	return &Redactor{replacer: strings.NewReplacer(pairs...)}
+}
+
+// LoadRedactor builds a redactor from the repo-local secrets file.
+func LoadRedactor(repoRoot string) (*Redactor, error) {
+	secrets, err := LoadSecrets(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedactor(secrets), nil
+}
+
+// Redact returns text with every secret value replaced.
+func (redactor *Redactor) Redact(text string) string {
+	if redactor == nil || redactor.replacer == nil {
+		return text
+	}
+	return redactor.replacer.Replace(text)
+}
+
+// RedactFile rewrites a file in place with secret values replaced. Missing files are ignored.
+func (redactor *Redactor) RedactFile(path string) error {
+	if redactor == nil || redactor.replacer == nil {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	redacted := redactor.replacer.Replace(string(data))
+	if redacted == string(data) {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(redacted), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RedactWorkerLogs rewrites the worker stdout and stderr logs with secret values replaced.
+func (redactor *Redactor) RedactWorkerLogs(workerStateDir string) error {
+	if strings.TrimSpace(workerStateDir) == "" {
+		return errors.New("worker state dir is required")
+	}
+	for _, name := range []string{stdoutLogFileName, stderrLogFileName} {
+		if err := redactor.RedactFile(filepath.Join(workerStateDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
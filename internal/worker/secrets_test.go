@@ -0,0 +1,179 @@
+// Tests for repo-local secrets and redaction.
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cmtonkinson/governator/internal/config"
+	"github.com/cmtonkinson/governator/internal/index"
+)
+
+// writeSecretsFile writes the repo-local secrets file for a test repo.
+func writeSecretsFile(t *testing.T, repoRoot string, content string) {
+	t.Helper()
+	path := SecretsPath(repoRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir local state: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write secrets: %v", err)
+	}
+}
+
+// TestLoadSecretsParsesDotenv covers comments, export prefixes, and quoting.
+func TestLoadSecretsParsesDotenv(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+	writeSecretsFile(t, repoRoot, strings.Join([]string{
+		"# integration credentials",
+		"",
+		"DATABASE_URL=postgres://app:pw@localhost/app",
+		"export API_KEY=\"key with spaces\"",
+		"TOKEN='abc=123'",
+		"EMPTY=",
+	}, "\n"))
+
+	secrets, err := LoadSecrets(repoRoot)
+	if err != nil {
+		t.Fatalf("LoadSecrets: %v", err)
+	}
+	want := map[string]string{
+		"DATABASE_URL": "postgres://app:pw@localhost/app",
+		"API_KEY":      "key with spaces",
+		"TOKEN":        "abc=123",
+		"EMPTY":        "",
+	}
+	if len(secrets) != len(want) {
+		t.Fatalf("secrets = %v, want %v", secrets, want)
+	}
+	for key, value := range want {
+		if secrets[key] != value {
+			t.Fatalf("secrets[%s] = %q, want %q", key, secrets[key], value)
+		}
+	}
+}
+
+// TestLoadSecretsMissingFile returns no secrets without error.
+func TestLoadSecretsMissingFile(t *testing.T) {
+	t.Parallel()
+	secrets, err := LoadSecrets(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadSecrets: %v", err)
+	}
+	if len(secrets) != 0 {
+		t.Fatalf("secrets = %v, want empty", secrets)
+	}
+}
+
+// TestLoadSecretsRejectsInvalidLines reports the offending line number.
+func TestLoadSecretsRejectsInvalidLines(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing equals", content: "OK=1\nnot a pair\n", wantErr: "line 2"},
+		{name: "invalid name", content: "BAD-NAME=1\n", wantErr: "line 1"},
+		{name: "reserved prefix", content: "GOVERNATOR_ROLE=admin\n", wantErr: "reserved"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoRoot := t.TempDir()
+			writeSecretsFile(t, repoRoot, tt.content)
+			_, err := LoadSecrets(repoRoot)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %q, want %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRedactorRedactsLongestValuesFirst ensures overlapping secrets redact completely.
+func TestRedactorRedactsLongestValuesFirst(t *testing.T) {
+	t.Parallel()
+	redactor := NewRedactor(map[string]string{
+		"SHORT": "abc",
+		"LONG":  "abcdef",
+		"EMPTY": "",
+	})
+	got := redactor.Redact("abcdef then abc")
+	want := "[REDACTED:LONG] then [REDACTED:SHORT]"
+	if got != want {
+		t.Fatalf("Redact = %q, want %q", got, want)
+	}
+
+	var nilRedactor *Redactor
+	if got := nilRedactor.Redact("abc"); got != "abc" {
+		t.Fatalf("nil redactor changed text: %q", got)
+	}
+}
+
+// TestRedactWorkerLogs rewrites stdout and stderr in place.
+func TestRedactWorkerLogs(t *testing.T) {
+	t.Parallel()
+	stateDir := t.TempDir()
+	stdoutPath := filepath.Join(stateDir, stdoutLogFileName)
+	if err := os.WriteFile(stdoutPath, []byte("token=s3cr3t\n"), 0o644); err != nil {
+		t.Fatalf("write stdout: %v", err)
+	}
+
+	redactor := NewRedactor(map[string]string{"API_KEY": "s3cr3t"})
+	if err := redactor.RedactWorkerLogs(stateDir); err != nil {
+		t.Fatalf("RedactWorkerLogs: %v", err)
+	}
+	content, err := os.ReadFile(stdoutPath)
+	if err != nil {
+		t.Fatalf("read stdout: %v", err)
+	}
+	if string(content) != "token=[REDACTED:API_KEY]\n" {
+		t.Fatalf("stdout = %q", string(content))
+	}
+}
+
+// TestExecuteWorkerFromConfigInjectsSecrets passes secrets to the worker without overriding governator variables.
+func TestExecuteWorkerFromConfigInjectsSecrets(t *testing.T) {
+	t.Parallel()
+	repoRoot := t.TempDir()
+	writeSecretsFile(t, repoRoot, "API_KEY=injected-key\n")
+	workerStateDir := workerStateDirPath(repoRoot)
+
+	cfg := config.Config{
+		Workers: config.WorkersConfig{
+			Commands: config.WorkerCommands{
+				Default: []string{"sh", "-c", "echo \"$API_KEY $GOVERNATOR_ROLE\"", "{task_path}"},
+			},
+		},
+		Timeouts: config.TimeoutsConfig{WorkerSeconds: 10},
+	}
+	task := index.Task{ID: "T-100", Path: "tasks/example.md", Role: "worker"}
+	stageResult := StageResult{
+		Env:            map[string]string{"GOVERNATOR_ROLE": "worker"},
+		WorkerStateDir: workerStateDir,
+		RepoRoot:       repoRoot,
+	}
+
+	result, err := ExecuteWorkerFromConfig(cfg, task, stageResult, repoRoot, nil)
+	if err != nil {
+		t.Fatalf("ExecuteWorkerFromConfig: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("exit code = %d (error: %v)", result.ExitCode, result.Error)
+	}
+	content, err := os.ReadFile(filepath.Join(repoRoot, result.StdoutPath))
+	if err != nil {
+		t.Fatalf("read stdout: %v", err)
+	}
+	if strings.TrimSpace(string(content)) != "injected-key worker" {
+		t.Fatalf("stdout = %q", string(content))
+	}
+	if _, ok := stageResult.Env["API_KEY"]; ok {
+		t.Fatal("secrets leaked into the staged env map")
+	}
+}
@@ -29,11 +29,15 @@ type StageInput struct {
 	Task            index.Task
 	TaskPromptPath  string
 	ExtraPromptPath []string
+	// ContextPrompts are generated sections placed after ExtraPromptPath, before the prompt
+	// budget is applied. StageEnvAndPrompts writes each to its Path; RenderPrompt does not.
+	ContextPrompts  []PromptSection
 	ExtraEnv        map[string]string
 	Stage           roles.Stage
 	Role            index.Role
 	ReasoningEffort string
 	AgentUsesCodex  bool
+	PromptBudget    PromptBudget
 	Warn            func(string)
 	WorkerStateDir  string
 }
@@ -47,6 +51,13 @@ type StageResult struct {
 	Env             map[string]string
 	WorkerStateDir  string
 	ReasoningEffort string
+	RepoRoot        string
+	Stage           roles.Stage
+	// PromptBytes and PromptTokens describe the final staged prompt size.
+	PromptBytes  int
+	PromptTokens int
+	// DroppedPrompts lists optional prompt files removed to fit the prompt budget.
+	DroppedPrompts []string
 }
 
 // StageEnvAndPrompts prepares worker prompt and environment staging artifacts.
@@ -90,16 +101,21 @@ func StageEnvAndPrompts(input StageInput) (StageResult, error) {
 		return StageResult{}, fmt.Errorf("resolve worktree root %s: %w", worktreeRoot, err)
 	}
 
-	registry, err := roles.LoadRegistry(absRepoRoot, input.Warn)
-	if err != nil {
-		return StageResult{}, fmt.Errorf("load role registry: %w", err)
+	if err := writeContextPrompts(absRepoRoot, input.ContextPrompts); err != nil {
+		return StageResult{}, err
 	}
-	reasoningLevel := normalizeReasoningLevel(input.ReasoningEffort)
-	includeReasoning := shouldIncludeReasoningPrompt(reasoningLevel, input.AgentUsesCodex)
-	promptFiles, err := orderedPromptFiles(absRepoRoot, registry, role, reasoningLevel, taskPath, input.ExtraPromptPath, includeReasoning)
+	rendered, err := assemblePrompt(absRepoRoot, input, role, taskPath)
 	if err != nil {
 		return StageResult{}, err
 	}
+	promptFiles := make([]string, 0, len(rendered.Sections))
+	for _, section := range rendered.Sections {
+		promptFiles = append(promptFiles, section.Path)
+	}
+	droppedPrompts := make([]string, 0, len(rendered.Dropped))
+	for _, section := range rendered.Dropped {
+		droppedPrompts = append(droppedPrompts, section.Path)
+	}
 
 	stageDir := strings.TrimSpace(input.WorkerStateDir)
 	if stageDir == "" {
@@ -119,7 +135,7 @@ func StageEnvAndPrompts(input StageInput) (StageResult, error) {
 	}
 
 	promptPath := filepath.Join(stageDir, promptFileName(input.Stage))
-	if err := writePromptFile(promptPath, promptFiles, absRepoRoot); err != nil {
+	if err := writePromptFile(promptPath, rendered.Content()); err != nil {
 		return StageResult{}, err
 	}
 
@@ -136,7 +152,12 @@ func StageEnvAndPrompts(input StageInput) (StageResult, error) {
 		EnvPath:         envPath,
 		Env:             env,
 		WorkerStateDir:  stageDir,
-		ReasoningEffort: reasoningLevel,
+		ReasoningEffort: rendered.ReasoningEffort,
+		RepoRoot:        absRepoRoot,
+		Stage:           input.Stage,
+		PromptBytes:     rendered.Bytes(),
+		PromptTokens:    rendered.EstimatedTokens(),
+		DroppedPrompts:  droppedPrompts,
 	}, nil
 }
 
@@ -249,36 +270,26 @@ func writePromptList(path string, prompts []string) error {
 	return nil
 }
 
-// writePromptFile writes the worker prompt by concatenating every prompt file’s contents.
-func writePromptFile(path string, prompts []string, repoRoot string) error {
-	if len(prompts) == 0 {
-		return errors.New("prompt files are required")
-	}
-	content, err := buildPromptContent(repoRoot, prompts)
-	if err != nil {
-		return err
-	}
+// writePromptFile writes the assembled worker prompt.
+func writePromptFile(path string, content string) error {
 	if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil {
 		return fmt.Errorf("write worker prompt %s: %w", path, err)
 	}
 	return nil
 }
 
-// buildPromptContent concatenates the contents of the provided prompt files.
-func buildPromptContent(repoRoot string, prompts []string) (string, error) {
-	builder := &strings.Builder{}
-	for i, prompt := range prompts {
-		if i > 0 {
-			builder.WriteString("\n\n")
+// writeContextPrompts writes generated context prompts so the prompt list points at real files.
+func writeContextPrompts(repoRoot string, prompts []PromptSection) error {
+	for _, prompt := range prompts {
+		path := resolvePromptPath(repoRoot, prompt.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("create context prompt dir %s: %w", filepath.Dir(path), err)
 		}
-		path := resolvePromptPath(repoRoot, prompt)
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return "", fmt.Errorf("read prompt %s: %w", prompt, err)
+		if err := os.WriteFile(path, []byte(prompt.Content), 0o644); err != nil {
+			return fmt.Errorf("write context prompt %s: %w", path, err)
 		}
-		builder.Write(data)
 	}
-	return strings.TrimSpace(builder.String()), nil
+	return nil
 }
 
 // buildEnvMap assembles the environment variables for worker execution.
@@ -544,6 +544,42 @@ func TestStageEnvAndPromptsTaskPromptOverride(t *testing.T) {
 	}
 }
 
+// TestStageEnvAndPromptsWritesContextPrompts stages generated context after extra prompt files.
+func TestStageEnvAndPromptsWritesContextPrompts(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "_governator", "roles", "worker.md"), "role prompt")
+	writeFile(t, filepath.Join(root, "_governator", "worker-contract.md"), "worker contract")
+	writeFile(t, filepath.Join(root, "_governator", "tasks", "T-001.md"), "task content")
+
+	contextPath := "_governator/_local-state/worker-test/dependency-context.md"
+	result, err := StageEnvAndPrompts(StageInput{
+		RepoRoot:       root,
+		WorktreeRoot:   root,
+		Task:           index.Task{ID: "T-001", Path: "_governator/tasks/T-001.md", Role: "worker"},
+		ContextPrompts: []PromptSection{{Path: contextPath, Content: "dependency context"}},
+		Stage:          roles.StageWork,
+		WorkerStateDir: workerStateDirPath(root),
+	})
+	if err != nil {
+		t.Fatalf("stage env and prompts: %v", err)
+	}
+	if last := result.PromptFiles[len(result.PromptFiles)-1]; last != contextPath {
+		t.Fatalf("last prompt file = %q, want %q", last, contextPath)
+	}
+	written, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(contextPath)))
+	if err != nil || string(written) != "dependency context" {
+		t.Fatalf("context prompt = %q, %v", string(written), err)
+	}
+	prompt, err := os.ReadFile(result.PromptPath)
+	if err != nil {
+		t.Fatalf("read prompt file: %v", err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(string(prompt)), "dependency context") {
+		t.Fatalf("prompt = %q, want context last", string(prompt))
+	}
+}
+
 // writeFile creates the file and parent directories with content.
 func writeFile(t *testing.T, path string, content string) {
 	t.Helper()
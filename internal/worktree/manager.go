@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -23,10 +25,12 @@ const (
 	taskDirPrefix = "task-"
 )
 
-// Manager coordinates creation and reuse of task worktrees.
+// Manager coordinates creation and reuse of task worktrees. Worktrees live in worktreeDir;
+// their metadata always stays in the repo's local state directory.
 type Manager struct {
 	repoRoot      string
 	localStateDir string
+	worktreeDir   string
 }
 
 // Spec defines the inputs needed to locate or create a task worktree.
@@ -36,6 +40,13 @@ type Spec struct {
 	BaseBranch   string
 }
 
+// Info describes an existing worktree recorded in the manager metadata.
+type Info struct {
+	WorkstreamID string
+	Branch       string
+	Path         string
+}
+
 // Result captures the resolved worktree location and whether it was reused.
 type Result struct {
 	Path         string
@@ -45,6 +56,12 @@ type Result struct {
 
 // NewManager constructs a Manager rooted at the provided repository root.
 func NewManager(repoRoot string) (Manager, error) {
+	return NewManagerWithRoot(repoRoot, "")
+}
+
+// NewManagerWithRoot constructs a Manager that places worktrees under root instead of the
+// repo's local state directory. See ResolveDir for how root is interpreted.
+func NewManagerWithRoot(repoRoot string, root string) (Manager, error) {
 	if strings.TrimSpace(repoRoot) == "" {
 		return Manager{}, errors.New("repo root is required")
 	}
@@ -59,8 +76,37 @@ func NewManager(repoRoot string) (Manager, error) {
 	if !info.IsDir() {
 		return Manager{}, fmt.Errorf("repo root %s is not a directory", absRoot)
 	}
+	worktreeDir, err := ResolveDir(absRoot, root)
+	if err != nil {
+		return Manager{}, err
+	}
 	localStateDir := filepath.Join(absRoot, localStateDirName)
-	return Manager{repoRoot: absRoot, localStateDir: localStateDir}, nil
+	return Manager{repoRoot: absRoot, localStateDir: localStateDir, worktreeDir: worktreeDir}, nil
+}
+
+// ResolveDir returns the directory that holds a repo's worktrees. An empty root means the
+// repo's local state directory. Otherwise root is absolute or relative to the repo root, and
+// each repo gets its own subdirectory so several repos can share one scratch volume.
+func ResolveDir(repoRoot string, root string) (string, error) {
+	absRoot, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("resolve absolute repo root %s: %w", repoRoot, err)
+	}
+	root = strings.TrimSpace(root)
+	if root == "" {
+		return filepath.Join(absRoot, localStateDirName), nil
+	}
+	if !filepath.IsAbs(root) {
+		root = filepath.Join(absRoot, root)
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(absRoot))
+	return filepath.Join(filepath.Clean(root), fmt.Sprintf("%s-%08x", filepath.Base(absRoot), hash.Sum32())), nil
+}
+
+// Dir returns the directory new worktrees are created in.
+func (manager Manager) Dir() string {
+	return manager.worktreeDir
 }
 
 // WorktreePath returns the deterministic worktree path for a task attempt.
@@ -72,14 +118,14 @@ func (manager Manager) WorktreePath(workstreamID string) (string, error) {
 		return "", err
 	}
 	dirName := taskDirName(workstreamID)
-	return filepath.Join(manager.localStateDir, dirName), nil
+	return filepath.Join(manager.worktreeDir, dirName), nil
 }
 
 // EnsureWorktree returns a task worktree path, creating it when needed.
 // This method now integrates with branch lifecycle management to ensure
 // task branches are created before worktrees.
 func (manager Manager) EnsureWorktree(spec Spec) (Result, error) {
-	if strings.TrimSpace(manager.repoRoot) == "" || strings.TrimSpace(manager.localStateDir) == "" {
+	if strings.TrimSpace(manager.repoRoot) == "" || strings.TrimSpace(manager.worktreeDir) == "" {
 		return Result{}, errors.New("worktree manager is not initialized")
 	}
 	if err := validateWorkstreamID(spec.WorkstreamID); err != nil {
@@ -89,8 +135,8 @@ func (manager Manager) EnsureWorktree(spec Spec) (Result, error) {
 		return Result{}, errors.New("branch is required")
 	}
 
-	if err := os.MkdirAll(manager.localStateDir, localStateDirMode); err != nil {
-		return Result{}, fmt.Errorf("create worktree directory %s: %w", manager.localStateDir, err)
+	if err := os.MkdirAll(manager.worktreeDir, localStateDirMode); err != nil {
+		return Result{}, fmt.Errorf("create worktree directory %s: %w", manager.worktreeDir, err)
 	}
 
 	path, reused, err := manager.locateExistingWorktree(spec)
@@ -199,6 +245,66 @@ func (manager Manager) ExistingWorktreePath(workstreamID string) (string, bool,
 	return manager.locateExistingWorktree(Spec{WorkstreamID: workstreamID})
 }
 
+// List returns the worktrees recorded in the manager metadata that still exist on disk,
+// sorted by workstream id.
+func (manager Manager) List() ([]Info, error) {
+	entries, err := os.ReadDir(manager.metadataDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read metadata directory %s: %w", manager.metadataDir(), err)
+	}
+	var infos []Info
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		workstreamID := strings.TrimSuffix(name, ".json")
+		meta, ok, err := manager.readMetadata(workstreamID)
+		if err != nil || !ok || meta.WorktreeRelPath == "" {
+			continue
+		}
+		path := filepath.Join(manager.repoRoot, filepath.FromSlash(meta.WorktreeRelPath))
+		if exists, err := pathExists(path); err != nil || !exists {
+			continue
+		}
+		infos = append(infos, Info{WorkstreamID: workstreamID, Branch: meta.Branch, Path: path})
+	}
+	return infos, nil
+}
+
+// DiskUsage returns the total size in bytes of the regular files under path. Symlinks are
+// not followed.
+func DiskUsage(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("measure disk usage of %s: %w", path, err)
+	}
+	return total, nil
+}
+
 func (manager Manager) ensureMetadata(workstreamID, path, branch string) error {
 	if strings.TrimSpace(path) == "" {
 		return errors.New("worktree path is required")
@@ -103,6 +103,57 @@ func TestEnsureWorktreeReusePreservesChanges(t *testing.T) {
 	}
 }
 
+// TestEnsureWorktreeExternalRoot verifies worktrees can live outside the repo and are listed with their size.
+func TestEnsureWorktreeExternalRoot(t *testing.T) {
+	repoRoot := initRepo(t)
+	scratch := t.TempDir()
+	branch := "task-T-003"
+
+	manager, err := NewManagerWithRoot(repoRoot, scratch)
+	if err != nil {
+		t.Fatalf("NewManagerWithRoot error: %v", err)
+	}
+	if !strings.HasPrefix(manager.Dir(), scratch+string(filepath.Separator)) {
+		t.Fatalf("Dir = %q, want a directory under %q", manager.Dir(), scratch)
+	}
+
+	result, err := manager.EnsureWorktree(Spec{
+		WorkstreamID: "T-003",
+		Branch:       branch,
+		BaseBranch:   "main",
+	})
+	if err != nil {
+		t.Fatalf("EnsureWorktree error: %v", err)
+	}
+	if filepath.Dir(result.Path) != manager.Dir() {
+		t.Fatalf("worktree path = %q, want it under %q", result.Path, manager.Dir())
+	}
+
+	reopened, err := NewManagerWithRoot(repoRoot, scratch)
+	if err != nil {
+		t.Fatalf("NewManagerWithRoot error: %v", err)
+	}
+	path, exists, err := reopened.ExistingWorktreePath("T-003")
+	if err != nil || !exists || path != result.Path {
+		t.Fatalf("ExistingWorktreePath = %q, %v, %v; want %q", path, exists, err, result.Path)
+	}
+
+	infos, err := reopened.List()
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if len(infos) != 1 || infos[0].WorkstreamID != "T-003" || infos[0].Branch != branch || infos[0].Path != result.Path {
+		t.Fatalf("List = %+v", infos)
+	}
+	size, err := DiskUsage(result.Path)
+	if err != nil {
+		t.Fatalf("DiskUsage error: %v", err)
+	}
+	if size < int64(len("test")) {
+		t.Fatalf("DiskUsage = %d, want at least the README size", size)
+	}
+}
+
 // initRepo initializes a git repository with a single commit.
 func initRepo(t *testing.T) string {
 	t.Helper()
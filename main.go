@@ -22,14 +22,18 @@ import (
 	"github.com/cmtonkinson/governator/internal/buildinfo"
 	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/dag"
+	"github.com/cmtonkinson/governator/internal/dryrun"
 	"github.com/cmtonkinson/governator/internal/index"
 	"github.com/cmtonkinson/governator/internal/inflight"
 	"github.com/cmtonkinson/governator/internal/repo"
+	"github.com/cmtonkinson/governator/internal/roles"
 	"github.com/cmtonkinson/governator/internal/run"
 	"github.com/cmtonkinson/governator/internal/status"
 	"github.com/cmtonkinson/governator/internal/supervisor"
 	"github.com/cmtonkinson/governator/internal/supervisorlock"
 	"github.com/cmtonkinson/governator/internal/tui"
+	"github.com/cmtonkinson/governator/internal/worker"
+	"github.com/cmtonkinson/governator/internal/worktree"
 )
 
 const usage = `governator - AI-powered task orchestration engine
@@ -48,6 +52,8 @@ COMMANDS:
     plan             Alias for 'start'
     execute          Alias for 'start'
     retry            Increase retry limit for a specific task by 1
+    revert           Revert a merged task on the base branch
+    prompt           Render the worker prompt stack for a task and stage
     status           Display current supervisor and task status
     why              Show the most recent supervisor log lines
     dag              Display task dependency graph (DAG)
@@ -117,6 +123,10 @@ func main() {
 		runExecute(commandArgs)
 	case "retry":
 		runRetry(commandArgs)
+	case "revert":
+		runRevert(commandArgs)
+	case "prompt":
+		runPrompt(commandArgs)
 	case "status":
 		runStatus(commandArgs)
 	case "why":
@@ -131,6 +141,8 @@ func main() {
 		runReset(commandArgs)
 	case "tail":
 		runTail(commandArgs)
+	case dryrun.AgentCommand:
+		os.Exit(dryrun.RunAgent(commandArgs, os.Stdout, os.Stderr))
 	case "-h", "--help", "help":
 		globalFlags.Usage()
 		os.Exit(0)
@@ -271,8 +283,9 @@ func commitInit(repoRoot string) error {
 	return nil
 }
 
-// launchSupervisor launches the unified supervisor in the background.
-func launchSupervisor(commandArg string) {
+// launchSupervisor launches the unified supervisor in the background. A non-nil dryRun
+// swaps every worker for the scripted fake agent.
+func launchSupervisor(commandArg string, dryRun *dryrun.Settings) {
 	repoRoot, err := repo.DiscoverRootFromCWD()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
@@ -311,7 +324,11 @@ func launchSupervisor(commandArg string) {
 	}
 	defer logFile.Close()
 
-	cmd := exec.Command(os.Args[0], commandArg, "--supervisor")
+	supervisorArgs := []string{commandArg, "--supervisor"}
+	if dryRun != nil {
+		supervisorArgs = append(supervisorArgs, "--dry-run", "--script", dryRun.ScriptPath)
+	}
+	cmd := exec.Command(os.Args[0], supervisorArgs...)
 	cmd.Dir = repoRoot
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
@@ -328,6 +345,9 @@ func launchSupervisor(commandArg string) {
 		LastTransition: time.Now().UTC(),
 		LogPath:        logPath,
 	}
+	if dryRun != nil {
+		state.DryRunScript = dryRun.ScriptPath
+	}
 	if err := supervisor.SaveState(repoRoot, state); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
@@ -336,6 +356,10 @@ func launchSupervisor(commandArg string) {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
+	if dryRun != nil {
+		fmt.Printf("start supervisor started in dry-run mode (pid %d, script %s)\n", pid, dryRun.ScriptPath)
+		return
+	}
 	fmt.Printf("start supervisor started (pid %d)\n", pid)
 }
 
@@ -371,9 +395,11 @@ func confirmPendingMigrations(repoRoot string, in io.Reader, out io.Writer) (boo
 func runStart(args []string) {
 	flags := flag.NewFlagSet("start", flag.ExitOnError)
 	supervisorMode := flags.Bool("supervisor", false, "")
+	dryRunMode := flags.Bool("dry-run", false, "")
+	scriptPath := flags.String("script", "", "")
 	flags.Usage = func() {
 		fmt.Fprint(os.Stderr, `USAGE:
-    governator start
+    governator start [options]
 
 DESCRIPTION:
     Start the unified supervisor in the background.
@@ -382,33 +408,74 @@ DESCRIPTION:
 
     Use 'governator status' to monitor progress and 'governator tail' to stream logs.
 
+    With --dry-run, every worker is replaced by a built-in fake agent that follows
+    a JSON script instead of calling an AI CLI. Planning, triage, scheduling,
+    retries, conflicts, and merges all run for real, so use a scratch clone.
+
 OPTIONS:
-    -h, --help    Show this help message
+    --dry-run          Replace every worker with the scripted fake agent
+    --script <path>    Dry-run script (default: _governator/dry-run.json)
+    -h, --help         Show this help message
 `)
 	}
 	flags.Parse(args)
 
-	if *supervisorMode {
-		runStartSupervisor()
-		return
-	}
-
 	if flags.NArg() > 0 {
 		fmt.Fprintf(os.Stderr, "governator start: unexpected arguments\n\n")
 		flags.Usage()
 		os.Exit(2)
 	}
+	if *scriptPath != "" && !*dryRunMode {
+		fmt.Fprintf(os.Stderr, "governator start: --script requires --dry-run\n\n")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var dryRun *dryrun.Settings
+	if *dryRunMode {
+		settings, err := resolveDryRunSettings(*scriptPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		dryRun = &settings
+	}
+
+	if *supervisorMode {
+		runStartSupervisor(dryRun)
+		return
+	}
 
-	launchSupervisor("start")
+	launchSupervisor("start", dryRun)
+}
+
+// resolveDryRunSettings points the fake agent at this binary and validates the script up front.
+func resolveDryRunSettings(scriptPath string) (dryrun.Settings, error) {
+	repoRoot, err := repo.DiscoverRootFromCWD()
+	if err != nil {
+		return dryrun.Settings{}, err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return dryrun.Settings{}, fmt.Errorf("resolve governator executable: %w", err)
+	}
+	settings, err := dryrun.NewSettings(repoRoot, executable, scriptPath)
+	if err != nil {
+		return dryrun.Settings{}, err
+	}
+	if _, err := dryrun.LoadScript(settings.ScriptPath); err != nil {
+		return dryrun.Settings{}, err
+	}
+	return settings, nil
 }
 
-func runStartSupervisor() {
+func runStartSupervisor(dryRun *dryrun.Settings) {
 	repoRoot, err := repo.DiscoverRootFromCWD()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(2)
 	}
-	if err := run.RunUnifiedSupervisor(repoRoot, run.UnifiedSupervisorOptions{Stdout: os.Stdout, Stderr: os.Stderr}); err != nil {
+	if err := run.RunUnifiedSupervisor(repoRoot, run.UnifiedSupervisorOptions{Stdout: os.Stdout, Stderr: os.Stderr, DryRun: dryRun}); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
@@ -514,11 +581,16 @@ OPTIONS:
 		return
 	}
 	_ = phase
+	// Keep a dry run in dry-run mode across the restart.
+	var startArgs []string
+	if state, ok, err := supervisor.LoadState(repoRoot); err == nil && ok && state.DryRunScript != "" {
+		startArgs = []string{"--dry-run", "--script", state.DryRunScript}
+	}
 	if err := run.StopUnifiedSupervisor(repoRoot, run.UnifiedSupervisorStopOptions{StopWorker: stopWorker}); err != nil && !errors.Is(err, supervisor.ErrSupervisorNotRunning) {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
-	runStart(nil)
+	runStart(startArgs)
 }
 
 func runReset(args []string) {
@@ -712,6 +784,194 @@ func resolveRetryTaskID(selector string, tasks []index.Task) (string, error) {
 	}
 }
 
+func runRevert(args []string) {
+	flags := flag.NewFlagSet("revert", flag.ExitOnError)
+	requeue := flags.Bool("requeue", false, "")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, `USAGE:
+    governator revert <task-id|task-number> [options]
+
+DESCRIPTION:
+    Undo a merged task. Governator finds the task's merge in the audit log (or by
+    its merge commit message), commits a revert on the base branch, and moves the
+    task to the reverted state. With branches.remote set, the revert is pushed
+    before local main moves. Merged tasks that depend on the reverted task are
+    listed as a warning; they are not reverted.
+    Stop the supervisor first; the repo root must be clean and on the base branch.
+
+OPTIONS:
+    --requeue     Move the task back to triaged with fresh attempt counters
+    -h, --help    Show this help message
+`)
+	}
+	positional := parseInterspersedFlags(flags, args)
+
+	if len(positional) != 1 {
+		fmt.Fprintf(os.Stderr, "governator revert: expected exactly 1 task id\n\n")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	repoRoot, err := repo.DiscoverRootFromCWD()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	if _, running, err := supervisor.AnyRunning(repoRoot); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	} else if running {
+		fmt.Fprintln(os.Stderr, "governator revert: supervisor is running; use governator stop first")
+		os.Exit(1)
+	}
+
+	indexPath := filepath.Join(repoRoot, "_governator", "_local-state", "index.json")
+	idx, err := index.Load(indexPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	taskID, err := resolveRetryTaskID(strings.TrimSpace(positional[0]), idx.Tasks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "governator revert: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	outcome, err := run.RevertTask(repoRoot, taskID, run.RevertOptions{Requeue: *requeue, Stderr: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "governator revert: %s\n", err.Error())
+		os.Exit(1)
+	}
+	if len(outcome.MergedDependents) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: merged tasks depend on %s and may be broken without it: %s\n", outcome.TaskID, strings.Join(outcome.MergedDependents, ", "))
+	}
+	fmt.Print(formatRevertOutcome(outcome))
+}
+
+// formatRevertOutcome renders the one-line revert summary.
+func formatRevertOutcome(outcome run.RevertOutcome) string {
+	state := index.TaskStateReverted
+	if outcome.Requeued {
+		state = index.TaskStateTriaged
+	}
+	return fmt.Sprintf("revert ok: %s merge %.12s reverted by %.12s; state %s\n", outcome.TaskID, outcome.MergeCommit, outcome.RevertCommit, state)
+}
+
+func runPrompt(args []string) {
+	flags := flag.NewFlagSet("prompt", flag.ExitOnError)
+	stageName := flags.String("stage", string(roles.StageWork), "")
+	roleName := flags.String("role", "", "")
+	summaryOnly := flags.Bool("summary", false, "")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, `USAGE:
+    governator prompt <task-id|task-number> [options]
+
+DESCRIPTION:
+    Render the exact prompt a worker would receive for a task and stage.
+    Sections appear in dispatch order (reasoning, worker contract, role prompt,
+    custom prompts, task file, extra context), each preceded by a comment naming
+    the source file with its size in bytes and estimated tokens.
+    The role is resolved as dispatch would resolve it unless --role is given.
+    Nothing is staged or written; use this when tuning custom-prompts/.
+
+OPTIONS:
+    --stage <stage>    Worker stage: work, test, review, or resolve (default: work)
+    --role <role>      Render with this role instead of the dispatch role
+    --summary          Print only the per-section sizes and the total
+    -h, --help         Show this help message
+`)
+	}
+	positional := parseInterspersedFlags(flags, args)
+
+	if len(positional) != 1 {
+		fmt.Fprintf(os.Stderr, "governator prompt: expected exactly 1 task id\n\n")
+		flags.Usage()
+		os.Exit(2)
+	}
+	stage := roles.Stage(strings.TrimSpace(*stageName))
+	if !stage.Valid() {
+		fmt.Fprintf(os.Stderr, "governator prompt: --stage %q is not one of work, test, review, resolve\n", *stageName)
+		os.Exit(2)
+	}
+
+	repoRoot, err := repo.DiscoverRootFromCWD()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	cfg, err := config.Load(repoRoot, nil, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	indexPath := filepath.Join(repoRoot, "_governator", "_local-state", "index.json")
+	idx, err := index.Load(indexPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	taskID, err := resolveRetryTaskID(strings.TrimSpace(positional[0]), idx.Tasks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "governator prompt: %s\n", err.Error())
+		os.Exit(1)
+	}
+	var task index.Task
+	for _, candidate := range idx.Tasks {
+		if candidate.ID == taskID {
+			task = candidate
+			break
+		}
+	}
+
+	warn := func(msg string) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	}
+	rendered, err := run.RenderTaskPrompt(repoRoot, idx.Tasks, task, stage, index.Role(strings.TrimSpace(*roleName)), cfg, warn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "governator prompt: %s\n", err.Error())
+		os.Exit(1)
+	}
+	fmt.Print(formatPromptPreview(task.ID, rendered, *summaryOnly))
+}
+
+// parseInterspersedFlags parses flags that may follow positional arguments and
+// returns the positional arguments in order.
+func parseInterspersedFlags(flags *flag.FlagSet, args []string) []string {
+	positional := []string{}
+	flags.Parse(args)
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		flags.Parse(flags.Args()[1:])
+	}
+	return positional
+}
+
+// formatPromptPreview renders prompt sections with source annotations and size totals.
+func formatPromptPreview(taskID string, rendered worker.RenderedPrompt, summaryOnly bool) string {
+	builder := &strings.Builder{}
+	reasoning := rendered.ReasoningEffort
+	if reasoning == "" {
+		reasoning = "default"
+	}
+	fmt.Fprintf(builder, "<!-- governator prompt: task=%s stage=%s role=%s reasoning=%s -->\n", taskID, rendered.Stage, rendered.Role, reasoning)
+	for i, section := range rendered.Sections {
+		fmt.Fprintf(builder, "<!-- [%d/%d] %s: %d bytes, ~%d tokens -->\n", i+1, len(rendered.Sections), section.Path, section.Bytes(), section.EstimatedTokens())
+		if summaryOnly {
+			continue
+		}
+		builder.WriteString(section.Content)
+		if section.Content != "" && !strings.HasSuffix(section.Content, "\n") {
+			builder.WriteString("\n")
+		}
+		builder.WriteString("\n")
+	}
+	for _, section := range rendered.Dropped {
+		fmt.Fprintf(builder, "<!-- dropped by prompt budget: %s: %d bytes, ~%d tokens -->\n", section.Path, section.Bytes(), section.EstimatedTokens())
+	}
+	fmt.Fprintf(builder, "<!-- total: %d sections, %d bytes, ~%d tokens -->\n", len(rendered.Sections), rendered.Bytes(), rendered.EstimatedTokens())
+	return builder.String()
+}
+
 // parseTaskNumberPrefix extracts a numeric task prefix (for example, 010 from 010-task-name).
 func parseTaskNumberPrefix(value string) (int, bool) {
 	trimmed := strings.TrimSpace(value)
@@ -1064,6 +1324,14 @@ func collectWhyTaskSections(repoRoot string, taskLines int, supervisorState supe
 		}
 		return nil, fmt.Errorf("load task index: %w", err)
 	}
+	cfg, err := config.Load(repoRoot, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := worktree.NewManagerWithRoot(repoRoot, cfg.Worktrees.Root)
+	if err != nil {
+		return nil, fmt.Errorf("create worktree manager: %w", err)
+	}
 
 	sections := make([]whyTaskSection, 0)
 	for _, task := range idx.Tasks {
@@ -1072,7 +1340,7 @@ func collectWhyTaskSections(repoRoot string, taskLines int, supervisorState supe
 			continue
 		}
 
-		logPath, err := latestTaskStdoutLog(repoRoot, task.ID)
+		logPath, err := latestTaskStdoutLog(manager, task.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -1113,18 +1381,18 @@ func whyTaskKind(task index.Task, supervisorState supervisor.SupervisorStateInfo
 
 // latestTaskStdoutLog returns the most relevant worker log for a task.
 // Prefer stdout when present and non-empty; otherwise fall back to stderr.
-func latestTaskStdoutLog(repoRoot string, taskID string) (string, error) {
+func latestTaskStdoutLog(manager worktree.Manager, taskID string) (string, error) {
 	if strings.TrimSpace(taskID) == "" {
 		return "", nil
 	}
-	taskStateDir := filepath.Join(
-		repoRoot,
-		"_governator",
-		"_local-state",
-		"task-"+taskID,
-		"_governator",
-		"_local-state",
-	)
+	worktreePath, ok, err := manager.ExistingWorktreePath(taskID)
+	if err != nil {
+		return "", fmt.Errorf("resolve task worktree %s: %w", taskID, err)
+	}
+	if !ok {
+		return "", nil
+	}
+	taskStateDir := filepath.Join(worktreePath, "_governator", "_local-state")
 	entries, err := os.ReadDir(taskStateDir)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
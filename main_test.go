@@ -14,7 +14,11 @@ import (
 
 	"github.com/cmtonkinson/governator/internal/config"
 	"github.com/cmtonkinson/governator/internal/index"
+	"github.com/cmtonkinson/governator/internal/roles"
+	"github.com/cmtonkinson/governator/internal/run"
 	"github.com/cmtonkinson/governator/internal/supervisorlock"
+	"github.com/cmtonkinson/governator/internal/worker"
+	"github.com/cmtonkinson/governator/internal/worktree"
 )
 
 const usageMessage = "USAGE:\n    governator [global options] <command> [command options]"
@@ -46,6 +50,30 @@ func TestCLICommands(t *testing.T) {
 			expectedExit:  2,
 			expectedError: usageMessage,
 		},
+		{
+			name:          "start script requires dry-run",
+			args:          []string{"start", "--script", "plan.json"},
+			expectedExit:  2,
+			expectedError: "--script requires --dry-run",
+		},
+		{
+			name:          "prompt requires task id",
+			args:          []string{"prompt", "--stage", "review"},
+			expectedExit:  2,
+			expectedError: "expected exactly 1 task id",
+		},
+		{
+			name:          "prompt rejects unknown stage",
+			args:          []string{"prompt", "010", "--stage", "deploy"},
+			expectedExit:  2,
+			expectedError: "is not one of work, test, review, resolve",
+		},
+		{
+			name:          "revert requires task id",
+			args:          []string{"revert", "--requeue"},
+			expectedExit:  2,
+			expectedError: "expected exactly 1 task id",
+		},
 		{
 			name:           "version flag",
 			args:           []string{"--version"},
@@ -309,6 +337,41 @@ func TestInitCommand(t *testing.T) {
 	}
 }
 
+func TestFormatPromptPreview(t *testing.T) {
+	rendered := worker.RenderedPrompt{
+		Role:  "engineer",
+		Stage: roles.StageWork,
+		Sections: []worker.PromptSection{
+			{Path: "_governator/worker-contract.md", Content: "contract\n"},
+			{Path: "_governator/tasks/010-task.md", Content: "task body"},
+		},
+	}
+
+	full := formatPromptPreview("010-task", rendered, false)
+	for _, want := range []string{
+		"<!-- governator prompt: task=010-task stage=work role=engineer reasoning=default -->",
+		"<!-- [1/2] _governator/worker-contract.md: 9 bytes, ~3 tokens -->\ncontract\n",
+		"<!-- [2/2] _governator/tasks/010-task.md: 9 bytes, ~3 tokens -->\ntask body\n",
+		"<!-- total: 2 sections, 20 bytes, ~5 tokens -->",
+	} {
+		if !strings.Contains(full, want) {
+			t.Fatalf("preview missing %q:\n%s", want, full)
+		}
+	}
+
+	rendered.Dropped = []worker.PromptSection{{Path: "_governator/custom-prompts/_global.md", Content: "global"}}
+	summary := formatPromptPreview("010-task", rendered, true)
+	if !strings.Contains(summary, "<!-- dropped by prompt budget: _governator/custom-prompts/_global.md: 6 bytes, ~2 tokens -->") {
+		t.Fatalf("summary missing dropped section:\n%s", summary)
+	}
+	if strings.Contains(summary, "task body") {
+		t.Fatalf("summary should omit content:\n%s", summary)
+	}
+	if !strings.Contains(summary, "<!-- total: 2 sections, 20 bytes, ~5 tokens -->") {
+		t.Fatalf("summary missing total:\n%s", summary)
+	}
+}
+
 func TestRetryCommand(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -947,6 +1010,46 @@ func TestWhyCommand(t *testing.T) {
 			t.Fatalf("missing stderr fallback section in output:\n%s", got)
 		}
 	})
+
+	t.Run("reads task logs under the configured worktrees root", func(t *testing.T) {
+		configPath := filepath.Join(tempDir, "_governator", "_durable-state", "config.json")
+		if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+			t.Fatalf("mkdir config dir: %v", err)
+		}
+		if err := os.WriteFile(configPath, []byte(`{"worktrees": {"root": "scratch"}}`), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		t.Cleanup(func() { _ = os.Remove(configPath) })
+
+		worktreeDir, err := worktree.ResolveDir(tempDir, "scratch")
+		if err != nil {
+			t.Fatalf("resolve worktree dir: %v", err)
+		}
+		workerDir := filepath.Join(worktreeDir, "task-T-ERR-001", "_governator", "_local-state", "worker-2-work-default")
+		if err := os.MkdirAll(workerDir, 0o755); err != nil {
+			t.Fatalf("mkdir worker dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(workerDir, "stdout.log"), []byte("s-1\ns-2\n"), 0o644); err != nil {
+			t.Fatalf("write stdout log: %v", err)
+		}
+
+		cmd := exec.Command(binaryPath, "why", "-s", "1", "-t", "2")
+		cmd.Dir = tempDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("why with custom worktrees root failed: %v, output: %s", err, output)
+		}
+		got := string(output)
+
+		relDir, err := filepath.Rel(tempDir, workerDir)
+		if err != nil {
+			t.Fatalf("relative worker dir: %v", err)
+		}
+		want := "from " + filepath.ToSlash(filepath.Join(relDir, "stdout.log")) + " ===\ns-1\ns-2\n"
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing section from configured worktrees root in output:\n%s", got)
+		}
+	})
 }
 
 func TestHandleTailQuitInput(t *testing.T) {
@@ -992,3 +1095,19 @@ func TestHandleTailQuitInput(t *testing.T) {
 		}
 	})
 }
+
+// TestFormatRevertOutcome shortens commits and reports the resulting state.
+func TestFormatRevertOutcome(t *testing.T) {
+	outcome := run.RevertOutcome{
+		TaskID:       "010-task",
+		MergeCommit:  "0123456789abcdef0123",
+		RevertCommit: "fedcba9876543210fedc",
+	}
+	if got, want := formatRevertOutcome(outcome), "revert ok: 010-task merge 0123456789ab reverted by fedcba987654; state reverted\n"; got != want {
+		t.Fatalf("formatRevertOutcome = %q, want %q", got, want)
+	}
+	outcome.Requeued = true
+	if got := formatRevertOutcome(outcome); !strings.HasSuffix(got, "state triaged\n") {
+		t.Fatalf("requeued outcome = %q", got)
+	}
+}